@@ -37,6 +37,28 @@ func LoadConfig(configPath string) (*types.Config, error) {
 	return config, nil
 }
 
+// WriteConfig writes config to configPath as YAML or JSON, by extension, so
+// LoadConfig can read it back
+func WriteConfig(config *types.Config, configPath string) error {
+	var data []byte
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(configPath)); ext {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(config)
+	case ".json":
+		data, err = json.MarshalIndent(config, "", "  ")
+	default:
+		return fmt.Errorf("unsupported config file format: %s (supported: .yaml, .yml, .json)", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
 // ValidateConfig validates the configuration
 func ValidateConfig(config *types.Config) error {
 	if config.Action == "" {
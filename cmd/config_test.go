@@ -249,6 +249,34 @@ func TestLoadConfigErrors(t *testing.T) {
 	}
 }
 
+func TestWriteConfigRoundTrips(t *testing.T) {
+	config := &types.Config{
+		Action:       types.ActionSendToURL,
+		AttackType:   types.AttackTypeXSS,
+		Payload:      types.Payload{Method: types.PayloadMethodAuto},
+		EvasionLevel: types.EvasionLevelAdvanced,
+		Target:       types.Target{Method: types.TargetMethodURL, URL: "http://example.com"},
+		ReportType:   types.ReportTypeJSON,
+	}
+	for _, name := range []string{"config.yaml", "config.json"} {
+		path := t.TempDir() + "/" + name
+		if err := WriteConfig(config, path); err != nil {
+			t.Fatalf("WriteConfig(%s) error = %v", name, err)
+		}
+		loaded, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("LoadConfig(%s) error = %v", name, err)
+		}
+		if loaded.Action != config.Action || loaded.Target.URL != config.Target.URL || loaded.EvasionLevel != config.EvasionLevel {
+			t.Errorf("%s: loaded %+v, want %+v", name, loaded, config)
+		}
+	}
+
+	if err := WriteConfig(config, t.TempDir()+"/config.txt"); err == nil {
+		t.Error("WriteConfig(.txt) succeeded, want an unsupported format error")
+	}
+}
+
 func TestValidateConfig(t *testing.T) {
 	tests := []struct {
 		name    string
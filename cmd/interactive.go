@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -11,174 +12,140 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
+	"obfuskit/internal/evasions"
 	"obfuskit/internal/evasions/command"
 	"obfuskit/internal/evasions/encoders"
 	"obfuskit/internal/evasions/path"
 	"obfuskit/types"
 )
 
-var EvasionFunctions = map[types.PayloadEncoding]func(string, types.EvasionLevel) []string{
-	types.PayloadEncodingBase64: func(payload string, level types.EvasionLevel) []string {
+// EvasionFunc generates the variants of payload for one encoding
+type EvasionFunc func(ctx context.Context, payload string, level types.EvasionLevel) []types.Variant
+
+// EvasionFunctions generate the variants for each encoding. Techniques that
+// randomize draw from the source carried by ctx (see evasions.WithRand).
+// Generators that do not tell their techniques apart leave Technique empty;
+// ExplainEvasion labels those at the family level.
+var EvasionFunctions = map[types.PayloadEncoding]EvasionFunc{
+	types.PayloadEncodingBase64: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
 		return encoders.Base64Variants(payload, level)
-	},
-	types.PayloadEncodingBestFit: func(payload string, level types.EvasionLevel) []string {
+	}),
+	types.PayloadEncodingBestFit: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
 		return encoders.BestFitVariants(payload, level)
-	},
-	types.PayloadEncodingHex: func(payload string, level types.EvasionLevel) []string {
-		return encoders.HexVariants(payload, level)
-	},
-	types.PayloadEncodingHTML: func(payload string, level types.EvasionLevel) []string {
-		return encoders.HTMLVariants(payload, level)
-	},
-	types.PayloadEncodingOctal: func(payload string, level types.EvasionLevel) []string {
-		return encoders.OctalVariants(payload, level)
-	},
-	types.PayloadEncodingUnicode: func(payload string, level types.EvasionLevel) []string {
-		return encoders.UnicodeVariants(payload, level)
-	},
-	types.PayloadEncodingUnixCmd: func(payload string, level types.EvasionLevel) []string {
-		return command.UnixCmdVariants(payload, level)
-	},
-	types.PayloadEncodingWindowsCmd: func(payload string, level types.EvasionLevel) []string {
-		return command.WindowsCmdVariants(payload, level)
-	},
-	types.PayloadEncodingPathTraversal: func(payload string, level types.EvasionLevel) []string {
-		return path.PathTraversalVariants(payload, level)
-	},
-	types.PayloadEncodingURL: func(payload string, level types.EvasionLevel) []string {
+	}),
+	types.PayloadEncodingHex: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		return encoders.HexVariantsWithRand(evasions.RandFrom(ctx), payload, level)
+	}),
+	types.PayloadEncodingHTML: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		return encoders.HTMLVariantsWithRand(evasions.RandFrom(ctx), payload, level)
+	}),
+	types.PayloadEncodingOctal: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		return encoders.OctalVariantsWithRand(evasions.RandFrom(ctx), payload, level)
+	}),
+	types.PayloadEncodingUnicode: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		return encoders.UnicodeVariantsWithRand(evasions.RandFrom(ctx), payload, level)
+	}),
+	types.PayloadEncodingUnixCmd: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		return command.UnixCmdVariantsWithRand(evasions.RandFrom(ctx), payload, level)
+	}),
+	types.PayloadEncodingWindowsCmd: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		return command.WindowsCmdVariantsWithRand(evasions.RandFrom(ctx), payload, level)
+	}),
+	types.PayloadEncodingPathTraversal: path.PathTraversalVariantsContext,
+	types.PayloadEncodingURL: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
 		return encoders.URLVariants(payload, level)
-	},
-	types.PayloadEncodingDoubleURL: func(payload string, level types.EvasionLevel) []string {
+	}),
+	types.PayloadEncodingDoubleURL: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
 		return encoders.DoubleURLVariants(payload, level)
-	},
-	types.PayloadEncodingMixedCase: func(payload string, level types.EvasionLevel) []string {
-		return encoders.MixedCaseVariants(payload, level)
-	},
-	types.PayloadEncodingUTF8: func(payload string, level types.EvasionLevel) []string {
+	}),
+	types.PayloadEncodingMixedCase: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		return encoders.MixedCaseVariantsWithRand(evasions.RandFrom(ctx), payload, level)
+	}),
+	types.PayloadEncodingUTF8: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
 		return encoders.UTF8Variants(payload, level)
-	},
+	}),
+	types.PayloadEncodingUTF7: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		return encoders.UTF7Variants(payload, level)
+	}),
+	types.PayloadEncodingInterleaved: fromValues(func(ctx context.Context, payload string, level types.EvasionLevel) []string {
+		// Seeded from the payload unless the worker carries its own source
+		if rng := evasions.RandFrom(ctx); rng != evasions.DefaultRand {
+			return encoders.InterleavedEncodingVariantsWithRand(rng, payload, level)
+		}
+		return encoders.InterleavedEncodingVariants(payload, level)
+	}),
 }
 
-var PayloadEvasionMap = map[types.AttackType][]types.PayloadEncoding{
-	types.AttackTypeXSS: {
-		types.PayloadEncodingHTML,
-		types.PayloadEncodingUnicode,
-		types.PayloadEncodingHex,
-		types.PayloadEncodingOctal,
-		types.PayloadEncodingBase64,
-		types.PayloadEncodingBestFit,
-	},
-	types.AttackTypeSQLI: {
-		types.PayloadEncodingUnixCmd,
-		types.PayloadEncodingUnicode,
-		types.PayloadEncodingHex,
-		types.PayloadEncodingOctal,
-		types.PayloadEncodingBase64,
-		types.PayloadEncodingBestFit,
-	},
-	types.AttackTypeUnixCMDI: {
-		types.PayloadEncodingUnixCmd,
-		types.PayloadEncodingUnicode,
-		types.PayloadEncodingHex,
-		types.PayloadEncodingOctal,
-		types.PayloadEncodingBase64,
-		types.PayloadEncodingBestFit,
-		types.PayloadEncodingPathTraversal,
-	},
-	types.AttackTypeWinCMDI: {
-		types.PayloadEncodingWindowsCmd,
-		types.PayloadEncodingUnicode,
-		types.PayloadEncodingHex,
-		types.PayloadEncodingOctal,
-		types.PayloadEncodingBase64,
-		types.PayloadEncodingBestFit,
-		types.PayloadEncodingPathTraversal,
-	},
-	types.AttackTypePath: {
-		types.PayloadEncodingPathTraversal,
-		types.PayloadEncodingUnicode,
-		types.PayloadEncodingHex,
-		types.PayloadEncodingOctal,
-		types.PayloadEncodingBase64,
-		types.PayloadEncodingBestFit,
-	},
-	types.AttackTypeFileAccess: {
-		types.PayloadEncodingPathTraversal,
-		types.PayloadEncodingUnicode,
-		types.PayloadEncodingHex,
-		types.PayloadEncodingOctal,
-		types.PayloadEncodingBase64,
-		types.PayloadEncodingBestFit,
-	},
-	types.AttackTypeLDAP: {
-		types.PayloadEncodingUnicode,
-		types.PayloadEncodingHex,
-		types.PayloadEncodingOctal,
-		types.PayloadEncodingBase64,
-		types.PayloadEncodingBestFit,
-	},
-	types.AttackTypeGeneric: {
-		types.PayloadEncodingHTML,
-		types.PayloadEncodingUnicode,
-		types.PayloadEncodingHex,
-		types.PayloadEncodingOctal,
-		types.PayloadEncodingBase64,
-		types.PayloadEncodingBestFit,
-		types.PayloadEncodingUnixCmd,
-		types.PayloadEncodingWindowsCmd,
-		types.PayloadEncodingPathTraversal,
-	},
+// fromValues adapts a generator of bare variant strings
+func fromValues(fn func(context.Context, string, types.EvasionLevel) []string) EvasionFunc {
+	return func(ctx context.Context, payload string, level types.EvasionLevel) []types.Variant {
+		values := fn(ctx, payload, level)
+		if values == nil {
+			return nil
+		}
+		variants := make([]types.Variant, len(values))
+		for i, value := range values {
+			variants[i] = types.Variant{Value: value}
+		}
+		return variants
+	}
 }
 
-var EvasionCategoryMap = map[types.PayloadEncoding]types.EvasionCategory{
-	types.PayloadEncodingHTML:          types.EvasionCategoryEncoder,
-	types.PayloadEncodingUnicode:       types.EvasionCategoryEncoder,
-	types.PayloadEncodingHex:           types.EvasionCategoryEncoder,
-	types.PayloadEncodingOctal:         types.EvasionCategoryEncoder,
-	types.PayloadEncodingBase64:        types.EvasionCategoryEncoder,
-	types.PayloadEncodingBestFit:       types.EvasionCategoryEncoder,
-	types.PayloadEncodingURL:           types.EvasionCategoryEncoder,
-	types.PayloadEncodingDoubleURL:     types.EvasionCategoryEncoder,
-	types.PayloadEncodingMixedCase:     types.EvasionCategoryEncoder,
-	types.PayloadEncodingUTF8:          types.EvasionCategoryEncoder,
-	types.PayloadEncodingUnixCmd:       types.EvasionCategoryCommand,
-	types.PayloadEncodingWindowsCmd:    types.EvasionCategoryCommand,
-	types.PayloadEncodingPathTraversal: types.EvasionCategoryPath,
-}
+// The attack-type encoding tables live in types, where validation reads
+// them too; these names keep them reachable from cmd
+var (
+	PayloadEvasionMap    = types.PayloadEvasionMap
+	EvasionCategoryMap   = types.EvasionCategoryMap
+	EvasionMinimumLevels = types.EvasionMinimumLevels
+
+	GetEvasionsForPayload  = types.GetEvasionsForPayload
+	GetEvasionsForLevel    = types.GetEvasionsForLevel
+	EvasionAppliesAtLevel  = types.EvasionAppliesAtLevel
+	GetEvasionsByCategory  = types.GetEvasionsByCategory
+	IsEvasionApplicable    = types.IsEvasionApplicable
+	CheckEvasionApplicable = types.CheckEvasionApplicable
+)
 
-func GetEvasionsForPayload(attackType types.AttackType) ([]types.PayloadEncoding, bool) {
-	evasions, exists := PayloadEvasionMap[attackType]
-	return evasions, exists
-}
+// InapplicableEvasionError is types.InapplicableEvasionError
+type InapplicableEvasionError = types.InapplicableEvasionError
 
-func GetEvasionsByCategory(attackType types.AttackType) map[types.EvasionCategory][]types.PayloadEncoding {
-	evasions, exists := PayloadEvasionMap[attackType]
-	if !exists {
-		return nil
-	}
-	categorized := make(map[types.EvasionCategory][]types.PayloadEncoding)
-	for _, evasion := range evasions {
-		category := EvasionCategoryMap[evasion]
-		categorized[category] = append(categorized[category], evasion)
-	}
-	return categorized
+func ApplyEvasion(payload string, evasionType types.PayloadEncoding, level types.EvasionLevel) ([]string, error) {
+	return ApplyEvasionContext(context.Background(), payload, evasionType, level)
 }
 
-func IsEvasionApplicable(payloadType types.AttackType, evasionType types.PayloadEncoding) bool {
-	evasions, exists := PayloadEvasionMap[payloadType]
-	if !exists {
-		return false
+// ApplyEvasionContext is ApplyEvasion drawing randomness from the source
+// carried by ctx, so a worker with its own seeded source gets reproducible
+// variants without contending on the global one
+func ApplyEvasionContext(ctx context.Context, payload string, evasionType types.PayloadEncoding, level types.EvasionLevel) ([]string, error) {
+	variants, err := ExplainEvasion(ctx, payload, evasionType, level)
+	if err != nil {
+		return nil, err
 	}
+	return evasions.Values(variants), nil
+}
 
-	for _, evasion := range evasions {
-		if evasion == evasionType {
-			return true
-		}
-	}
-	return false
+// EvasionExplanations describe each encoding family in a sentence
+var EvasionExplanations = map[types.PayloadEncoding]string{
+	types.PayloadEncodingURL:           "URL (percent) encoding that the server decodes before use",
+	types.PayloadEncodingDoubleURL:     "URL encoding applied twice, for filters that decode only once",
+	types.PayloadEncodingMixedCase:     "case changes that case-insensitive parsers ignore",
+	types.PayloadEncodingBase64:        "Base64 forms for contexts that decode them",
+	types.PayloadEncodingBestFit:       "Unicode look-alikes that best-fit mapping turns back into ASCII",
+	types.PayloadEncodingHex:           "hex escapes and literals the target decodes",
+	types.PayloadEncodingHTML:          "HTML entities that the browser decodes",
+	types.PayloadEncodingOctal:         "octal escapes the target decodes",
+	types.PayloadEncodingUnicode:       "Unicode escapes and invisible characters that evade literal matching",
+	types.PayloadEncodingUnixCmd:       "shell syntax that runs the same command in a different spelling",
+	types.PayloadEncodingWindowsCmd:    "cmd.exe syntax that runs the same command in a different spelling",
+	types.PayloadEncodingPathTraversal: "path forms that resolve to the same file",
+	types.PayloadEncodingUTF8:          "UTF-8 forms, including invalid ones, that decoders accept",
+	types.PayloadEncodingUTF7:          "UTF-7 forms that a parser sniffing or told the UTF-7 charset decodes back",
+	types.PayloadEncodingInterleaved:   "each character in a different encoding, so no single decode pass reveals the payload",
 }
 
-func ApplyEvasion(payload string, evasionType types.PayloadEncoding, level types.EvasionLevel) ([]string, error) {
+// ExplainEvasion is ApplyEvasionContext returning each variant with the
+// technique that produced it and why it might bypass a filter
+func ExplainEvasion(ctx context.Context, payload string, evasionType types.PayloadEncoding, level types.EvasionLevel) ([]types.Variant, error) {
 	if payload == "" {
 		return nil, nil
 	}
@@ -194,7 +161,25 @@ func ApplyEvasion(payload string, evasionType types.PayloadEncoding, level types
 		}
 	}()
 
-	return evasionFunc(payload, level), nil
+	var variants []types.Variant
+	if evasions.Exhaustive(ctx) {
+		evasions.EnumerateDraws(func(rng evasions.Rand) {
+			variants = append(variants, evasionFunc(evasions.WithRand(ctx, rng), payload, level)...)
+		})
+		variants = evasions.UniqueVariants(variants)
+	} else {
+		variants = evasionFunc(ctx, payload, level)
+	}
+	for i := range variants {
+		variants[i].Encoding = string(evasionType)
+		variants[i].Level = string(level)
+		variants[i].SourcePayload = payload
+		if variants[i].Technique == "" {
+			variants[i].Technique = string(evasionType)
+			variants[i].Explanation = EvasionExplanations[evasionType]
+		}
+	}
+	return variants, nil
 }
 
 func ApplyEvasionsToPayload(payload string, attackType types.AttackType, level types.EvasionLevel) map[types.PayloadEncoding][]string {
@@ -202,7 +187,7 @@ func ApplyEvasionsToPayload(payload string, attackType types.AttackType, level t
 		return nil
 	}
 
-	evasions, exists := GetEvasionsForPayload(attackType)
+	evasions, exists := types.GetEvasionsForLevel(attackType, level)
 	if !exists {
 		return nil
 	}
@@ -223,14 +208,14 @@ func ApplyEvasionsToPayload(payload string, attackType types.AttackType, level t
 }
 
 func GetAllAttackTypes() []types.AttackType {
-	types := make([]types.AttackType, 0, len(PayloadEvasionMap))
-	for payloadType := range PayloadEvasionMap {
-		types = append(types, payloadType)
+	attackTypes := make([]types.AttackType, 0, len(types.PayloadEvasionMap))
+	for payloadType := range types.PayloadEvasionMap {
+		attackTypes = append(attackTypes, payloadType)
 	}
-	sort.Slice(types, func(i, j int) bool {
-		return types[i] < types[j]
+	sort.Slice(attackTypes, func(i, j int) bool {
+		return attackTypes[i] < attackTypes[j]
 	})
-	return types
+	return attackTypes
 }
 
 func PrintPayloadEvasionMap() {
@@ -241,7 +226,7 @@ func PrintPayloadEvasionMap() {
 
 	for _, attackType := range attackTypes {
 		fmt.Printf("\n%s:\n", attackType)
-		categorized := GetEvasionsByCategory(attackType)
+		categorized := types.GetEvasionsByCategory(attackType)
 
 		for category, evasions := range categorized {
 			fmt.Printf("  %s:\n", category)
@@ -308,6 +293,8 @@ var (
 		item{string(types.PayloadEncodingDoubleURL), "Apply URL encoding twice"},
 		item{string(types.PayloadEncodingMixedCase), "Use mixed case characters in payloads"},
 		item{string(types.PayloadEncodingUTF8), "Use UTF-8 byte sequences"},
+		item{string(types.PayloadEncodingUTF7), "Use UTF-7 encoded forms"},
+		item{string(types.PayloadEncodingInterleaved), "Encode each character with a different scheme"},
 	}
 
 	evasionLevelItems = []list.Item{
@@ -339,8 +326,24 @@ var (
 		item{string(types.ReportTypeNuclei), "Generate nuclei YAML templates for automated scanning"},
 		item{string(types.ReportTypeJSON), "Generate data in JSON format"},
 	}
+
+	confirmItems = []list.Item{
+		item{confirmRun, "Start with this configuration"},
+		item{confirmSave, "Write this configuration to " + DefaultInteractiveConfigPath + " and exit"},
+		item{confirmBack, "Return to the report format to change the selection"},
+	}
+)
+
+// Choices offered on the confirmation screen
+const (
+	confirmRun  = "Run now"
+	confirmSave = "Save config"
+	confirmBack = "Go back to edit"
 )
 
+// DefaultInteractiveConfigPath is where "Save config" writes the selection
+const DefaultInteractiveConfigPath = "obfuskit-config.yaml"
+
 // state represents the current state of the UI
 type state int
 
@@ -359,6 +362,7 @@ const (
 	stateEnterURL
 	stateChooseReportMethod
 	stateChooseSpecificReport
+	stateConfirm
 	stateDone
 )
 
@@ -378,12 +382,16 @@ type Model struct {
 	SelectedTargetMethod  types.TargetMethod
 	SelectedReportType    types.ReportType
 	URL                   string
-	ready                 bool
-	autoAttack            bool
-	autoPayload           bool
-	autoReport            bool
-	isEnteringPayloads    bool
-	payloadInputBuffer    string
+	// RunNow and SaveConfig record the choice made on the confirmation
+	// screen; main runs the selection or writes it to a config file
+	RunNow             bool
+	SaveConfig         bool
+	ready              bool
+	autoAttack         bool
+	autoPayload        bool
+	autoReport         bool
+	isEnteringPayloads bool
+	payloadInputBuffer string
 }
 
 // initialModel creates the initial model state
@@ -624,21 +632,58 @@ func (m Model) handleEnterKey() (tea.Model, tea.Cmd) {
 		} else {
 			m.autoReport = true
 			m.SelectedReportType = "All"
-			m.current = stateDone
-			return m, tea.Quit
+			m.showConfirm()
 		}
 
 	case stateChooseSpecificReport:
 		m.SelectedReportType = types.ReportType(m.list.SelectedItem().(item).title)
-		m.current = stateDone
-		return m, tea.Quit
+		m.showConfirm()
+
+	case stateConfirm:
+		switch m.list.SelectedItem().(item).title {
+		case confirmRun:
+			m.RunNow = true
+			m.current = stateDone
+			return m, tea.Quit
+		case confirmSave:
+			m.SaveConfig = true
+			m.current = stateDone
+			return m, tea.Quit
+		default:
+			m.backFromConfirm()
+		}
 	}
 
 	return m, nil
 }
 
+// showConfirm moves to the confirmation screen with its choices
+func (m *Model) showConfirm() {
+	m.RunNow, m.SaveConfig = false, false
+	m.list.SetItems(confirmItems)
+	m.list.Select(0)
+	m.list.Title = "Run this configuration?"
+	m.current = stateConfirm
+}
+
+// backFromConfirm returns to the report format choice that led to the
+// confirmation screen
+func (m *Model) backFromConfirm() {
+	if m.autoReport {
+		m.list.SetItems(reportItems)
+		m.list.Title = "Choose report format:"
+		m.current = stateChooseReportMethod
+	} else {
+		m.list.SetItems(specificReportItems)
+		m.list.Title = "Choose specific report format:"
+		m.current = stateChooseSpecificReport
+	}
+}
+
 func (m Model) handleEscKey() (tea.Model, tea.Cmd) {
 	switch m.current {
+	case stateConfirm:
+		m.backFromConfirm()
 	case stateChooseAttackMethod:
 		m.list.SetItems(mainMenuItems)
 		m.list.Title = "Select what you want to do:"
@@ -719,46 +764,51 @@ func (m Model) View() string {
 		}
 
 		return display.String()
+	case stateConfirm:
+		return m.summary() + "\n\n" + m.list.View() + "\n\n(Press Enter to choose, 'esc' to go back)"
 	case stateDone:
-		summary := fmt.Sprintf(`
+		return m.summary() + "\n\nPress any key to exit..."
+	default:
+		return m.list.View() + "\n\n(Press 'q' to quit, 'esc' to go back)"
+	}
+}
+
+// summary describes the selection made so far
+func (m Model) summary() string {
+	summary := fmt.Sprintf(`
 Configuration Summary:
 =====================
 Main Action    : %s
 Attack Type    : %s %s
 Evasion Method : %s %s`,
-			string(m.SelectedAction),
-			string(m.SelectedAttackType), autoString(m.autoAttack),
-			string(m.SelectedPayloadMethod), autoString(m.autoPayload))
+		string(m.SelectedAction),
+		string(m.SelectedAttackType), autoString(m.autoAttack),
+		string(m.SelectedPayloadMethod), autoString(m.autoPayload))
 
-		if m.SelectedEncoding != "" {
-			summary += fmt.Sprintf("\nEncoding       : %s", m.SelectedEncoding)
-		}
+	if m.SelectedEncoding != "" {
+		summary += fmt.Sprintf("\nEncoding       : %s", m.SelectedEncoding)
+	}
 
-		if m.SelectedEvasionLevel != "" {
-			summary += fmt.Sprintf("\nEvasion Level  : %s", m.SelectedEvasionLevel)
-		}
+	if m.SelectedEvasionLevel != "" {
+		summary += fmt.Sprintf("\nEvasion Level  : %s", m.SelectedEvasionLevel)
+	}
 
-		if m.SelectedPayloadSource != "" {
-			summary += fmt.Sprintf("\nPayload Source : %s", m.SelectedPayloadSource)
-			if m.SelectedPayloadSource == types.PayloadSourceFromFile && m.PayloadFilePath != "" {
-				summary += fmt.Sprintf(" (%s)", m.PayloadFilePath)
-			} else if m.SelectedPayloadSource == types.PayloadSourceEnterManually && len(m.CustomPayloads) > 0 {
-				summary += fmt.Sprintf(" (%d payloads)", len(m.CustomPayloads))
-			}
+	if m.SelectedPayloadSource != "" {
+		summary += fmt.Sprintf("\nPayload Source : %s", m.SelectedPayloadSource)
+		if m.SelectedPayloadSource == types.PayloadSourceFromFile && m.PayloadFilePath != "" {
+			summary += fmt.Sprintf(" (%s)", m.PayloadFilePath)
+		} else if m.SelectedPayloadSource == types.PayloadSourceEnterManually && len(m.CustomPayloads) > 0 {
+			summary += fmt.Sprintf(" (%d payloads)", len(m.CustomPayloads))
 		}
+	}
 
-		summary += fmt.Sprintf(`
+	summary += fmt.Sprintf(`
 Target         : %s (%s)
-Report Type    : %s %s
+Report Type    : %s %s`,
+		string(m.SelectedTargetMethod), m.URL,
+		string(m.SelectedReportType), autoString(m.autoReport))
 
-Press any key to exit...`,
-			string(m.SelectedTargetMethod), m.URL,
-			string(m.SelectedReportType), autoString(m.autoReport))
-
-		return summary
-	default:
-		return m.list.View() + "\n\n(Press 'q' to quit, 'esc' to go back)"
-	}
+	return summary
 }
 
 func autoString(auto bool) string {
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"obfuskit/internal/evasions"
+	"obfuskit/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// randomizedEncodings are the encodings whose techniques draw random values
+var randomizedEncodings = []types.PayloadEncoding{
+	types.PayloadEncodingHex,
+	types.PayloadEncodingHTML,
+	types.PayloadEncodingOctal,
+	types.PayloadEncodingUnicode,
+	types.PayloadEncodingUnixCmd,
+	types.PayloadEncodingWindowsCmd,
+	types.PayloadEncodingPathTraversal,
+}
+
+func TestApplyEvasionContextReproduciblePerSeed(t *testing.T) {
+	const payload = "../../etc/passwd; cat /etc/shadow"
+
+	generate := func(seed int64, worker int) map[types.PayloadEncoding][]string {
+		ctx := evasions.WithRand(context.Background(), evasions.NewRand(seed, worker))
+		out := make(map[types.PayloadEncoding][]string)
+		for _, encoding := range randomizedEncodings {
+			variants, err := ApplyEvasionContext(ctx, payload, encoding, types.EvasionLevelAdvanced)
+			if err != nil {
+				t.Fatalf("ApplyEvasionContext(%s) error = %v", encoding, err)
+			}
+			out[encoding] = variants
+		}
+		return out
+	}
+
+	first := generate(42, 1)
+	if !reflect.DeepEqual(first, generate(42, 1)) {
+		t.Error("same seed and worker produced different variants")
+	}
+	if reflect.DeepEqual(first, generate(42, 2)) {
+		t.Error("different workers produced identical variants; streams should differ")
+	}
+}
+
+func TestUTF8VariantsAreRegisteredAndApplied(t *testing.T) {
+	if _, ok := EvasionFunctions[types.PayloadEncodingUTF8]; !ok {
+		t.Fatalf("EvasionFunctions has no %s entry", types.PayloadEncodingUTF8)
+	}
+	for _, attackType := range []types.AttackType{types.AttackTypeXSS, types.AttackTypeSQLI, types.AttackTypePath} {
+		if !IsEvasionApplicable(attackType, types.PayloadEncodingUTF8) {
+			t.Errorf("%s is not applied to %s payloads", types.PayloadEncodingUTF8, attackType)
+		}
+	}
+
+	variants := ApplyEvasionsToPayload("<script>", types.AttackTypeXSS, types.EvasionLevelBasic)[types.PayloadEncodingUTF8]
+	if len(variants) == 0 {
+		t.Errorf("ApplyEvasionsToPayload() gave no %s variants", types.PayloadEncodingUTF8)
+	}
+}
+
+func benchmarkApplyEvasionParallel(b *testing.B, newCtx func(worker int) context.Context) {
+	var workers atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		ctx := newCtx(int(workers.Add(1)))
+		for pb.Next() {
+			for _, encoding := range randomizedEncodings {
+				if _, err := ApplyEvasionContext(ctx, "../../etc/passwd", encoding, types.EvasionLevelAdvanced); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkApplyEvasionParallel(b *testing.B) {
+	b.Run("global", func(b *testing.B) {
+		benchmarkApplyEvasionParallel(b, func(int) context.Context {
+			return context.Background()
+		})
+	})
+	b.Run("per-worker", func(b *testing.B) {
+		benchmarkApplyEvasionParallel(b, func(worker int) context.Context {
+			return evasions.WithRand(context.Background(), evasions.NewRand(1, worker))
+		})
+	})
+}
+
+// confirmModel walks to the confirmation screen through a specific report
+// format choice
+func confirmModel(t *testing.T) Model {
+	t.Helper()
+	m := initialModel()
+	m.list.SetSize(80, 40)
+	m.list.SetItems(specificReportItems)
+	m.current = stateChooseSpecificReport
+	m = press(t, m, tea.KeyEnter)
+	if m.current != stateConfirm {
+		t.Fatalf("after choosing a report format state = %v, want stateConfirm", m.current)
+	}
+	return m
+}
+
+// press sends key to m and returns the updated model
+func press(t *testing.T, m Model, key tea.KeyType) Model {
+	t.Helper()
+	updated, _ := m.Update(tea.KeyMsg{Type: key})
+	return updated.(Model)
+}
+
+// choose selects the confirmation choice titled title and presses enter
+func choose(t *testing.T, m Model, title string) (Model, tea.Cmd) {
+	t.Helper()
+	for i, choice := range confirmItems {
+		if choice.(item).title == title {
+			m.list.Select(i)
+		}
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(Model), cmd
+}
+
+func TestConfirmRunNowQuitsWithRunFlag(t *testing.T) {
+	m, cmd := choose(t, confirmModel(t), confirmRun)
+	if !m.RunNow || m.SaveConfig {
+		t.Errorf("RunNow = %v, SaveConfig = %v; want only RunNow", m.RunNow, m.SaveConfig)
+	}
+	if m.current != stateDone || cmd == nil {
+		t.Errorf("state = %v, quit = %v; want stateDone and quit", m.current, cmd != nil)
+	}
+}
+
+func TestConfirmSaveConfigQuitsWithSaveFlag(t *testing.T) {
+	m, cmd := choose(t, confirmModel(t), confirmSave)
+	if !m.SaveConfig || m.RunNow {
+		t.Errorf("RunNow = %v, SaveConfig = %v; want only SaveConfig", m.RunNow, m.SaveConfig)
+	}
+	if m.current != stateDone || cmd == nil {
+		t.Errorf("state = %v, quit = %v; want stateDone and quit", m.current, cmd != nil)
+	}
+}
+
+func TestConfirmGoBackReturnsToReportChoice(t *testing.T) {
+	m, cmd := choose(t, confirmModel(t), confirmBack)
+	if m.current != stateChooseSpecificReport {
+		t.Errorf("state = %v, want stateChooseSpecificReport", m.current)
+	}
+	if m.RunNow || m.SaveConfig || cmd != nil {
+		t.Error("going back must not run, save or quit")
+	}
+
+	// Esc goes back too, to the report method when all formats were chosen
+	m = confirmModel(t)
+	m.autoReport = true
+	if m = press(t, m, tea.KeyEsc); m.current != stateChooseReportMethod {
+		t.Errorf("after esc state = %v, want stateChooseReportMethod", m.current)
+	}
+}
@@ -0,0 +1,163 @@
+// Package canon predicts how a lenient target canonicalizes input: the
+// decoders, whitespace stripping, case folding and loose JSON splitting
+// shared by the vulnerable demo app.
+package canon
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Decode modes understood by DecodeOnce
+const (
+	ModeURL        = "url"
+	ModeBase64     = "b64"
+	ModeHex        = "hex"
+	ModeHTML       = "html"
+	ModeOctal      = "octal"
+	ModeUnicode    = "unicode"
+	ModeWhitespace = "ws"
+	ModeIDNA       = "idna"
+)
+
+// DecodeOnce applies one decoding step the way a lenient server would.
+// Unknown modes return s unchanged; decode failures for url, b64 and hex
+// return an error.
+func DecodeOnce(mode, s string) (string, error) {
+	switch mode {
+	case ModeURL:
+		return url.QueryUnescape(s)
+	case ModeBase64, "base64":
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			// Try URL encoding variant
+			d2, err2 := base64.URLEncoding.DecodeString(s)
+			if err2 != nil {
+				return "", err
+			}
+			return string(d2), nil
+		}
+		return string(data), nil
+	case ModeHex:
+		data, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case ModeHTML:
+		return html.UnescapeString(s), nil
+	case ModeOctal:
+		return DecodeOctalEscapes(s), nil
+	case ModeUnicode:
+		return DecodeUnicodeEscapes(s), nil
+	case ModeWhitespace, "whitespace":
+		return RemoveOddWhitespace(s), nil
+	case ModeIDNA:
+		// Very naive: split host and attempt puny-like lowercasing only
+		parts := strings.Split(s, "/")
+		if len(parts) > 0 {
+			parts[0] = strings.ToLower(parts[0])
+		}
+		return strings.Join(parts, "/"), nil
+	default:
+		return s, nil
+	}
+}
+
+// RemoveOddWhitespace strips the non-breaking and zero-width characters
+// some servers drop before matching
+func RemoveOddWhitespace(s string) string {
+	// Remove a set of uncommon whitespace characters
+	odd := []rune{'\u00A0', '\u2007', '\u202F', '\u200B', '\u200C', '\u200D', '\u2060'}
+	for _, r := range odd {
+		s = strings.ReplaceAll(s, string(r), "")
+	}
+	return s
+}
+
+// DecodeOctalEscapes decodes \NNN escapes, leaving anything else as is
+func DecodeOctalEscapes(s string) string {
+	var out bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if isOctal(s[i+1]) && isOctal(s[i+2]) && isOctal(s[i+3]) {
+				val := (int(s[i+1]-'0') << 6) | (int(s[i+2]-'0') << 3) | int(s[i+3]-'0')
+				out.WriteByte(byte(val))
+				i += 3
+				continue
+			}
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
+}
+
+func isOctal(b byte) bool { return b >= '0' && b <= '7' }
+
+// DecodeUnicodeEscapes decodes \uXXXX and \xHH escapes, leaving anything else as is
+func DecodeUnicodeEscapes(s string) string {
+	// Handle \uXXXX and \xHH sequences
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case 'u':
+				if i+6 <= len(s) {
+					hexDigits := s[i+2 : i+6]
+					if v, err := strconv.ParseInt(hexDigits, 16, 32); err == nil {
+						out.WriteRune(rune(v))
+						i += 5
+						continue
+					}
+				}
+			case 'x':
+				if i+4 <= len(s) {
+					hexDigits := s[i+2 : i+4]
+					if v, err := strconv.ParseInt(hexDigits, 16, 8); err == nil {
+						out.WriteByte(byte(v))
+						i += 3
+						continue
+					}
+				}
+			}
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
+}
+
+// SplitLoose splits naive JSON-like text into tokens by punctuation
+func SplitLoose(s string) []string {
+	seps := func(r rune) bool {
+		switch r {
+		case '{', '}', ':', ',', '\n', '\r', '\t', ' ', '"', '\'':
+			return true
+		}
+		return false
+	}
+	fields := strings.FieldsFunc(s, seps)
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ApplyCase folds s to the mode's case: "upper", "lower", or unchanged
+func ApplyCase(mode, s string) string {
+	switch strings.ToLower(mode) {
+	case "upper":
+		return strings.ToUpper(s)
+	case "lower":
+		return strings.ToLower(s)
+	default:
+		return s
+	}
+}
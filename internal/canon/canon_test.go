@@ -0,0 +1,83 @@
+package canon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeOnce(t *testing.T) {
+	tests := []struct {
+		mode     string
+		input    string
+		expected string
+	}{
+		{ModeURL, "%3Cscript%3E+x", "<script> x"},
+		{ModeBase64, "PHNjcmlwdD4=", "<script>"},
+		{"base64", "PHNjcmlwdD4=", "<script>"},
+		{ModeBase64, "Pz8_", "???"},
+		{ModeHex, "3c7363726970743e", "<script>"},
+		{ModeHex, "0x3c73", "<s"},
+		{ModeHTML, "&lt;script&#x3e;", "<script>"},
+		{ModeOctal, `\074script\076`, "<script>"},
+		{ModeUnicode, `<script\x3e`, "<script>"},
+		{ModeWhitespace, "sel\u200bect\u00a0", "select"},
+		{"whitespace", "a\u2060b", "ab"},
+		{ModeIDNA, "EXAMPLE.com/Path", "example.com/Path"},
+		{"unknown", "%3C", "%3C"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+"/"+tt.input, func(t *testing.T) {
+			got, err := DecodeOnce(tt.mode, tt.input)
+			if err != nil {
+				t.Fatalf("DecodeOnce(%q, %q) error = %v", tt.mode, tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("DecodeOnce(%q, %q) = %q, want %q", tt.mode, tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeOnceErrors(t *testing.T) {
+	for _, tt := range []struct{ mode, input string }{
+		{ModeURL, "%zz"},
+		{ModeBase64, "!!!"},
+		{ModeHex, "xyz"},
+	} {
+		if _, err := DecodeOnce(tt.mode, tt.input); err == nil {
+			t.Errorf("DecodeOnce(%q, %q) error = nil, want error", tt.mode, tt.input)
+		}
+	}
+}
+
+func TestEscapeDecodersLeaveIncompleteSequences(t *testing.T) {
+	if got := DecodeOctalEscapes(`\07`); got != `\07` {
+		t.Errorf("DecodeOctalEscapes(short) = %q", got)
+	}
+	if got := DecodeOctalEscapes(`\089x`); got != `\089x` {
+		t.Errorf("DecodeOctalEscapes(non-octal) = %q", got)
+	}
+	if got := DecodeUnicodeEscapes(`\u12`); got != `\u12` {
+		t.Errorf("DecodeUnicodeEscapes(short) = %q", got)
+	}
+}
+
+func TestSplitLoose(t *testing.T) {
+	got := SplitLoose("{\"a\": 1, 'b':\t\"two\"\n,c:3}")
+	want := []string{"a", "1", "b", "two", "c", "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitLoose() = %q, want %q", got, want)
+	}
+	if got := SplitLoose(" {} "); len(got) != 0 {
+		t.Errorf("SplitLoose(empty object) = %q, want none", got)
+	}
+}
+
+func TestApplyCase(t *testing.T) {
+	for mode, want := range map[string]string{"upper": "KEY", "LOWER": "key", "": "KeY", "original": "KeY"} {
+		if got := ApplyCase(mode, "KeY"); got != want {
+			t.Errorf("ApplyCase(%q) = %q, want %q", mode, got, want)
+		}
+	}
+}
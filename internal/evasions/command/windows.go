@@ -2,7 +2,6 @@ package command
 
 import (
 	"fmt"
-	"math/rand"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
 	"regexp"
@@ -12,22 +11,28 @@ import (
 // WindowsCmdVariants generates various Windows command evasion techniques
 // based on the specified obfuscation level
 func WindowsCmdVariants(payload string, level types.EvasionLevel) []string {
+	return WindowsCmdVariantsWithRand(evasions.DefaultRand, payload, level)
+}
+
+// WindowsCmdVariantsWithRand is WindowsCmdVariants drawing randomness from rng
+func WindowsCmdVariantsWithRand(rng evasions.Rand, payload string, level types.EvasionLevel) []string {
 	var variants []string
 
 	// Basic evasion techniques
 	variants = append(variants,
-		randomQuoteEvasion(payload),   // Using quotes at random places to break commands
-		randomCaretEvasion(payload),   // Using ^ to escape characters
-		variableSubstitution(payload), // %var% style substitution
-		commaEvasion(payload),         // Command commas (,)
-		spacingVariations(payload),    // Various spacing techniques
-		delayedExpansion(payload),     // Delayed expansion with !var!
-		envVarObfuscation(payload),    // Environment variable obfuscation
-		commandSeparators(payload),    // Using & and | for separation
-		forTokens(payload),            // FOR /F tokens obfuscation
-		doubleQuoteEvasion(payload),   // Double quote variations
-		parenthesisEvasion(payload),   // Parenthesis variations
-		randomCase(payload),           // Random capitalization
+		randomQuoteEvasion(rng, payload), // Using quotes at random places to break commands
+		randomCaretEvasion(rng, payload), // Using ^ to escape characters
+		variableSubstitution(payload),    // %var% style substitution
+		commaEvasion(rng, payload),       // Command commas (,)
+		spacingVariations(rng, payload),  // Various spacing techniques
+		delayedExpansion(payload),        // Delayed expansion with !var!
+		envVarObfuscation(rng, payload),  // Environment variable obfuscation
+		commandSeparators(rng, payload),  // Using & and | for separation
+		forTokens(payload),               // FOR /F tokens obfuscation
+		doubleQuoteEvasion(rng, payload), // Double quote variations
+		parenthesisEvasion(payload),      // Parenthesis variations
+		randomCase(rng, payload),         // Random capitalization
+		caretInsertion(payload),          // ^ between the characters of each word
 	)
 
 	// Return basic variants if level is Basic
@@ -42,11 +47,13 @@ func WindowsCmdVariants(payload string, level types.EvasionLevel) []string {
 		setCommands(payload),            // SET command obfuscation
 		forCommands(payload),            // FOR command obfuscation
 		multiLevelQuoting(payload),      // Nested quoting
-		combinedEvasions(payload),       // Combining multiple techniques
+		combinedEvasions(rng, payload),  // Combining multiple techniques
 		callWrapping(payload),           // CALL command wrapping
-		cmdFlags(payload),               // constants.exe flags like /v:on /c
+		cmdFlags(rng, payload),          // constants.exe flags like /v:on /c
 		substitutionTechniques(payload), // Multiple substitution techniques
-		comSpecEvasion(payload),         // %COMSPEC% variations
+		comSpecEvasion(rng, payload),    // %COMSPEC% variations
+		emptyExpansion(payload),         // Empty variable substrings inside words
+		expansionSpaces(payload),        // Spaces cut out of %PROGRAMFILES%
 	)
 
 	// Return medium variants if level is Medium
@@ -56,17 +63,17 @@ func WindowsCmdVariants(payload string, level types.EvasionLevel) []string {
 
 	// Advanced level adds the most complex evasion techniques
 	variants = append(variants,
-		encodedCommands(payload),         // Encoded powershell commands
-		batCompression(payload),          // Batch compression techniques
-		multiStageExecution(payload),     // Multi-stage command execution
-		powerShellObfuscation(payload),   // PowerShell obfuscation techniques
-		regexBypass(payload),             // Regex bypass techniques
-		unicodeEvasion(payload),          // Unicode character evasions
-		tempFileExecution(payload),       // Temp file execution techniques
-		environmentMisdirection(payload), // Environment misdirection
-		charCodeEvasion(payload),         // Character code concatenation
-		batchFileAlternatives(payload),   // Alternative batch file techniques
-		advancedForLoops(payload),        // Advanced FOR loop techniques
+		encodedCommands(payload),              // Encoded powershell commands
+		batCompression(payload),               // Batch compression techniques
+		multiStageExecution(payload),          // Multi-stage command execution
+		powerShellObfuscation(payload),        // PowerShell obfuscation techniques
+		regexBypass(rng, payload),             // Regex bypass techniques
+		unicodeEvasion(rng, payload),          // Unicode character evasions
+		tempFileExecution(rng, payload),       // Temp file execution techniques
+		environmentMisdirection(rng, payload), // Environment misdirection
+		charCodeEvasion(payload),              // Character code concatenation
+		batchFileAlternatives(rng, payload),   // Alternative batch file techniques
+		advancedForLoops(rng, payload),        // Advanced FOR loop techniques
 	)
 
 	return evasions.UniqueStrings(variants)
@@ -87,14 +94,14 @@ func quoteEvasion(payload string) string {
 	return strings.Join(words, " ")
 }
 
-func randomQuoteEvasion(payload string) string {
+func randomQuoteEvasion(rng evasions.Rand, payload string) string {
 	words := strings.Fields(payload)
 	if len(words) < 2 {
 		return payload
 	}
 
 	for i := 1; i < len(words); i++ {
-		if rand.Intn(2) == 0 {
+		if rng.Intn(2) == 0 {
 			words[i] = "\"" + words[i] + "\""
 		}
 	}
@@ -114,10 +121,10 @@ func caretEvasion(payload string) string {
 	return result
 }
 
-func randomCaretEvasion(payload string) string {
+func randomCaretEvasion(rng evasions.Rand, payload string) string {
 	result := ""
 	for _, char := range payload {
-		if rand.Intn(3) == 0 && !strings.ContainsRune(" &|()<>^", char) {
+		if rng.Intn(3) == 0 && !strings.ContainsRune(" &|()<>^", char) {
 			result += string('^') + string(char)
 		} else {
 			result += string(char)
@@ -126,6 +133,46 @@ func randomCaretEvasion(payload string) string {
 	return result
 }
 
+// caretInsertion escapes every character of each word but its first, which
+// cmd strips before running it: w^h^o^a^m^i
+func caretInsertion(payload string) string {
+	words := strings.Fields(payload)
+	for i, word := range words {
+		runes := []rune(word)
+		var escaped strings.Builder
+		for j, char := range runes {
+			if j > 0 && !strings.ContainsRune("&|()<>^", char) {
+				escaped.WriteRune('^')
+			}
+			escaped.WriteRune(char)
+		}
+		words[i] = escaped.String()
+	}
+	return strings.Join(words, " ")
+}
+
+// emptyExpansion splits the command name with %COMSPEC:~0,0%, a zero-length
+// substring of a variable that is always set: who%COMSPEC:~0,0%ami
+func emptyExpansion(payload string) string {
+	words := strings.Fields(payload)
+	if len(words) == 0 {
+		return payload
+	}
+	runes := []rune(words[0])
+	if len(runes) < 2 {
+		return payload
+	}
+	half := len(runes) / 2
+	words[0] = string(runes[:half]) + "%COMSPEC:~0,0%" + string(runes[half:])
+	return strings.Join(words, " ")
+}
+
+// expansionSpaces replaces spaces with %PROGRAMFILES:~10,1%, the space in
+// "C:\Program Files": dir%PROGRAMFILES:~10,1%C:\
+func expansionSpaces(payload string) string {
+	return strings.Join(strings.Fields(payload), "%PROGRAMFILES:~10,1%")
+}
+
 func variableSubstitution(payload string) string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
@@ -139,13 +186,13 @@ func variableSubstitution(payload string) string {
 	return result
 }
 
-func commaEvasion(payload string) string {
+func commaEvasion(rng evasions.Rand, payload string) string {
 	// Replace some spaces with commas
 	words := strings.Fields(payload)
 	result := words[0]
 
 	for i := 1; i < len(words); i++ {
-		if rand.Intn(3) == 0 {
+		if rng.Intn(3) == 0 {
 			result += "," + words[i]
 		} else {
 			result += " " + words[i]
@@ -155,13 +202,13 @@ func commaEvasion(payload string) string {
 	return result
 }
 
-func spacingVariations(payload string) string {
+func spacingVariations(rng evasions.Rand, payload string) string {
 	words := strings.Fields(payload)
 	result := words[0]
 
 	for i := 1; i < len(words); i++ {
 		// Add random number of spaces
-		spaces := 1 + rand.Intn(3)
+		spaces := 1 + rng.Intn(3)
 		result += strings.Repeat(" ", spaces) + words[i]
 	}
 
@@ -177,7 +224,7 @@ func delayedExpansion(payload string) string {
 	return "setlocal enabledelayedexpansion && set v=" + parts[0] + " && !v! " + strings.Join(parts[1:], " ")
 }
 
-func envVarObfuscation(payload string) string {
+func envVarObfuscation(rng evasions.Rand, payload string) string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
 		return payload
@@ -185,20 +232,20 @@ func envVarObfuscation(payload string) string {
 
 	// Choose a random common env var
 	envVars := []string{"%TEMP%\\", "%WINDIR%\\", "%SYSTEMROOT%\\"}
-	prefix := envVars[rand.Intn(len(envVars))]
+	prefix := envVars[rng.Intn(len(envVars))]
 
 	return prefix + parts[0] + " " + strings.Join(parts[1:], " ")
 }
 
-func commandSeparators(payload string) string {
+func commandSeparators(rng evasions.Rand, payload string) string {
 	separators := []string{" & ", " && ", " | ", " || "}
-	sep := separators[rand.Intn(len(separators))]
+	sep := separators[rng.Intn(len(separators))]
 
 	// Add a harmless command
 	harmlessCommands := []string{"echo.", "ver", "dir", "type nul", "cls"}
-	harmless := harmlessCommands[rand.Intn(len(harmlessCommands))]
+	harmless := harmlessCommands[rng.Intn(len(harmlessCommands))]
 
-	if rand.Intn(2) == 0 {
+	if rng.Intn(2) == 0 {
 		return harmless + sep + payload
 	} else {
 		return payload + sep + harmless
@@ -217,11 +264,11 @@ func forTokens(payload string) string {
 	return fmt.Sprintf("for /F \"tokens=*\" %%a in ('%s') do %%a %s", cmd, args)
 }
 
-func doubleQuoteEvasion(payload string) string {
+func doubleQuoteEvasion(rng evasions.Rand, payload string) string {
 	// Replace characters with quoted versions
 	re := regexp.MustCompile(`([a-zA-Z0-9])`)
 	result := re.ReplaceAllStringFunc(payload, func(s string) string {
-		if rand.Intn(4) == 0 {
+		if rng.Intn(4) == 0 {
 			return "\"" + s + "\""
 		}
 		return s
@@ -239,12 +286,12 @@ func parenthesisEvasion(payload string) string {
 	return "(" + parts[0] + ")" + " " + strings.Join(parts[1:], " ")
 }
 
-func randomCase(payload string) string {
+func randomCase(rng evasions.Rand, payload string) string {
 	result := ""
 	for _, char := range payload {
-		if rand.Intn(2) == 0 && (char >= 'a' && char <= 'z') {
+		if rng.Intn(2) == 0 && (char >= 'a' && char <= 'z') {
 			result += strings.ToUpper(string(char))
-		} else if rand.Intn(2) == 0 && (char >= 'A' && char <= 'Z') {
+		} else if rng.Intn(2) == 0 && (char >= 'A' && char <= 'Z') {
 			result += strings.ToLower(string(char))
 		} else {
 			result += string(char)
@@ -293,11 +340,11 @@ func multiLevelQuoting(payload string) string {
 	return "constants.exe /V:ON /C \"set cmd=\"" + payload + "\" && !cmd!\""
 }
 
-func combinedEvasions(payload string) string {
+func combinedEvasions(rng evasions.Rand, payload string) string {
 	// Apply multiple techniques at once
 	result := caretEvasion(payload)
-	result = randomCase(result)
-	result = commandSeparators(result)
+	result = randomCase(rng, result)
+	result = commandSeparators(rng, result)
 	return result
 }
 
@@ -305,9 +352,9 @@ func callWrapping(payload string) string {
 	return "call " + payload
 }
 
-func cmdFlags(payload string) string {
+func cmdFlags(rng evasions.Rand, payload string) string {
 	flags := []string{"/c", "/v:on /c", "/r /c", "/v:on /r /c", "/q /c"}
-	flag := flags[rand.Intn(len(flags))]
+	flag := flags[rng.Intn(len(flags))]
 
 	return "constants.exe " + flag + " " + quoteEvasion(payload)
 }
@@ -349,14 +396,14 @@ func substitutionTechniques(payload string) string {
 	return finalCmd + " && %command%" + args
 }
 
-func comSpecEvasion(payload string) string {
+func comSpecEvasion(rng evasions.Rand, payload string) string {
 	comspecVariations := []string{
 		"%COMSPEC%",
 		"%SYSTEMROOT%\\system32\\constants.exe",
 		"%WINDIR%\\system32\\constants.exe",
 	}
 
-	comspec := comspecVariations[rand.Intn(len(comspecVariations))]
+	comspec := comspecVariations[rng.Intn(len(comspecVariations))]
 	return comspec + " /c " + payload
 }
 
@@ -393,11 +440,11 @@ func powerShellObfuscation(payload string) string {
 	return "powershell -nop -c \"&([scriptblock]::Create('" + encodedPayload + "'))\""
 }
 
-func regexBypass(payload string) string {
+func regexBypass(rng evasions.Rand, payload string) string {
 	// Insert regex-breaking characters
 	re := regexp.MustCompile(`([a-zA-Z0-9_])`)
 	result := re.ReplaceAllStringFunc(payload, func(s string) string {
-		if rand.Intn(5) == 0 {
+		if rng.Intn(5) == 0 {
 			return "[" + s + "]"
 		}
 		return s
@@ -406,11 +453,11 @@ func regexBypass(payload string) string {
 	return result
 }
 
-func unicodeEvasion(payload string) string {
+func unicodeEvasion(rng evasions.Rand, payload string) string {
 	// Use Unicode escape sequences in batch
 	result := ""
 	for _, c := range payload {
-		if rand.Intn(3) == 0 && c > 32 && c < 127 {
+		if rng.Intn(3) == 0 && c > 32 && c < 127 {
 			result += fmt.Sprintf("%%u%04x", c)
 		} else {
 			result += string(c)
@@ -420,13 +467,13 @@ func unicodeEvasion(payload string) string {
 	return result
 }
 
-func tempFileExecution(payload string) string {
+func tempFileExecution(rng evasions.Rand, payload string) string {
 	// Create a technique that simulates writing to a temp file
-	tempFile := "%TEMP%\\x" + fmt.Sprintf("%d", rand.Intn(10000)) + ".bat"
+	tempFile := "%TEMP%\\x" + fmt.Sprintf("%d", rng.Intn(10000)) + ".bat"
 	return fmt.Sprintf("(echo %s)>%s && call %s", payload, tempFile, tempFile)
 }
 
-func environmentMisdirection(payload string) string {
+func environmentMisdirection(rng evasions.Rand, payload string) string {
 	// Use environment variables to confuse WAF
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
@@ -441,7 +488,7 @@ func environmentMisdirection(payload string) string {
 
 	// Create a complex chain of environment variables
 	result := "set x=%"
-	result += envVars[rand.Intn(len(envVars))]
+	result += envVars[rng.Intn(len(envVars))]
 	result += "% && set y=" + cmd + " && call %y%"
 
 	if len(parts) > 1 {
@@ -485,7 +532,7 @@ func charCodeEvasion(payload string) string {
 	return result + " && %cmd%" + args
 }
 
-func batchFileAlternatives(payload string) string {
+func batchFileAlternatives(rng evasions.Rand, payload string) string {
 	alternatives := []string{
 		"constants.exe /k " + payload + " & exit",
 		"constants.exe /c start /b " + payload,
@@ -493,10 +540,10 @@ func batchFileAlternatives(payload string) string {
 		"wmic process call create \"" + payload + "\"",
 	}
 
-	return alternatives[rand.Intn(len(alternatives))]
+	return alternatives[rng.Intn(len(alternatives))]
 }
 
-func advancedForLoops(payload string) string {
+func advancedForLoops(rng evasions.Rand, payload string) string {
 	parts := strings.Fields(payload)
 	if len(parts) == 0 {
 		return payload
@@ -515,5 +562,5 @@ func advancedForLoops(payload string) string {
 		fmt.Sprintf("for /f \"usebackq tokens=*\" %%a in (`echo %s`) do %%a %s", cmd, args),
 	}
 
-	return loopVariants[rand.Intn(len(loopVariants))]
+	return loopVariants[rng.Intn(len(loopVariants))]
 }
@@ -0,0 +1,24 @@
+package command
+
+import (
+	"slices"
+	"testing"
+
+	"obfuskit/types"
+)
+
+func TestWindowsCmdVariantsInsertCarets(t *testing.T) {
+	variants := WindowsCmdVariants("whoami", types.EvasionLevelMedium)
+	for _, want := range []string{
+		"w^h^o^a^m^i",
+		"who%COMSPEC:~0,0%ami",
+	} {
+		if !slices.Contains(variants, want) {
+			t.Errorf("variants are missing %q", want)
+		}
+	}
+
+	if got := expansionSpaces("dir C:\\"); got != `dir%PROGRAMFILES:~10,1%C:\` {
+		t.Errorf("expansionSpaces() = %q", got)
+	}
+}
@@ -1,11 +1,21 @@
 package encoders
 
 import (
+	"fmt"
 	"net/url"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
+	"regexp"
+	"strings"
 )
 
+// dangerousURLChars are the characters a WAF typically looks for in an XSS or
+// SQL injection payload
+const dangerousURLChars = `<>'"()`
+
+// percentEncoded matches the %XX sequence of a URL encoded byte
+var percentEncoded = regexp.MustCompile(`%[0-9A-Fa-f]{2}`)
+
 // DoubleURLVariants generates double URL encoded variants of the input payload
 // based on the specified obfuscation level
 func DoubleURLVariants(payload string, level types.EvasionLevel) []string {
@@ -33,6 +43,23 @@ func DoubleURLVariants(payload string, level types.EvasionLevel) []string {
 	doubleStandard := url.QueryEscape(standard)
 	variants = append(variants, doubleStandard)
 
+	// The payload may come already encoded: encoding it once more is its
+	// double encoding, and the selective variants work on what it decodes to
+	// Only %XX sequences mark it encoded: a raw "1+1" or "--+" is not
+	raw := payload
+	if percentEncoded.MatchString(payload) {
+		if decoded, err := url.PathUnescape(payload); err == nil {
+			raw = decoded
+			variants = append(variants, standard)
+		}
+	}
+
+	// Only the dangerous characters double encoded, the rest literal, for
+	// WAFs that normalize once and match on the decoded payload
+	if strings.ContainsAny(raw, dangerousURLChars) {
+		variants = append(variants, doubleEncodeDangerous(raw, false))
+	}
+
 	// Return basic variants if level is Basic
 	if level == types.EvasionLevelBasic {
 		return evasions.UniqueStrings(variants)
@@ -48,6 +75,11 @@ func DoubleURLVariants(payload string, level types.EvasionLevel) []string {
 	querySecond := url.QueryEscape(pathFirst)
 	variants = append(variants, querySecond)
 
+	// Dangerous characters alternating between single and double encoding
+	if strings.ContainsAny(raw, dangerousURLChars) {
+		variants = append(variants, doubleEncodeDangerous(raw, true))
+	}
+
 	// Return medium variants if level is Medium
 	if level == types.EvasionLevelMedium {
 		return evasions.UniqueStrings(variants)
@@ -67,3 +99,27 @@ func DoubleURLVariants(payload string, level types.EvasionLevel) []string {
 
 	return evasions.UniqueStrings(variants)
 }
+
+// doubleEncodeDangerous double encodes the dangerous characters of s and
+// leaves the rest literal. With mixed, every other dangerous character is
+// only single encoded.
+func doubleEncodeDangerous(s string, mixed bool) string {
+	var result strings.Builder
+	double := true
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if strings.IndexByte(dangerousURLChars, c) < 0 {
+			result.WriteByte(c)
+			continue
+		}
+		if double {
+			result.WriteString(fmt.Sprintf("%%25%02X", c))
+		} else {
+			result.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+		if mixed {
+			double = !double
+		}
+	}
+	return result.String()
+}
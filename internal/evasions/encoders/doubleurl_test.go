@@ -84,6 +84,56 @@ func TestDoubleURLVariants(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "Basic level double encodes only the dangerous characters",
+			payload:  "<img src=x onerror=alert('1')>",
+			level:    types.EvasionLevelBasic,
+			minCount: 2,
+			checks: []func([]string) bool{
+				func(variants []string) bool {
+					return containsVariant(variants, "%253Cimg src=x onerror=alert%2528%25271%2527%2529%253E")
+				},
+			},
+		},
+		{
+			name:     "Medium level mixes single and double encoding",
+			payload:  "<b>",
+			level:    types.EvasionLevelMedium,
+			minCount: 3,
+			checks: []func([]string) bool{
+				func(variants []string) bool {
+					return containsVariant(variants, "%253Cb%3E")
+				},
+			},
+		},
+		{
+			name:     "Already encoded payload",
+			payload:  "%3Cscript%3E",
+			level:    types.EvasionLevelBasic,
+			minCount: 2,
+			checks: []func([]string) bool{
+				func(variants []string) bool {
+					// Encoding it once more is its double encoding
+					return containsVariant(variants, "%253Cscript%253E")
+				},
+			},
+		},
+		{
+			name:     "Raw payload with a plus",
+			payload:  "' OR 1=1--+",
+			level:    types.EvasionLevelBasic,
+			minCount: 2,
+			checks: []func([]string) bool{
+				func(variants []string) bool {
+					// A "+" is not encoding: the payload stays as written
+					return containsVariant(variants, "%2527 OR 1=1--+")
+				},
+				func(variants []string) bool {
+					// and is not sent single encoded as if that doubled it
+					return !containsVariant(variants, url.QueryEscape("' OR 1=1--+"))
+				},
+			},
+		},
 		{
 			name:     "Complex XSS payload",
 			payload:  "<script>alert('xss')</script>",
@@ -2,7 +2,6 @@ package encoders
 
 import (
 	"fmt"
-	"math/rand"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
 	"regexp"
@@ -12,6 +11,11 @@ import (
 // HexVariants generates various hex encoded variants of the input payload
 // based on the specified obfuscation level
 func HexVariants(payload string, level types.EvasionLevel) []string {
+	return HexVariantsWithRand(evasions.DefaultRand, payload, level)
+}
+
+// HexVariantsWithRand is HexVariants drawing randomness from rng
+func HexVariantsWithRand(rng evasions.Rand, payload string, level types.EvasionLevel) []string {
 	var variants []string
 
 	// Basic hex encodings
@@ -58,25 +62,25 @@ func HexVariants(payload string, level types.EvasionLevel) []string {
 
 	// Advanced level adds null bytes and control characters
 	variants = append(variants,
-		appendRandomly(payload, "00"), // Null character
+		appendRandomly(rng, payload, "00"), // Null character
 		// Control characters for bypassing filters
-		appendRandomly(payload, "01"), // Start of Heading
-		appendRandomly(payload, "02"), // Start of Text
-		appendRandomly(payload, "03"), // End of Text
-		appendRandomly(payload, "04"), // End of Transmission
-		appendRandomly(payload, "05"), // Enquiry
-		appendRandomly(payload, "06"), // Acknowledge
-		appendRandomly(payload, "07"), // Bell
-		appendRandomly(payload, "08"), // Backspace
-		appendRandomly(payload, "0A"), // Line feed
-		appendRandomly(payload, "0B"), // Vertical tab
-		appendRandomly(payload, "0C"), // Form feed
-		appendRandomly(payload, "0D"), // Carriage return
-		appendRandomly(payload, "0E"), // Shift Out
-		appendRandomly(payload, "0F"), // Shift In
+		appendRandomly(rng, payload, "01"), // Start of Heading
+		appendRandomly(rng, payload, "02"), // Start of Text
+		appendRandomly(rng, payload, "03"), // End of Text
+		appendRandomly(rng, payload, "04"), // End of Transmission
+		appendRandomly(rng, payload, "05"), // Enquiry
+		appendRandomly(rng, payload, "06"), // Acknowledge
+		appendRandomly(rng, payload, "07"), // Bell
+		appendRandomly(rng, payload, "08"), // Backspace
+		appendRandomly(rng, payload, "0A"), // Line feed
+		appendRandomly(rng, payload, "0B"), // Vertical tab
+		appendRandomly(rng, payload, "0C"), // Form feed
+		appendRandomly(rng, payload, "0D"), // Carriage return
+		appendRandomly(rng, payload, "0E"), // Shift Out
+		appendRandomly(rng, payload, "0F"), // Shift In
 
 		// Complex insertion between words
-		appendRandomlyInBetweenWords(payload, "00"),
+		appendRandomlyInBetweenWords(rng, payload, "00"),
 	)
 
 	// Advanced WAF evasion techniques - splitting hex values
@@ -90,7 +94,7 @@ func HexVariants(payload string, level types.EvasionLevel) []string {
 
 // appendRandomlyInBetweenWords splits the payload into words and non-words,
 // then for each word it performs 1–3 random insertions of the escape sequence.
-func appendRandomlyInBetweenWords(payload, hexBytes string) string {
+func appendRandomlyInBetweenWords(rng evasions.Rand, payload, hexBytes string) string {
 	// Prepare the insertion string
 	insert := `\x` + hexBytes
 	if hexBytes == `\b` {
@@ -108,10 +112,10 @@ func appendRandomlyInBetweenWords(payload, hexBytes string) string {
 	for i, tok := range tokens {
 		if wordRe.MatchString(tok) {
 			// Decide how many times to insert into this word
-			times := rand.Intn(3) + 1
+			times := rng.Intn(3) + 1
 			for j := 0; j < times; j++ {
 				// Pick a random insertion point within the word
-				pos := rand.Intn(len(tok) + 1)
+				pos := rng.Intn(len(tok) + 1)
 				tok = tok[:pos] + insert + tok[pos:]
 			}
 			tokens[i] = tok
@@ -121,13 +125,13 @@ func appendRandomlyInBetweenWords(payload, hexBytes string) string {
 	return strings.Join(tokens, "")
 }
 
-func appendRandomly(payload string, hexBytes string) string {
+func appendRandomly(rng evasions.Rand, payload string, hexBytes string) string {
 	// Find a random index and insert the string
-	var b strings.Builder
-	b.WriteString(payload[:rand.Intn(len(payload))])
+	b := newBuffer()
+	b.WriteString(payload[:rng.Intn(len(payload))])
 	b.WriteString("\\x" + hexBytes)
-	b.WriteString(payload[rand.Intn(len(payload)):])
-	return b.String()
+	b.WriteString(payload[rng.Intn(len(payload)):])
+	return releaseBuffer(b)
 }
 
 func appendHexLiteral(payload, hexByte string) string {
@@ -135,31 +139,31 @@ func appendHexLiteral(payload, hexByte string) string {
 }
 
 func toHex(s string, upper bool) string {
-	var b strings.Builder
+	b := newBuffer()
 	for _, c := range []byte(s) {
 		if upper {
-			b.WriteString(fmt.Sprintf("%02X", c))
+			fmt.Fprintf(b, "%02X", c)
 		} else {
-			b.WriteString(fmt.Sprintf("%02x", c))
+			fmt.Fprintf(b, "%02x", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func addPrefix(hex string) string {
-	var out strings.Builder
+	out := newBuffer()
 	for i := 0; i < len(hex); i += 2 {
 		out.WriteString("\\x" + hex[i:i+2])
 	}
-	return out.String()
+	return releaseBuffer(out)
 }
 
 func percentEncode(hex string) string {
-	var out strings.Builder
+	out := newBuffer()
 	for i := 0; i < len(hex); i += 2 {
 		out.WriteString("%" + hex[i:i+2])
 	}
-	return out.String()
+	return releaseBuffer(out)
 }
 
 func jsConcatStyle(hex string) string {
@@ -176,11 +180,11 @@ func splitHex(encoded, prefix string) string {
 }
 
 func addCurlyPrefix(hex string) string {
-	var out strings.Builder
+	out := newBuffer()
 	for i := 0; i < len(hex); i += 2 {
 		out.WriteString("\\x{" + hex[i:i+2] + "}")
 	}
-	return out.String()
+	return releaseBuffer(out)
 }
 
 func splitCurlyHex(hexCurly string) string {
@@ -2,13 +2,17 @@ package encoders
 
 import (
 	"fmt"
-	"math/rand"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
 	"strings"
 )
 
 func HTMLVariants(payload string, level types.EvasionLevel) []string {
+	return HTMLVariantsWithRand(evasions.DefaultRand, payload, level)
+}
+
+// HTMLVariantsWithRand is HTMLVariants drawing randomness from rng
+func HTMLVariantsWithRand(rng evasions.Rand, payload string, level types.EvasionLevel) []string {
 	var variants []string
 
 	htmlDecimalEntities := toHTMLDecimalEntities(payload)
@@ -30,14 +34,14 @@ func HTMLVariants(payload string, level types.EvasionLevel) []string {
 	}
 
 	variants = append(variants,
-		partialHTMLEncoding(payload),       // Only encode special chars
-		mixedCaseEntities(payload),         // Mixed case in hex entities (&#x6A;&#X6b;)
-		entityWithoutSemicolon(payload),    // Some entities without semicolons
-		unnecessaryLeadingZeros(payload),   // Extra zeros (&#00104;&#x00068;)
-		jsInHtmlContext(payload),           // JavaScript syntax in HTML context
-		entitiesWithComments(payload),      // Entities with HTML comments
-		doubleEncodedEntities(payload),     // Double-encoded entities
-		attributeEncodingVariants(payload), // Attribute encoding variants
+		partialHTMLEncoding(rng, payload),       // Only encode special chars
+		mixedCaseEntities(payload),              // Mixed case in hex entities (&#x6A;&#X6b;)
+		entityWithoutSemicolon(payload),         // Some entities without semicolons
+		unnecessaryLeadingZeros(rng, payload),   // Extra zeros (&#00104;&#x00068;)
+		jsInHtmlContext(rng, payload),           // JavaScript syntax in HTML context
+		entitiesWithComments(rng, payload),      // Entities with HTML comments
+		doubleEncodedEntities(payload),          // Double-encoded entities
+		attributeEncodingVariants(rng, payload), // Attribute encoding variants
 	)
 
 	if level == types.EvasionLevelMedium {
@@ -45,43 +49,43 @@ func HTMLVariants(payload string, level types.EvasionLevel) []string {
 	}
 
 	variants = append(variants,
-		multipleEncodingLayers(payload),    // Multiple encoding layers
-		cssEscapeSequences(payload),        // CSS escape sequences
-		urlEncodedEntities(payload),        // URL-encoded entities
-		invalidEntityPadding(payload),      // Invalid padding in entities
-		caseNormalizationTrick(payload),    // Case normalization tricks
-		entityFragmentation(payload),       // Entity fragmentation with whitespace
-		encodingWithBase(payload),          // Using different bases (decimal, octal, hex)
-		nonStandardEntityFormats(payload),  // Non-standard entity formats
-		conditionalCommentsBypass(payload), // Conditional comments bypass
-		dataAttributeObfuscation(payload),  // Data attribute obfuscation
-		svgContentEncoding(payload),        // SVG content encoding
-		templateOverrideEncoding(payload),  // Template override encoding
-		javascriptEscapeSequences(payload), // JavaScript escape sequences in HTML
-		encodingWithCharacterSets(payload), // Character set tricks
+		multipleEncodingLayers(payload),         // Multiple encoding layers
+		cssEscapeSequences(payload),             // CSS escape sequences
+		urlEncodedEntities(payload),             // URL-encoded entities
+		invalidEntityPadding(rng, payload),      // Invalid padding in entities
+		caseNormalizationTrick(payload),         // Case normalization tricks
+		entityFragmentation(payload),            // Entity fragmentation with whitespace
+		encodingWithBase(payload),               // Using different bases (decimal, octal, hex)
+		nonStandardEntityFormats(payload),       // Non-standard entity formats
+		conditionalCommentsBypass(payload),      // Conditional comments bypass
+		dataAttributeObfuscation(payload),       // Data attribute obfuscation
+		svgContentEncoding(payload),             // SVG content encoding
+		templateOverrideEncoding(payload),       // Template override encoding
+		javascriptEscapeSequences(rng, payload), // JavaScript escape sequences in HTML
+		encodingWithCharacterSets(payload),      // Character set tricks
 	)
 
 	return evasions.UniqueStrings(variants)
 }
 
 func toHTMLDecimalEntities(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 	for _, c := range []byte(s) {
-		b.WriteString(fmt.Sprintf("&#%d;", c))
+		fmt.Fprintf(b, "&#%d;", c)
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func toHTMLHexEntities(s string, uppercase bool) string {
-	var b strings.Builder
+	b := newBuffer()
 	for _, c := range []byte(s) {
 		if uppercase {
-			b.WriteString(fmt.Sprintf("&#X%X;", c))
+			fmt.Fprintf(b, "&#X%X;", c)
 		} else {
-			b.WriteString(fmt.Sprintf("&#x%x;", c))
+			fmt.Fprintf(b, "&#x%x;", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func toNamedEntities(s string) string {
@@ -99,7 +103,7 @@ func toNamedEntities(s string) string {
 		'®': "&reg;",
 	}
 
-	var b strings.Builder
+	b := newBuffer()
 	for _, c := range []byte(s) {
 		if entity, ok := entities[c]; ok {
 			b.WriteString(entity)
@@ -107,7 +111,7 @@ func toNamedEntities(s string) string {
 			b.WriteByte(c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func mixedEntities(s string) string {
@@ -119,111 +123,111 @@ func mixedEntities(s string) string {
 		'&':  "&amp;",
 	}
 
-	var b strings.Builder
+	b := newBuffer()
 	for i, c := range []byte(s) {
 		if entity, ok := entities[c]; ok {
 			b.WriteString(entity)
 		} else {
 			if i%3 == 0 {
-				b.WriteString(fmt.Sprintf("&#%d;", c))
+				fmt.Fprintf(b, "&#%d;", c)
 			} else if i%3 == 1 {
-				b.WriteString(fmt.Sprintf("&#x%x;", c))
+				fmt.Fprintf(b, "&#x%x;", c)
 			} else {
 				b.WriteByte(c)
 			}
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
-func partialHTMLEncoding(s string) string {
+func partialHTMLEncoding(rng evasions.Rand, s string) string {
 	toEncode := map[byte]bool{
 		'<': true, '>': true, '&': true, '"': true, '\'': true,
 		';': true, '(': true, ')': true, '{': true, '}': true,
 	}
 
-	var b strings.Builder
+	b := newBuffer()
 	for _, c := range []byte(s) {
-		if toEncode[c] || rand.Intn(3) == 0 {
-			if rand.Intn(2) == 0 {
-				b.WriteString(fmt.Sprintf("&#%d;", c))
+		if toEncode[c] || rng.Intn(3) == 0 {
+			if rng.Intn(2) == 0 {
+				fmt.Fprintf(b, "&#%d;", c)
 			} else {
-				b.WriteString(fmt.Sprintf("&#x%x;", c))
+				fmt.Fprintf(b, "&#x%x;", c)
 			}
 		} else {
 			b.WriteByte(c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func mixedCaseEntities(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 	for i, c := range []byte(s) {
 		if i%2 == 0 {
-			b.WriteString(fmt.Sprintf("&#x%x;", c))
+			fmt.Fprintf(b, "&#x%x;", c)
 		} else {
-			b.WriteString(fmt.Sprintf("&#X%X;", c))
+			fmt.Fprintf(b, "&#X%X;", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func entityWithoutSemicolon(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 	for i, c := range []byte(s) {
 		if i%3 == 0 {
-			b.WriteString(fmt.Sprintf("&#%d", c))
+			fmt.Fprintf(b, "&#%d", c)
 		} else {
-			b.WriteString(fmt.Sprintf("&#%d;", c))
+			fmt.Fprintf(b, "&#%d;", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
-func unnecessaryLeadingZeros(s string) string {
-	var b strings.Builder
+func unnecessaryLeadingZeros(rng evasions.Rand, s string) string {
+	b := newBuffer()
 	for i, c := range []byte(s) {
 		if i%2 == 0 {
-			zeros := rand.Intn(4) + 2
-			b.WriteString(fmt.Sprintf("&#%0*d;", zeros+len(fmt.Sprintf("%d", c)), c))
+			zeros := rng.Intn(4) + 2
+			fmt.Fprintf(b, "&#%0*d;", zeros+len(fmt.Sprintf("%d", c)), c)
 		} else {
-			zeros := rand.Intn(4) + 2
-			b.WriteString(fmt.Sprintf("&#x%0*x;", zeros+len(fmt.Sprintf("%x", c)), c))
+			zeros := rng.Intn(4) + 2
+			fmt.Fprintf(b, "&#x%0*x;", zeros+len(fmt.Sprintf("%x", c)), c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
-func jsInHtmlContext(s string) string {
-	var b strings.Builder
+func jsInHtmlContext(rng evasions.Rand, s string) string {
+	b := newBuffer()
 	b.WriteString("<script>document.write('")
 
 	for _, c := range []byte(s) {
-		switch rand.Intn(3) {
+		switch rng.Intn(3) {
 		case 0:
-			b.WriteString(fmt.Sprintf("\\x%02x", c))
+			fmt.Fprintf(b, "\\x%02x", c)
 		case 1:
-			b.WriteString(fmt.Sprintf("\\u00%02x", c))
+			fmt.Fprintf(b, "\\u00%02x", c)
 		case 2:
 			b.WriteByte(c)
 		}
 	}
 
 	b.WriteString("');</script>")
-	return b.String()
+	return releaseBuffer(b)
 }
 
-func entitiesWithComments(s string) string {
-	var b strings.Builder
+func entitiesWithComments(rng evasions.Rand, s string) string {
+	b := newBuffer()
 	for _, c := range []byte(s) {
 		b.WriteString("&#")
-		if rand.Intn(2) == 0 {
+		if rng.Intn(2) == 0 {
 			b.WriteString("<!---->")
 		}
-		b.WriteString(fmt.Sprintf("%d;", c))
+		fmt.Fprintf(b, "%d;", c)
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func doubleEncodedEntities(s string) string {
@@ -231,24 +235,24 @@ func doubleEncodedEntities(s string) string {
 	firstPass := toHTMLHexEntities(s, false)
 
 	// Then encode again
-	var b strings.Builder
+	b := newBuffer()
 	for _, c := range []byte(firstPass) {
-		b.WriteString(fmt.Sprintf("&#%d;", c))
+		fmt.Fprintf(b, "&#%d;", c)
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
-func attributeEncodingVariants(s string) string {
-	var b strings.Builder
+func attributeEncodingVariants(rng evasions.Rand, s string) string {
+	b := newBuffer()
 	b.WriteString("<div title=\"")
 
 	for _, c := range []byte(s) {
-		switch rand.Intn(3) {
+		switch rng.Intn(3) {
 		case 0:
-			b.WriteString(fmt.Sprintf("&#%d;", c))
+			fmt.Fprintf(b, "&#%d;", c)
 		case 1:
-			b.WriteString(fmt.Sprintf("&#x%x;", c))
+			fmt.Fprintf(b, "&#x%x;", c)
 		case 2:
 			if c == '"' {
 				b.WriteString("&quot;")
@@ -259,7 +263,7 @@ func attributeEncodingVariants(s string) string {
 	}
 
 	b.WriteString("\"></div>")
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func multipleEncodingLayers(s string) string {
@@ -273,47 +277,47 @@ func multipleEncodingLayers(s string) string {
 }
 
 func cssEscapeSequences(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 	b.WriteString("<style>content:'")
 
 	for _, c := range []byte(s) {
-		b.WriteString(fmt.Sprintf("\\%x ", c))
+		fmt.Fprintf(b, "\\%x ", c)
 	}
 
 	b.WriteString("';</style>")
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func urlEncodedEntities(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	for _, c := range []byte(s) {
 		b.WriteString("%26%23")
-		b.WriteString(fmt.Sprintf("%d;", c))
+		fmt.Fprintf(b, "%d;", c)
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
-func invalidEntityPadding(s string) string {
-	var b strings.Builder
+func invalidEntityPadding(rng evasions.Rand, s string) string {
+	b := newBuffer()
 
 	for _, c := range []byte(s) {
-		switch rand.Intn(3) {
+		switch rng.Intn(3) {
 		case 0:
-			b.WriteString(fmt.Sprintf("&#\u200B%d;", c))
+			fmt.Fprintf(b, "&#\u200B%d;", c)
 		case 1:
-			b.WriteString(fmt.Sprintf("&# %d;", c))
+			fmt.Fprintf(b, "&# %d;", c)
 		case 2:
-			b.WriteString(fmt.Sprintf("&#\t%d;", c))
+			fmt.Fprintf(b, "&#\t%d;", c)
 		}
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func caseNormalizationTrick(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	specialChars := map[byte]string{
 		'<': "&LT;",
@@ -329,39 +333,39 @@ func caseNormalizationTrick(s string) string {
 		}
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func entityFragmentation(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	for _, c := range []byte(s) {
-		b.WriteString(fmt.Sprintf("&#%d\r;", c))
+		fmt.Fprintf(b, "&#%d\r;", c)
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func encodingWithBase(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	for i, c := range []byte(s) {
 		switch i % 3 {
 		case 0:
 			// Decimal
-			b.WriteString(fmt.Sprintf("&#%d;", c))
+			fmt.Fprintf(b, "&#%d;", c)
 		case 1:
 			// Octal (as decimal)
 			octal := fmt.Sprintf("%o", c)
 			decimal, _ := parseInt(octal, 8)
-			b.WriteString(fmt.Sprintf("&#%d;", decimal))
+			fmt.Fprintf(b, "&#%d;", decimal)
 		case 2:
 			// Hexadecimal
-			b.WriteString(fmt.Sprintf("&#x%x;", c))
+			fmt.Fprintf(b, "&#x%x;", c)
 		}
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func parseInt(s string, base int) (int, error) {
@@ -387,38 +391,38 @@ func parseInt(s string, base int) (int, error) {
 
 // nonStandardEntityFormats creates non-standard entity formats
 func nonStandardEntityFormats(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	for i, c := range []byte(s) {
 		switch i % 4 {
 		case 0:
-			b.WriteString(fmt.Sprintf("&#x%X;", c))
+			fmt.Fprintf(b, "&#x%X;", c)
 		case 1:
-			b.WriteString(fmt.Sprintf("&#x%x;", c))
+			fmt.Fprintf(b, "&#x%x;", c)
 		case 2:
-			b.WriteString(fmt.Sprintf(";&&#%d;", c))
+			fmt.Fprintf(b, ";&&#%d;", c)
 		case 3:
 			if c > 127 || c < 32 {
-				b.WriteString(fmt.Sprintf("&#%d;", c))
+				fmt.Fprintf(b, "&#%d;", c)
 			} else {
 				b.WriteByte(c)
 			}
 		}
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func conditionalCommentsBypass(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	b.WriteString("<!--[if gte IE 4]>\n")
 	for _, c := range []byte(s) {
-		b.WriteString(fmt.Sprintf("&#%d;", c))
+		fmt.Fprintf(b, "&#%d;", c)
 	}
 
 	b.WriteString("\n<![endif]-->")
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func dataAttributeObfuscation(s string) string {
@@ -432,17 +436,17 @@ func dataAttributeObfuscation(s string) string {
 }
 
 func svgContentEncoding(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	b.WriteString("<svg><script type=\"text/javascript\"><![CDATA[\n")
 	b.WriteString("document.write('")
 
 	for _, c := range []byte(s) {
-		b.WriteString(fmt.Sprintf("&#%d;", c))
+		fmt.Fprintf(b, "&#%d;", c)
 	}
 
 	b.WriteString("');\n]]></script></svg>")
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func templateOverrideEncoding(s string) string {
@@ -455,41 +459,41 @@ func templateOverrideEncoding(s string) string {
 	return strings.Join(parts, "")
 }
 
-func javascriptEscapeSequences(s string) string {
-	var b strings.Builder
+func javascriptEscapeSequences(rng evasions.Rand, s string) string {
+	b := newBuffer()
 
 	b.WriteString("<script>var x = '")
 
 	for _, c := range []byte(s) {
-		switch rand.Intn(4) {
+		switch rng.Intn(4) {
 		case 0:
-			b.WriteString(fmt.Sprintf("\\x%02x", c))
+			fmt.Fprintf(b, "\\x%02x", c)
 		case 1:
-			b.WriteString(fmt.Sprintf("\\u00%02x", c))
+			fmt.Fprintf(b, "\\u00%02x", c)
 		case 2:
-			b.WriteString(fmt.Sprintf("\\%o", c))
+			fmt.Fprintf(b, "\\%o", c)
 		case 3:
 			if c >= 32 && c <= 126 && c != '\'' && c != '\\' {
 				b.WriteByte(c)
 			} else {
-				b.WriteString(fmt.Sprintf("\\x%02x", c))
+				fmt.Fprintf(b, "\\x%02x", c)
 			}
 		}
 	}
 
 	b.WriteString("';</script>")
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func encodingWithCharacterSets(s string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	b.WriteString("<meta charset=\"utf-7\"><div>")
 
 	for _, c := range []byte(s) {
-		b.WriteString(fmt.Sprintf("+%c-", c))
+		fmt.Fprintf(b, "+%c-", c)
 	}
 
 	b.WriteString("</div>")
-	return b.String()
+	return releaseBuffer(b)
 }
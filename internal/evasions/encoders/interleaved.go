@@ -0,0 +1,128 @@
+package encoders
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"unicode/utf8"
+
+	"obfuskit/internal/evasions"
+	"obfuskit/types"
+)
+
+// interleaveScheme encodes one character; max is the largest rune it can
+// represent in a single escape
+type interleaveScheme struct {
+	encode func(r rune) string
+	max    rune
+}
+
+var (
+	schemeURL = interleaveScheme{func(r rune) string {
+		var b strings.Builder
+		buf := make([]byte, utf8.UTFMax)
+		for _, c := range buf[:utf8.EncodeRune(buf, r)] {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+		return b.String()
+	}, utf8.MaxRune}
+	schemeHTMLHex    = interleaveScheme{func(r rune) string { return fmt.Sprintf("&#x%x;", r) }, utf8.MaxRune}
+	schemeHTMLDec    = interleaveScheme{func(r rune) string { return fmt.Sprintf("&#%d;", r) }, utf8.MaxRune}
+	schemeUnicode    = interleaveScheme{func(r rune) string { return fmt.Sprintf("\\u%04x", r) }, 0xFFFF}
+	schemeHex        = interleaveScheme{func(r rune) string { return fmt.Sprintf("\\x%02x", r) }, 0x7F}
+	schemeIISUnicode = interleaveScheme{func(r rune) string { return fmt.Sprintf("%%u%04X", r) }, 0xFFFF}
+)
+
+// interleaveSchemes lists the schemes each level draws from; later levels
+// include the earlier ones
+var interleaveSchemes = map[types.EvasionLevel][]interleaveScheme{
+	types.EvasionLevelBasic:    {schemeURL, schemeHTMLHex},
+	types.EvasionLevelMedium:   {schemeURL, schemeHTMLHex, schemeUnicode, schemeHex},
+	types.EvasionLevelAdvanced: {schemeURL, schemeHTMLHex, schemeUnicode, schemeHex, schemeHTMLDec, schemeIISUnicode},
+}
+
+// interleaveCounts is how many interleavings each level produces
+var interleaveCounts = map[types.EvasionLevel]int{
+	types.EvasionLevelBasic:    3,
+	types.EvasionLevelMedium:   6,
+	types.EvasionLevelAdvanced: 10,
+}
+
+// InterleavedEncodingVariants encodes every character of the payload with a
+// randomly chosen scheme (URL, HTML entity, \u and \x escapes), so no single
+// decoding pass recovers the payload. Output is seeded from the payload and
+// is the same on every run.
+func InterleavedEncodingVariants(payload string, level types.EvasionLevel) []string {
+	h := fnv.New64a()
+	h.Write([]byte(payload))
+	return InterleavedEncodingVariantsWithRand(rand.New(rand.NewSource(int64(h.Sum64()))), payload, level)
+}
+
+// InterleavedEncodingVariantsWithRand is InterleavedEncodingVariants drawing randomness from rng
+func InterleavedEncodingVariantsWithRand(rng evasions.Rand, payload string, level types.EvasionLevel) []string {
+	schemes, ok := interleaveSchemes[level]
+	if !ok {
+		schemes = interleaveSchemes[types.EvasionLevelMedium]
+	}
+	count, ok := interleaveCounts[level]
+	if !ok {
+		count = interleaveCounts[types.EvasionLevelMedium]
+	}
+
+	var variants []string
+	for i := 0; i < count; i++ {
+		variants = append(variants, interleave(rng, payload, schemes))
+	}
+	return evasions.UniqueStrings(variants)
+}
+
+// interleave encodes each rune with a random scheme, making sure at least two
+// schemes appear whenever the payload has two or more characters
+func interleave(rng evasions.Rand, payload string, schemes []interleaveScheme) string {
+	runes := []rune(payload)
+	picks := make([]int, len(runes))
+	for i, r := range runes {
+		picks[i] = pickScheme(rng, r, schemes)
+	}
+	if len(runes) > 1 && allSame(picks) {
+		// Re-pick the second character among the other schemes
+		if others := eligibleSchemes(runes[1], schemes, picks[0]); len(others) > 0 {
+			picks[1] = others[rng.Intn(len(others))]
+		}
+	}
+
+	var b strings.Builder
+	for i, r := range runes {
+		b.WriteString(schemes[picks[i]].encode(r))
+	}
+	return b.String()
+}
+
+// pickScheme chooses the index of a scheme that can represent r. The first
+// scheme of every level (URL) covers all runes, so there always is one.
+func pickScheme(rng evasions.Rand, r rune, schemes []interleaveScheme) int {
+	eligible := eligibleSchemes(r, schemes, -1)
+	return eligible[rng.Intn(len(eligible))]
+}
+
+// eligibleSchemes returns the indexes of the schemes that can represent r,
+// except exclude
+func eligibleSchemes(r rune, schemes []interleaveScheme, exclude int) []int {
+	var eligible []int
+	for i, scheme := range schemes {
+		if i != exclude && r <= scheme.max {
+			eligible = append(eligible, i)
+		}
+	}
+	return eligible
+}
+
+func allSame(picks []int) bool {
+	for _, p := range picks[1:] {
+		if p != picks[0] {
+			return false
+		}
+	}
+	return true
+}
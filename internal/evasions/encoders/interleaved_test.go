@@ -0,0 +1,66 @@
+package encoders
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"obfuskit/internal/canon"
+	"obfuskit/types"
+)
+
+var interleavedSchemePatterns = map[string]*regexp.Regexp{
+	"url":       regexp.MustCompile(`%[0-9A-F]{2}`),
+	"html":      regexp.MustCompile(`&#x[0-9a-f]+;`),
+	"unicode":   regexp.MustCompile(`\\u[0-9a-f]{4}`),
+	"hexescape": regexp.MustCompile(`\\x[0-9a-f]{2}`),
+}
+
+// decodeInterleaved undoes the medium-level schemes one family at a time
+func decodeInterleaved(t *testing.T, s string) string {
+	t.Helper()
+	for _, mode := range []string{canon.ModeUnicode, canon.ModeHTML, canon.ModeURL} {
+		var err error
+		if s, err = canon.DecodeOnce(mode, s); err != nil {
+			t.Fatalf("decoding %s: %v", mode, err)
+		}
+	}
+	return s
+}
+
+func TestInterleavedEncodingVariants(t *testing.T) {
+	payload := "<script>alert(1)</script>"
+	variants := InterleavedEncodingVariants(payload, types.EvasionLevelMedium)
+	if len(variants) < 2 {
+		t.Fatalf("got %d variants, want several", len(variants))
+	}
+
+	for _, v := range variants {
+		schemes := 0
+		for _, re := range interleavedSchemePatterns {
+			if re.MatchString(v) {
+				schemes++
+			}
+		}
+		if schemes < 2 {
+			t.Errorf("variant %q uses %d encoding schemes, want at least 2", v, schemes)
+		}
+		if got := decodeInterleaved(t, v); got != payload {
+			t.Errorf("variant %q decodes to %q, want %q", v, got, payload)
+		}
+	}
+
+	if again := InterleavedEncodingVariants(payload, types.EvasionLevelMedium); !reflect.DeepEqual(variants, again) {
+		t.Error("same payload produced different variants")
+	}
+}
+
+func TestInterleavedEncodingTwoCharacters(t *testing.T) {
+	for _, v := range InterleavedEncodingVariants("ab", types.EvasionLevelBasic) {
+		url := interleavedSchemePatterns["url"].MatchString(v)
+		html := interleavedSchemePatterns["html"].MatchString(v)
+		if !url || !html {
+			t.Errorf("variant %q should mix URL and HTML encoding", v)
+		}
+	}
+}
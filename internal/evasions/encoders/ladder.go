@@ -0,0 +1,54 @@
+package encoders
+
+// DefaultLadderPasses is how many decode passes the CLI ladder preview shows
+const DefaultLadderPasses = 5
+
+// DecodeLadder shows what a payload looks like after successive URL-decode
+// passes, as seen by each layer of a stack that decodes more than once (WAF,
+// proxy, framework, application). The first entry is the input unchanged and
+// each following entry is one more pass, stopping once a pass no longer
+// changes the string or after maxPasses passes.
+//
+// Decoding is lenient like most servers: malformed escapes such as "%zz" are
+// left in place instead of failing, and "+" is not treated as a space.
+func DecodeLadder(s string, maxPasses int) []string {
+	ladder := []string{s}
+	for pass := 0; pass < maxPasses; pass++ {
+		decoded := percentDecode(s)
+		if decoded == s {
+			break
+		}
+		ladder = append(ladder, decoded)
+		s = decoded
+	}
+	return ladder
+}
+
+// percentDecode decodes every valid %XX escape once and keeps anything else
+func percentDecode(s string) string {
+	decoded := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHexDigit(s[i+1]) && isHexDigit(s[i+2]) {
+			decoded = append(decoded, unhex(s[i+1])<<4|unhex(s[i+2]))
+			i += 2
+			continue
+		}
+		decoded = append(decoded, s[i])
+	}
+	return string(decoded)
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,55 @@
+package encoders
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeLadder(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxPasses int
+		expected  []string
+	}{
+		{
+			name:      "double encoded dots stabilize after two passes",
+			input:     "%252e%252e",
+			maxPasses: 5,
+			expected:  []string{"%252e%252e", "%2e%2e", ".."},
+		},
+		{
+			name:      "stops at maxPasses",
+			input:     "%25252f",
+			maxPasses: 1,
+			expected:  []string{"%25252f", "%252f"},
+		},
+		{
+			name:      "plain input is already stable",
+			input:     "../etc/passwd",
+			maxPasses: 3,
+			expected:  []string{"../etc/passwd"},
+		},
+		{
+			name:      "malformed escapes are kept",
+			input:     "%zz%2F%",
+			maxPasses: 3,
+			expected:  []string{"%zz%2F%", "%zz/%"},
+		},
+		{
+			name:      "plus is not a space",
+			input:     "a+b%2520c",
+			maxPasses: 3,
+			expected:  []string{"a+b%2520c", "a+b%20c", "a+b c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecodeLadder(tt.input, tt.maxPasses)
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("DecodeLadder(%q, %d) = %q, want %q", tt.input, tt.maxPasses, got, tt.expected)
+			}
+		})
+	}
+}
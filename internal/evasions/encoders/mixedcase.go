@@ -7,9 +7,21 @@ import (
 	"unicode"
 )
 
+// caseKeywords are the tokens keyword-based WAF rules commonly match on,
+// which the advanced level varies the case of on their own
+var caseKeywords = []string{"script", "select", "union", "alert", "onerror"}
+
+// randomCaseDraws is how many randomly cased variants the medium level adds
+const randomCaseDraws = 3
+
 // MixedCaseVariants generates mixed case variants of the input payload
 // based on the specified obfuscation level
 func MixedCaseVariants(payload string, level types.EvasionLevel) []string {
+	return MixedCaseVariantsWithRand(evasions.DefaultRand, payload, level)
+}
+
+// MixedCaseVariantsWithRand is MixedCaseVariants drawing randomness from rng
+func MixedCaseVariantsWithRand(rng evasions.Rand, payload string, level types.EvasionLevel) []string {
 	var variants []string
 
 	// Basic mixed case patterns
@@ -36,6 +48,9 @@ func MixedCaseVariants(payload string, level types.EvasionLevel) []string {
 		camelCase(payload),          // camelCase style
 		snakeToUpperCase(payload),   // SNAKE_CASE style
 	)
+	for i := 0; i < randomCaseDraws; i++ {
+		variants = append(variants, drawCase(rng, payload))
+	}
 
 	// Return medium variants if level is Medium
 	if level == types.EvasionLevelMedium {
@@ -52,12 +67,82 @@ func MixedCaseVariants(payload string, level types.EvasionLevel) []string {
 		preserveSpecialCase(payload), // Preserve special chars, vary letters
 	)
 
+	// Only the keywords recased, the rest of the payload as written
+	if containsKeyword(payload) {
+		variants = append(variants,
+			keywordCase(payload, inverseZebraCase), // SeLeCt
+			keywordCase(payload, zebraCase),        // sElEcT
+			keywordCase(payload, strings.ToUpper),  // SELECT
+			keywordCase(payload, func(s string) string { return drawCase(rng, s) }),
+		)
+	}
+
 	return evasions.UniqueStrings(variants)
 }
 
+// drawCase gives every letter a case drawn from rng
+func drawCase(rng evasions.Rand, s string) string {
+	result := newBuffer()
+	for _, r := range s {
+		if unicode.IsLetter(r) && rng.Intn(2) == 0 {
+			result.WriteRune(unicode.ToUpper(r))
+		} else {
+			result.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return releaseBuffer(result)
+}
+
+// containsKeyword reports whether s contains any of caseKeywords, in any case
+func containsKeyword(s string) bool {
+	lower := asciiLower(s)
+	for _, keyword := range caseKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// keywordCase applies recase to every occurrence of caseKeywords in s and
+// leaves the rest of s untouched
+func keywordCase(s string, recase func(string) string) string {
+	lower := asciiLower(s)
+	result := newBuffer()
+	for i := 0; i < len(s); {
+		matched := ""
+		for _, keyword := range caseKeywords {
+			if strings.HasPrefix(lower[i:], keyword) {
+				matched = keyword
+				break
+			}
+		}
+		if matched == "" {
+			result.WriteByte(s[i])
+			i++
+			continue
+		}
+		result.WriteString(recase(s[i : i+len(matched)]))
+		i += len(matched)
+	}
+	return releaseBuffer(result)
+}
+
+// asciiLower lowercases the ASCII letters of s only, so that byte offsets
+// into the result are offsets into s
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
 // alternatingCase creates alternating upper/lower case
 func alternatingCase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	upper := false
 	for _, r := range s {
 		if unicode.IsLetter(r) {
@@ -71,12 +156,12 @@ func alternatingCase(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // randomCase randomly changes case of characters
 func randomCase(s string, ratio float64) string {
-	var result strings.Builder
+	result := newBuffer()
 	runes := []rune(s)
 	changeCount := int(float64(len(runes)) * ratio)
 	changed := 0
@@ -93,7 +178,7 @@ func randomCase(s string, ratio float64) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // firstLetterUpper makes the first letter uppercase
@@ -128,7 +213,7 @@ func lastLetterUpper(s string) string {
 
 // wordBoundaryUpper makes the first letter of each word uppercase
 func wordBoundaryUpper(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	wordStart := true
 	for _, r := range s {
 		if unicode.IsLetter(r) {
@@ -145,12 +230,12 @@ func wordBoundaryUpper(s string) string {
 			}
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // vowelUppercase makes only vowels uppercase
 func vowelUppercase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	vowels := "aeiouAEIOU"
 	for _, r := range s {
 		if strings.ContainsRune(vowels, unicode.ToLower(r)) {
@@ -159,12 +244,12 @@ func vowelUppercase(s string) string {
 			result.WriteRune(unicode.ToLower(r))
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // consonantUppercase makes only consonants uppercase
 func consonantUppercase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	vowels := "aeiouAEIOU"
 	for _, r := range s {
 		if unicode.IsLetter(r) && !strings.ContainsRune(vowels, unicode.ToLower(r)) {
@@ -173,12 +258,12 @@ func consonantUppercase(s string) string {
 			result.WriteRune(unicode.ToLower(r))
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // reverseCase inverts the normal case
 func reverseCase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		if unicode.IsUpper(r) {
 			result.WriteRune(unicode.ToLower(r))
@@ -188,12 +273,12 @@ func reverseCase(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // camelCase converts to camelCase style
 func camelCase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	wordStart := false
 	for _, r := range s {
 		if unicode.IsLetter(r) {
@@ -210,12 +295,12 @@ func camelCase(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // snakeToUpperCase converts to UPPER_CASE style
 func snakeToUpperCase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		if unicode.IsSpace(r) {
 			result.WriteRune('_')
@@ -223,12 +308,12 @@ func snakeToUpperCase(s string) string {
 			result.WriteRune(unicode.ToUpper(r))
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // unicodeCaseVariants creates Unicode case transformations
 func unicodeCaseVariants(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for i, r := range s {
 		if unicode.IsLetter(r) {
 			switch i % 3 {
@@ -243,12 +328,12 @@ func unicodeCaseVariants(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // leetSpeakCase combines leet speak with case variations
 func leetSpeakCase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for i, r := range s {
 		switch unicode.ToLower(r) {
 		case 'a':
@@ -293,12 +378,12 @@ func leetSpeakCase(s string) string {
 			}
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // zebraCase creates zebra striping pattern
 func zebraCase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	letterCount := 0
 	for _, r := range s {
 		if unicode.IsLetter(r) {
@@ -312,12 +397,12 @@ func zebraCase(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // inverseZebraCase creates inverse zebra striping pattern
 func inverseZebraCase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	letterCount := 0
 	for _, r := range s {
 		if unicode.IsLetter(r) {
@@ -331,7 +416,7 @@ func inverseZebraCase(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // randomWordCase applies random case to each word
@@ -355,7 +440,7 @@ func randomWordCase(s string) string {
 
 // preserveSpecialCase preserves special characters, varies letter case
 func preserveSpecialCase(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	letterIndex := 0
 	for _, r := range s {
 		if unicode.IsLetter(r) {
@@ -369,5 +454,5 @@ func preserveSpecialCase(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
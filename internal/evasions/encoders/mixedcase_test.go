@@ -1,7 +1,9 @@
 package encoders
 
 import (
+	"obfuskit/internal/evasions"
 	"obfuskit/types"
+	"reflect"
 	"strings"
 	"testing"
 	"unicode"
@@ -110,11 +112,11 @@ func TestMixedCaseVariants(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			variants := MixedCaseVariants(tt.payload, tt.level)
-			
+
 			if len(variants) < tt.minCount {
 				t.Errorf("MixedCaseVariants() returned %d variants, expected at least %d", len(variants), tt.minCount)
 			}
-			
+
 			// Check for duplicates
 			seen := make(map[string]bool)
 			for _, variant := range variants {
@@ -123,7 +125,7 @@ func TestMixedCaseVariants(t *testing.T) {
 				}
 				seen[variant] = true
 			}
-			
+
 			// Run additional checks
 			for i, check := range tt.checks {
 				if !check(variants) {
@@ -203,12 +205,12 @@ func TestRandomCase(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := randomCase(tt.input, tt.ratio)
-			
+
 			// Should not be empty for non-empty input
 			if len(result) == 0 && len(tt.input) > 0 {
 				t.Errorf("randomCase() returned empty string for non-empty input")
 			}
-			
+
 			// Length should be preserved
 			if len(result) != len(tt.input) {
 				t.Errorf("randomCase() changed string length: got %d, want %d", len(result), len(tt.input))
@@ -257,8 +259,8 @@ func TestWordBoundaryUpper(t *testing.T) {
 
 func TestVowelConsonantCase(t *testing.T) {
 	tests := []struct {
-		name    string
-		input   string
+		name     string
+		input    string
 		funcName string
 		testFunc func(string) string
 	}{
@@ -279,12 +281,12 @@ func TestVowelConsonantCase(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := tt.testFunc(tt.input)
-			
+
 			// Should not be empty for non-empty input
 			if len(result) == 0 && len(tt.input) > 0 {
 				t.Errorf("%s() returned empty string for non-empty input", tt.funcName)
 			}
-			
+
 			// Should have some case transformation
 			if result == tt.input && len(tt.input) > 0 {
 				hasVowels := strings.ContainsAny(strings.ToLower(tt.input), "aeiou")
@@ -295,10 +297,10 @@ func TestVowelConsonantCase(t *testing.T) {
 						break
 					}
 				}
-				
+
 				// Only expect change if input has relevant characters
-				if (tt.funcName == "vowelUppercase" && hasVowels) || 
-				   (tt.funcName == "consonantUppercase" && hasConsonants) {
+				if (tt.funcName == "vowelUppercase" && hasVowels) ||
+					(tt.funcName == "consonantUppercase" && hasConsonants) {
 					t.Errorf("%s() should transform case, got same string: %s", tt.funcName, result)
 				}
 			}
@@ -418,7 +420,7 @@ func TestSnakeToUpperCase(t *testing.T) {
 func TestLeetSpeakCase(t *testing.T) {
 	input := "hello"
 	result := leetSpeakCase(input)
-	
+
 	// Should contain some leet substitutions
 	hasLeetChars := strings.ContainsAny(result, "@30$1")
 	if !hasLeetChars {
@@ -428,20 +430,20 @@ func TestLeetSpeakCase(t *testing.T) {
 
 func TestZebraCaseVariants(t *testing.T) {
 	input := "abcdef"
-	
+
 	zebra := zebraCase(input)
 	inverseZebra := inverseZebraCase(input)
-	
+
 	// They should be different
 	if zebra == inverseZebra {
 		t.Errorf("zebraCase() and inverseZebraCase() should produce different results")
 	}
-	
+
 	// Both should have mixed case
 	if !hasMixedCase(zebra) {
 		t.Errorf("zebraCase() should produce mixed case: %s", zebra)
 	}
-	
+
 	if !hasMixedCase(inverseZebra) {
 		t.Errorf("inverseZebraCase() should produce mixed case: %s", inverseZebra)
 	}
@@ -450,14 +452,14 @@ func TestZebraCaseVariants(t *testing.T) {
 func TestRandomWordCase(t *testing.T) {
 	input := "hello world test case"
 	result := randomWordCase(input)
-	
+
 	words := strings.Fields(result)
 	originalWords := strings.Fields(input)
-	
+
 	if len(words) != len(originalWords) {
 		t.Errorf("randomWordCase() should preserve word count")
 	}
-	
+
 	// Should have some variation
 	hasVariation := false
 	for i, word := range words {
@@ -466,7 +468,7 @@ func TestRandomWordCase(t *testing.T) {
 			break
 		}
 	}
-	
+
 	if !hasVariation {
 		t.Errorf("randomWordCase() should produce some variation: %s", result)
 	}
@@ -476,7 +478,7 @@ func TestRandomWordCase(t *testing.T) {
 func hasAlternatingCase(s string) bool {
 	lastWasUpper := false
 	firstLetter := true
-	
+
 	for _, r := range s {
 		if unicode.IsLetter(r) {
 			isUpper := unicode.IsUpper(r)
@@ -493,14 +495,14 @@ func hasAlternatingCase(s string) bool {
 func hasMixedCase(s string) bool {
 	hasUpper := false
 	hasLower := false
-	
+
 	for _, r := range s {
 		if unicode.IsUpper(r) {
 			hasUpper = true
 		} else if unicode.IsLower(r) {
 			hasLower = true
 		}
-		
+
 		if hasUpper && hasLower {
 			return true
 		}
@@ -508,6 +510,27 @@ func hasMixedCase(s string) bool {
 	return false
 }
 
+func TestMixedCaseVariantsAreSeedableAndTargetKeywords(t *testing.T) {
+	payload := "1 UNION select x --<img onerror=alert(1)>"
+
+	first := MixedCaseVariantsWithRand(evasions.NewRand(7, 0), payload, types.EvasionLevelAdvanced)
+	second := MixedCaseVariantsWithRand(evasions.NewRand(7, 0), payload, types.EvasionLevelAdvanced)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different variants:\n%q\n%q", first, second)
+	}
+
+	// Only the keywords change case
+	for _, want := range []string{
+		"1 UnIoN SeLeCt x --<img OnErRoR=AlErT(1)>",
+		"1 uNiOn sElEcT x --<img oNeRrOr=aLeRt(1)>",
+		"1 UNION SELECT x --<img ONERROR=ALERT(1)>",
+	} {
+		if !containsVariant(first, want) {
+			t.Errorf("advanced variants lack %q", want)
+		}
+	}
+}
+
 // Benchmark tests
 func BenchmarkMixedCaseVariantsBasic(b *testing.B) {
 	payload := "<script>alert('test')</script>"
@@ -528,4 +551,4 @@ func BenchmarkMixedCaseVariantsAdvanced(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		MixedCaseVariants(payload, types.EvasionLevelAdvanced)
 	}
-}
\ No newline at end of file
+}
@@ -2,7 +2,6 @@ package encoders
 
 import (
 	"fmt"
-	"math/rand"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
 	"regexp"
@@ -12,6 +11,11 @@ import (
 // OctalVariants generates various octal encoded variants of the input payload
 // based on the specified obfuscation level
 func OctalVariants(payload string, level types.EvasionLevel) []string {
+	return OctalVariantsWithRand(evasions.DefaultRand, payload, level)
+}
+
+// OctalVariantsWithRand is OctalVariants drawing randomness from rng
+func OctalVariantsWithRand(rng evasions.Rand, payload string, level types.EvasionLevel) []string {
 	var variants []string
 
 	// Basic octal encodings
@@ -22,12 +26,12 @@ func OctalVariants(payload string, level types.EvasionLevel) []string {
 
 	// Add basic variants
 	variants = append(variants,
-		standardOctal,              // Standard octal (177 145 154 154 157)
-		leadingZeroOctal,           // Leading zero octal (0177 0145 0154 0154 0157)
-		cStyleOctal,                // C-style octal (\177\145\154\154\157)
-		cStyleLeadingZeroOctal,     // C-style with leading zeros (\0177\0145\0154\0154\0157)
-		mixSpacedOctal(payload),    // Mixed spaces (177 145  154   154 157)
-		tabSeparatedOctal(payload), // Tab separated octal (177	145	154	154	157)
+		standardOctal,                // Standard octal (177 145 154 154 157)
+		leadingZeroOctal,             // Leading zero octal (0177 0145 0154 0154 0157)
+		cStyleOctal,                  // C-style octal (\177\145\154\154\157)
+		cStyleLeadingZeroOctal,       // C-style with leading zeros (\0177\0145\0154\0154\0157)
+		mixSpacedOctal(rng, payload), // Mixed spaces (177 145  154   154 157)
+		tabSeparatedOctal(payload),   // Tab separated octal (177	145	154	154	157)
 	)
 
 	// Return basic variants if level is Basic
@@ -43,7 +47,7 @@ func OctalVariants(payload string, level types.EvasionLevel) []string {
 		partialOctalEncoding(payload), // Only encode special chars
 		jsOctalStringLiteral(payload), // JavaScript octal string literal
 		bashOctalEncoding(payload),    // Bash-style octal encoding
-		overPaddedOctal(payload),      // Over-padded octal (00177 00145...)
+		overPaddedOctal(rng, payload), // Over-padded octal (00177 00145...)
 		splitDigitGroups(cStyleOctal), // Split digit groups (\1\7\7\1\4\5...)
 	)
 
@@ -54,18 +58,18 @@ func OctalVariants(payload string, level types.EvasionLevel) []string {
 
 	// Advanced level adds complex evasion techniques
 	variants = append(variants,
-		multilineSplitOctal(payload),           // Split across multiple lines
-		commentedOctal(payload),                // With comments
-		nestingOctalEncoding(payload),          // Nested encoding
-		mixedRadixEncoding(payload),            // Mixed radix encoding
-		octalWithControlChars(payload),         // With control characters
-		encodedPathTraversal(payload),          // Path traversal with octal
-		doubleEncodedOctal(payload),            // Double-encoded octal
-		shuffleDigitOrder(payload),             // Shuffle digit order with markers
-		octalWithUnicode(payload),              // Mix octal with unicode escapes
-		obfuscatedOctalAssignment(payload),     // Obfuscated assignment pattern
-		escapedOctalVariant(payload),           // Escaped octal with special syntax
-		octalWithWhitespaceVariations(payload), // Various whitespace formats
+		multilineSplitOctal(payload),                // Split across multiple lines
+		commentedOctal(rng, payload),                // With comments
+		nestingOctalEncoding(payload),               // Nested encoding
+		mixedRadixEncoding(rng, payload),            // Mixed radix encoding
+		octalWithControlChars(rng, payload),         // With control characters
+		encodedPathTraversal(payload),               // Path traversal with octal
+		doubleEncodedOctal(payload),                 // Double-encoded octal
+		shuffleDigitOrder(payload),                  // Shuffle digit order with markers
+		octalWithUnicode(payload),                   // Mix octal with unicode escapes
+		obfuscatedOctalAssignment(payload),          // Obfuscated assignment pattern
+		escapedOctalVariant(payload),                // Escaped octal with special syntax
+		octalWithWhitespaceVariations(rng, payload), // Various whitespace formats
 	)
 
 	return evasions.UniqueStrings(variants)
@@ -73,19 +77,19 @@ func OctalVariants(payload string, level types.EvasionLevel) []string {
 
 // toOctal converts a string to octal representation, optionally with leading zeros
 func toOctal(s string, leadingZero bool) string {
-	var b strings.Builder
+	b := newBuffer()
 	for i, c := range []byte(s) {
 		if i > 0 {
 			b.WriteString(" ")
 		}
 
 		if leadingZero {
-			b.WriteString(fmt.Sprintf("0%o", c))
+			fmt.Fprintf(b, "0%o", c)
 		} else {
-			b.WriteString(fmt.Sprintf("%o", c))
+			fmt.Fprintf(b, "%o", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // addBackslashPrefix adds backslash prefix to each octal value
@@ -98,34 +102,34 @@ func addBackslashPrefix(octal string) string {
 }
 
 // mixSpacedOctal creates mixed spacing between octal values
-func mixSpacedOctal(payload string) string {
-	var b strings.Builder
+func mixSpacedOctal(rng evasions.Rand, payload string) string {
+	b := newBuffer()
 	for i, c := range []byte(payload) {
 		if i > 0 {
 			// Add random number of spaces (1-4)
-			spaces := rand.Intn(4) + 1
+			spaces := rng.Intn(4) + 1
 			b.WriteString(strings.Repeat(" ", spaces))
 		}
-		b.WriteString(fmt.Sprintf("%o", c))
+		fmt.Fprintf(b, "%o", c)
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // tabSeparatedOctal creates tab-separated octal values
 func tabSeparatedOctal(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 	for i, c := range []byte(payload) {
 		if i > 0 {
 			b.WriteString("\t")
 		}
-		b.WriteString(fmt.Sprintf("%o", c))
+		fmt.Fprintf(b, "%o", c)
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // octBinaryMix mixes octal and binary encodings
 func octBinaryMix(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 	for i, c := range []byte(payload) {
 		if i > 0 {
 			b.WriteString(" ")
@@ -133,17 +137,17 @@ func octBinaryMix(payload string) string {
 
 		// Alternate between octal and binary
 		if i%2 == 0 {
-			b.WriteString(fmt.Sprintf("%o", c))
+			fmt.Fprintf(b, "%o", c)
 		} else {
-			b.WriteString(fmt.Sprintf("0b%08b", c))
+			fmt.Fprintf(b, "0b%08b", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // octHexMix mixes octal and hexadecimal encodings
 func octHexMix(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 	for i, c := range []byte(payload) {
 		if i > 0 {
 			b.WriteString(" ")
@@ -151,17 +155,17 @@ func octHexMix(payload string) string {
 
 		// Alternate between octal and hex
 		if i%2 == 0 {
-			b.WriteString(fmt.Sprintf("0%o", c))
+			fmt.Fprintf(b, "0%o", c)
 		} else {
-			b.WriteString(fmt.Sprintf("0x%02x", c))
+			fmt.Fprintf(b, "0x%02x", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // octDecimalMix mixes octal and decimal encodings
 func octDecimalMix(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 	for i, c := range []byte(payload) {
 		if i > 0 {
 			b.WriteString(" ")
@@ -169,26 +173,26 @@ func octDecimalMix(payload string) string {
 
 		// Alternate between octal and decimal
 		if i%2 == 0 {
-			b.WriteString(fmt.Sprintf("%o", c))
+			fmt.Fprintf(b, "%o", c)
 		} else {
-			b.WriteString(fmt.Sprintf("%d", c))
+			fmt.Fprintf(b, "%d", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // partialOctalEncoding only encodes special characters, leaving alphanumeric as-is
 func partialOctalEncoding(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 	for _, c := range []byte(payload) {
 		// Encode only special characters
 		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
 			b.WriteByte(c)
 		} else {
-			b.WriteString(fmt.Sprintf("\\%o", c))
+			fmt.Fprintf(b, "\\%o", c)
 		}
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // jsOctalStringLiteral creates JavaScript-style octal string literals
@@ -202,27 +206,27 @@ func jsOctalStringLiteral(payload string) string {
 
 // bashOctalEncoding creates bash-style octal encoding with $'...' syntax
 func bashOctalEncoding(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 	b.WriteString("$'")
 	for _, c := range []byte(payload) {
-		b.WriteString(fmt.Sprintf("\\%o", c))
+		fmt.Fprintf(b, "\\%o", c)
 	}
 	b.WriteString("'")
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // overPaddedOctal adds excessive leading zeros to octal values
-func overPaddedOctal(payload string) string {
-	var b strings.Builder
+func overPaddedOctal(rng evasions.Rand, payload string) string {
+	b := newBuffer()
 	for i, c := range []byte(payload) {
 		if i > 0 {
 			b.WriteString(" ")
 		}
 		// Add 2-4 leading zeros
-		padding := rand.Intn(3) + 2
-		b.WriteString(fmt.Sprintf("%0*o", padding, c))
+		padding := rng.Intn(3) + 2
+		fmt.Fprintf(b, "%0*o", padding, c)
 	}
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // splitDigitGroups breaks up octal digits with separators
@@ -230,13 +234,13 @@ func splitDigitGroups(octal string) string {
 	re := regexp.MustCompile(`\\([0-7]+)`)
 	return re.ReplaceAllStringFunc(octal, func(match string) string {
 		digits := match[1:] // Remove leading backslash
-		var result strings.Builder
+		result := newBuffer()
 
 		for _, digit := range digits {
-			result.WriteString(fmt.Sprintf("\\%c", digit))
+			fmt.Fprintf(result, "\\%c", digit)
 		}
 
-		return result.String()
+		return releaseBuffer(result)
 	})
 }
 
@@ -252,8 +256,8 @@ func multilineSplitOctal(payload string) string {
 }
 
 // commentedOctal intersperses comments in octal encoding
-func commentedOctal(payload string) string {
-	var b strings.Builder
+func commentedOctal(rng evasions.Rand, payload string) string {
+	b := newBuffer()
 	comments := []string{
 		"/* harmless */",
 		"// ignore",
@@ -263,16 +267,16 @@ func commentedOctal(payload string) string {
 	}
 
 	for i, c := range []byte(payload) {
-		b.WriteString(fmt.Sprintf("\\%o", c))
+		fmt.Fprintf(b, "\\%o", c)
 
 		// Add a comment after some octal values
 		if i%3 == 0 {
-			comment := comments[rand.Intn(len(comments))]
+			comment := comments[rng.Intn(len(comments))]
 			b.WriteString(comment)
 		}
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // nestingOctalEncoding creates nested octal encoding patterns
@@ -284,67 +288,67 @@ func nestingOctalEncoding(payload string) string {
 	}
 
 	// Then encode that octal string again
-	var b strings.Builder
+	b := newBuffer()
 	for _, c := range []byte(octalStr) {
-		b.WriteString(fmt.Sprintf("\\%o", c))
+		fmt.Fprintf(b, "\\%o", c)
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // mixedRadixEncoding mixes octal with other radix encodings in complex patterns
-func mixedRadixEncoding(payload string) string {
-	var b strings.Builder
+func mixedRadixEncoding(rng evasions.Rand, payload string) string {
+	b := newBuffer()
 	for i, c := range []byte(payload) {
 		if i > 0 {
 			// Use different separators
 			separators := []string{" ", ".", "_", "-", "+"}
-			b.WriteString(separators[rand.Intn(len(separators))])
+			b.WriteString(separators[rng.Intn(len(separators))])
 		}
 
 		// Cycle through different radix encodings
 		switch i % 4 {
 		case 0:
-			b.WriteString(fmt.Sprintf("0%o", c)) // Octal
+			fmt.Fprintf(b, "0%o", c) // Octal
 		case 1:
-			b.WriteString(fmt.Sprintf("0x%x", c)) // Hex
+			fmt.Fprintf(b, "0x%x", c) // Hex
 		case 2:
-			b.WriteString(fmt.Sprintf("%d", c)) // Decimal
+			fmt.Fprintf(b, "%d", c) // Decimal
 		case 3:
-			b.WriteString(fmt.Sprintf("0b%b", c)) // Binary
+			fmt.Fprintf(b, "0b%b", c) // Binary
 		}
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // octalWithControlChars inserts control characters between octal values
-func octalWithControlChars(payload string) string {
-	var b strings.Builder
+func octalWithControlChars(rng evasions.Rand, payload string) string {
+	b := newBuffer()
 	controlChars := []string{
 		"\\x00", "\\x01", "\\x02", "\\x03", "\\x04",
 		"\\x05", "\\x06", "\\x07", "\\x08", "\\x09",
 	}
 
 	for _, c := range []byte(payload) {
-		b.WriteString(fmt.Sprintf("\\%o", c))
+		fmt.Fprintf(b, "\\%o", c)
 		// Insert random control character
-		b.WriteString(controlChars[rand.Intn(len(controlChars))])
+		b.WriteString(controlChars[rng.Intn(len(controlChars))])
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // encodedPathTraversal creates path traversal with octal encoding
 func encodedPathTraversal(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 	b.WriteString("../") // Add path traversal prefix
 
 	for _, c := range []byte(payload) {
-		b.WriteString(fmt.Sprintf("\\%o", c))
+		fmt.Fprintf(b, "\\%o", c)
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // doubleEncodedOctal performs double encoding on octal values
@@ -363,7 +367,7 @@ func doubleEncodedOctal(payload string) string {
 
 // shuffleDigitOrder shuffles octal digits with position markers
 func shuffleDigitOrder(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	for _, c := range []byte(payload) {
 		// Convert to octal without leading backslash
@@ -378,28 +382,28 @@ func shuffleDigitOrder(payload string) string {
 			b.WriteString(fmt.Sprintf("\\[1]%c\\[0]%c",
 				octal[1], octal[0]))
 		} else {
-			b.WriteString(fmt.Sprintf("\\[0]%c", octal[0]))
+			fmt.Fprintf(b, "\\[0]%c", octal[0])
 		}
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // octalWithUnicode mixes octal with Unicode escape sequences
 func octalWithUnicode(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	for i, c := range []byte(payload) {
 		if i%2 == 0 {
 			// Octal encoding
-			b.WriteString(fmt.Sprintf("\\%o", c))
+			fmt.Fprintf(b, "\\%o", c)
 		} else {
 			// Unicode encoding
-			b.WriteString(fmt.Sprintf("\\u%04x", c))
+			fmt.Fprintf(b, "\\u%04x", c)
 		}
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 func obfuscatedOctalAssignment(payload string) string {
@@ -415,20 +419,20 @@ func obfuscatedOctalAssignment(payload string) string {
 }
 
 func escapedOctalVariant(payload string) string {
-	var b strings.Builder
+	b := newBuffer()
 
 	b.WriteString("eval(\"")
 	for _, c := range []byte(payload) {
-		b.WriteString(fmt.Sprintf("\\\\%o", c))
+		fmt.Fprintf(b, "\\\\%o", c)
 	}
 	b.WriteString("\")")
 
-	return b.String()
+	return releaseBuffer(b)
 }
 
 // octalWithWhitespaceVariations creates octal encoding with various whitespace formats
-func octalWithWhitespaceVariations(payload string) string {
-	var b strings.Builder
+func octalWithWhitespaceVariations(rng evasions.Rand, payload string) string {
+	b := newBuffer()
 	whitespaces := []string{
 		" ", "\t", "\n", "\r", "\f", "\v",
 		"\u00A0", // Non-breaking space
@@ -439,14 +443,14 @@ func octalWithWhitespaceVariations(payload string) string {
 	for i, c := range []byte(payload) {
 		if i > 0 {
 			// Add 1-3 random whitespace characters
-			count := rand.Intn(3) + 1
+			count := rng.Intn(3) + 1
 			for j := 0; j < count; j++ {
-				b.WriteString(whitespaces[rand.Intn(len(whitespaces))])
+				b.WriteString(whitespaces[rng.Intn(len(whitespaces))])
 			}
 		}
 
-		b.WriteString(fmt.Sprintf("\\%o", c))
+		fmt.Fprintf(b, "\\%o", c)
 	}
 
-	return b.String()
+	return releaseBuffer(b)
 }
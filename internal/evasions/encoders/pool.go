@@ -0,0 +1,39 @@
+package encoders
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize keeps the buffers of unusually long payloads out of
+// the pool, so one large run does not pin their memory for good
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool recycles the buffers the per-character encoders build variants
+// in. Parallel generation otherwise grows and drops a builder per variant.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// pooling switches bufferPool off, for comparing pooled and unpooled output
+var pooling = true
+
+// newBuffer returns an empty buffer from the pool
+func newBuffer() *bytes.Buffer {
+	if !pooling {
+		return new(bytes.Buffer)
+	}
+	b := bufferPool.Get().(*bytes.Buffer)
+	b.Reset()
+	return b
+}
+
+// releaseBuffer returns b's contents and puts b back in the pool; b must not
+// be used afterwards
+func releaseBuffer(b *bytes.Buffer) string {
+	s := b.String()
+	if pooling && b.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(b)
+	}
+	return s
+}
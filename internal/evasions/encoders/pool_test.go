@@ -0,0 +1,86 @@
+package encoders
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"obfuskit/internal/evasions"
+	"obfuskit/types"
+)
+
+const poolPayload = `<script>alert("x1")</script>' OR 1=1 -- ../../etc/passwd`
+
+// pooledEncoders are the encoders building variants in pooled buffers, each
+// seeded the same way on every call
+var pooledEncoders = map[string]func(string, types.EvasionLevel) []string{
+	"html": func(s string, level types.EvasionLevel) []string {
+		return HTMLVariantsWithRand(evasions.NewRand(1, 0), s, level)
+	},
+	"octal": func(s string, level types.EvasionLevel) []string {
+		return OctalVariantsWithRand(evasions.NewRand(1, 0), s, level)
+	},
+	"hex": func(s string, level types.EvasionLevel) []string {
+		return HexVariantsWithRand(evasions.NewRand(1, 0), s, level)
+	},
+	"unicode": func(s string, level types.EvasionLevel) []string {
+		return UnicodeVariantsWithRand(evasions.NewRand(1, 0), s, level)
+	},
+	"mixedcase": func(s string, level types.EvasionLevel) []string {
+		return MixedCaseVariantsWithRand(evasions.NewRand(1, 0), s, level)
+	},
+	"utf8": UTF8Variants,
+	"url":  URLVariants,
+}
+
+func TestPooledBuffersMatchUnpooledOutput(t *testing.T) {
+	defer func() { pooling = true }()
+
+	for name, encode := range pooledEncoders {
+		pooling = false
+		want := encode(poolPayload, types.EvasionLevelAdvanced)
+
+		pooling = true
+		// Reused buffers must not leak earlier variants into later ones
+		encode("short", types.EvasionLevelAdvanced)
+		if got := encode(poolPayload, types.EvasionLevelAdvanced); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: pooled output differs from unpooled:\n got %q\nwant %q", name, got, want)
+		}
+
+		// Concurrent generation shares the pool
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if got := encode(poolPayload, types.EvasionLevelAdvanced); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: concurrent pooled output differs from unpooled", name)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+// BenchmarkEncodersPooled and BenchmarkEncodersUnpooled compare allocations
+// with and without buffer pooling: go test -bench Encoders -benchmem
+func BenchmarkEncodersPooled(b *testing.B) {
+	benchmarkEncoders(b, true)
+}
+
+func BenchmarkEncodersUnpooled(b *testing.B) {
+	benchmarkEncoders(b, false)
+}
+
+func benchmarkEncoders(b *testing.B, pooled bool) {
+	pooling = pooled
+	defer func() { pooling = true }()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for _, encode := range pooledEncoders {
+				encode(poolPayload, types.EvasionLevelAdvanced)
+			}
+		}
+	})
+}
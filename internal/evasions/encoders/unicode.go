@@ -2,7 +2,6 @@ package encoders
 
 import (
 	"fmt"
-	"math/rand"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
 	"strings"
@@ -12,6 +11,11 @@ import (
 // UnicodeVariants generates various unicode encoded variants of the input payload
 // based on the specified obfuscation level
 func UnicodeVariants(payload string, level types.EvasionLevel) []string {
+	return UnicodeVariantsWithRand(evasions.DefaultRand, payload, level)
+}
+
+// UnicodeVariantsWithRand is UnicodeVariants drawing randomness from rng
+func UnicodeVariantsWithRand(rng evasions.Rand, payload string, level types.EvasionLevel) []string {
 	var variants []string
 
 	// Define string builders for different unicode encoding styles
@@ -105,8 +109,8 @@ func UnicodeVariants(payload string, level types.EvasionLevel) []string {
 
 	// 2. Mixed encoding strategies
 	variants = append(variants,
-		mixedEncodingStrategy(payload),
-		mixedEncodingStrategyAdvanced(payload),
+		mixedEncodingStrategy(rng, payload),
+		mixedEncodingStrategyAdvanced(rng, payload),
 	)
 
 	// 3. Bidirectional override characters
@@ -116,22 +120,22 @@ func UnicodeVariants(payload string, level types.EvasionLevel) []string {
 
 	// 4. Homoglyphs substitution
 	variants = append(variants,
-		substituteHomoglyphs(payload),
+		substituteHomoglyphs(rng, payload),
 	)
 
 	// 5. Combining characters
 	variants = append(variants,
-		addCombiningMarks(payload),
+		addCombiningMarks(rng, payload),
 	)
 
 	// 6. Invisible control characters
 	variants = append(variants,
-		addInvisibleControls(payload),
+		addInvisibleControls(rng, payload),
 	)
 
 	// 7. Unicode normalization exploits
 	variants = append(variants,
-		normalizedVariants(payload),
+		normalizedVariants(rng, payload),
 	)
 
 	// 8. Case folding
@@ -152,7 +156,7 @@ func insertZeroWidthSpaces(s string) string {
 	// Zero-width space (U+200B)
 	zwsp := "\u200B"
 
-	var result strings.Builder
+	result := newBuffer()
 	for i, r := range s {
 		result.WriteRune(r)
 		// Don't add after the last character
@@ -160,55 +164,55 @@ func insertZeroWidthSpaces(s string) string {
 			result.WriteString(zwsp)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // mixedEncodingStrategy creates a string with mixed encoding strategies
-func mixedEncodingStrategy(s string) string {
-	var result strings.Builder
+func mixedEncodingStrategy(rng evasions.Rand, s string) string {
+	result := newBuffer()
 	encodingTypes := []int{0, 1, 2, 3} // Different encoding types
 
 	for _, r := range s {
-		encodingType := encodingTypes[rand.Intn(len(encodingTypes))]
+		encodingType := encodingTypes[rng.Intn(len(encodingTypes))]
 		switch encodingType {
 		case 0:
 			result.WriteRune(r) // Raw character
 		case 1:
-			result.WriteString(fmt.Sprintf(`\u%04X`, r)) // JS Unicode
+			fmt.Fprintf(result, `\u%04X`, r) // JS Unicode
 		case 2:
-			result.WriteString(fmt.Sprintf(`&#x%X;`, r)) // HTML hex entity
+			fmt.Fprintf(result, `&#x%X;`, r) // HTML hex entity
 		case 3:
-			result.WriteString(fmt.Sprintf(`&#%d;`, r)) // HTML decimal entity
+			fmt.Fprintf(result, `&#%d;`, r) // HTML decimal entity
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // mixedEncodingStrategyAdvanced creates a string with mixed encoding including advanced bypasses
-func mixedEncodingStrategyAdvanced(s string) string {
-	var result strings.Builder
+func mixedEncodingStrategyAdvanced(rng evasions.Rand, s string) string {
+	result := newBuffer()
 	encodingTypes := []int{0, 1, 2, 3, 4, 5} // Different encoding types
 
 	for _, r := range s {
-		encodingType := encodingTypes[rand.Intn(len(encodingTypes))]
+		encodingType := encodingTypes[rng.Intn(len(encodingTypes))]
 		switch encodingType {
 		case 0:
 			result.WriteRune(r) // Raw character
 		case 1:
-			result.WriteString(fmt.Sprintf(`\u%04X`, r)) // JS Unicode
+			fmt.Fprintf(result, `\u%04X`, r) // JS Unicode
 		case 2:
-			result.WriteString(fmt.Sprintf(`&#x%X;`, r)) // HTML hex entity
+			fmt.Fprintf(result, `&#x%X;`, r) // HTML hex entity
 		case 3:
-			result.WriteString(fmt.Sprintf(`&#%d;`, r)) // HTML decimal entity
+			fmt.Fprintf(result, `&#%d;`, r) // HTML decimal entity
 		case 4:
 			// Double encoding - HTML entity inside a JS Unicode escape
-			result.WriteString(fmt.Sprintf(`\u%04X`, []rune("&#" + fmt.Sprintf("%d", r) + ";")[0]))
+			fmt.Fprintf(result, `\u%04X`, []rune("&#" + fmt.Sprintf("%d", r) + ";")[0])
 		case 5:
 			// CSS Unicode escape
-			result.WriteString(fmt.Sprintf(`\\%X `, r))
+			fmt.Fprintf(result, `\\%X `, r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // addBidirectionalOverrides adds bidirectional text control characters
@@ -224,7 +228,7 @@ func addBidirectionalOverrides(s string) string {
 }
 
 // substituteHomoglyphs replaces characters with similar-looking Unicode characters
-func substituteHomoglyphs(s string) string {
+func substituteHomoglyphs(rng evasions.Rand, s string) string {
 	homoglyphs := map[rune]rune{
 		'a': 'а', // Cyrillic 'а' instead of Latin 'a'
 		'e': 'е', // Cyrillic 'е' instead of Latin 'e'
@@ -238,19 +242,19 @@ func substituteHomoglyphs(s string) string {
 		'y': 'у', // Cyrillic 'у' instead of Latin 'y'
 	}
 
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
-		if replacement, ok := homoglyphs[r]; ok && rand.Intn(2) == 0 {
+		if replacement, ok := homoglyphs[r]; ok && rng.Intn(2) == 0 {
 			result.WriteRune(replacement)
 		} else {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // addCombiningMarks adds combining diacritical marks to characters
-func addCombiningMarks(s string) string {
+func addCombiningMarks(rng evasions.Rand, s string) string {
 	combiningMarks := []rune{
 		'\u0300', // Combining grave accent
 		'\u0301', // Combining acute accent
@@ -264,21 +268,21 @@ func addCombiningMarks(s string) string {
 		'\u0309', // Combining hook above
 	}
 
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		result.WriteRune(r)
 		// Randomly add 1-3 combining marks
-		numMarks := rand.Intn(3) + 1
+		numMarks := rng.Intn(3) + 1
 		for i := 0; i < numMarks; i++ {
-			mark := combiningMarks[rand.Intn(len(combiningMarks))]
+			mark := combiningMarks[rng.Intn(len(combiningMarks))]
 			result.WriteRune(mark)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // addInvisibleControls adds invisible control characters between visible characters
-func addInvisibleControls(s string) string {
+func addInvisibleControls(rng evasions.Rand, s string) string {
 	controls := []string{
 		"\u200B", // Zero-width space
 		"\u200C", // Zero-width non-joiner
@@ -289,24 +293,24 @@ func addInvisibleControls(s string) string {
 		"\uFEFF", // Zero-width no-break space (BOM)
 	}
 
-	var result strings.Builder
+	result := newBuffer()
 	for i, r := range s {
 		result.WriteRune(r)
 		// Don't add after the last character
 		if i < len([]rune(s))-1 {
 			// Add 1-3 control characters
-			numControls := rand.Intn(3) + 1
+			numControls := rng.Intn(3) + 1
 			for j := 0; j < numControls; j++ {
-				control := controls[rand.Intn(len(controls))]
+				control := controls[rng.Intn(len(controls))]
 				result.WriteString(control)
 			}
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
-func normalizedVariants(s string) string {
-	var result strings.Builder
+func normalizedVariants(rng evasions.Rand, s string) string {
+	result := newBuffer()
 	for _, r := range s {
 		// TODO: Add more here !!
 		normalizedMap := map[string]string{
@@ -320,7 +324,7 @@ func normalizedVariants(s string) string {
 		switch r {
 		case 'a', 'e', 'i', 'o', 'u', 'n':
 			// Decompose selected characters with a 50% chance
-			if rand.Intn(2) == 0 {
+			if rng.Intn(2) == 0 {
 				switch r {
 				case 'a':
 					result.WriteString(normalizedMap["a\\u0301"])
@@ -342,7 +346,7 @@ func normalizedVariants(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // caseFolding replaces characters with their case folding equivalents
@@ -359,7 +363,7 @@ func caseFolding(s string) string {
 		'ﬄ': "ffl", // Latin small ligature ffl → ffl
 	}
 
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		if replacement, ok := specialCaseFolding[r]; ok {
 			result.WriteString(replacement)
@@ -371,7 +375,7 @@ func caseFolding(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // rtlOverride adds right-to-left override characters to reverse text
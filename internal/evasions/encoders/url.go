@@ -11,6 +11,9 @@ import (
 // URLVariants generates various URL encoded variants of the input payload
 // based on the specified obfuscation level
 func URLVariants(payload string, level types.EvasionLevel) []string {
+	if payload == "" {
+		return []string{}
+	}
 	var variants []string
 
 	// Basic URL encoding
@@ -29,12 +32,11 @@ func URLVariants(payload string, level types.EvasionLevel) []string {
 		manualUpper, // Manual uppercase %XX
 	)
 
-	// For safe strings, add forced encodings
-	if urlEncoded == payload {
-		// Force encode all characters
-		variants = append(variants, forceURLEncode(payload, false))
-		variants = append(variants, forceURLEncode(payload, true))
-	}
+	// Full encoding, safe characters included
+	variants = append(variants,
+		forceURLEncode(payload, false),
+		forceURLEncode(payload, true),
+	)
 
 	// Return basic variants if level is Basic
 	if level == types.EvasionLevelBasic {
@@ -67,24 +69,44 @@ func URLVariants(payload string, level types.EvasionLevel) []string {
 		unicodeNormalizationEncode(payload), // Unicode normalization attacks
 	)
 
+	// Only the separators encoded, for WAFs that split paths on a raw / or
+	// parameters on a raw = before decoding
+	for _, separator := range []byte{'/', '='} {
+		if strings.IndexByte(payload, separator) >= 0 {
+			variants = append(variants,
+				encodeOnly(payload, separator, false),
+				encodeOnly(payload, separator, true),
+			)
+		}
+	}
+
 	return evasions.UniqueStrings(variants)
 }
 
+// encodeOnly percent-encodes every c in s and leaves the rest raw
+func encodeOnly(s string, c byte, uppercase bool) string {
+	format := "%%%02x"
+	if uppercase {
+		format = "%%%02X"
+	}
+	return strings.ReplaceAll(s, string(c), fmt.Sprintf(format, c))
+}
+
 // manualURLEncode manually URL encodes characters with specified case
 func manualURLEncode(s string, uppercase bool) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, b := range []byte(s) {
 		if shouldEncode(b) {
 			if uppercase {
-				result.WriteString(fmt.Sprintf("%%%02X", b))
+				fmt.Fprintf(result, "%%%02X", b)
 			} else {
-				result.WriteString(fmt.Sprintf("%%%02x", b))
+				fmt.Fprintf(result, "%%%02x", b)
 			}
 		} else {
 			result.WriteByte(b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // shouldEncode determines if a character should be URL encoded
@@ -95,69 +117,69 @@ func shouldEncode(b byte) bool {
 
 // forceURLEncode forces URL encoding of all characters, even safe ones
 func forceURLEncode(s string, uppercase bool) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, b := range []byte(s) {
 		if uppercase {
-			result.WriteString(fmt.Sprintf("%%%02X", b))
+			fmt.Fprintf(result, "%%%02X", b)
 		} else {
-			result.WriteString(fmt.Sprintf("%%%02x", b))
+			fmt.Fprintf(result, "%%%02x", b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // partialURLEncode encodes only a percentage of characters
 func partialURLEncode(s string, ratio float64) string {
-	var result strings.Builder
+	result := newBuffer()
 	bytes := []byte(s)
 	encodeCount := int(float64(len(bytes)) * ratio)
 	encoded := 0
 
 	for i, b := range bytes {
 		if shouldEncode(b) && encoded < encodeCount && i%2 == 0 {
-			result.WriteString(fmt.Sprintf("%%%02x", b))
+			fmt.Fprintf(result, "%%%02x", b)
 			encoded++
 		} else {
 			result.WriteByte(b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // mixedCaseURLEncode creates mixed case URL encoding
 func mixedCaseURLEncode(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for i, b := range []byte(s) {
 		if shouldEncode(b) {
 			if i%2 == 0 {
-				result.WriteString(fmt.Sprintf("%%%02x", b))
+				fmt.Fprintf(result, "%%%02x", b)
 			} else {
-				result.WriteString(fmt.Sprintf("%%%02X", b))
+				fmt.Fprintf(result, "%%%02X", b)
 			}
 		} else {
 			result.WriteByte(b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // unicodeURLEncode encodes using Unicode percent encoding
 func unicodeURLEncode(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		if r > 127 {
 			// Encode non-ASCII as UTF-8 percent encoding
 			utf8Bytes := []byte(string(r))
 			for _, b := range utf8Bytes {
-				result.WriteString(fmt.Sprintf("%%%02X", b))
+				fmt.Fprintf(result, "%%%02X", b)
 			}
 		} else if shouldEncode(byte(r)) {
-			result.WriteString(fmt.Sprintf("%%%02X", byte(r)))
+			fmt.Fprintf(result, "%%%02X", byte(r))
 		} else {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // plusSpaceEncode encodes spaces as + instead of %20
@@ -168,42 +190,42 @@ func plusSpaceEncode(s string) string {
 
 // malformedURLEncode creates malformed URL encodings
 func malformedURLEncode(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, b := range []byte(s) {
 		if shouldEncode(b) {
 			switch b % 4 {
 			case 0:
-				result.WriteString(fmt.Sprintf("%%%02x", b)) // Normal
+				fmt.Fprintf(result, "%%%02x", b) // Normal
 			case 1:
-				result.WriteString(fmt.Sprintf("%%0%x", b)) // Missing leading zero
+				fmt.Fprintf(result, "%%0%x", b) // Missing leading zero
 			case 2:
-				result.WriteString(fmt.Sprintf("%%%x", b)) // Single digit
+				fmt.Fprintf(result, "%%%x", b) // Single digit
 			case 3:
-				result.WriteString(fmt.Sprintf("%%%02X", b)) // Uppercase
+				fmt.Fprintf(result, "%%%02X", b) // Uppercase
 			}
 		} else {
 			result.WriteByte(b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // overloadedURLEncode creates overloaded URL encodings
 func overloadedURLEncode(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, b := range []byte(s) {
 		if shouldEncode(b) {
-			result.WriteString(fmt.Sprintf("%%%02x", b))
+			fmt.Fprintf(result, "%%%02x", b)
 		} else {
 			// Encode even safe characters sometimes
 			if b%3 == 0 {
-				result.WriteString(fmt.Sprintf("%%%02x", b))
+				fmt.Fprintf(result, "%%%02x", b)
 			} else {
 				result.WriteByte(b)
 			}
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // nullByteURLEncode injects null bytes in URL encoding
@@ -219,39 +241,39 @@ func nullByteURLEncode(s string) string {
 
 // tabNewlineURLEncode uses tab and newline in encoding
 func tabNewlineURLEncode(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for i, b := range []byte(s) {
 		if shouldEncode(b) {
 			if i%5 == 0 {
-				result.WriteString(fmt.Sprintf("%%09%%%02x", b)) // Tab prefix
+				fmt.Fprintf(result, "%%09%%%02x", b) // Tab prefix
 			} else if i%7 == 0 {
-				result.WriteString(fmt.Sprintf("%%0A%%%02x", b)) // Newline prefix
+				fmt.Fprintf(result, "%%0A%%%02x", b) // Newline prefix
 			} else {
-				result.WriteString(fmt.Sprintf("%%%02x", b))
+				fmt.Fprintf(result, "%%%02x", b)
 			}
 		} else {
 			result.WriteByte(b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // backslashURLEncode uses backslash variations
 func backslashURLEncode(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, b := range []byte(s) {
 		if shouldEncode(b) {
-			result.WriteString(fmt.Sprintf("\\x%02x", b))
+			fmt.Fprintf(result, "\\x%02x", b)
 		} else {
 			result.WriteByte(b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // unicodeNormalizationEncode uses Unicode normalization attacks
 func unicodeNormalizationEncode(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		switch r {
 		case '<':
@@ -266,11 +288,11 @@ func unicodeNormalizationEncode(s string) string {
 			result.WriteString("%u0026")
 		default:
 			if shouldEncode(byte(r)) {
-				result.WriteString(fmt.Sprintf("%%%02x", byte(r)))
+				fmt.Fprintf(result, "%%%02x", byte(r))
 			} else {
 				result.WriteRune(r)
 			}
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
@@ -78,11 +78,54 @@ func TestURLVariants(t *testing.T) {
 		{
 			name:     "Empty payload",
 			payload:  "",
+			level:    types.EvasionLevelAdvanced,
+			minCount: 0,
+			checks: []func([]string) bool{
+				func(variants []string) bool {
+					return variants != nil && len(variants) == 0
+				},
+			},
+		},
+		{
+			name:     "Basic level fully encodes payloads with special characters",
+			payload:  "<a>",
 			level:    types.EvasionLevelBasic,
-			minCount: 1,
+			minCount: 2,
 			checks: []func([]string) bool{
 				func(variants []string) bool {
-					return len(variants) >= 1
+					return containsVariant(variants, "%3c%61%3e") && containsVariant(variants, "%3C%61%3E")
+				},
+				func(variants []string) bool {
+					// Only the special characters encoded
+					return containsVariant(variants, "%3ca%3e")
+				},
+			},
+		},
+		{
+			name:     "Medium level varies hex case and space encoding",
+			payload:  "a b/c",
+			level:    types.EvasionLevelMedium,
+			minCount: 4,
+			checks: []func([]string) bool{
+				func(variants []string) bool {
+					return containsVariant(variants, "a%20b%2fc") && containsVariant(variants, "a%20b%2Fc")
+				},
+				func(variants []string) bool {
+					return containsVariant(variants, "a+b%2fc")
+				},
+			},
+		},
+		{
+			name:     "Advanced level encodes only the slashes or equals signs",
+			payload:  "../etc/passwd?x=1",
+			level:    types.EvasionLevelAdvanced,
+			minCount: 8,
+			checks: []func([]string) bool{
+				func(variants []string) bool {
+					return containsVariant(variants, "..%2Fetc%2Fpasswd?x=1") && containsVariant(variants, "..%2fetc%2fpasswd?x=1")
+				},
+				func(variants []string) bool {
+					return containsVariant(variants, "../etc/passwd?x%3D1")
 				},
 			},
 		},
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
-	"strings"
 	"unicode/utf8"
 )
 
@@ -58,85 +57,85 @@ func UTF8Variants(payload string, level types.EvasionLevel) []string {
 
 // utf8HexEncoding encodes UTF-8 bytes as hex
 func utf8HexEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		utf8Bytes := []byte(string(r))
 		for _, b := range utf8Bytes {
-			result.WriteString(fmt.Sprintf("\\x%02x", b))
+			fmt.Fprintf(result, "\\x%02x", b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8OctalEncoding encodes UTF-8 bytes as octal
 func utf8OctalEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		utf8Bytes := []byte(string(r))
 		for _, b := range utf8Bytes {
-			result.WriteString(fmt.Sprintf("\\%03o", b))
+			fmt.Fprintf(result, "\\%03o", b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8DecimalEncoding encodes UTF-8 bytes as decimal
 func utf8DecimalEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		utf8Bytes := []byte(string(r))
 		for _, b := range utf8Bytes {
-			result.WriteString(fmt.Sprintf("&#%d;", b))
+			fmt.Fprintf(result, "&#%d;", b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8BinaryEncoding encodes UTF-8 bytes as binary
 func utf8BinaryEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		utf8Bytes := []byte(string(r))
 		for _, b := range utf8Bytes {
-			result.WriteString(fmt.Sprintf("\\b%08b", b))
+			fmt.Fprintf(result, "\\b%08b", b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8PercentEncoding encodes UTF-8 bytes with percent encoding
 func utf8PercentEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		utf8Bytes := []byte(string(r))
 		for _, b := range utf8Bytes {
-			result.WriteString(fmt.Sprintf("%%%02X", b))
+			fmt.Fprintf(result, "%%%02X", b)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8OverlongEncoding creates overlong UTF-8 sequences
 func utf8OverlongEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		if r < 128 {
 			// Create overlong encoding for ASCII characters
 			// 2-byte overlong: 110xxxxx 10xxxxxx
 			b1 := 0xC0 | (byte(r) >> 6)
 			b2 := 0x80 | (byte(r) & 0x3F)
-			result.WriteString(fmt.Sprintf("\\x%02x\\x%02x", b1, b2))
+			fmt.Fprintf(result, "\\x%02x\\x%02x", b1, b2)
 		} else {
 			// Normal encoding for non-ASCII
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8NormalizationC applies NFC normalization variants
 func utf8NormalizationC(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		switch r {
 		case 'é':
@@ -149,12 +148,12 @@ func utf8NormalizationC(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8NormalizationD applies NFD normalization variants
 func utf8NormalizationD(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		switch r {
 		case 'a':
@@ -167,44 +166,44 @@ func utf8NormalizationD(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8MixedEncoding mixes different UTF-8 encoding styles
 func utf8MixedEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for i, r := range s {
 		utf8Bytes := []byte(string(r))
 		switch i % 4 {
 		case 0:
 			for _, b := range utf8Bytes {
-				result.WriteString(fmt.Sprintf("\\x%02x", b))
+				fmt.Fprintf(result, "\\x%02x", b)
 			}
 		case 1:
 			for _, b := range utf8Bytes {
-				result.WriteString(fmt.Sprintf("\\%03o", b))
+				fmt.Fprintf(result, "\\%03o", b)
 			}
 		case 2:
 			for _, b := range utf8Bytes {
-				result.WriteString(fmt.Sprintf("&#%d;", b))
+				fmt.Fprintf(result, "&#%d;", b)
 			}
 		case 3:
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8NullByteEncoding injects null bytes
 func utf8NullByteEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for i, r := range s {
 		result.WriteRune(r)
 		if i%3 == 0 {
 			result.WriteString("\\x00") // Inject null byte
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8BOMVariants adds Byte Order Mark variants
@@ -215,50 +214,50 @@ func utf8BOMVariants(s string) string {
 
 // utf8MalformedSequences creates malformed UTF-8 sequences
 func utf8MalformedSequences(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for i, r := range s {
 		if i%5 == 0 && r < 128 {
 			// Create malformed sequence: start byte without continuation
-			result.WriteString(fmt.Sprintf("\\x%02x", 0xC0|byte(r)))
+			fmt.Fprintf(result, "\\x%02x", 0xC0|byte(r))
 		} else {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8SurrogateEncoding uses surrogate pair encoding
 func utf8SurrogateEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		if r > 0xFFFF {
 			// Convert to surrogate pairs
 			r -= 0x10000
 			high := 0xD800 + (r >> 10)
 			low := 0xDC00 + (r & 0x3FF)
-			result.WriteString(fmt.Sprintf("\\u%04X\\u%04X", high, low))
+			fmt.Fprintf(result, "\\u%04X\\u%04X", high, low)
 		} else {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8ReplacementChar injects replacement characters
 func utf8ReplacementChar(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for i, r := range s {
 		result.WriteRune(r)
 		if i%4 == 0 {
 			result.WriteRune('\uFFFD') // Unicode replacement character
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8ControlCharEncoding injects control characters
 func utf8ControlCharEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	controlChars := []rune{'\u0000', '\u0001', '\u0002', '\u0003', '\u0004', '\u0005'}
 	for i, r := range s {
 		result.WriteRune(r)
@@ -266,12 +265,12 @@ func utf8ControlCharEncoding(s string) string {
 			result.WriteRune(controlChars[i%len(controlChars)])
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8ZeroWidthEncoding injects zero-width characters
 func utf8ZeroWidthEncoding(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	zeroWidthChars := []rune{'\u200B', '\u200C', '\u200D', '\uFEFF'}
 	for i, r := range s {
 		result.WriteRune(r)
@@ -279,12 +278,12 @@ func utf8ZeroWidthEncoding(s string) string {
 			result.WriteRune(zeroWidthChars[i%len(zeroWidthChars)])
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8DirectionalMarks injects bidirectional text marks
 func utf8DirectionalMarks(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	dirMarks := []rune{'\u202A', '\u202B', '\u202C', '\u202D', '\u202E'}
 	for i, r := range s {
 		result.WriteRune(r)
@@ -292,12 +291,12 @@ func utf8DirectionalMarks(s string) string {
 			result.WriteRune(dirMarks[i%len(dirMarks)])
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // utf8CompatibilityChars uses Unicode compatibility characters
 func utf8CompatibilityChars(s string) string {
-	var result strings.Builder
+	result := newBuffer()
 	for _, r := range s {
 		switch r {
 		case 'A':
@@ -314,7 +313,7 @@ func utf8CompatibilityChars(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return releaseBuffer(result)
 }
 
 // isValidUTF8 checks if a byte sequence is valid UTF-8
@@ -0,0 +1,120 @@
+package encoders
+
+import (
+	"encoding/base64"
+	"strings"
+	"unicode/utf16"
+
+	"obfuskit/internal/evasions"
+	"obfuskit/types"
+)
+
+// utf7Base64 is the modified Base64 of RFC 2152: no padding
+var utf7Base64 = base64.StdEncoding.WithPadding(base64.NoPadding)
+
+// UTF7Variants generates UTF-7 forms of the payload (<script> becomes
+// +ADw-script+AD4-). Filters that read the bytes as ASCII or UTF-8 see no
+// markup, while a browser or parser that sniffs or is told the charset is
+// UTF-7 decodes it back.
+func UTF7Variants(payload string, level types.EvasionLevel) []string {
+	var variants []string
+
+	// Basic: shift out of ASCII only for the characters RFC 2152 does not
+	// require to be sent directly (<, >, ", ;, ...)
+	standard := utf7Encode(payload, utf7Optional, true)
+	variants = append(variants, standard)
+
+	if level == types.EvasionLevelBasic {
+		return evasions.UniqueStrings(variants)
+	}
+
+	variants = append(variants,
+		utf7EncodeEach(payload),                  // One shift per character (+ADw-+AC8-)
+		utf7Encode(payload, utf7Always, true),    // The whole payload in one shift
+		utf7Encode(payload, utf7Optional, false), // '-' dropped where RFC 2152 allows
+		utf7Encode(payload, utf7NonAlnum, true),  // Everything but letters and digits
+	)
+
+	if level == types.EvasionLevelMedium {
+		return evasions.UniqueStrings(variants)
+	}
+
+	// Advanced: suggest the charset, via the UTF-7 byte order mark that
+	// legacy sniffers act on or a meta tag ahead of the payload
+	variants = append(variants,
+		"+/v8-"+standard,
+		`<meta http-equiv="Content-Type" content="text/html; charset=utf-7">`+standard,
+	)
+
+	return evasions.UniqueStrings(variants)
+}
+
+// utf7Optional shifts the RFC 2152 "optional direct" characters, '+' and
+// anything outside the directly encoded set
+func utf7Optional(r rune) bool {
+	if r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+		return false
+	}
+	return !strings.ContainsRune("'(),-./:? \t\r\n", r)
+}
+
+func utf7Always(rune) bool { return true }
+
+func utf7NonAlnum(r rune) bool {
+	return !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+}
+
+// utf7Encode encodes each run of characters selected by shift as one UTF-7
+// base64 block. With terminate false, the closing '-' is written only when
+// the next character would otherwise be read as part of the block.
+func utf7Encode(payload string, shift func(rune) bool, terminate bool) string {
+	runes := []rune(payload)
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		if !shift(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && shift(runes[j]) {
+			j++
+		}
+		b.WriteString(utf7Block(runes[i:j]))
+		if terminate || j == len(runes) || utf7NeedsTerminator(runes[j]) {
+			b.WriteByte('-')
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// utf7EncodeEach gives every shifted character its own block
+func utf7EncodeEach(payload string) string {
+	var b strings.Builder
+	for _, r := range payload {
+		if utf7Optional(r) {
+			b.WriteString(utf7Block([]rune{r}) + "-")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// utf7Block is "+" followed by the modified Base64 of runes as UTF-16BE
+func utf7Block(runes []rune) string {
+	units := utf16.Encode(runes)
+	raw := make([]byte, 0, 2*len(units))
+	for _, u := range units {
+		raw = append(raw, byte(u>>8), byte(u))
+	}
+	return "+" + utf7Base64.EncodeToString(raw)
+}
+
+// utf7NeedsTerminator reports whether r following a block would be decoded
+// as part of it
+func utf7NeedsTerminator(r rune) bool {
+	return r == '-' || r == '+' || r == '/' ||
+		r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9'
+}
@@ -0,0 +1,34 @@
+package encoders
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"obfuskit/types"
+)
+
+func TestUTF7Variants(t *testing.T) {
+	payload := "<script>alert(1)</script>"
+
+	basic := UTF7Variants(payload, types.EvasionLevelBasic)
+	if !slices.Contains(basic, "+ADw-script+AD4-alert(1)+ADw-/script+AD4-") {
+		t.Errorf("basic variants lack the standard UTF-7 form: %v", basic)
+	}
+
+	advanced := UTF7Variants(payload, types.EvasionLevelAdvanced)
+	for _, v := range advanced {
+		if strings.Contains(v, "<script") {
+			t.Errorf("variant %q still carries a literal <script", v)
+		}
+	}
+	if !slices.ContainsFunc(advanced, func(v string) bool { return strings.HasPrefix(v, "+ADw-script") }) {
+		t.Errorf("no variant encodes < as +ADw-: %v", advanced)
+	}
+	if !slices.ContainsFunc(advanced, func(v string) bool { return strings.HasPrefix(v, "+/v8-") }) {
+		t.Errorf("advanced variants lack the UTF-7 byte order mark: %v", advanced)
+	}
+	if len(advanced) <= len(basic) {
+		t.Errorf("advanced level gave %d variants, basic %d", len(advanced), len(basic))
+	}
+}
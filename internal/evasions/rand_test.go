@@ -0,0 +1,54 @@
+package evasions
+
+import "testing"
+
+func TestNewRandStreamsDifferForNeighboringSeeds(t *testing.T) {
+	stream := func(seed int64, worker int) [4]int {
+		rng := NewRand(seed, worker)
+		var draws [4]int
+		for i := range draws {
+			draws[i] = rng.Intn(1 << 30)
+		}
+		return draws
+	}
+	if stream(7, 2) != stream(7, 2) {
+		t.Error("the same seed and worker gave different streams")
+	}
+	// seed+worker would give these the same stream
+	if stream(1, 0) == stream(0, 1) {
+		t.Error("seed 1 worker 0 and seed 0 worker 1 share a stream")
+	}
+	if stream(5, 0) == stream(5, 1) {
+		t.Error("workers of one seed share a stream")
+	}
+}
+
+func TestEnumerateDrawsEmitsEveryAlternativeOfEveryDraw(t *testing.T) {
+	separators := []string{";", "&&", "||", "|"}
+	commands := []string{"id", "whoami", "uname"}
+	got := make(map[string]int)
+	EnumerateDraws(func(rng Rand) {
+		got[separators[rng.Intn(len(separators))]+commands[rng.Intn(len(commands))]]++
+	})
+
+	// Each alternative of each draw, the other draw at its first
+	want := []string{";id", "&&id", "||id", "|id", ";whoami", ";uname"}
+	if len(got) != len(want) {
+		t.Errorf("EnumerateDraws generated %v, want %v", got, want)
+	}
+	for _, value := range want {
+		if got[value] == 0 {
+			t.Errorf("EnumerateDraws did not generate %q", value)
+		}
+	}
+
+	// Large ranges are capped
+	calls := 0
+	EnumerateDraws(func(rng Rand) {
+		rng.Intn(1000)
+		calls++
+	})
+	if calls != MaxEnumeratedAlternatives {
+		t.Errorf("a draw over 1000 values was enumerated %d times, want %d", calls, MaxEnumeratedAlternatives)
+	}
+}
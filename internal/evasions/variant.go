@@ -0,0 +1,50 @@
+package evasions
+
+import "obfuskit/types"
+
+// UniqueVariants drops variants whose Value was already seen, keeping the first
+func UniqueVariants(input []types.Variant) []types.Variant {
+	seen := map[string]struct{}{}
+	var result []types.Variant
+
+	for _, v := range input {
+		if _, ok := seen[v.Value]; !ok {
+			seen[v.Value] = struct{}{}
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// Values returns the bare variant strings
+func Values(variants []types.Variant) []string {
+	if variants == nil {
+		return nil
+	}
+	values := make([]string, len(variants))
+	for i, v := range variants {
+		values[i] = v.Value
+	}
+	return values
+}
+
+// SampleDistinct keeps at most k variants per Technique, in order, so a
+// tight request budget is spent on breadth rather than on many variants of
+// one technique. k <= 0 keeps everything.
+func SampleDistinct(input []types.Variant, k int) []types.Variant {
+	if k <= 0 {
+		return input
+	}
+	counts := map[string]int{}
+	var result []types.Variant
+
+	for _, v := range input {
+		if counts[v.Technique] < k {
+			counts[v.Technique]++
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,138 @@
+package genai
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AnalyticsCollector tracks AI generation performance and costs. It is safe
+// for concurrent use; read it through Summary.
+type AnalyticsCollector struct {
+	mu sync.Mutex
+
+	TotalRequests      int64           `json:"total_requests"`
+	SuccessfulRequests int64           `json:"successful_requests"`
+	TotalTokens        int64           `json:"total_tokens"`
+	TotalCost          float64         `json:"total_cost"`
+	AverageQuality     float64         `json:"average_quality"`
+	SuccessRate        float64         `json:"success_rate"`
+	GenerationTimes    []time.Duration `json:"-"`
+
+	// qualitySum and rated add up the confidence of every generated payload
+	// for AverageQuality
+	qualitySum float64
+	rated      int64
+}
+
+// AnalyticsSummary is a snapshot of an AnalyticsCollector
+type AnalyticsSummary struct {
+	Requests           int64
+	SuccessfulRequests int64
+	Tokens             int64
+	Cost               float64
+	// AverageLatency is the mean of the generation times
+	AverageLatency time.Duration
+	// SuccessRate is the share of requests that returned payloads, 0 to 1
+	SuccessRate float64
+	// AverageQuality is the mean confidence of the generated payloads
+	AverageQuality float64
+}
+
+// RecordGeneration records a generation request that returned payloads,
+// with the tokens it used and their cost
+func (a *AnalyticsCollector) RecordGeneration(duration time.Duration, payloads []GeneratedPayload, tokens int, cost float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.record(duration)
+	if len(payloads) > 0 {
+		a.SuccessfulRequests++
+	}
+	a.TotalTokens += int64(tokens)
+	a.TotalCost += cost
+	for _, payload := range payloads {
+		a.qualitySum += payload.Confidence
+		a.rated++
+	}
+	a.update()
+}
+
+// RecordFailure records a generation request that failed
+func (a *AnalyticsCollector) RecordFailure(duration time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.record(duration)
+	a.update()
+}
+
+func (a *AnalyticsCollector) record(duration time.Duration) {
+	a.TotalRequests++
+	a.GenerationTimes = append(a.GenerationTimes, duration)
+}
+
+// update recomputes the averages; a.mu must be held
+func (a *AnalyticsCollector) update() {
+	a.SuccessRate = float64(a.SuccessfulRequests) / float64(a.TotalRequests)
+	if a.rated > 0 {
+		a.AverageQuality = a.qualitySum / float64(a.rated)
+	}
+}
+
+// AverageGenerationTime returns the mean of the recorded generation times
+func (a *AnalyticsCollector) AverageGenerationTime() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.averageGenerationTime()
+}
+
+func (a *AnalyticsCollector) averageGenerationTime() time.Duration {
+	if len(a.GenerationTimes) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, duration := range a.GenerationTimes {
+		total += duration
+	}
+	return total / time.Duration(len(a.GenerationTimes))
+}
+
+// Summary returns a consistent snapshot of the analytics
+func (a *AnalyticsCollector) Summary() AnalyticsSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return AnalyticsSummary{
+		Requests:           a.TotalRequests,
+		SuccessfulRequests: a.SuccessfulRequests,
+		Tokens:             a.TotalTokens,
+		Cost:               a.TotalCost,
+		AverageLatency:     a.averageGenerationTime(),
+		SuccessRate:        a.SuccessRate,
+		AverageQuality:     a.AverageQuality,
+	}
+}
+
+// FormatSummary returns the analytics formatted for display at the end of a run
+func (a *AnalyticsCollector) FormatSummary() string {
+	summary := a.Summary()
+	cost := "not tracked (set cost_per_1k_tokens)"
+	if summary.Cost > 0 {
+		cost = fmt.Sprintf("$%.4f", summary.Cost)
+	}
+	return fmt.Sprintf(`
+🤖 GENAI ANALYTICS
+==================
+  Requests: %d (%d successful)
+  Success Rate: %.1f%%
+  Tokens Used: %d
+  Estimated Cost: %s
+  Average Latency: %v
+  Average Quality: %.2f
+`,
+		summary.Requests, summary.SuccessfulRequests,
+		summary.SuccessRate*100,
+		summary.Tokens,
+		cost,
+		summary.AverageLatency.Round(time.Millisecond),
+		summary.AverageQuality,
+	)
+}
@@ -0,0 +1,77 @@
+package genai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"obfuskit/types"
+)
+
+func TestAnalyticsSummarizesConcurrentGenerations(t *testing.T) {
+	// An Ollama-style endpoint that fails every fourth request
+	var served atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if served.Add(1)%4 == 0 {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		content := `{"payloads": [{"payload": "<svg onload=alert(1)>", "confidence": 0.8}, {"payload": "<img src=x onerror=alert(1)>", "confidence": 0.6}]}`
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response":          content,
+			"prompt_eval_count": 100,
+			"eval_count":        50,
+		})
+	}))
+	defer server.Close()
+
+	engine := NewEngine(&Config{
+		Provider:        "local",
+		Model:           "test",
+		APIEndpoint:     server.URL,
+		EnableAnalytics: true,
+		CostPer1KTokens: 0.002,
+	})
+
+	const generations = 8
+	var wg sync.WaitGroup
+	for i := 0; i < generations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			engine.GeneratePayloads(&PayloadGenerationRequest{AttackType: types.AttackTypeXSS, Count: 2})
+		}()
+	}
+	wg.Wait()
+
+	summary := engine.GetAnalytics().Summary()
+	if summary.Requests != generations || summary.SuccessfulRequests != 6 {
+		t.Fatalf("got %d requests, %d successful; want %d, 6", summary.Requests, summary.SuccessfulRequests, generations)
+	}
+	if summary.Tokens != 6*150 {
+		t.Errorf("Tokens = %d, want %d", summary.Tokens, 6*150)
+	}
+	if want := 0.75; summary.SuccessRate != want {
+		t.Errorf("SuccessRate = %v, want %v", summary.SuccessRate, want)
+	}
+	if want := 6 * 0.15 * 0.002; summary.Cost < want*0.999 || summary.Cost > want*1.001 {
+		t.Errorf("Cost = %v, want %v", summary.Cost, want)
+	}
+	if summary.AverageQuality < 0.69 || summary.AverageQuality > 0.71 {
+		t.Errorf("AverageQuality = %v, want the mean confidence 0.7", summary.AverageQuality)
+	}
+	if summary.AverageLatency <= 0 {
+		t.Errorf("AverageLatency = %v, want the mean generation time", summary.AverageLatency)
+	}
+
+	formatted := engine.GetAnalytics().FormatSummary()
+	for _, want := range []string{"Requests: 8 (6 successful)", "Success Rate: 75.0%", "Tokens Used: 900", "Estimated Cost: $0.0018"} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("summary lacks %q:\n%s", want, formatted)
+		}
+	}
+}
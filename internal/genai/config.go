@@ -2,6 +2,7 @@ package genai
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +22,17 @@ var DefaultConfigs = map[string]*Config{
 		EnableAnalytics:     true,
 		EnableQualityFilter: true,
 	},
+	"azure": {
+		Provider:            "azure",
+		Model:               "gpt-4o-mini",
+		MaxTokens:           1500,
+		Temperature:         0.7,
+		Timeout:             60 * time.Second,
+		MaxRetries:          3,
+		EnableCaching:       true,
+		EnableAnalytics:     true,
+		EnableQualityFilter: true,
+	},
 	"anthropic": {
 		Provider:            "anthropic",
 		Model:               "claude-3-sonnet-20240229",
@@ -131,21 +143,56 @@ func loadConfigFromEnv() *Config {
 	}
 
 	// Provider-specific environment variables
+	apiKey, endpoint := providerEnv(provider)
+	if apiKey != "" {
+		config.APIKey = apiKey
+	}
+	if endpoint != "" {
+		config.APIEndpoint = endpoint
+	}
+
+	return config
+}
+
+// providerEnv returns the API key and endpoint set in the environment
+// variables specific to provider
+func providerEnv(provider string) (apiKey, endpoint string) {
 	switch provider {
 	case "openai":
-		if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
-			config.APIKey = apiKey
-		}
+		return os.Getenv("OPENAI_API_KEY"), ""
+	case "azure":
+		return os.Getenv("AZURE_OPENAI_API_KEY"), os.Getenv("AZURE_OPENAI_ENDPOINT")
 	case "anthropic":
-		if apiKey := os.Getenv("ANTHROPIC_API_KEY"); apiKey != "" {
-			config.APIKey = apiKey
-		}
+		return os.Getenv("ANTHROPIC_API_KEY"), ""
 	case "huggingface":
-		if apiKey := os.Getenv("HUGGINGFACE_API_KEY"); apiKey != "" {
-			config.APIKey = apiKey
-		}
+		return os.Getenv("HUGGINGFACE_API_KEY"), ""
 	}
+	return "", ""
+}
 
+// UseProvider switches config to provider and fills a missing API key or
+// endpoint from that provider's environment variables. The model, key and
+// endpoint of a config loaded for another provider are replaced by the new
+// provider's defaults rather than sent to it. An empty provider keeps the
+// loaded one.
+func UseProvider(config *Config, provider string) *Config {
+	if provider != "" && provider != config.Provider {
+		defaults := getDefaultConfig(provider)
+		switched := *config
+		switched.Provider = provider
+		switched.Model = defaults.Model
+		switched.APIKey = ""
+		switched.APIEndpoint = defaults.APIEndpoint
+		config = &switched
+	}
+
+	apiKey, endpoint := providerEnv(config.Provider)
+	if config.APIKey == "" {
+		config.APIKey = apiKey
+	}
+	if config.APIEndpoint == "" {
+		config.APIEndpoint = endpoint
+	}
 	return config
 }
 
@@ -243,7 +290,13 @@ func SaveConfig(config *Config, path string) error {
 	return nil
 }
 
-// ValidateConfig validates the configuration
+// ErrNotConfigured is wrapped by the ValidateConfig error of a config that is
+// valid but for the provider's missing API key or endpoint, the case
+// Engine.IsConfigured reports
+var ErrNotConfigured = errors.New("AI provider is not configured")
+
+// ValidateConfig validates the configuration. A config whose only problem
+// is a missing API key or endpoint gets an error wrapping ErrNotConfigured.
 func ValidateConfig(config *Config) error {
 	if config == nil {
 		return fmt.Errorf("config cannot be nil")
@@ -257,11 +310,19 @@ func ValidateConfig(config *Config) error {
 		return fmt.Errorf("model is required")
 	}
 
-	// Provider-specific validation
+	// Provider-specific validation. Missing credentials are reported after
+	// every other check, so that they never hide another error.
+	var notConfigured error
 	switch config.Provider {
 	case "openai", "anthropic", "huggingface":
 		if config.APIKey == "" {
-			return fmt.Errorf("API key is required for provider %s", config.Provider)
+			notConfigured = fmt.Errorf("%w: API key is required for provider %s", ErrNotConfigured, config.Provider)
+		}
+	case "azure":
+		if config.APIKey == "" {
+			notConfigured = fmt.Errorf("%w: API key is required for provider %s", ErrNotConfigured, config.Provider)
+		} else if config.APIEndpoint == "" {
+			notConfigured = fmt.Errorf("%w: API endpoint is required for azure provider", ErrNotConfigured)
 		}
 	case "local":
 		if config.APIEndpoint == "" {
@@ -283,6 +344,16 @@ func ValidateConfig(config *Config) error {
 		return fmt.Errorf("timeout must be positive")
 	}
 
+	if config.PromptTemplate != "" {
+		if _, err := parsePromptTemplate(config.PromptTemplate); err != nil {
+			return err
+		}
+	}
+
+	if notConfigured != nil {
+		return notConfigured
+	}
+
 	return nil
 }
 
@@ -304,6 +375,10 @@ func GetProviderModels() map[string][]string {
 			"gpt-3.5-turbo",
 			"gpt-3.5-turbo-16k",
 		},
+		"azure": {
+			// Azure requests name a deployment, not a model
+			"your-deployment-name",
+		},
 		"anthropic": {
 			"claude-3-opus-20240229",
 			"claude-3-sonnet-20240229",
@@ -335,6 +410,10 @@ func GenerateExampleConfig(provider string) *Config {
 	switch provider {
 	case "openai":
 		config.APIKey = "sk-your-openai-api-key-here"
+	case "azure":
+		config.APIKey = "your-azure-openai-api-key-here"
+		config.APIEndpoint = "https://your-resource.openai.azure.com"
+		config.Model = "your-deployment-name"
 	case "anthropic":
 		config.APIKey = "your-anthropic-api-key-here"
 	case "huggingface":
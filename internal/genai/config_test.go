@@ -0,0 +1,66 @@
+package genai
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateConfigOnlyMarksMissingCredentialsAsNotConfigured(t *testing.T) {
+	valid := func() *Config {
+		return &Config{Provider: "openai", Model: "gpt-4o-mini", MaxTokens: 100, Temperature: 0.7, Timeout: time.Second}
+	}
+
+	config := valid()
+	if err := ValidateConfig(config); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("missing API key: ValidateConfig() = %v, want ErrNotConfigured", err)
+	}
+
+	// Other problems are reported even when the key is missing too
+	for name, breakConfig := range map[string]func(*Config){
+		"provider":    func(c *Config) { c.Provider = "nope" },
+		"model":       func(c *Config) { c.Model = "" },
+		"temperature": func(c *Config) { c.Temperature = 5 },
+		"timeout":     func(c *Config) { c.Timeout = 0 },
+	} {
+		config := valid()
+		breakConfig(config)
+		err := ValidateConfig(config)
+		if err == nil || errors.Is(err, ErrNotConfigured) {
+			t.Errorf("bad %s: ValidateConfig() = %v, want an error other than ErrNotConfigured", name, err)
+		}
+	}
+
+	config = valid()
+	config.APIKey = "sk-test"
+	if err := ValidateConfig(config); err != nil {
+		t.Errorf("complete config: ValidateConfig() = %v", err)
+	}
+}
+
+func TestUseProviderReadsTheChosenProvidersEnvironment(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-openai")
+	t.Setenv("AZURE_OPENAI_API_KEY", "azure-key")
+	t.Setenv("AZURE_OPENAI_ENDPOINT", "https://res.openai.azure.com")
+
+	// Switching away from a loaded provider drops its key instead of
+	// sending it to the new one
+	loaded := getDefaultConfig("openai")
+	loaded.APIKey = "sk-openai"
+	config := UseProvider(loaded, "azure")
+	if config.Provider != "azure" || config.APIKey != "azure-key" || config.APIEndpoint != "https://res.openai.azure.com" {
+		t.Errorf("UseProvider(openai, azure) = %s key=%q endpoint=%q, want the Azure environment", config.Provider, config.APIKey, config.APIEndpoint)
+	}
+	if loaded.Provider != "openai" || loaded.APIKey != "sk-openai" {
+		t.Errorf("UseProvider modified the loaded config: %+v", loaded)
+	}
+
+	// Without a provider the loaded one is kept, and its own key wins
+	// over the environment
+	fromFile := getDefaultConfig("azure")
+	fromFile.APIKey = "file-key"
+	config = UseProvider(fromFile, "")
+	if config.Provider != "azure" || config.APIKey != "file-key" || config.APIEndpoint != "https://res.openai.azure.com" {
+		t.Errorf("UseProvider(azure, \"\") = %s key=%q endpoint=%q, want the file key and the environment endpoint", config.Provider, config.APIKey, config.APIEndpoint)
+	}
+}
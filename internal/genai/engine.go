@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -67,9 +68,10 @@ type Engine struct {
 
 // Config holds configuration for the GenAI engine
 type Config struct {
-	Provider    string        `json:"provider"` // "openai", "anthropic", "local", "huggingface"
+	Provider    string        `json:"provider"` // "openai", "azure", "anthropic", "local", "huggingface"
 	APIKey      string        `json:"api_key"`
 	APIEndpoint string        `json:"api_endpoint"`
+	APIVersion  string        `json:"api_version,omitempty"` // Azure api-version (empty = DefaultAzureAPIVersion)
 	Model       string        `json:"model"`
 	MaxTokens   int           `json:"max_tokens"`
 	Temperature float64       `json:"temperature"`
@@ -80,16 +82,12 @@ type Config struct {
 	EnableAnalytics     bool `json:"enable_analytics"`
 	EnableQualityFilter bool `json:"enable_quality_filter"`
 	MaxRetries          int  `json:"max_retries"`
-}
-
-// AnalyticsCollector tracks AI generation performance and costs
-type AnalyticsCollector struct {
-	TotalRequests   int64           `json:"total_requests"`
-	TotalTokens     int64           `json:"total_tokens"`
-	TotalCost       float64         `json:"total_cost"`
-	AverageQuality  float64         `json:"average_quality"`
-	SuccessRate     float64         `json:"success_rate"`
-	GenerationTimes []time.Duration `json:"-"`
+	// CostPer1KTokens prices the provider's tokens for the analytics cost
+	// estimate (0 = cost not tracked)
+	CostPer1KTokens float64 `json:"cost_per_1k_tokens"`
+	// PromptTemplate replaces the built-in prompt with a text/template
+	// executed with PromptData (empty = built-in prompt)
+	PromptTemplate string `json:"prompt_template,omitempty"`
 }
 
 // NewEngine creates a new GenAI engine with the specified configuration
@@ -125,26 +123,34 @@ func (e *Engine) GeneratePayloads(req *PayloadGenerationRequest) (*GenerationRes
 	fmt.Printf("   Provider: %s | Model: %s | Attack: %s\n", e.Config.Provider, e.Config.Model, req.AttackType)
 
 	// Build the AI prompt based on request context
-	prompt := e.buildPrompt(req)
+	prompt, err := e.buildPrompt(req)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate payloads using the selected AI provider
 	var payloads []GeneratedPayload
-	var err error
+	var tokens int
 
 	switch e.Config.Provider {
 	case "openai":
-		payloads, err = e.generateWithOpenAI(prompt, req)
+		payloads, tokens, err = e.generateWithOpenAI(prompt, req)
+	case "azure":
+		payloads, tokens, err = e.generateWithAzure(prompt, req)
 	case "anthropic":
-		payloads, err = e.generateWithAnthropic(prompt, req)
+		payloads, tokens, err = e.generateWithAnthropic(prompt, req)
 	case "local":
-		payloads, err = e.generateWithLocal(prompt, req)
+		payloads, tokens, err = e.generateWithLocal(prompt, req)
 	case "huggingface":
-		payloads, err = e.generateWithHuggingFace(prompt, req)
+		payloads, tokens, err = e.generateWithHuggingFace(prompt, req)
 	default:
 		return nil, fmt.Errorf("unsupported AI provider: %s", e.Config.Provider)
 	}
 
 	if err != nil {
+		if e.Config.EnableAnalytics {
+			e.Analytics.RecordFailure(time.Since(startTime))
+		}
 		return nil, fmt.Errorf("AI generation failed: %v", err)
 	}
 
@@ -157,10 +163,11 @@ func (e *Engine) GeneratePayloads(req *PayloadGenerationRequest) (*GenerationRes
 	payloads = e.enhancePayloads(payloads, req)
 
 	duration := time.Since(startTime)
+	cost := float64(tokens) / 1000 * e.Config.CostPer1KTokens
 
 	// Update analytics
 	if e.Config.EnableAnalytics {
-		e.updateAnalytics(len(payloads), duration)
+		e.Analytics.RecordGeneration(duration, payloads, tokens, cost)
 	}
 
 	result := &GenerationResult{
@@ -168,6 +175,8 @@ func (e *Engine) GeneratePayloads(req *PayloadGenerationRequest) (*GenerationRes
 		TotalGenerated: len(payloads),
 		GenerationTime: duration,
 		ModelUsed:      e.Config.Model,
+		TokensUsed:     tokens,
+		Cost:           cost,
 	}
 
 	fmt.Printf("✅ AI Generation Complete: %d payloads in %v\n", len(payloads), duration)
@@ -175,8 +184,13 @@ func (e *Engine) GeneratePayloads(req *PayloadGenerationRequest) (*GenerationRes
 	return result, nil
 }
 
-// buildPrompt creates an intelligent prompt for AI payload generation
-func (e *Engine) buildPrompt(req *PayloadGenerationRequest) string {
+// buildPrompt creates an intelligent prompt for AI payload generation, or
+// renders the configured prompt template
+func (e *Engine) buildPrompt(req *PayloadGenerationRequest) (string, error) {
+	if e.Config.PromptTemplate != "" {
+		return e.renderPrompt(req)
+	}
+
 	var prompt strings.Builder
 
 	// System context
@@ -244,21 +258,9 @@ func (e *Engine) buildPrompt(req *PayloadGenerationRequest) string {
 	prompt.WriteString("- Polyglot and multi-context payloads\n")
 
 	// Output format
-	prompt.WriteString("\nRETURN RESULTS AS JSON with this exact structure:\n")
-	prompt.WriteString("{\n")
-	prompt.WriteString("  \"payloads\": [\n")
-	prompt.WriteString("    {\n")
-	prompt.WriteString("      \"payload\": \"actual_payload_here\",\n")
-	prompt.WriteString("      \"technique\": \"technique_name\",\n")
-	prompt.WriteString("      \"confidence\": 0.85,\n")
-	prompt.WriteString("      \"explanation\": \"brief_explanation\",\n")
-	prompt.WriteString("      \"evasion_methods\": [\"method1\", \"method2\"],\n")
-	prompt.WriteString("      \"success_probability\": 0.75\n")
-	prompt.WriteString("    }\n")
-	prompt.WriteString("  ]\n")
-	prompt.WriteString("}\n")
-
-	return prompt.String()
+	prompt.WriteString(promptOutputFormat)
+
+	return prompt.String(), nil
 }
 
 // getAttackTypeContext provides specialized context for each attack type
@@ -311,9 +313,59 @@ Advanced techniques: Blind XXE, parameter entity injection, SOAP injection.`,
 	return "CONTEXT: Generic security testing payload generation."
 }
 
-// generateWithOpenAI generates payloads using OpenAI API
-func (e *Engine) generateWithOpenAI(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, error) {
-	requestBody := map[string]interface{}{
+// generateWithOpenAI generates payloads using OpenAI API, or the
+// OpenAI-compatible API (vLLM, LocalAI, a gateway) at the configured endpoint
+func (e *Engine) generateWithOpenAI(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, int, error) {
+	endpoint := e.Config.APIEndpoint
+	if endpoint == "" {
+		endpoint = "https://api.openai.com/v1/chat/completions"
+	}
+
+	return e.makeAPIRequest(endpoint, e.chatCompletionBody(prompt), "OpenAI")
+}
+
+// generateWithAzure generates payloads using an Azure OpenAI deployment
+func (e *Engine) generateWithAzure(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, int, error) {
+	endpoint, err := azureEndpoint(e.Config)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return e.makeAPIRequest(endpoint, e.chatCompletionBody(prompt), "Azure")
+}
+
+// DefaultAzureAPIVersion is the api-version of Azure OpenAI requests when
+// the config sets none
+const DefaultAzureAPIVersion = "2024-06-01"
+
+// azureEndpoint returns the chat completions URL of an Azure OpenAI
+// deployment. APIEndpoint is either the resource
+// (https://NAME.openai.azure.com), in which case Model names the deployment,
+// or the deployment's full chat completions URL. The api-version is added
+// unless the URL already has one.
+func azureEndpoint(config *Config) (string, error) {
+	endpoint, err := url.Parse(strings.TrimRight(config.APIEndpoint, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid Azure endpoint: %v", err)
+	}
+	if !strings.Contains(endpoint.Path, "/deployments/") {
+		endpoint = endpoint.JoinPath("openai", "deployments", config.Model, "chat", "completions")
+	}
+	query := endpoint.Query()
+	if query.Get("api-version") == "" {
+		version := config.APIVersion
+		if version == "" {
+			version = DefaultAzureAPIVersion
+		}
+		query.Set("api-version", version)
+		endpoint.RawQuery = query.Encode()
+	}
+	return endpoint.String(), nil
+}
+
+// chatCompletionBody returns the body of an OpenAI chat completions request
+func (e *Engine) chatCompletionBody(prompt string) map[string]interface{} {
+	return map[string]interface{}{
 		"model": e.Config.Model,
 		"messages": []map[string]string{
 			{
@@ -329,12 +381,10 @@ func (e *Engine) generateWithOpenAI(prompt string, req *PayloadGenerationRequest
 		"temperature":     e.Config.Temperature,
 		"response_format": map[string]string{"type": "json_object"},
 	}
-
-	return e.makeAPIRequest("https://api.openai.com/v1/chat/completions", requestBody, "OpenAI")
 }
 
 // generateWithAnthropic generates payloads using Anthropic Claude API
-func (e *Engine) generateWithAnthropic(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, error) {
+func (e *Engine) generateWithAnthropic(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, int, error) {
 	requestBody := map[string]interface{}{
 		"model":       e.Config.Model,
 		"max_tokens":  e.Config.MaxTokens,
@@ -351,7 +401,7 @@ func (e *Engine) generateWithAnthropic(prompt string, req *PayloadGenerationRequ
 }
 
 // generateWithLocal generates payloads using local LLM
-func (e *Engine) generateWithLocal(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, error) {
+func (e *Engine) generateWithLocal(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, int, error) {
 	// For local models (Ollama, LM Studio, etc.)
 	requestBody := map[string]interface{}{
 		"model":       e.Config.Model,
@@ -370,7 +420,7 @@ func (e *Engine) generateWithLocal(prompt string, req *PayloadGenerationRequest)
 }
 
 // generateWithHuggingFace generates payloads using HuggingFace API
-func (e *Engine) generateWithHuggingFace(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, error) {
+func (e *Engine) generateWithHuggingFace(prompt string, req *PayloadGenerationRequest) ([]GeneratedPayload, int, error) {
 	requestBody := map[string]interface{}{
 		"inputs": prompt,
 		"parameters": map[string]interface{}{
@@ -385,16 +435,17 @@ func (e *Engine) generateWithHuggingFace(prompt string, req *PayloadGenerationRe
 	return e.makeAPIRequest(endpoint, requestBody, "HuggingFace")
 }
 
-// makeAPIRequest makes HTTP requests to AI providers
-func (e *Engine) makeAPIRequest(endpoint string, requestBody map[string]interface{}, provider string) ([]GeneratedPayload, error) {
+// makeAPIRequest makes HTTP requests to AI providers, returning the
+// generated payloads and the tokens the provider reports using
+func (e *Engine) makeAPIRequest(endpoint string, requestBody map[string]interface{}, provider string) ([]GeneratedPayload, int, error) {
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
+		return nil, 0, fmt.Errorf("failed to marshal request: %v", err)
 	}
 
 	req, err := http.NewRequestWithContext(e.Context, "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, 0, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	// Set headers based on provider
@@ -403,6 +454,8 @@ func (e *Engine) makeAPIRequest(endpoint string, requestBody map[string]interfac
 	switch provider {
 	case "OpenAI":
 		req.Header.Set("Authorization", "Bearer "+e.Config.APIKey)
+	case "Azure":
+		req.Header.Set("api-key", e.Config.APIKey)
 	case "Anthropic":
 		req.Header.Set("x-api-key", e.Config.APIKey)
 		req.Header.Set("anthropic-version", "2023-06-01")
@@ -412,35 +465,35 @@ func (e *Engine) makeAPIRequest(endpoint string, requestBody map[string]interfac
 
 	resp, err := e.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("API request failed: %v", err)
+		return nil, 0, fmt.Errorf("API request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, 0, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
+		return nil, 0, fmt.Errorf("failed to read response: %v", err)
 	}
 
 	return e.parseResponse(body, provider)
 }
 
 // parseResponse parses API responses from different providers
-func (e *Engine) parseResponse(body []byte, provider string) ([]GeneratedPayload, error) {
+func (e *Engine) parseResponse(body []byte, provider string) ([]GeneratedPayload, int, error) {
 	var response map[string]interface{}
 	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
+		return nil, 0, fmt.Errorf("failed to parse response: %v", err)
 	}
 
 	var content string
 
 	// Extract content based on provider response format
 	switch provider {
-	case "OpenAI":
+	case "OpenAI", "Azure":
 		if choices, ok := response["choices"].([]interface{}); ok && len(choices) > 0 {
 			if choice, ok := choices[0].(map[string]interface{}); ok {
 				if message, ok := choice["message"].(map[string]interface{}); ok {
@@ -463,11 +516,39 @@ func (e *Engine) parseResponse(body []byte, provider string) ([]GeneratedPayload
 	}
 
 	if content == "" {
-		return nil, fmt.Errorf("no content received from %s API", provider)
+		return nil, 0, fmt.Errorf("no content received from %s API", provider)
 	}
 
 	// Parse the JSON content to extract payloads
-	return e.parsePayloadJSON(content)
+	payloads, err := e.parsePayloadJSON(content)
+	if err != nil {
+		return nil, 0, err
+	}
+	return payloads, usageTokens(response, provider), nil
+}
+
+// usageTokens returns the tokens a provider's response reports using, or 0
+// when it reports none
+func usageTokens(response map[string]interface{}, provider string) int {
+	number := func(m map[string]interface{}, key string) int {
+		n, _ := m[key].(float64)
+		return int(n)
+	}
+	usage, _ := response["usage"].(map[string]interface{})
+	switch provider {
+	case "OpenAI", "Azure":
+		if usage != nil {
+			return number(usage, "total_tokens")
+		}
+	case "Anthropic":
+		if usage != nil {
+			return number(usage, "input_tokens") + number(usage, "output_tokens")
+		}
+	case "Local":
+		// Ollama counts the prompt and the completion
+		return number(response, "prompt_eval_count") + number(response, "eval_count")
+	}
+	return 0
 }
 
 // parsePayloadJSON parses the AI-generated JSON content
@@ -553,17 +634,6 @@ func (e *Engine) enhancePayloads(payloads []GeneratedPayload, req *PayloadGenera
 	return payloads
 }
 
-// updateAnalytics updates the analytics collector
-func (e *Engine) updateAnalytics(payloadCount int, duration time.Duration) {
-	e.Analytics.TotalRequests++
-	e.Analytics.GenerationTimes = append(e.Analytics.GenerationTimes, duration)
-
-	// Calculate success rate (simplified for now)
-	if payloadCount > 0 {
-		e.Analytics.SuccessRate = float64(payloadCount) / float64(e.Analytics.TotalRequests)
-	}
-}
-
 // GetAnalytics returns current analytics data
 func (e *Engine) GetAnalytics() *AnalyticsCollector {
 	return e.Analytics
@@ -579,6 +649,8 @@ func (e *Engine) IsConfigured() bool {
 	switch e.Config.Provider {
 	case "openai", "anthropic", "huggingface":
 		return e.Config.APIKey != ""
+	case "azure":
+		return e.Config.APIKey != "" && e.Config.APIEndpoint != ""
 	case "local":
 		return e.Config.APIEndpoint != "" || e.Config.Model != ""
 	default:
@@ -0,0 +1,68 @@
+package genai
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptData is what a custom prompt template (Config.PromptTemplate) is
+// executed with. Besides the request's fields, such as {{.AttackType}},
+// {{.BasePayload}}, {{.Count}}, {{.EvasionLevel}}, {{.Creativity}} and
+// {{with .WAFInfo}}{{.Vendor}}{{end}} (WAFInfo is nil when no WAF was
+// detected), a template can use:
+//
+//	{{.AttackContext}} the built-in description of the attack type
+//	{{.OutputFormat}}  the built-in instructions for the JSON response
+//
+// The response is parsed as that JSON whatever the template, so a custom
+// template should include {{.OutputFormat}} or ask for the same structure.
+type PromptData struct {
+	*PayloadGenerationRequest
+	AttackContext string
+	OutputFormat  string
+}
+
+// promptOutputFormat asks the model for the JSON parseResponse reads
+const promptOutputFormat = `
+RETURN RESULTS AS JSON with this exact structure:
+{
+  "payloads": [
+    {
+      "payload": "actual_payload_here",
+      "technique": "technique_name",
+      "confidence": 0.85,
+      "explanation": "brief_explanation",
+      "evasion_methods": ["method1", "method2"],
+      "success_probability": 0.75
+    }
+  ]
+}
+`
+
+// parsePromptTemplate parses a custom prompt template
+func parsePromptTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("prompt").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt_template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// renderPrompt executes the configured prompt template for req
+func (e *Engine) renderPrompt(req *PayloadGenerationRequest) (string, error) {
+	tmpl, err := parsePromptTemplate(e.Config.PromptTemplate)
+	if err != nil {
+		return "", err
+	}
+	var prompt strings.Builder
+	err = tmpl.Execute(&prompt, PromptData{
+		PayloadGenerationRequest: req,
+		AttackContext:            e.getAttackTypeContext(req.AttackType),
+		OutputFormat:             promptOutputFormat,
+	})
+	if err != nil {
+		return "", fmt.Errorf("rendering prompt_template: %v", err)
+	}
+	return prompt.String(), nil
+}
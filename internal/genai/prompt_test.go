@@ -0,0 +1,57 @@
+package genai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"obfuskit/types"
+)
+
+func TestPromptTemplateRendersIntoProviderRequest(t *testing.T) {
+	var prompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Prompt string `json:"prompt"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		prompt = body.Prompt
+		json.NewEncoder(w).Encode(map[string]string{
+			"response": `{"payloads": [{"payload": "<svg onload=alert(1)>", "confidence": 0.9}]}`,
+		})
+	}))
+	defer server.Close()
+
+	config := &Config{
+		Provider:       "local",
+		Model:          "test",
+		APIEndpoint:    server.URL,
+		PromptTemplate: "Write {{.Count}} {{.AttackType}} payloads{{with .WAFInfo}} for {{.Vendor}}{{end}} from {{.BasePayload}}.{{.OutputFormat}}",
+	}
+	engine := NewEngine(config)
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("ValidateConfig: %v", err)
+	}
+	_, err := engine.GeneratePayloads(&PayloadGenerationRequest{
+		AttackType:  types.AttackTypeXSS,
+		BasePayload: "<script>",
+		WAFInfo:     &WAFContext{Vendor: "ModSecurity"},
+		Count:       3,
+	})
+	if err != nil {
+		t.Fatalf("GeneratePayloads: %v", err)
+	}
+
+	if want := "Write 3 xss payloads for ModSecurity from <script>.\nRETURN RESULTS AS JSON"; !strings.HasPrefix(prompt, want) {
+		t.Errorf("prompt = %q, want it to start with %q", prompt, want)
+	}
+
+	config.PromptTemplate = "{{.AttackType"
+	if err := ValidateConfig(config); err == nil {
+		t.Error("ValidateConfig accepted an unterminated template")
+	}
+}
@@ -0,0 +1,90 @@
+package genai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"obfuskit/types"
+)
+
+// chatCompletionServer answers like the OpenAI chat completions API and
+// records the last request it received
+func chatCompletionServer(t *testing.T, received **http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*received = r
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": `{"payloads": [{"payload": "<svg onload=alert(1)>", "confidence": 0.9}]}`}},
+			},
+			"usage": map[string]int{"total_tokens": 42},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAICompatibleAndAzureEndpoints(t *testing.T) {
+	var received *http.Request
+	server := chatCompletionServer(t, &received)
+
+	tests := []struct {
+		name       string
+		config     *Config
+		wantPath   string
+		wantQuery  string
+		wantHeader string
+		wantValue  string
+	}{
+		{
+			name:       "openai at a compatible gateway",
+			config:     &Config{Provider: "openai", Model: "llama3", APIKey: "sk-test", APIEndpoint: server.URL + "/v1/chat/completions"},
+			wantPath:   "/v1/chat/completions",
+			wantHeader: "Authorization",
+			wantValue:  "Bearer sk-test",
+		},
+		{
+			name:       "azure resource",
+			config:     &Config{Provider: "azure", Model: "payloads", APIKey: "azure-key", APIEndpoint: server.URL + "/"},
+			wantPath:   "/openai/deployments/payloads/chat/completions",
+			wantQuery:  "api-version=" + DefaultAzureAPIVersion,
+			wantHeader: "api-key",
+			wantValue:  "azure-key",
+		},
+		{
+			name: "azure deployment URL",
+			config: &Config{Provider: "azure", Model: "ignored", APIKey: "azure-key", APIVersion: "2024-10-21",
+				APIEndpoint: server.URL + "/openai/deployments/gpt4o/chat/completions"},
+			wantPath:   "/openai/deployments/gpt4o/chat/completions",
+			wantQuery:  "api-version=2024-10-21",
+			wantHeader: "api-key",
+			wantValue:  "azure-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateConfig(mergeWithDefaults(tt.config)); err != nil {
+				t.Fatalf("ValidateConfig: %v", err)
+			}
+			result, err := NewEngine(tt.config).GeneratePayloads(&PayloadGenerationRequest{AttackType: types.AttackTypeXSS, Count: 1})
+			if err != nil {
+				t.Fatalf("GeneratePayloads: %v", err)
+			}
+			if result.TotalGenerated != 1 || result.TokensUsed != 42 {
+				t.Errorf("got %d payloads and %d tokens, want 1 and 42", result.TotalGenerated, result.TokensUsed)
+			}
+			if received.URL.Path != tt.wantPath || received.URL.RawQuery != tt.wantQuery {
+				t.Errorf("request went to %s, want path %s and query %q", received.URL, tt.wantPath, tt.wantQuery)
+			}
+			if got := received.Header.Get(tt.wantHeader); got != tt.wantValue {
+				t.Errorf("%s header = %q, want %q", tt.wantHeader, got, tt.wantValue)
+			}
+			if tt.config.Provider == "azure" && received.Header.Get("Authorization") != "" {
+				t.Error("azure request carries an Authorization header")
+			}
+		})
+	}
+}
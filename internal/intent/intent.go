@@ -0,0 +1,146 @@
+// Package intent recognizes whether a string still carries the attack of a
+// base payload: the signature checks behind -strict-variants and the decode
+// depth analysis in reports.
+package intent
+
+import (
+	"regexp"
+	"strings"
+
+	"obfuskit/types"
+)
+
+// checks hold, per attack type, a test receiving the lowercased original and
+// the lowercased candidate. Attack types without a check are not recognized.
+var checks = map[types.AttackType]func(original, text string) bool{
+	types.AttackTypeXSS:        xssIntact,
+	types.AttackTypeSQLI:       sqliIntact,
+	types.AttackTypePath:       pathIntact,
+	types.AttackTypeFileAccess: pathIntact,
+	types.AttackTypeUnixCMDI:   commandIntact,
+	types.AttackTypeWinCMDI:    commandIntact,
+	types.AttackTypeOsCMDI:     commandIntact,
+}
+
+// Known reports whether attackType has a signature check
+func Known(attackType types.AttackType) bool {
+	_, ok := checks[attackType]
+	return ok
+}
+
+// Carries reports whether text, as is, still has the structure that makes
+// original an attack of the given type. Comparison is case insensitive and
+// text is not decoded first. Attack types without a check always carry.
+func Carries(attackType types.AttackType, original, text string) bool {
+	check, ok := checks[attackType]
+	if !ok {
+		return true
+	}
+	return check(strings.ToLower(original), strings.ToLower(text))
+}
+
+var (
+	tagPattern     = regexp.MustCompile(`<\s*([a-z][a-z0-9]*)`)
+	handlerPattern = regexp.MustCompile(`\bon[a-z]+\s*=`)
+)
+
+// xssIntact requires the original's first tag to still open, or else its
+// javascript: URL or event handler to survive
+func xssIntact(original, variant string) bool {
+	if m := tagPattern.FindStringSubmatch(original); m != nil {
+		return opensTag(variant, m[1])
+	}
+	if strings.Contains(original, "javascript:") {
+		return strings.Contains(strings.Join(strings.Fields(variant), ""), "javascript:")
+	}
+	if handlerPattern.MatchString(original) {
+		return handlerPattern.MatchString(variant)
+	}
+	return true
+}
+
+// opensTag reports whether text has a "<" followed, after any whitespace, by
+// tag, the way tagPattern matches it
+func opensTag(text, tag string) bool {
+	for {
+		i := strings.IndexByte(text, '<')
+		if i < 0 {
+			return false
+		}
+		text = strings.TrimLeft(text[i+1:], " \t\n\f\r")
+		if strings.HasPrefix(text, tag) {
+			return true
+		}
+	}
+}
+
+// sqlKeywords are the statement keywords a SQL injection variant must keep;
+// AND/OR are left out since operator substitution (&&, ||) is a valid rewrite
+var sqlKeywords = []string{"union", "select", "insert", "update", "delete", "drop", "sleep", "benchmark", "waitfor"}
+
+var sqlCommentPattern = regexp.MustCompile(`/\*.*?\*/`)
+
+func sqliIntact(original, variant string) bool {
+	variant = sqlCommentPattern.ReplaceAllString(variant, "")
+	for _, keyword := range sqlKeywords {
+		if containsWord(original, keyword) && !strings.Contains(variant, keyword) {
+			return false
+		}
+	}
+	if strings.Contains(original, "'") && !strings.ContainsAny(variant, `'"`) {
+		return false
+	}
+	return true
+}
+
+// pathIntact requires a parent directory step and the original's target file
+func pathIntact(original, variant string) bool {
+	variant = strings.ReplaceAll(variant, `\`, "/")
+	original = strings.ReplaceAll(original, `\`, "/")
+	if strings.Contains(original, "..") && !strings.Contains(variant, "..") {
+		return false
+	}
+	segments := strings.FieldsFunc(original, func(r rune) bool { return r == '/' || r == '\x00' })
+	if len(segments) > 0 {
+		if target := segments[len(segments)-1]; target != ".." && !strings.Contains(variant, target) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	commandSeparators = regexp.MustCompile("[;|&\n`]|\\$\\(")
+	shellNoise        = strings.NewReplacer("${ifs}", " ", "$ifs", " ", "$@", "", "${9}", "", "'", "", `"`, "", `\`, "", "^", "")
+)
+
+// commandIntact requires each command name of the original to survive once
+// quoting, carets and $IFS-style separators are removed
+func commandIntact(original, variant string) bool {
+	variant = shellNoise.Replace(variant)
+	for _, part := range commandSeparators.Split(original, -1) {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		name := fields[0]
+		if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+			name = name[i+1:]
+		}
+		if name != "" && !strings.Contains(variant, name) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsWord(s, word string) bool {
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	}) {
+		if field == word {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"obfuskit/request"
+	"obfuskit/types"
 )
 
 // PayloadResults represents the structure for storing generated payloads
@@ -9,10 +10,68 @@ type PayloadResults struct {
 	OriginalPayload string
 	AttackType      string
 	EvasionType     string
-	Variants        []string
+	Variants        []types.Variant
 	Level           string
 }
 
+// NewVariants wraps bare variant strings; AssignVariantIDs fills in the rest
+func NewVariants(values []string) []types.Variant {
+	if values == nil {
+		return nil
+	}
+	variants := make([]types.Variant, len(values))
+	for i, value := range values {
+		variants[i] = types.Variant{Value: value}
+	}
+	return variants
+}
+
+// Values returns the bare variant strings, in order
+func (p PayloadResults) Values() []string {
+	if p.Variants == nil {
+		return nil
+	}
+	values := make([]string, len(p.Variants))
+	for i, variant := range p.Variants {
+		values[i] = variant.Value
+	}
+	return values
+}
+
+// IDs returns the variant IDs, in order
+func (p PayloadResults) IDs() []string {
+	if p.Variants == nil {
+		return nil
+	}
+	ids := make([]string, len(p.Variants))
+	for i, variant := range p.Variants {
+		ids[i] = variant.ID
+	}
+	return ids
+}
+
+// Techniques returns the technique behind each variant, in order, so a
+// finding can be attributed to e.g. double URL encoding of the dots rather
+// than just its encoding. A variant without a technique label is labeled
+// with its encoding, as AssignVariantIDs does.
+func (p PayloadResults) Techniques() []string {
+	if p.Variants == nil {
+		return nil
+	}
+	techniques := make([]string, len(p.Variants))
+	for i, variant := range p.Variants {
+		switch {
+		case variant.Technique != "":
+			techniques[i] = variant.Technique
+		case variant.Encoding != "":
+			techniques[i] = variant.Encoding
+		default:
+			techniques[i] = p.EvasionType
+		}
+	}
+	return techniques
+}
+
 // TestResults represents the complete test execution results
 type TestResults struct {
 	Config         interface{} // will be replaced with actual config type
@@ -28,8 +87,13 @@ type TestSummary struct {
 	TotalVariants   int
 	SuccessfulTests int
 	FailedTests     int
+	ChallengeTests  int
 	AttackTypes     []string
 	EvasionTypes    []string
+	// Bypasses counts requests that got through; UniqueWeaknesses groups
+	// them by normalized payload (see report.GroupWeaknesses)
+	Bypasses         int
+	UniqueWeaknesses int
 }
 
 // PayloadRequest is the expected JSON format from api
@@ -46,6 +110,7 @@ type EvadedPayload struct {
 	EvasionType     string `json:"evasion_type"`
 	Level           string `json:"evasion_level"`
 	Variant         string `json:"variant"`
+	VariantID       string `json:"variant_id"`
 }
 
 type PayloadResponse struct {
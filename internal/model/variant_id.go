@@ -0,0 +1,45 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// variantIDBytes is the number of SHA-256 bytes kept in a variant ID
+const variantIDBytes = 12
+
+// VariantID returns a stable identifier for a generated variant. The same
+// original payload, encoding and variant always produce the same ID, across
+// runs and platforms, so it can be used for cross-run correlation and as a
+// database key.
+func VariantID(originalPayload, encoding, variant string) string {
+	h := sha256.New()
+	// NUL separators keep ("ab", "c") and ("a", "bc") distinct
+	h.Write([]byte(originalPayload))
+	h.Write([]byte{0})
+	h.Write([]byte(encoding))
+	h.Write([]byte{0})
+	h.Write([]byte(variant))
+	return hex.EncodeToString(h.Sum(nil)[:variantIDBytes])
+}
+
+// AssignVariantIDs (re)computes the ID of every variant, first filling in
+// SourcePayload, Encoding, Technique and Level from p where they are unset
+func (p *PayloadResults) AssignVariantIDs() {
+	for i := range p.Variants {
+		variant := &p.Variants[i]
+		if variant.SourcePayload == "" {
+			variant.SourcePayload = p.OriginalPayload
+		}
+		if variant.Encoding == "" {
+			variant.Encoding = p.EvasionType
+		}
+		if variant.Technique == "" {
+			variant.Technique = variant.Encoding
+		}
+		if variant.Level == "" {
+			variant.Level = p.Level
+		}
+		variant.ID = VariantID(p.OriginalPayload, p.EvasionType, variant.Value)
+	}
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVariantIDDeterministic(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		encoding string
+		variant  string
+		expected string
+	}{
+		{
+			name:     "known value",
+			original: "<script>alert(1)</script>",
+			encoding: "URLVariants",
+			variant:  "%3Cscript%3Ealert(1)%3C%2Fscript%3E",
+			expected: "759edbd537e414c6282a31d3",
+		},
+		{
+			name:     "empty inputs",
+			expected: "96a296d224f285c67bee93c3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				if got := VariantID(tt.original, tt.encoding, tt.variant); got != tt.expected {
+					t.Fatalf("VariantID() = %q on call %d, want %q", got, i, tt.expected)
+				}
+			}
+			if got := VariantID(tt.original, tt.encoding, tt.variant); len(got) != 24 {
+				t.Errorf("VariantID() length = %d, want 24 hex chars", len(got))
+			}
+		})
+	}
+
+	// Pinned value guards against accidental changes to the hashing scheme
+	if got := VariantID("a", "b", "c"); got != "8badde10c760e9b702defb4b" {
+		t.Errorf("VariantID(a, b, c) = %q, scheme changed", got)
+	}
+}
+
+func TestVariantIDFieldBoundaries(t *testing.T) {
+	if VariantID("ab", "c", "d") == VariantID("a", "bc", "d") {
+		t.Error("fields shifted across boundaries produced the same ID")
+	}
+	if VariantID("a", "b", "c") == VariantID("a", "c", "b") {
+		t.Error("swapped fields produced the same ID")
+	}
+}
+
+func TestVariantIDLowCollision(t *testing.T) {
+	seen := make(map[string]string)
+	for i := 0; i < 20000; i++ {
+		key := fmt.Sprintf("payload-%d|enc-%d|variant-%d", i%97, i%13, i)
+		id := VariantID(fmt.Sprintf("payload-%d", i%97), fmt.Sprintf("enc-%d", i%13), fmt.Sprintf("variant-%d", i))
+		if prev, exists := seen[id]; exists {
+			t.Fatalf("collision between %q and %q", prev, key)
+		}
+		seen[id] = key
+	}
+}
+
+func TestAssignVariantIDs(t *testing.T) {
+	pr := PayloadResults{
+		OriginalPayload: "x",
+		EvasionType:     "HexVariants",
+		Variants:        NewVariants([]string{"0x78", "\\x78"}),
+		Level:           "Basic",
+	}
+	pr.AssignVariantIDs()
+
+	for i, variant := range pr.Variants {
+		if want := VariantID(pr.OriginalPayload, pr.EvasionType, variant.Value); variant.ID != want {
+			t.Errorf("Variants[%d].ID = %q, want %q", i, variant.ID, want)
+		}
+		if variant.SourcePayload != "x" || variant.Encoding != "HexVariants" || variant.Technique != "HexVariants" || variant.Level != "Basic" {
+			t.Errorf("Variants[%d] metadata not filled in: %+v", i, variant)
+		}
+	}
+}
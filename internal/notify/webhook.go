@@ -0,0 +1,151 @@
+// Package notify delivers run summaries to webhooks (generic JSON or Slack)
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"obfuskit/internal/model"
+	"obfuskit/internal/version"
+	"obfuskit/types"
+)
+
+// DefaultTimeout bounds each webhook delivery
+const DefaultTimeout = 10 * time.Second
+
+// maxTopTechniques is the number of techniques listed in a summary
+const maxTopTechniques = 5
+
+// TechniqueCount is the number of bypasses achieved by one evasion technique
+type TechniqueCount struct {
+	Technique string `json:"technique"`
+	Bypasses  int    `json:"bypasses"`
+}
+
+// Summary is the JSON document POSTed to generic webhooks
+type Summary struct {
+	Tool          string           `json:"tool"`
+	Version       string           `json:"version"`
+	CompletedAt   string           `json:"completed_at"`
+	Target        string           `json:"target"`
+	AttackType    string           `json:"attack_type"`
+	Tags          []string         `json:"tags,omitempty"`
+	Total         int              `json:"total"`
+	Blocked       int              `json:"blocked"`
+	Bypassed      int              `json:"bypassed"`
+	BypassRate    float64          `json:"bypass_rate"`
+	TopTechniques []TechniqueCount `json:"top_techniques"`
+}
+
+// BuildSummary condenses run results into a notification summary
+func BuildSummary(results *model.TestResults) Summary {
+	summary := Summary{
+		Tool:          "obfuskit",
+		Version:       version.Version,
+		CompletedAt:   time.Now().UTC().Format(time.RFC3339),
+		TopTechniques: []TechniqueCount{},
+	}
+
+	if config, ok := results.Config.(*types.Config); ok && config != nil {
+		summary.Target = config.Target.URL
+		summary.AttackType = string(config.AttackType)
+		summary.Tags = config.Tags
+	}
+
+	requestResults := results.AllRequestResults
+	if len(requestResults) == 0 {
+		requestResults = results.RequestResults
+	}
+
+	bypassesByTechnique := make(map[string]int)
+	for _, r := range requestResults {
+		summary.Total++
+		if r.Blocked {
+			summary.Blocked++
+			continue
+		}
+		summary.Bypassed++
+		bypassesByTechnique[r.EvasionTechnique]++
+	}
+	if summary.Total > 0 {
+		summary.BypassRate = float64(summary.Bypassed) / float64(summary.Total) * 100
+	}
+
+	summary.TopTechniques = topTechniques(bypassesByTechnique)
+	return summary
+}
+
+func topTechniques(counts map[string]int) []TechniqueCount {
+	techniques := make([]TechniqueCount, 0, len(counts))
+	for technique, bypasses := range counts {
+		techniques = append(techniques, TechniqueCount{Technique: technique, Bypasses: bypasses})
+	}
+	sort.Slice(techniques, func(i, j int) bool {
+		if techniques[i].Bypasses != techniques[j].Bypasses {
+			return techniques[i].Bypasses > techniques[j].Bypasses
+		}
+		return techniques[i].Technique < techniques[j].Technique
+	})
+	if len(techniques) > maxTopTechniques {
+		techniques = techniques[:maxTopTechniques]
+	}
+	return techniques
+}
+
+// SlackMessage formats a summary as a Slack incoming-webhook payload
+func SlackMessage(summary Summary) map[string]string {
+	var text bytes.Buffer
+	fmt.Fprintf(&text, "*ObfusKit run completed* (%s)\n", summary.Version)
+	if summary.Target != "" {
+		fmt.Fprintf(&text, "Target: %s\n", summary.Target)
+	}
+	if summary.AttackType != "" {
+		fmt.Fprintf(&text, "Attack type: %s\n", summary.AttackType)
+	}
+	if len(summary.Tags) > 0 {
+		fmt.Fprintf(&text, "Tags: %s\n", strings.Join(summary.Tags, ", "))
+	}
+	fmt.Fprintf(&text, "Requests: %d | Blocked: %d | Bypassed: %d (%.1f%%)",
+		summary.Total, summary.Blocked, summary.Bypassed, summary.BypassRate)
+	if len(summary.TopTechniques) > 0 {
+		text.WriteString("\nTop techniques:")
+		for _, tc := range summary.TopTechniques {
+			fmt.Fprintf(&text, "\n• %s: %d", tc.Technique, tc.Bypasses)
+		}
+	}
+	return map[string]string{"text": text.String()}
+}
+
+// SendWebhook POSTs the summary as JSON
+func SendWebhook(url string, summary Summary, timeout time.Duration) error {
+	return postJSON(url, summary, timeout)
+}
+
+// SendSlack POSTs the summary formatted as a Slack message
+func SendSlack(url string, summary Summary, timeout time.Duration) error {
+	return postJSON(url, SlackMessage(summary), timeout)
+}
+
+func postJSON(url string, body interface{}, timeout time.Duration) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %v", err)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"obfuskit/internal/model"
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+func sampleResults() *model.TestResults {
+	return &model.TestResults{
+		Config: &types.Config{
+			AttackType: types.AttackTypeXSS,
+			Target:     types.Target{URL: "https://target.example"},
+		},
+		AllRequestResults: []request.TestResult{
+			{EvasionTechnique: "basic_header", StatusCode: 403, Blocked: true},
+			{EvasionTechnique: "basic_query_param", StatusCode: 200},
+			{EvasionTechnique: "basic_query_param", StatusCode: 200},
+			{EvasionTechnique: "duplicate_form_param", StatusCode: 200},
+		},
+	}
+}
+
+func TestSendWebhookDeliversSummary(t *testing.T) {
+	received := make(chan Summary, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		var summary Summary
+		if err := json.NewDecoder(r.Body).Decode(&summary); err != nil {
+			t.Errorf("failed to decode summary: %v", err)
+		}
+		received <- summary
+	}))
+	defer server.Close()
+
+	if err := SendWebhook(server.URL, BuildSummary(sampleResults()), time.Second); err != nil {
+		t.Fatalf("SendWebhook() error: %v", err)
+	}
+
+	summary := <-received
+	if summary.Target != "https://target.example" {
+		t.Errorf("Target = %q", summary.Target)
+	}
+	if summary.Total != 4 || summary.Blocked != 1 || summary.Bypassed != 3 {
+		t.Errorf("counts = %d/%d/%d, want 4/1/3", summary.Total, summary.Blocked, summary.Bypassed)
+	}
+	if summary.BypassRate != 75 {
+		t.Errorf("BypassRate = %.1f, want 75", summary.BypassRate)
+	}
+	if len(summary.TopTechniques) == 0 || summary.TopTechniques[0].Technique != "basic_query_param" {
+		t.Errorf("TopTechniques = %+v, want basic_query_param first", summary.TopTechniques)
+	}
+}
+
+func TestSendSlackFormatsMessage(t *testing.T) {
+	received := make(chan map[string]string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode message: %v", err)
+		}
+		received <- msg
+	}))
+	defer server.Close()
+
+	if err := SendSlack(server.URL, BuildSummary(sampleResults()), time.Second); err != nil {
+		t.Fatalf("SendSlack() error: %v", err)
+	}
+
+	text := (<-received)["text"]
+	for _, want := range []string{"https://target.example", "Bypassed: 3", "basic_query_param: 2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("slack text missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestSendWebhookErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"server error", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) }},
+		{"timeout", func(w http.ResponseWriter, r *http.Request) { time.Sleep(200 * time.Millisecond) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			if err := SendWebhook(server.URL, Summary{}, 50*time.Millisecond); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package payload
+
+import (
+	"fmt"
+	"strings"
+
+	"obfuskit/internal/model"
+	"obfuskit/types"
+)
+
+// MissingTechniques returns the names in required that no generated variant
+// carries, in the order given. A name matches a variant's technique label or
+// its encoding, by full or short name ("utf8" for UTF8Variants), ignoring case.
+func MissingTechniques(payloadResults []model.PayloadResults, required []string) []string {
+	seen := make(map[string]bool)
+	for _, payloadResult := range payloadResults {
+		for _, variant := range payloadResult.Variants {
+			seen[strings.ToLower(variant.Technique)] = true
+			seen[strings.ToLower(variant.Encoding)] = true
+		}
+	}
+
+	var missing []string
+	for _, name := range required {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		if encoding, ok := types.ParsePayloadEncoding(name); ok && seen[strings.ToLower(string(encoding))] {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	return missing
+}
+
+// checkRequiredTechniques fails generation when a technique listed in
+// -require-techniques produced no variant, so CI notices one silently
+// dropping out
+func checkRequiredTechniques(payloadResults []model.PayloadResults, required []string) error {
+	if missing := MissingTechniques(payloadResults, required); len(missing) > 0 {
+		return fmt.Errorf("required techniques produced no variants: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package payload
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"obfuskit/internal/model"
+	"obfuskit/internal/waf"
+	"obfuskit/types"
+)
+
+// DefaultMaxEscalations is the -max-escalations used when none is set: from
+// basic, enough rounds to reach advanced
+const DefaultMaxEscalations = 2
+
+// payloadState tracks how the requests for one base payload fared
+type payloadState struct {
+	attackType types.AttackType
+	payload    string
+	bypassed   atomic.Bool
+	blocked    atomic.Bool
+}
+
+// nextEvasionLevel returns the level above level, if any
+func nextEvasionLevel(level types.EvasionLevel) (types.EvasionLevel, bool) {
+	switch level {
+	case types.EvasionLevelBasic:
+		return types.EvasionLevelMedium, true
+	case types.EvasionLevelMedium:
+		return types.EvasionLevelAdvanced, true
+	default:
+		return "", false
+	}
+}
+
+// escalatePayloads regenerates, at level, every base payload that was
+// blocked and got through to no target. Variants already in sent are
+// dropped (and the new ones added to it). With a fingerprinted WAF only the
+// encodings known to work against it are kept, when any of them apply.
+func escalatePayloads(config *types.Config, states map[string]*payloadState, level types.EvasionLevel, sent map[string]bool) []model.PayloadResults {
+	// States are per target; fold them into one per base payload
+	payloads := make(map[string]*payloadState)
+	blocked := make(map[string]bool)
+	bypassed := make(map[string]bool)
+	for _, state := range states {
+		key := string(state.attackType) + "\x00" + state.payload
+		payloads[key] = state
+		blocked[key] = blocked[key] || state.blocked.Load()
+		bypassed[key] = bypassed[key] || state.bypassed.Load()
+	}
+
+	// Walk payloads in a fixed order so a seeded run escalates reproducibly
+	keys := make([]string, 0, len(payloads))
+	for key := range payloads {
+		if blocked[key] && !bypassed[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var preferred []string
+	if fingerprint, ok := config.WAFFingerprint.(*waf.WAFFingerprint); ok && fingerprint != nil {
+		preferred = waf.GetOptimalEvasions(fingerprint.WAFType)
+	}
+
+	var escalated []model.PayloadResults
+	ctx := GenerationContext(config, 0)
+	for _, key := range keys {
+		state := payloads[key]
+		generated := &model.TestResults{Config: config}
+		if err := generateVariantsForPayload(ctx, generated, state.payload, state.attackType, level); err != nil {
+			continue
+		}
+		for _, payloadResult := range preferEncodings(generated.PayloadResults, preferred) {
+			var fresh []types.Variant
+			for _, variant := range payloadResult.Variants {
+				if sentKey := payloadResult.AttackType + "\x00" + variant.Value; !sent[sentKey] {
+					sent[sentKey] = true
+					fresh = append(fresh, variant)
+				}
+			}
+			if len(fresh) > 0 {
+				payloadResult.Variants = fresh
+				escalated = append(escalated, payloadResult)
+			}
+		}
+	}
+	assignVariantIDs(escalated)
+	return escalated
+}
+
+// preferEncodings keeps the results whose encoding matches one of preferred
+// ("unicode" matches UnicodeVariants), or all of them when none match
+func preferEncodings(payloadResults []model.PayloadResults, preferred []string) []model.PayloadResults {
+	var kept []model.PayloadResults
+	for _, payloadResult := range payloadResults {
+		encoding := strings.ToLower(payloadResult.EvasionType)
+		for _, name := range preferred {
+			if strings.HasPrefix(encoding, name) {
+				kept = append(kept, payloadResult)
+				break
+			}
+		}
+	}
+	if len(kept) == 0 {
+		return payloadResults
+	}
+	return kept
+}
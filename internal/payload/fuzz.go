@@ -0,0 +1,103 @@
+package payload
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"obfuskit/internal/model"
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+// FuzzOptions configures `obfuskit fuzz`
+type FuzzOptions struct {
+	// URL holds one or more request.FuzzMarker markers where payloads go
+	URL        string
+	AttackType types.AttackType
+	Level      types.EvasionLevel
+	// PayloadsDir overrides the built-in base payloads (see LoadBasePayloadsFrom)
+	PayloadsDir string
+	// Limit caps the base payloads used (0 = all)
+	Limit   int
+	Threads int
+}
+
+// Fuzz generates the encoded variants of the attack's base payloads and sends
+// each one with the payload in place of the URL's FUZZ marker. It is the
+// quick path for probing one parameter, without a config or the full set of
+// injectors.
+func Fuzz(opts FuzzOptions) ([]request.TestResult, error) {
+	if _, err := request.FuzzURL(opts.URL, ""); err != nil {
+		return nil, err
+	}
+
+	basePayloads, err := LoadBasePayloadsFrom(opts.AttackType, opts.PayloadsDir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s payloads: %w", opts.AttackType, err)
+	}
+	payloads := basePayloads[string(opts.AttackType)]
+	if opts.Limit > 0 && len(payloads) > opts.Limit {
+		payloads = payloads[:opts.Limit]
+	}
+
+	config := &types.Config{AttackType: opts.AttackType, EvasionLevel: opts.Level}
+	generated := &model.TestResults{Config: config}
+	for _, payload := range payloads {
+		if err := GenerateVariantsForPayload(generated, payload, opts.AttackType, opts.Level); err != nil {
+			return nil, err
+		}
+	}
+	assignVariantIDs(generated.PayloadResults)
+
+	// Encodings often agree on a variant; send each value once
+	var variants []types.Variant
+	seen := make(map[string]bool)
+	for _, payloadResult := range generated.PayloadResults {
+		for _, variant := range payloadResult.Variants {
+			if !seen[variant.Value] {
+				seen[variant.Value] = true
+				variants = append(variants, variant)
+			}
+		}
+	}
+	fmt.Printf("🎯 Fuzzing %s with %d %s variants of %d payloads\n", opts.URL, len(variants), opts.AttackType, len(payloads))
+
+	threads := opts.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	injector := request.NewFastHTTPMarkerInjectorWithOptions(request.DefaultInjectorOptions())
+	logger := request.NewLoggerWithLevel(os.Stderr, request.LogLevelError)
+
+	// Results keep the variant order whatever the thread count
+	sent := make([][]request.TestResult, len(variants))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < threads; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				testResults := injector.Inject(opts.URL, variants[i].Value, logger)
+				for k := range testResults {
+					testResults[k].Variant = variants[i]
+					testResults[k].AttackType = string(opts.AttackType)
+					testResults[k].CandidateBypass = request.Classify(testResults[k], string(opts.AttackType), nil) == request.OutcomeCandidateBypass
+				}
+				sent[i] = testResults
+			}
+		}()
+	}
+	for i := range variants {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var results []request.TestResult
+	for _, testResults := range sent {
+		results = append(results, testResults...)
+	}
+	return results, nil
+}
@@ -2,23 +2,85 @@ package payload
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"obfuskit/cmd"
+	"obfuskit/internal/evasions"
+	"obfuskit/internal/evasions/path"
 	"obfuskit/internal/genai"
 	"obfuskit/internal/logging"
 	"obfuskit/internal/model"
 	"obfuskit/internal/util"
 	"obfuskit/internal/waf"
+	"obfuskit/payloads"
 	"obfuskit/request"
 	"obfuskit/types"
 )
 
 func HandleGeneratePayloads(results *model.TestResults, level types.EvasionLevel, showProgress bool, threads int) error {
+	config, ok := results.Config.(*types.Config)
+	if !ok {
+		return fmt.Errorf("invalid config type in TestResults")
+	}
+	if err := generatePayloads(results, config, level, showProgress); err != nil {
+		return err
+	}
+
+	// -stdout prints the variants instead (see GenerateToWriter)
+	if config.Stdout {
+		return nil
+	}
+	savePayloads(results)
+	return nil
+}
+
+// generatePayloads generates the variants of every base payload into
+// results.PayloadResults and checks them against -require-techniques
+func generatePayloads(results *model.TestResults, config *types.Config, level types.EvasionLevel, showProgress bool) error {
+	err := StreamPayloads(results, level, showProgress, func(payloadResult model.PayloadResults) {
+		results.PayloadResults = append(results.PayloadResults, payloadResult)
+	})
+	if err != nil {
+		return err
+	}
+	if err := checkRequiredTechniques(results.PayloadResults, config.RequireTechniques); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Generated %d payload variants across %d base payloads\n",
+		GetTotalVariants(results), len(results.PayloadResults))
+	return nil
+}
+
+// savePayloads writes the generated variants to the payload files
+func savePayloads(results *model.TestResults) {
+	if err := util.SavePayloadsToFile(results); err != nil {
+		fmt.Printf("Warning: Failed to save payloads to file: %v\n", err)
+	} else {
+		fmt.Println("✅ Payloads saved to:")
+		fmt.Println("  - payloads_output.txt (detailed with metadata)")
+		fmt.Println("  - payloads_simple.txt (one payload per line)")
+	}
+}
+
+// StreamPayloads generates the variants of every base payload and hands them
+// to emit one payload result (a base payload under one encoding) at a time,
+// as soon as they are generated, instead of collecting them all first.
+// Payload results reach emit deduplicated, filtered and with their variant
+// IDs assigned, in the order HandleGeneratePayloads returns them; emit is
+// called from the calling goroutine. Nothing is written to disk.
+func StreamPayloads(results *model.TestResults, level types.EvasionLevel, showProgress bool, emit func(model.PayloadResults)) error {
 	logging.Infoln("\n🔧 Generating payloads...")
 
 	config, ok := results.Config.(*types.Config)
@@ -26,6 +88,90 @@ func HandleGeneratePayloads(results *model.TestResults, level types.EvasionLevel
 		return fmt.Errorf("invalid config type in TestResults")
 	}
 
+	basePayloads, err := loadGenerationPayloads(config, level)
+	if err != nil {
+		return err
+	}
+
+	// Count total payloads for progress tracking
+	totalPayloads := 0
+	for _, payloads := range basePayloads {
+		totalPayloads += len(payloads)
+	}
+
+	// Initialize progress bar
+	var progress *util.TaskProgress
+	if showProgress && totalPayloads > 0 {
+		progress = util.NewTaskProgress("Generating payloads", totalPayloads, true)
+	}
+
+	// Walk attack types in a fixed order so a seeded run draws the same
+	// random values for the same payloads every time
+	attackTypeKeys := make([]string, 0, len(basePayloads))
+	for attackType := range basePayloads {
+		attackTypeKeys = append(attackTypeKeys, attackType)
+	}
+	sort.Strings(attackTypeKeys)
+
+	var filterOptions *util.FilterOptions
+	if options, ok := config.FilterOptions.(*util.FilterOptions); ok {
+		filterOptions = options
+	}
+
+	// Each base payload's variants are generated into a scratch result,
+	// deduplicated against everything emitted before (the same base payload
+	// can come from several attack types) and filtered one by one
+	ctx := GenerationContext(config, 0)
+	scratch := &model.TestResults{Config: config}
+	seen := make(map[string]bool)
+	currentPayload, generated, emitted := 0, 0, 0
+	for _, attackType := range attackTypeKeys {
+		for _, payload := range basePayloads[attackType] {
+			scratch.PayloadResults = scratch.PayloadResults[:0]
+			if err := generateVariantsForPayload(ctx, scratch, payload, types.AttackType(attackType), level); err != nil {
+				return err
+			}
+			for _, payloadResult := range scratch.PayloadResults {
+				key := payloadResult.OriginalPayload + "|" + payloadResult.EvasionType
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				generated++
+				if filterOptions != nil {
+					if filterOptions.Limit > 0 && emitted >= filterOptions.Limit {
+						continue
+					}
+					filtered := util.FilterPayloadResults([]model.PayloadResults{payloadResult}, filterOptions)
+					if len(filtered) == 0 {
+						continue
+					}
+					payloadResult = filtered[0]
+				}
+				payloadResult.AssignVariantIDs()
+				emitted++
+				emit(payloadResult)
+			}
+
+			currentPayload++
+			if progress != nil {
+				progress.Update(currentPayload)
+			}
+		}
+	}
+
+	if progress != nil {
+		progress.Finish()
+	}
+	if filterOptions != nil {
+		util.PrintFilterSummary(filterOptions, generated, emitted)
+	}
+	return nil
+}
+
+// loadGenerationPayloads loads the base payloads of every configured attack
+// type, plus the AI-generated ones when AI is enabled, keyed by attack type
+func loadGenerationPayloads(config *types.Config, level types.EvasionLevel) (map[string][]string, error) {
 	// Handle multiple attack types
 	attackTypesToProcess := []types.AttackType{config.AttackType}
 
@@ -40,10 +186,19 @@ func HandleGeneratePayloads(results *model.TestResults, level types.EvasionLevel
 	globalSeenPayloads := make(map[string]bool) // Track payloads across all attack types
 
 	for _, attackType := range attackTypesToProcess {
-		basePayloads, err := LoadBasePayloads(attackType)
+		basePayloads, err := LoadBasePayloadsFrom(attackType, config.Payload.Dir, config.Payload.Files)
 		if err != nil {
 			logging.Warnf("Warning: Failed to load payloads for %s: %v\n", attackType, err)
-			continue
+			if attackType != types.AttackTypeFileAccess {
+				continue
+			}
+			basePayloads = make(map[string][]string)
+		}
+
+		// File access payloads are also generated from the sensitive file list
+		if attackType == types.AttackTypeFileAccess {
+			key := string(types.AttackTypeFileAccess)
+			basePayloads[key] = append(basePayloads[key], fileAccessBasePayloads(config)...)
 		}
 
 		// Merge payloads from this attack type with deduplication
@@ -62,8 +217,20 @@ func HandleGeneratePayloads(results *model.TestResults, level types.EvasionLevel
 		aiCfg, ok := config.AIConfig.(*genai.Config)
 		if !ok {
 			fmt.Println("Warning: Invalid AI configuration; skipping AI generation")
+		} else if engine := genai.NewEngine(aiCfg); !engine.IsConfigured() {
+			// Checked once here rather than failing once per attack type.
+			// The warning goes to stderr so that -stdout runs show it too.
+			if config.AIRequired {
+				return nil, fmt.Errorf("AI generation is required but the %s provider is not configured (missing API key or endpoint)", aiCfg.Provider)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: AI provider %s is not configured (missing API key or endpoint); generating without AI\n", aiCfg.Provider)
 		} else {
-			engine := genai.NewEngine(aiCfg)
+			// Every generation in the run adds to one set of analytics
+			if analytics, ok := config.AIAnalytics.(*genai.AnalyticsCollector); ok {
+				engine.Analytics = analytics
+			} else {
+				config.AIAnalytics = engine.GetAnalytics()
+			}
 			logging.Infoln("🤖 Using GenAI to generate additional base payloads...")
 
 			// Attempt to construct WAF context if available
@@ -122,65 +289,9 @@ func HandleGeneratePayloads(results *model.TestResults, level types.EvasionLevel
 	}
 
 	if len(allBasePayloads) == 0 {
-		return fmt.Errorf("no payloads could be loaded for any attack types")
+		return nil, fmt.Errorf("no payloads could be loaded for any attack types")
 	}
-
-	basePayloads := allBasePayloads
-
-	// Count total payloads for progress tracking
-	totalPayloads := 0
-	for _, payloads := range basePayloads {
-		totalPayloads += len(payloads)
-	}
-
-	// Initialize progress bar
-	var progress *util.TaskProgress
-	if showProgress && totalPayloads > 0 {
-		progress = util.NewTaskProgress("Generating payloads", totalPayloads, true)
-	}
-
-	currentPayload := 0
-	for attackType, payloads := range basePayloads {
-		for _, payload := range payloads {
-			if err := GenerateVariantsForPayload(results, payload, types.AttackType(attackType), level); err != nil {
-				return err
-			}
-
-			currentPayload++
-			if progress != nil {
-				progress.Update(currentPayload)
-			}
-		}
-	}
-
-	if progress != nil {
-		progress.Finish()
-	}
-
-	// Final deduplication across all results
-	results.PayloadResults = deduplicatePayloadResults(results.PayloadResults)
-
-	// Apply filtering if configured
-	originalCount := len(results.PayloadResults)
-	if config, ok := results.Config.(*types.Config); ok && config.FilterOptions != nil {
-		if filterOptions, ok := config.FilterOptions.(*util.FilterOptions); ok {
-			results.PayloadResults = util.FilterPayloadResults(results.PayloadResults, filterOptions)
-			util.PrintFilterSummary(filterOptions, originalCount, len(results.PayloadResults))
-		}
-	}
-
-	fmt.Printf("✅ Generated %d payload variants across %d base payloads\n",
-		GetTotalVariants(results), len(results.PayloadResults))
-
-	if err := util.SavePayloadsToFile(results); err != nil {
-		fmt.Printf("Warning: Failed to save payloads to file: %v\n", err)
-	} else {
-		fmt.Println("✅ Payloads saved to:")
-		fmt.Println("  - payloads_output.txt (detailed with metadata)")
-		fmt.Println("  - payloads_simple.txt (one payload per line)")
-	}
-
-	return nil
+	return allBasePayloads, nil
 }
 
 func HandleSendToURL(results *model.TestResults, level types.EvasionLevel, showProgress bool, threads int) error {
@@ -190,12 +301,15 @@ func HandleSendToURL(results *model.TestResults, level types.EvasionLevel, showP
 	if !ok {
 		return fmt.Errorf("invalid config type in TestResults")
 	}
+	// The probes below share the payloads' scope and request budget
+	injectorOptions, err := injectorOptionsFromConfig(config)
+	if err != nil {
+		return err
+	}
 
 	// Perform WAF fingerprinting if enabled
-	var wafFingerprint *waf.WAFFingerprint
 	if config.EnableFingerprinting {
-		var err error
-		wafFingerprint, err = waf.FingerprintWAF(config.Target.URL)
+		wafFingerprint, err := waf.FingerprintWAF(config.Target.URL, injectorOptions.Send)
 		if err != nil {
 			fmt.Printf("⚠️  WAF fingerprinting failed: %v\n", err)
 		} else {
@@ -212,97 +326,405 @@ func HandleSendToURL(results *model.TestResults, level types.EvasionLevel, showP
 		}
 	}
 
-	// First generate the payloads
-	err := HandleGeneratePayloads(results, level, showProgress, threads)
+	if config.ProbeNormalization {
+		probeNormalization(config, injectorOptions)
+	}
+
+	// Nuclei payloads are sent as written. -require-techniques must see every
+	// variant before anything is sent, and -shuffle needs them all to
+	// shuffle; otherwise variants are sent as they are generated
+	var source variantSource
+	if config.Payload.Source == types.PayloadSourceNuclei {
+		if err := importNucleiPayloads(results, config); err != nil {
+			return err
+		}
+	} else if len(config.RequireTechniques) > 0 || config.Shuffle {
+		if err := generatePayloads(results, config, level, showProgress); err != nil {
+			return err
+		}
+	} else {
+		source = func(enqueue func(model.PayloadResults)) error {
+			return StreamPayloads(results, level, showProgress, enqueue)
+		}
+	}
+	if err := sendVariants(results, config, injectorOptions, level, showProgress, threads, source); err != nil {
+		return err
+	}
+
+	// Sending needs no payload files; -save-payloads writes them anyway
+	if config.SavePayloads {
+		savePayloads(results)
+	}
+	return nil
+}
+
+// HandleCompareEncodings generates every encoding of the single configured
+// payload and sends the variants to the target, so the encodings can be
+// ranked against each other (-compare-encodings)
+func HandleCompareEncodings(results *model.TestResults, level types.EvasionLevel, showProgress bool, threads int) error {
+	config, ok := results.Config.(*types.Config)
+	if !ok {
+		return fmt.Errorf("invalid config type in TestResults")
+	}
+	if len(config.Payload.Custom) != 1 {
+		return fmt.Errorf("comparing encodings needs exactly one payload, got %d", len(config.Payload.Custom))
+	}
+	injectorOptions, err := injectorOptionsFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	payload := config.Payload.Custom[0]
+	attackType := config.AttackType
+	if attackType == "" {
+		attackType = util.DetectAttackType(payload)
+	}
+	if err := generateVariantsForPayload(GenerationContext(config, 0), results, payload, attackType, level); err != nil {
+		return err
+	}
+	assignVariantIDs(results.PayloadResults)
+	fmt.Printf("🧪 Comparing %d encodings of %s (%d variants)\n",
+		len(results.PayloadResults), payload, GetTotalVariants(results))
+
+	return sendVariants(results, config, injectorOptions, level, showProgress, threads, nil)
+}
+
+// variantSource feeds variants to sendVariants while it sends: it calls
+// enqueue with each payload result in turn and returns once it has no more
+type variantSource func(enqueue func(model.PayloadResults)) error
+
+// sendVariants sends every generated variant to the configured target with
+// each injector, using injectorOptions, and records the results. With a
+// source, the variants come from it instead of results.PayloadResults, are
+// sent as soon as it enqueues them and are appended to results.PayloadResults
+// as they go.
+func sendVariants(results *model.TestResults, config *types.Config, injectorOptions *request.InjectorOptions, level types.EvasionLevel, showProgress bool, threads int, source variantSource) error {
+	threads, err := types.ResolveThreads(threads)
 	if err != nil {
 		return err
 	}
 
-	// Then send them to the target URL
-	fmt.Printf("🚀 Sending %d payload variants to %s\n", GetTotalVariants(results), config.Target.URL)
+	targets, rejected := scopedTargets(targetURLs(config), injectorOptions.Scope)
+	for _, err := range rejected {
+		fmt.Printf("⛔ %v\n", err)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no target is in scope (%s)", strings.Join(config.Target.Scope, ","))
+	}
+	switch {
+	case source != nil && len(targets) == 1:
+		fmt.Printf("🚀 Sending payload variants to %s as they are generated\n", targets[0])
+	case source != nil:
+		fmt.Printf("🚀 Sending payload variants to %d targets as they are generated\n", len(targets))
+	case len(targets) == 1:
+		fmt.Printf("🚀 Sending %d payload variants to %s\n", GetTotalVariants(results), targets[0])
+	default:
+		fmt.Printf("🚀 Sending %d payload variants to %d targets\n", GetTotalVariants(results), len(targets))
+	}
 
-	totalVariants := GetTotalVariants(results)
-	var urlProgress *util.TaskProgress
-	if showProgress && totalVariants > 0 {
-		urlProgress = util.NewTaskProgress("Testing payloads", totalVariants, true)
+	// Optional clean response every result is compared with
+	var baseline *request.Baseline
+	if config.DiffBaseline != "" {
+		if baseline, err = request.LoadBaseline(config.DiffBaseline); err != nil {
+			return err
+		}
 	}
 
-	// Create a work queue for parallel processing
-	type workItem struct {
-		variant      string
-		payloadIndex int
-		variantIndex int
+	// Stored mode tags every request with a canary to look for on the view
+	// page. Variants of packed encodings are sent without: a canary appended
+	// to them would corrupt their encoding.
+	canaries := injectorOptions.Canaries
+	plainOptions := injectorOptions
+	if canaries != nil {
+		withoutCanaries := *injectorOptions
+		withoutCanaries.Canaries = nil
+		plainOptions = &withoutCanaries
 	}
 
-	workQueue := make(chan workItem, totalVariants)
-	var resultsMutex sync.Mutex
-	var wg sync.WaitGroup
-	var currentVariant int
-	var progressMutex sync.Mutex
+	// Optional sink that receives every result as it is produced
+	var sink request.ResultSink
+	if config.Sink != "" {
+		if sink, err = request.OpenSink(config.Sink); err != nil {
+			return err
+		}
+		defer func() {
+			if err := sink.Close(); err != nil {
+				fmt.Printf("⚠️  Failed to close result sink: %v\n", err)
+			}
+		}()
+	}
+	var sinkErrOnce sync.Once
+
+	// Optional AIMD limiter; -threads becomes the upper bound. Its
+	// concurrency changes are run progress, so they print at info level
+	// whatever OBFUSKIT_LOG_LEVEL says.
+	var limiter *request.AdaptiveConcurrency
+	if config.AdaptiveConcurrency {
+		limiter = request.NewAdaptiveConcurrency(threads, request.NewLoggerWithLevel(os.Stdout, request.LogLevelInfo))
+	}
 
-	// Create worker function
-	worker := func() {
-		defer wg.Done()
+	// -shuffle dispatches each round's variants in a seeded random order
+	var shuffler *rand.Rand
+	if config.Shuffle {
+		seed := config.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		shuffler = rand.New(rand.NewSource(seed))
+	}
+
+	// Each host gets at most -per-host-conns of the -threads workers
+	hostLimiter := request.NewHostLimiter(config.PerHostConns)
 
-		// Create a logger for this worker
-		logger := request.NewLogger(os.Stdout)
+	// Variants of one base payload sent to one target (across evasion types
+	// and escalation rounds) share a state, for -stop-on-first-bypass and
+	// -adaptive-escalate
+	payloadStates := make(map[string]*payloadState)
+	var resultsMutex sync.Mutex
 
-		// Create injectors for this worker
-		injectors := []request.FastHTTPInjector{
-			request.NewFastHTTPHeaderInjector(),
-			request.NewFastHTTPQueryInjector(),
-			request.NewFastHTTPBodyInjector(),
-			request.NewFastHTTPProtocolInjector(),
+	// sendRound sends every variant source enqueues with all injectors; the
+	// progress bar needs the number of variants up front
+	sendRound := func(source variantSource, totalVariants int, progressLabel string) error {
+		var urlProgress *util.TaskProgress
+		if showProgress && totalVariants > 0 {
+			urlProgress = util.NewTaskProgress(progressLabel, totalVariants, true)
 		}
 
-		for work := range workQueue {
-			if !showProgress {
-				fmt.Printf("Testing payload %d variant %d\r", work.payloadIndex+1, work.variantIndex+1)
+		workQueue := newHostQueue(hostLimiter, hostQueueCapacity)
+		var wg sync.WaitGroup
+		var currentVariant int
+		var progressMutex sync.Mutex
+
+		// Create worker function
+		worker := func() {
+			defer wg.Done()
+
+			// Create a logger for this worker
+			logger := request.NewLogger(os.Stdout)
+
+			// Create injectors for this worker
+			injectors := request.NewInjectors(injectorOptions)
+			plainInjectors := injectors
+			if plainOptions != injectorOptions {
+				plainInjectors = request.NewInjectors(plainOptions)
 			}
 
-			// Test this variant with all injectors
-			for _, injector := range injectors {
-				testResults := injector.Inject(config.Target.URL, work.variant, logger)
+			for {
+				work, ok := workQueue.pop()
+				if !ok {
+					return
+				}
+				// Drain the queue without sending once the request cap is hit
+				if injectorOptions.Budget.Exhausted() {
+					workQueue.done(work.host)
+					continue
+				}
+				// Another variant of this payload already got through
+				if config.StopOnFirstBypass && work.state.bypassed.Load() {
+					workQueue.done(work.host)
+					if urlProgress != nil {
+						progressMutex.Lock()
+						currentVariant++
+						urlProgress.Update(currentVariant)
+						progressMutex.Unlock()
+					}
+					continue
+				}
+				if !showProgress {
+					fmt.Printf("Testing payload %d variant %d\r", work.payloadIndex+1, work.variantIndex+1)
+				}
+
+				// Test this variant with all injectors
+				variantInjectors := injectors
+				if !request.CarriesCanary(work.variant) {
+					variantInjectors = plainInjectors
+				}
+				for _, injector := range variantInjectors {
+					if injectorOptions.Budget.Exhausted() || (config.StopOnFirstBypass && work.state.bypassed.Load()) {
+						break
+					}
+					if limiter != nil {
+						limiter.Acquire()
+					}
+					testResults := injector.Inject(work.target, work.variant.Value, logger)
+					for k := range testResults {
+						testResults[k].Variant = work.variant
+						testResults[k].AttackType = work.attackType
+						outcome := request.Classify(testResults[k], work.attackType, config.InterestingStatusCodes)
+						testResults[k].CandidateBypass = outcome == request.OutcomeCandidateBypass
+						testResults[k].Deviations = baseline.Deviations(testResults[k])
+						switch outcome {
+						case request.OutcomeBypassed, request.OutcomeCandidateBypass:
+							work.state.bypassed.Store(true)
+						case request.OutcomeBlocked:
+							work.state.blocked.Store(true)
+						}
+					}
+					if limiter != nil {
+						limiter.Release(request.IsFailure(testResults))
+					}
+
+					// Thread-safe append to results
+					resultsMutex.Lock()
+					results.RequestResults = append(results.RequestResults, testResults...)
+					resultsMutex.Unlock()
+
+					if sink != nil {
+						for _, result := range testResults {
+							if err := sink.Write(result); err != nil {
+								sinkErrOnce.Do(func() { fmt.Printf("\n⚠️  Result sink write failed: %v\n", err) })
+							}
+						}
+					}
+				}
+
+				workQueue.done(work.host)
 
-				// Thread-safe append to results
-				resultsMutex.Lock()
-				results.RequestResults = append(results.RequestResults, testResults...)
-				resultsMutex.Unlock()
+				// Update progress thread-safely
+				if urlProgress != nil {
+					progressMutex.Lock()
+					currentVariant++
+					urlProgress.Update(currentVariant)
+					progressMutex.Unlock()
+				}
 			}
+		}
 
-			// Update progress thread-safely
-			if urlProgress != nil {
-				progressMutex.Lock()
-				currentVariant++
-				urlProgress.Update(currentVariant)
-				progressMutex.Unlock()
+		// Start the workers, then queue the work items as source yields
+		// them, fanning each variant out to every target
+		for i := 0; i < threads; i++ {
+			wg.Add(1)
+			go worker()
+		}
+		dispatch := workQueue.push
+		var shuffled []workItem
+		if shuffler != nil {
+			dispatch = func(item workItem) { shuffled = append(shuffled, item) }
+		}
+		payloadIndex := 0
+		err := source(func(payloadResult model.PayloadResults) {
+			for j, variant := range payloadResult.Variants {
+				for _, target := range targets {
+					key := target + "\x00" + payloadResult.AttackType + "\x00" + payloadResult.OriginalPayload
+					state := payloadStates[key]
+					if state == nil {
+						state = &payloadState{attackType: types.AttackType(payloadResult.AttackType), payload: payloadResult.OriginalPayload}
+						payloadStates[key] = state
+					}
+					dispatch(workItem{
+						variant:      variant,
+						attackType:   payloadResult.AttackType,
+						payloadIndex: payloadIndex,
+						variantIndex: j,
+						target:       target,
+						host:         request.HostKey(target),
+						state:        state,
+					})
+				}
 			}
+			payloadIndex++
+		})
+		if shuffler != nil {
+			shuffleWork(shuffled, shuffler)
+			for _, item := range shuffled {
+				workQueue.push(item)
+			}
+		}
+
+		// Wait for the queue to drain
+		workQueue.close()
+		wg.Wait()
+
+		if urlProgress != nil {
+			urlProgress.Finish()
 		}
+		return err
 	}
 
-	// Start workers
-	for i := 0; i < threads; i++ {
-		wg.Add(1)
-		go worker()
+	totalVariants := GetTotalVariants(results) * len(targets)
+	if source == nil {
+		source = payloadSource(results.PayloadResults)
+	} else {
+		// Streamed variants are recorded as they are sent
+		stream := source
+		source = func(enqueue func(model.PayloadResults)) error {
+			return stream(func(payloadResult model.PayloadResults) {
+				results.PayloadResults = append(results.PayloadResults, payloadResult)
+				enqueue(payloadResult)
+			})
+		}
+		totalVariants = 0
+	}
+	if err := sendRound(source, totalVariants, "Testing payloads"); err != nil {
+		return err
 	}
 
-	// Queue all work items
-	for i, payloadResult := range results.PayloadResults {
-		for j, variant := range payloadResult.Variants {
-			workQueue <- workItem{
-				variant:      variant,
-				payloadIndex: i,
-				variantIndex: j,
+	// Retry payloads that were only ever blocked at higher evasion levels
+	if config.AdaptiveEscalate {
+		escalations := config.MaxEscalations
+		if escalations <= 0 {
+			escalations = DefaultMaxEscalations
+		}
+		sent := make(map[string]bool)
+		for _, payloadResult := range results.PayloadResults {
+			for _, variant := range payloadResult.Variants {
+				sent[payloadResult.AttackType+"\x00"+variant.Value] = true
 			}
 		}
+		for round := 1; round <= escalations && !injectorOptions.Budget.Exhausted(); round++ {
+			next, ok := nextEvasionLevel(level)
+			if !ok {
+				break
+			}
+			level = next
+			escalated := escalatePayloads(config, payloadStates, level, sent)
+			if len(escalated) == 0 {
+				break
+			}
+			fmt.Printf("\n⬆️  Escalating %d blocked payloads to %s evasion (%d variants, round %d/%d)\n",
+				len(escalated), level, GetTotalVariants(&model.TestResults{PayloadResults: escalated}), round, escalations)
+			escalatedVariants := GetTotalVariants(&model.TestResults{PayloadResults: escalated}) * len(targets)
+			if err := sendRound(payloadSource(escalated), escalatedVariants, fmt.Sprintf("Escalation %d", round)); err != nil {
+				return err
+			}
+			results.PayloadResults = append(results.PayloadResults, escalated...)
+		}
 	}
 
-	// Close queue and wait for completion
-	close(workQueue)
-	wg.Wait()
+	// Second phase of stored mode: which canaries did the view page keep?
+	if canaries != nil {
+		found, err := request.FetchCanaries(config.ViewURL, injectorOptions)
+		if err != nil {
+			fmt.Printf("\n⚠️  Stored check failed: could not fetch %s: %v\n", config.ViewURL, err)
+		} else {
+			stored := request.MarkStored(results.RequestResults, found)
+			fmt.Printf("\n💾 %d/%d requests stored their payload: its canary appeared on %s\n",
+				stored, len(results.RequestResults), config.ViewURL)
+		}
+	}
+
+	if warning := request.CacheWarning(results.RequestResults); warning != "" {
+		fmt.Printf("\n%s\n", warning)
+	}
+
+	if limiter != nil {
+		fmt.Printf("\n⚙️  Adaptive concurrency finished at %d/%d workers\n", limiter.Limit(), threads)
+	}
+
+	if config.StopOnFirstBypass {
+		bypassedCount := 0
+		for _, state := range payloadStates {
+			if state.bypassed.Load() {
+				bypassedCount++
+			}
+		}
+		fmt.Printf("\n⏭️  %d/%d payloads bypassed; their remaining variants were skipped (-stop-on-first-bypass)\n",
+			bypassedCount, len(payloadStates))
+	}
 
-	if urlProgress != nil {
-		urlProgress.Finish()
+	if budget := injectorOptions.Budget; budget.Exhausted() {
+		fmt.Printf("\n🛑 Request cap reached: sent %d/%d requests (-max-requests); remaining variants were not tested\n",
+			budget.Sent(), budget.Max())
 	}
 
 	// Preserve full set before filtering for consistent reporting baselines
@@ -326,6 +748,165 @@ func HandleSendToURL(results *model.TestResults, level types.EvasionLevel, showP
 	return nil
 }
 
+// shuffleWork puts items in a random order drawn from rng
+func shuffleWork(items []workItem, rng *rand.Rand) {
+	rng.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+}
+
+// payloadSource is a variantSource enqueuing payloadResults in order
+func payloadSource(payloadResults []model.PayloadResults) variantSource {
+	return func(enqueue func(model.PayloadResults)) error {
+		for _, payloadResult := range payloadResults {
+			enqueue(payloadResult)
+		}
+		return nil
+	}
+}
+
+// assignVariantIDs populates the stable variant IDs once the final set of
+// variants is known (after deduplication and filtering)
+func assignVariantIDs(payloadResults []model.PayloadResults) {
+	for i := range payloadResults {
+		payloadResults[i].AssignVariantIDs()
+	}
+}
+
+// fileAccessBasePayloads expands the sensitive file list (or the configured
+// wordlist) into ../ traversal payloads
+func fileAccessBasePayloads(config *types.Config) []string {
+	targets := path.DefaultSensitiveFiles
+	if config.Payload.SensitiveFiles != "" {
+		loaded, err := path.LoadSensitiveFiles(config.Payload.SensitiveFiles)
+		if err != nil {
+			logging.Warnf("Warning: Failed to load sensitive file list %s: %v\n", config.Payload.SensitiveFiles, err)
+		} else if len(loaded) > 0 {
+			targets = loaded
+		}
+	}
+	depth := path.DefaultFileAccessDepth
+	if config.MaxTraversalDepth > 0 {
+		depth = config.MaxTraversalDepth
+	}
+	return path.FileAccessPayloads(targets, depth)
+}
+
+// targetURLs returns every URL variants are sent to
+func targetURLs(config *types.Config) []string {
+	if len(config.Target.URLs) > 0 {
+		return config.Target.URLs
+	}
+	return []string{config.Target.URL}
+}
+
+// scopedTargets splits targets into those scope allows and an error for
+// each one it refuses
+func scopedTargets(targets []string, scope *request.Scope) ([]string, []error) {
+	var allowed []string
+	var rejected []error
+	for _, target := range targets {
+		if err := scope.Check(target); err != nil {
+			rejected = append(rejected, err)
+			continue
+		}
+		allowed = append(allowed, target)
+	}
+	return allowed, rejected
+}
+
+// ShowNormalizedTargets writes each target URL next to the normalized form
+// the injectors will actually request (scheme and port filled in, fragment
+// dropped), so scheme, port and IPv6 surprises show up before sending
+func ShowNormalizedTargets(w io.Writer, config *types.Config) error {
+	fmt.Fprintln(w, "🎯 Normalized targets:")
+	for _, target := range targetURLs(config) {
+		normalized, err := request.NormalizeURL(target)
+		if err != nil {
+			return fmt.Errorf("cannot normalize target %q: %w", target, err)
+		}
+		fmt.Fprintf(w, "  %s → %s\n", target, normalized)
+	}
+	if config.Target.Host != "" {
+		fmt.Fprintf(w, "  (Host header: %s)\n", config.Target.Host)
+	}
+	return nil
+}
+
+// injectorOptionsFromConfig builds the shared injector settings from the config
+func injectorOptionsFromConfig(config *types.Config) (*request.InjectorOptions, error) {
+	opts := request.DefaultInjectorOptions()
+	if len(config.Target.ParamNames) > 0 {
+		opts.ParamNames = config.Target.ParamNames
+	}
+	opts.Host = config.Target.Host
+	opts.UserAgent = config.Target.UserAgent
+	if config.Target.RotateUserAgents {
+		opts.UserAgents = request.NewUserAgentRotator(nil, config.Target.UserAgentSeed)
+	}
+	if config.Target.Diversify {
+		opts.Diversifier = request.NewHeaderDiversifier(config.Seed, config.Target.DiversifyPadding)
+		if opts.UserAgents == nil && opts.UserAgent == "" {
+			opts.UserAgents = request.NewUserAgentRotator(nil, config.Seed)
+		}
+	}
+	if config.MaxRequests > 0 {
+		opts.Budget = request.NewRequestBudget(config.MaxRequests)
+	}
+	if config.Target.CookieJar {
+		opts.CookieJar = request.NewCookieJar()
+	}
+	if config.ViewURL != "" {
+		opts.Canaries = request.NewCanaryTracker()
+	}
+	opts.CacheBust = config.Target.CacheBust
+	opts.FollowRedirects = config.Target.FollowRedirects
+	opts.MaxRedirects = config.Target.MaxRedirects
+	opts.ResetIsBlock = config.Target.ResetIsBlock
+	opts.Timing = config.Target.Timing
+	opts.ReadBufferSize = config.Target.ReadBufferSize
+	opts.MaxResponseBodySize = config.Target.MaxResponseSize
+	if len(config.Target.SourceIPs) > 0 {
+		rotator, err := request.NewSourceAddressRotator(config.Target.SourceIPs, config.Seed)
+		if err != nil {
+			return nil, err
+		}
+		opts.SourceAddresses = rotator
+	}
+	scope, err := request.ParseScope(config.Target.Scope)
+	if err != nil {
+		return nil, err
+	}
+	opts.Scope = scope
+	opts.DisableConnect = config.Target.DisableConnect
+	opts.DisableChunked = config.Target.DisableChunked
+	opts.DisableMultipleContentLength = config.Target.DisableMultipleContentLength
+	opts.HeaderNameInjection = config.Target.HeaderNameInjection
+	opts.Pipeline = config.Target.Pipeline
+	if len(config.Target.SplitParams) > 0 {
+		if err := request.ValidateSplit(config.Target.SplitParams, config.Target.SplitAt); err != nil {
+			return nil, err
+		}
+		opts.SplitParams = config.Target.SplitParams
+		opts.SplitAt = config.Target.SplitAt
+	}
+	if config.Target.BlockSignaturesFile != "" {
+		signatures, err := request.LoadBlockSignatures(config.Target.BlockSignaturesFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.BlockSignatures = signatures
+	}
+	if config.Target.BodyFile != "" {
+		template, err := request.LoadBodyTemplate(config.Target.BodyFile, config.Target.ContentType)
+		if err != nil {
+			return nil, err
+		}
+		opts.BodyTemplate = template
+	}
+	return opts, nil
+}
+
 func HandleExistingPayloads(results *model.TestResults, level types.EvasionLevel, showProgress bool, threads int) error {
 	fmt.Println("\n📁 Processing existing payloads...")
 
@@ -334,9 +915,12 @@ func HandleExistingPayloads(results *model.TestResults, level types.EvasionLevel
 		return fmt.Errorf("invalid config type in TestResults")
 	}
 
-	var payloads []string
-	var err error
+	threads, err := types.ResolveThreads(threads)
+	if err != nil {
+		return err
+	}
 
+	var payloads []string
 	switch config.Payload.Source {
 	case "From File":
 		payloads, err = util.LoadPayloadsFromFile(config.Payload.FilePath)
@@ -355,21 +939,53 @@ func HandleExistingPayloads(results *model.TestResults, level types.EvasionLevel
 		existingProgress = util.NewTaskProgress("Processing payloads", len(payloads), true)
 	}
 
-	// Process each existing payload
-	for i, payload := range payloads {
-		// Try to detect attack type or use a generic approach
-		attackType := util.DetectAttackType(payload)
-		err := GenerateVariantsForPayload(results, payload, attackType, level)
-		if err != nil {
-			fmt.Printf("Warning: Failed to generate variants for payload '%s': %v\n", payload, err)
-			continue
-		}
+	// Each payload's variants land in its own slot and are appended in input
+	// order afterwards, so the output does not depend on the thread count.
+	// Seeded runs draw payload i's random values from stream i for the same reason.
+	slots := make([][]model.PayloadResults, len(payloads))
+	workQueue := make(chan int, len(payloads))
+	var wg sync.WaitGroup
+	var processed int
+	var progressMutex sync.Mutex
 
-		if existingProgress != nil {
-			existingProgress.Update(i + 1)
+	worker := func() {
+		defer wg.Done()
+		for i := range workQueue {
+			payload := payloads[i]
+
+			// Try to detect attack type or use a generic approach
+			attackType := util.DetectAttackType(payload)
+			slot := &model.TestResults{Config: config}
+			err := generateVariantsForPayload(GenerationContext(config, i), slot, payload, attackType, level)
+			if err != nil {
+				fmt.Printf("Warning: Failed to generate variants for payload '%s': %v\n", payload, err)
+			} else {
+				slots[i] = slot.PayloadResults
+			}
+
+			if existingProgress != nil {
+				progressMutex.Lock()
+				processed++
+				existingProgress.Update(processed)
+				progressMutex.Unlock()
+			}
 		}
 	}
 
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go worker()
+	}
+	for i := range payloads {
+		workQueue <- i
+	}
+	close(workQueue)
+	wg.Wait()
+
+	for _, slot := range slots {
+		results.PayloadResults = append(results.PayloadResults, slot...)
+	}
+
 	if existingProgress != nil {
 		existingProgress.Finish()
 	}
@@ -382,6 +998,7 @@ func HandleExistingPayloads(results *model.TestResults, level types.EvasionLevel
 			util.PrintFilterSummary(filterOptions, originalCount, len(results.PayloadResults))
 		}
 	}
+	assignVariantIDs(results.PayloadResults)
 
 	fmt.Printf("✅ Processed %d existing payloads into %d variants\n",
 		len(payloads), GetTotalVariants(results))
@@ -389,20 +1006,84 @@ func HandleExistingPayloads(results *model.TestResults, level types.EvasionLevel
 	return nil
 }
 
+// GenerationContext carries the random source for generation stream id (a
+// worker, or a payload index when output must not depend on scheduling), the
+// configured -max-depth and the -traversal-target. Without a configured seed the shared global
+// source is used.
+func GenerationContext(config *types.Config, worker int) context.Context {
+	ctx := context.Background()
+	if config == nil {
+		return ctx
+	}
+	if config.Seed != 0 {
+		ctx = evasions.WithRand(ctx, evasions.NewRand(config.Seed, worker))
+	}
+	ctx = path.WithMaxTraversalDepth(ctx, config.MaxTraversalDepth)
+	return path.WithTargetFile(ctx, config.TraversalTarget)
+}
+
 func GenerateVariantsForPayload(results *model.TestResults, payload string, attackType types.AttackType, level types.EvasionLevel) error {
-	evasions, exists := cmd.GetEvasionsForPayload(attackType)
+	config, _ := results.Config.(*types.Config)
+	return generateVariantsForPayload(GenerationContext(config, 0), results, payload, attackType, level)
+}
+
+func generateVariantsForPayload(ctx context.Context, results *model.TestResults, payload string, attackType types.AttackType, level types.EvasionLevel) error {
+	config, _ := results.Config.(*types.Config)
+	levels := encodingLevels(config)
+
+	// Exhaustive generation runs every encoding at its highest level
+	exhaustive := config != nil && config.Exhaustive
+	if exhaustive {
+		level, levels = types.EvasionLevelAdvanced, nil
+	}
+
+	// With per-encoding levels an encoding's own level decides whether it
+	// applies, so start from every encoding for the attack type
+	var evasionTypes []types.PayloadEncoding
+	exists := false
+	if len(levels) > 0 {
+		var all []types.PayloadEncoding
+		all, exists = cmd.GetEvasionsForPayload(attackType)
+		for _, evasionType := range all {
+			if cmd.EvasionAppliesAtLevel(evasionType, levelFor(levels, evasionType, level)) {
+				evasionTypes = append(evasionTypes, evasionType)
+			}
+		}
+	} else {
+		evasionTypes, exists = cmd.GetEvasionsForLevel(attackType, level)
+	}
 	if !exists {
-		evasions = []types.PayloadEncoding{
+		evasionTypes = []types.PayloadEncoding{
 			types.PayloadEncodingBase64,
 			types.PayloadEncodingHex,
 			types.PayloadEncodingUnicode,
 		}
 	}
 
-	filteredEvasions := FilterEvasionEncodings(evasions, results.Config)
+	filteredEvasions := prioritizeDecoded(FilterEvasionEncodings(evasionTypes, results.Config), config)
+
+	// Optionally keep only a few variants per technique for breadth, drop
+	// variants that no longer carry the attack (-strict-variants), and keep
+	// only the chosen techniques (-only-techniques, -exclude-techniques)
+	distinct, strict := 0, false
+	var only, exclude []string
+	if config != nil {
+		distinct, strict = config.DistinctTechniques, config.StrictVariants
+		only, exclude = config.OnlyTechniques, config.ExcludeTechniques
+	}
 
 	for _, evasionType := range filteredEvasions {
-		variants, err := cmd.ApplyEvasion(payload, evasionType, level)
+		level := levelFor(levels, evasionType, level)
+
+		// Labeled variants so sampling can tell techniques apart. Under
+		// -exhaustive the encoders enumerate their draws instead of making
+		// them (see evasions.EnumerateDraws), so the output depends on
+		// neither the worker nor the order payloads are generated in.
+		encodingCtx := ctx
+		if exhaustive {
+			encodingCtx = evasions.WithExhaustive(ctx)
+		}
+		variants, err := cachedExplainEvasion(encodingCtx, payload, evasionType, level)
 		if err != nil {
 			fmt.Printf("Warning: Failed to apply %s to payload: %v\n", evasionType, err)
 			continue
@@ -410,21 +1091,19 @@ func GenerateVariantsForPayload(results *model.TestResults, payload string, atta
 
 		// Deduplicate variants within this evasion type
 		if len(variants) > 0 {
-			seenVariants := make(map[string]bool)
-			deduplicatedVariants := []string{}
-			for _, variant := range variants {
-				if !seenVariants[variant] {
-					deduplicatedVariants = append(deduplicatedVariants, variant)
-					seenVariants[variant] = true
-				}
+			unique := evasions.UniqueVariants(variants)
+			if strict {
+				unique = FilterIntact(attackType, payload, unique)
 			}
+			unique = FilterTechniques(unique, evasionType, only, exclude)
+			sampled := evasions.SampleDistinct(unique, distinct)
 
-			if len(deduplicatedVariants) > 0 {
+			if len(sampled) > 0 {
 				results.PayloadResults = append(results.PayloadResults, model.PayloadResults{
 					OriginalPayload: payload,
 					AttackType:      string(attackType),
 					EvasionType:     string(evasionType),
-					Variants:        deduplicatedVariants,
+					Variants:        sampled,
 					Level:           string(level),
 				})
 			}
@@ -433,6 +1112,34 @@ func GenerateVariantsForPayload(results *model.TestResults, payload string, atta
 	return nil
 }
 
+// encodingLevels parses config.EncodingLevels, skipping entries that name an
+// unknown encoding or level (validation reports those)
+func encodingLevels(config *types.Config) map[types.PayloadEncoding]types.EvasionLevel {
+	if config == nil || len(config.EncodingLevels) == 0 {
+		return nil
+	}
+	levels := make(map[types.PayloadEncoding]types.EvasionLevel)
+	for name, value := range config.EncodingLevels {
+		encoding, ok := types.ParsePayloadEncoding(name)
+		if !ok {
+			continue
+		}
+		if level, ok := types.ParseEvasionLevel(value); ok {
+			levels[encoding] = level
+		}
+	}
+	return levels
+}
+
+// levelFor returns the level encoding is generated at: its override, if any,
+// or level
+func levelFor(levels map[types.PayloadEncoding]types.EvasionLevel, encoding types.PayloadEncoding, level types.EvasionLevel) types.EvasionLevel {
+	if override, ok := levels[encoding]; ok {
+		return override
+	}
+	return level
+}
+
 // deduplicatePayloadResults removes duplicate payload results based on original payload and evasion type
 func deduplicatePayloadResults(results []model.PayloadResults) []model.PayloadResults {
 	seen := make(map[string]bool)
@@ -465,6 +1172,7 @@ func FilterEvasionEncodings(evasions []types.PayloadEncoding, config interface{}
 		encodingTypes := map[types.PayloadEncoding]bool{
 			types.PayloadEncodingBase64: true, types.PayloadEncodingHex: true, types.PayloadEncodingHTML: true,
 			types.PayloadEncodingUnicode: true, types.PayloadEncodingOctal: true, types.PayloadEncodingBestFit: true,
+			types.PayloadEncodingInterleaved: true, types.PayloadEncodingUTF7: true,
 		}
 		for _, evasion := range evasions {
 			if encodingTypes[evasion] {
@@ -492,7 +1200,18 @@ func FilterEvasionEncodings(evasions []types.PayloadEncoding, config interface{}
 	return filtered
 }
 
+// DefaultPayloadsDir is where base payload files are read from by default
+const DefaultPayloadsDir = "payloads"
+
+// LoadBasePayloads loads the base payloads for attackType from DefaultPayloadsDir
 func LoadBasePayloads(attackType types.AttackType) (map[string][]string, error) {
+	return LoadBasePayloadsFrom(attackType, DefaultPayloadsDir, nil)
+}
+
+// LoadBasePayloadsFrom loads base payloads from dir, reading <attack type>.txt
+// unless files maps the attack type to another file. An empty dir means
+// DefaultPayloadsDir.
+func LoadBasePayloadsFrom(attackType types.AttackType, dir string, files map[string]string) (map[string][]string, error) {
 	payloads := make(map[string][]string)
 	attackTypes := []types.AttackType{}
 	if attackType == types.AttackTypeGeneric {
@@ -524,9 +1243,15 @@ func LoadBasePayloads(attackType types.AttackType) (map[string][]string, error)
 	} else {
 		attackTypes = []types.AttackType{attackType}
 	}
+	// Only the default location falls back to the embedded payload files
+	useEmbedded := dir == "" || dir == DefaultPayloadsDir
+
 	for _, aType := range attackTypes {
-		filePath := filepath.Join("payloads", string(aType)+".txt")
+		filePath := payloadFilePath(aType, dir, files)
 		filePayloads, err := LoadPayloadsFromFile(filePath)
+		if errors.Is(err, fs.ErrNotExist) && useEmbedded && files[string(aType)] == "" {
+			filePayloads, err = loadEmbeddedPayloads(aType)
+		}
 		if err != nil {
 			fmt.Printf("Warning: Could not load payloads for %s: %v\n", aType, err)
 			continue
@@ -549,14 +1274,43 @@ func LoadBasePayloads(attackType types.AttackType) (map[string][]string, error)
 	return payloads, nil
 }
 
+// payloadFilePath resolves the payload file for an attack type
+func payloadFilePath(attackType types.AttackType, dir string, files map[string]string) string {
+	if dir == "" {
+		dir = DefaultPayloadsDir
+	}
+	if file, ok := files[string(attackType)]; ok && file != "" {
+		if filepath.IsAbs(file) {
+			return file
+		}
+		return filepath.Join(dir, file)
+	}
+	return filepath.Join(dir, string(attackType)+".txt")
+}
+
+// loadEmbeddedPayloads reads the payload file built into the binary
+func loadEmbeddedPayloads(attackType types.AttackType) ([]string, error) {
+	file, err := payloads.Files.Open(string(attackType) + ".txt")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readPayloads(file)
+}
+
 func LoadPayloadsFromFile(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
+	return readPayloads(file)
+}
+
+// readPayloads returns one payload per line, skipping blank lines and # comments
+func readPayloads(r io.Reader) ([]string, error) {
 	var payloads []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line != "" && !strings.HasPrefix(line, "#") {
@@ -0,0 +1,1536 @@
+package payload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"obfuskit/internal/evasions"
+	"obfuskit/internal/evasions/path"
+	"obfuskit/internal/genai"
+	"obfuskit/internal/model"
+	"obfuskit/internal/report"
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+// withPayloadDir changes into a fresh directory holding files, named by
+// file name, and returns it. Generation saves its output to the working
+// directory, so every test that generates runs inside one.
+func withPayloadDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write payloads: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestLoadBasePayloadsFromOverrides(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return path
+	}
+
+	write("xss.txt", "<svg onload=alert(1)>\n")
+	write("sql-injection.txt", "# comment\n' OR 1=1 --\nadmin'--\n")
+	absolute := write("custom-ldap.list", "*)(uid=*\n")
+
+	tests := []struct {
+		name       string
+		attackType types.AttackType
+		files      map[string]string
+		expected   []string
+	}{
+		{"default file name in dir", types.AttackTypeXSS, nil, []string{"<svg onload=alert(1)>"}},
+		{"relative mapped file", types.AttackTypeSQLI, map[string]string{"sqli": "sql-injection.txt"}, []string{"' OR 1=1 --", "admin'--"}},
+		{"absolute mapped file", types.AttackTypeLDAP, map[string]string{"ldapi": absolute}, []string{"*)(uid=*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payloads, err := LoadBasePayloadsFrom(tt.attackType, dir, tt.files)
+			if err != nil {
+				t.Fatalf("LoadBasePayloadsFrom() error: %v", err)
+			}
+			got := payloads[string(tt.attackType)]
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("payloads = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := LoadBasePayloadsFrom(types.AttackTypeSQLI, dir, nil); err == nil {
+		t.Error("expected an error for sqli without a mapping, as sqli.txt does not exist")
+	}
+}
+
+func TestLoadBasePayloadsFallsBackToEmbedded(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	if _, err := os.Stat(DefaultPayloadsDir); !os.IsNotExist(err) {
+		t.Fatalf("expected no %s directory in the working dir", DefaultPayloadsDir)
+	}
+
+	payloads, err := LoadBasePayloads(types.AttackTypeXSS)
+	if err != nil {
+		t.Fatalf("LoadBasePayloads() error: %v", err)
+	}
+	if len(payloads[string(types.AttackTypeXSS)]) == 0 {
+		t.Error("expected embedded xss payloads")
+	}
+
+	// An explicit directory does not fall back
+	if _, err := LoadBasePayloadsFrom(types.AttackTypeXSS, "missing-dir", nil); err == nil {
+		t.Error("expected an error for a missing -payloads-dir")
+	}
+}
+
+func TestHandleSendToURLStopsAtMaxRequests(t *testing.T) {
+	var received atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	}))
+	defer server.Close()
+
+	// Generation saves payload files to the working directory
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n<svg onload=alert(1)>\n"})
+
+	const maxRequests = 25
+	// Fingerprinting probes count against the cap too
+	for _, fingerprint := range []bool{false, true} {
+		received.Store(0)
+		config := &types.Config{
+			Action:               types.ActionSendToURL,
+			AttackType:           types.AttackTypeXSS,
+			EvasionLevel:         types.EvasionLevelBasic,
+			Payload:              types.Payload{Dir: dir},
+			Target:               types.Target{URL: server.URL},
+			MaxRequests:          maxRequests,
+			EnableFingerprinting: fingerprint,
+		}
+		results := &model.TestResults{Config: config}
+		if err := HandleSendToURL(results, types.EvasionLevelBasic, true, 4); err != nil {
+			t.Fatalf("HandleSendToURL() error: %v", err)
+		}
+
+		// Some fingerprinting probes are malformed and never reach the handler
+		if got := received.Load(); got > maxRequests || (!fingerprint && got != maxRequests) {
+			t.Errorf("fingerprint=%v: server received %d requests, want the cap of %d", fingerprint, got, maxRequests)
+		}
+		if len(results.RequestResults) > maxRequests {
+			t.Errorf("fingerprint=%v: recorded %d results, more than the cap of %d", fingerprint, len(results.RequestResults), maxRequests)
+		}
+	}
+}
+
+func TestHandleSendToURLStopsOnFirstBypass(t *testing.T) {
+	// Every request gets through, so each payload's first variant bypasses
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n<svg onload=alert(1)>\n"})
+
+	for _, threads := range []int{1, 4} {
+		config := &types.Config{
+			Action:            types.ActionSendToURL,
+			AttackType:        types.AttackTypeXSS,
+			EvasionLevel:      types.EvasionLevelBasic,
+			Payload:           types.Payload{Dir: dir},
+			Target:            types.Target{URL: server.URL},
+			StopOnFirstBypass: true,
+		}
+		results := &model.TestResults{Config: config}
+		if err := HandleSendToURL(results, types.EvasionLevelBasic, true, threads); err != nil {
+			t.Fatalf("HandleSendToURL() error: %v", err)
+		}
+
+		tested := map[string]map[string]bool{}
+		for _, result := range results.RequestResults {
+			original := result.Variant.SourcePayload
+			if tested[original] == nil {
+				tested[original] = map[string]bool{}
+			}
+			tested[original][result.Variant.ID] = true
+		}
+
+		if len(tested) != 2 {
+			t.Errorf("threads=%d: tested %d base payloads, want 2", threads, len(tested))
+		}
+		// A worker's first variant of a payload bypasses, so it skips the
+		// rest: at most one variant per worker and payload is tested
+		for original, variants := range tested {
+			if len(variants) > threads {
+				t.Errorf("threads=%d: %d variants of %q tested after its first bypass, want at most %d",
+					threads, len(variants), original, threads)
+			}
+		}
+	}
+}
+
+// memorySink records every result it receives
+type memorySink struct {
+	mu      sync.Mutex
+	results []request.TestResult
+	closed  bool
+}
+
+func (s *memorySink) Write(result request.TestResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, result)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestHandleSendToURLStreamsEveryResultToSink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n"})
+
+	sink := &memorySink{}
+	request.RegisterSink("memory", func(string) (request.ResultSink, error) { return sink, nil })
+
+	config := &types.Config{
+		Action:       types.ActionSendToURL,
+		AttackType:   types.AttackTypeXSS,
+		EvasionLevel: types.EvasionLevelBasic,
+		Payload:      types.Payload{Dir: dir},
+		Target:       types.Target{URL: server.URL},
+		Sink:         "memory:",
+	}
+	results := &model.TestResults{Config: config}
+	if err := HandleSendToURL(results, types.EvasionLevelBasic, true, 8); err != nil {
+		t.Fatalf("HandleSendToURL() error: %v", err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if !sink.closed {
+		t.Error("sink was not closed")
+	}
+	// Every request result exactly once: the same multiset on both sides
+	key := func(result request.TestResult) string {
+		return result.Variant.ID + "|" + result.EvasionTechnique + "|" + request.RecordRequest(result.Request).URL +
+			"|" + string(result.Request.Body())
+	}
+	counts := map[string]int{}
+	for _, result := range results.AllRequestResults {
+		counts[key(result)]++
+	}
+	for _, result := range sink.results {
+		counts[key(result)]--
+	}
+	if len(sink.results) != len(results.AllRequestResults) {
+		t.Errorf("sink received %d results, run produced %d", len(sink.results), len(results.AllRequestResults))
+	}
+	for k, n := range counts {
+		if n != 0 {
+			t.Errorf("result %q: %+d difference between run and sink", k, n)
+		}
+	}
+}
+
+func TestHandleSendToURLStreamsVariantsWithoutPayloadFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n<svg onload=alert(1)>\n"})
+	newConfig := func(action types.Action) *types.Config {
+		return &types.Config{
+			Action:       action,
+			AttackType:   types.AttackTypeXSS,
+			EvasionLevel: types.EvasionLevelBasic,
+			Payload:      types.Payload{Dir: dir},
+			Target:       types.Target{URL: server.URL},
+			Seed:         7,
+		}
+	}
+	payloadFiles := []string{"payloads_output.txt", "payloads_simple.txt"}
+
+	sent := &model.TestResults{Config: newConfig(types.ActionSendToURL)}
+	if err := HandleSendToURL(sent, types.EvasionLevelBasic, false, 4); err != nil {
+		t.Fatalf("HandleSendToURL() error: %v", err)
+	}
+	for _, name := range payloadFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("sending wrote %s (stat error %v), want no payload files", name, err)
+		}
+	}
+
+	// Every variant generation produces is recorded and sent
+	generated := &model.TestResults{Config: newConfig(types.ActionGeneratePayloads)}
+	if err := HandleGeneratePayloads(generated, types.EvasionLevelBasic, false, 1); err != nil {
+		t.Fatalf("HandleGeneratePayloads() error: %v", err)
+	}
+	if GetTotalVariants(generated) == 0 {
+		t.Fatal("generation produced no variants")
+	}
+	// Some encoders order their variants by map iteration, so compare the
+	// number of variants per base payload and encoding
+	shape := func(payloadResults []model.PayloadResults) map[string]int {
+		counts := map[string]int{}
+		for _, payloadResult := range payloadResults {
+			counts[payloadResult.OriginalPayload+"|"+payloadResult.EvasionType] += len(payloadResult.Variants)
+		}
+		return counts
+	}
+	if !reflect.DeepEqual(shape(sent.PayloadResults), shape(generated.PayloadResults)) {
+		t.Errorf("send recorded %d variants, generation produced %d; want the same variants per payload and encoding",
+			GetTotalVariants(sent), GetTotalVariants(generated))
+	}
+	tested := map[string]bool{}
+	for _, result := range sent.AllRequestResults {
+		tested[result.Variant.ID] = true
+	}
+	for _, payloadResult := range sent.PayloadResults {
+		for _, variant := range payloadResult.Variants {
+			if !tested[variant.ID] {
+				t.Errorf("variant %s (%q) was never sent", variant.ID, variant.Value)
+			}
+		}
+	}
+
+	// -save-payloads writes them anyway
+	for _, name := range payloadFiles {
+		os.Remove(filepath.Join(dir, name))
+	}
+	config := newConfig(types.ActionSendToURL)
+	config.SavePayloads = true
+	if err := HandleSendToURL(&model.TestResults{Config: config}, types.EvasionLevelBasic, false, 4); err != nil {
+		t.Fatalf("HandleSendToURL() with -save-payloads error: %v", err)
+	}
+	for _, name := range payloadFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("-save-payloads did not write %s: %v", name, err)
+		}
+	}
+}
+
+func TestHandleSendToURLFlagsStoredPayloads(t *testing.T) {
+	// The write endpoint persists only the <svg> payloads, the view endpoint
+	// renders everything persisted
+	var mu sync.Mutex
+	var comments []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/write", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, values := range r.Form {
+			for _, value := range values {
+				if strings.Contains(value, "svg") {
+					comments = append(comments, value)
+				}
+			}
+		}
+	})
+	mux.HandleFunc("/view", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, comment := range comments {
+			fmt.Fprintf(w, "<p>%s</p>\n", comment)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n<svg onload=alert(1)>\n"})
+
+	config := &types.Config{
+		Action:       types.ActionSendToURL,
+		AttackType:   types.AttackTypeXSS,
+		EvasionLevel: types.EvasionLevelBasic,
+		Payload:      types.Payload{Dir: dir},
+		Target:       types.Target{URL: server.URL + "/write"},
+		ViewURL:      server.URL + "/view",
+	}
+	results := &model.TestResults{Config: config}
+	if err := HandleSendToURL(results, types.EvasionLevelBasic, false, 4); err != nil {
+		t.Fatalf("HandleSendToURL() error: %v", err)
+	}
+
+	stored := 0
+	seen := make(map[string]bool)
+	for _, result := range results.AllRequestResults {
+		if !request.CarriesCanary(result.Variant) {
+			if result.Canary != "" {
+				t.Errorf("%s variant sent with canary %s, which corrupts its encoding", result.Variant.Encoding, result.Canary)
+			}
+			continue
+		}
+		if result.Canary == "" {
+			t.Fatalf("result %s/%s carries no canary", result.EvasionTechnique, result.RequestPart)
+		}
+		if seen[result.Canary] {
+			t.Errorf("canary %s was sent with more than one request", result.Canary)
+		}
+		seen[result.Canary] = true
+		// The header injector's base64 transform encodes the canary along
+		// with the payload
+		sent := string(result.Request.RequestURI()) + result.Request.Header.String() + string(result.Request.Body())
+		if result.EvasionTechnique != "header_base64_encoding" && !strings.Contains(sent, result.Canary) {
+			t.Errorf("result %s/%s does not carry its canary %s in the request", result.EvasionTechnique, result.RequestPart, result.Canary)
+		}
+		if strings.Contains(result.Payload, result.Canary) {
+			t.Errorf("result payload %q includes its canary, want the variant alone", result.Payload)
+		}
+		if !result.Stored {
+			continue
+		}
+		stored++
+		if result.Variant.SourcePayload != "<svg onload=alert(1)>" {
+			t.Errorf("variant of %q flagged stored, but only <svg> payloads were persisted", result.Variant.SourcePayload)
+		}
+	}
+	if stored == 0 {
+		t.Error("no result was flagged stored, want the persisted <svg> payloads")
+	}
+}
+
+func TestShuffleWorkIsASeededPermutation(t *testing.T) {
+	var items []workItem
+	for payload := 0; payload < 5; payload++ {
+		for variant := 0; variant < 8; variant++ {
+			items = append(items, workItem{payloadIndex: payload, variantIndex: variant})
+		}
+	}
+	shuffled := func(seed int64) []workItem {
+		order := slices.Clone(items)
+		shuffleWork(order, rand.New(rand.NewSource(seed)))
+		return order
+	}
+
+	first, second := shuffled(7), shuffled(7)
+	if !reflect.DeepEqual(first, second) {
+		t.Error("the same seed dispatched two different orders")
+	}
+	if reflect.DeepEqual(first, items) {
+		t.Error("shuffled order is the sequential order")
+	}
+	if reflect.DeepEqual(first, shuffled(8)) {
+		t.Error("seeds 7 and 8 dispatched the same order")
+	}
+	// A permutation: every item exactly once
+	seen := map[[2]int]int{}
+	for _, item := range first {
+		seen[[2]int{item.payloadIndex, item.variantIndex}]++
+	}
+	if len(seen) != len(items) {
+		t.Errorf("shuffled order holds %d distinct items, want %d", len(seen), len(items))
+	}
+	for item, n := range seen {
+		if n != 1 {
+			t.Errorf("item %v dispatched %d times", item, n)
+		}
+	}
+}
+
+func TestHandleSendToURLShuffleSendsEveryVariantOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n<svg onload=alert(1)>\n"})
+	run := func(shuffle bool) *model.TestResults {
+		config := &types.Config{
+			Action:       types.ActionSendToURL,
+			AttackType:   types.AttackTypeXSS,
+			EvasionLevel: types.EvasionLevelBasic,
+			Payload:      types.Payload{Dir: dir},
+			Target:       types.Target{URL: server.URL},
+			Seed:         7,
+			Shuffle:      shuffle,
+		}
+		results := &model.TestResults{Config: config}
+		// One worker sends in dispatch order
+		if err := HandleSendToURL(results, types.EvasionLevelBasic, false, 1); err != nil {
+			t.Fatalf("HandleSendToURL() error: %v", err)
+		}
+		return results
+	}
+	sequential, shuffled := run(false), run(true)
+
+	if len(shuffled.AllRequestResults) != len(sequential.AllRequestResults) {
+		t.Errorf("shuffled run sent %d requests, sequential run %d", len(shuffled.AllRequestResults), len(sequential.AllRequestResults))
+	}
+	// Each variant goes to the injectors once, in one consecutive stretch
+	var order []string
+	for _, result := range shuffled.AllRequestResults {
+		if len(order) == 0 || order[len(order)-1] != result.Variant.ID {
+			order = append(order, result.Variant.ID)
+		}
+	}
+	var generated []string
+	for _, payloadResult := range shuffled.PayloadResults {
+		for _, variant := range payloadResult.Variants {
+			generated = append(generated, variant.ID)
+		}
+	}
+	if slices.Equal(order, generated) {
+		t.Error("variants were sent in generation order")
+	}
+	slices.Sort(order)
+	slices.Sort(generated)
+	if !slices.Equal(order, generated) {
+		t.Errorf("sent %d variants (some more than once or not at all), want each of the %d generated exactly once", len(order), len(generated))
+	}
+}
+
+func TestHandleSendToURLSendsNucleiPayloadsAsWritten(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, values := range r.Form {
+			for _, value := range values {
+				received[value] = true
+			}
+		}
+	}))
+	defer server.Close()
+
+	dir := withPayloadDir(t, nil)
+
+	// Inline payloads at the top level, a payload file under http
+	template := `id: corpus
+info:
+  name: Existing corpus
+payloads:
+  xss:
+    - "<script>alert(1)</script>"
+    - "<svg onload=alert(1)>"
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}/?q={{sqli}}"
+    payloads:
+      sqli: sqli.txt
+`
+	if err := os.WriteFile(filepath.Join(dir, "corpus.yaml"), []byte(template), 0644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sqli.txt"), []byte("' OR 1=1--\n<svg onload=alert(1)>\n"), 0644); err != nil {
+		t.Fatalf("write payload file: %v", err)
+	}
+	want := []string{"<script>alert(1)</script>", "<svg onload=alert(1)>", "' OR 1=1--"}
+
+	loaded, err := LoadNucleiPayloads(filepath.Join(dir, "corpus.yaml"))
+	if err != nil {
+		t.Fatalf("LoadNucleiPayloads() error: %v", err)
+	}
+	if !slices.Equal(loaded, want) {
+		t.Errorf("LoadNucleiPayloads() = %q, want %q", loaded, want)
+	}
+
+	config := &types.Config{
+		Action:     types.ActionSendToURL,
+		AttackType: types.AttackTypeAll,
+		Payload:    types.Payload{Source: types.PayloadSourceNuclei, FilePath: filepath.Join(dir, "corpus.yaml")},
+		Target:     types.Target{URL: server.URL},
+	}
+	results := &model.TestResults{Config: config}
+	if err := HandleSendToURL(results, types.EvasionLevelBasic, false, 4); err != nil {
+		t.Fatalf("HandleSendToURL() error: %v", err)
+	}
+
+	if GetTotalVariants(results) != len(want) {
+		t.Errorf("sent %d variants, want the %d payloads and no generated ones", GetTotalVariants(results), len(want))
+	}
+	for _, result := range results.AllRequestResults {
+		if !slices.Contains(want, result.Variant.Value) || result.Variant.Technique != NucleiTechnique {
+			t.Errorf("sent variant %q (%s), want only the imported payloads", result.Variant.Value, result.Variant.Technique)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, payload := range want {
+		if !received[payload] {
+			t.Errorf("server never received %q", payload)
+		}
+	}
+}
+
+func TestHandleExistingPayloadsIndependentOfThreads(t *testing.T) {
+	payloads := []string{
+		"<script>alert(1)</script>",
+		"' UNION SELECT password FROM users --",
+		"../../etc/passwd",
+		"; cat /etc/shadow | bash",
+		"plain-value",
+		"<img src=x onerror=alert(1)>",
+	}
+
+	generate := func(threads int) []string {
+		config := &types.Config{
+			Action:       types.ActionUseExistingPayloads,
+			EvasionLevel: types.EvasionLevelAdvanced,
+			Seed:         7,
+			Payload:      types.Payload{Source: types.PayloadSourceEnterManually, Custom: payloads},
+		}
+		results := &model.TestResults{Config: config}
+		if err := HandleExistingPayloads(results, types.EvasionLevelAdvanced, false, threads); err != nil {
+			t.Fatalf("HandleExistingPayloads(threads=%d) error: %v", threads, err)
+		}
+		var out []string
+		for _, result := range results.PayloadResults {
+			for _, variant := range result.Variants {
+				out = append(out, strings.Join([]string{result.OriginalPayload, result.AttackType, result.EvasionType, variant.ID, variant.Value}, "|"))
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	serial := generate(1)
+	if len(serial) == 0 {
+		t.Fatal("no variants generated")
+	}
+	for _, threads := range []int{2, 8} {
+		if parallel := generate(threads); !slices.Equal(serial, parallel) {
+			t.Errorf("threads=%d produced %d variants differing from the serial run's %d", threads, len(parallel), len(serial))
+		}
+	}
+}
+
+func TestStrictVariantsDropXSSWithoutScriptTag(t *testing.T) {
+	original := "<script>alert(1)</script>"
+	variants := []types.Variant{
+		{Value: "<ScRiPt>alert(1)</sCrIpT>", Technique: "mixedcase"},
+		{Value: "%3Cscript%3Ealert(1)%3C%2Fscript%3E", Technique: "url"},
+		{Value: "&#x3c;script&#x3e;alert(1)&#x3c;/script&#x3e;", Technique: "html"},
+		{Value: "\\u003cscript\\u003ealert(1)", Technique: "unicode"},
+		{Value: "PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==", Technique: "base64"},
+		{Value: "＜ｓｃｒｉｐｔ＞alert(1)＜/ｓｃｒｉｐｔ＞", Technique: "fullwidth"},
+		{Value: "scriptalert(1)/script", Technique: "stripped"},
+		{Value: "3c7363726970743e616c6572742831293c2f7363726970743e", Technique: "rawhex"},
+	}
+
+	var kept []string
+	for _, variant := range FilterIntact(types.AttackTypeXSS, original, variants) {
+		kept = append(kept, variant.Technique)
+	}
+	want := []string{"mixedcase", "url", "html", "unicode", "base64", "fullwidth"}
+	if !slices.Equal(kept, want) {
+		t.Errorf("strict mode kept %v, want %v", kept, want)
+	}
+
+	// Attack types without a check keep everything
+	if !PreservesIntent(types.AttackTypeLDAP, "*)(uid=*", "unrelated") {
+		t.Error("unchecked attack type dropped a variant")
+	}
+}
+
+func TestCompareEncodingsRanksUnblockedEncodingsFirst(t *testing.T) {
+	// The WAF only recognizes HTML entities
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seen := r.URL.RawQuery + string(body)
+		if unescaped, err := url.QueryUnescape(r.URL.RawQuery); err == nil {
+			seen += unescaped
+		}
+		for _, values := range r.Header {
+			seen += strings.Join(values, "")
+		}
+		if strings.Contains(seen, "&lt;") || strings.Contains(seen, "&#") {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	withPayloadDir(t, nil)
+
+	config := &types.Config{
+		Action:       types.ActionSendToURL,
+		AttackType:   types.AttackTypeXSS,
+		EvasionLevel: types.EvasionLevelBasic,
+		Payload:      types.Payload{Source: types.PayloadSourceEnterManually, Custom: []string{"<script>alert(1)</script>"}},
+		Target:       types.Target{URL: server.URL},
+	}
+	results := &model.TestResults{Config: config}
+	if err := HandleCompareEncodings(results, types.EvasionLevelBasic, true, 4); err != nil {
+		t.Fatalf("HandleCompareEncodings() error: %v", err)
+	}
+
+	// Injectors that re-encode the payload (base64 headers, ...) get HTML
+	// entities past this WAF too, so HTML ranks low rather than at zero
+	ranks := report.RankEncodings(results)
+	position := map[string]int{}
+	for i, rank := range ranks {
+		position[rank.Encoding] = i
+	}
+	html, ok := position[string(types.PayloadEncodingHTML)]
+	if !ok {
+		t.Fatalf("HTMLVariants missing from ranking: %+v", ranks)
+	}
+	if rate := ranks[html].BypassRate(); rate >= 0.5 {
+		t.Errorf("HTMLVariants bypass rate = %.2f, want most of its requests blocked", rate)
+	}
+	for _, encoding := range []types.PayloadEncoding{types.PayloadEncodingHex, types.PayloadEncodingOctal, types.PayloadEncodingBase64} {
+		i, ok := position[string(encoding)]
+		if !ok {
+			t.Errorf("%s missing from ranking", encoding)
+			continue
+		}
+		if ranks[i].Bypasses != ranks[i].Requests {
+			t.Errorf("%s: %d/%d requests bypassed, want all", encoding, ranks[i].Bypasses, ranks[i].Requests)
+		}
+		if i > html {
+			t.Errorf("unblocked %s ranked below HTMLVariants: %+v", encoding, ranks)
+		}
+	}
+}
+
+func TestAdaptiveEscalateRetriesBlockedPayloadAtHigherLevels(t *testing.T) {
+	// Everything is blocked, so every round escalates
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n"})
+
+	tests := []struct {
+		maxEscalations int
+		levels         []string
+	}{
+		{1, []string{"Basic", "Medium"}},
+		{0, []string{"Basic", "Medium", "Advanced"}},
+		{5, []string{"Basic", "Medium", "Advanced"}},
+	}
+	for _, tt := range tests {
+		config := &types.Config{
+			Action:           types.ActionSendToURL,
+			AttackType:       types.AttackTypeXSS,
+			EvasionLevel:     types.EvasionLevelBasic,
+			Payload:          types.Payload{Dir: dir},
+			Target:           types.Target{URL: server.URL},
+			AdaptiveEscalate: true,
+			MaxEscalations:   tt.maxEscalations,
+		}
+		results := &model.TestResults{Config: config}
+		if err := HandleSendToURL(results, types.EvasionLevelBasic, true, 8); err != nil {
+			t.Fatalf("HandleSendToURL() error: %v", err)
+		}
+
+		var levels []string
+		for _, payloadResult := range results.PayloadResults {
+			if !slices.Contains(levels, payloadResult.Level) {
+				levels = append(levels, payloadResult.Level)
+			}
+		}
+		if !slices.Equal(levels, tt.levels) {
+			t.Errorf("max-escalations=%d: generated levels %v, want %v", tt.maxEscalations, levels, tt.levels)
+		}
+
+		sentAt := map[string]bool{}
+		for _, result := range results.RequestResults {
+			sentAt[result.Variant.Level] = true
+		}
+		for _, level := range tt.levels {
+			if !sentAt[level] {
+				t.Errorf("max-escalations=%d: no request sent for %s variants", tt.maxEscalations, level)
+			}
+		}
+	}
+}
+
+func TestPerHostConnsCapsSlowHostWhileFastHostProgresses(t *testing.T) {
+	const perHost = 2
+
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n"})
+	newConfig := func(urls ...string) *types.Config {
+		return &types.Config{
+			Action:       types.ActionSendToURL,
+			AttackType:   types.AttackTypeXSS,
+			EvasionLevel: types.EvasionLevelBasic,
+			Payload:      types.Payload{Dir: dir},
+			Target:       types.Target{URL: urls[0], URLs: urls},
+			PerHostConns: perHost,
+		}
+	}
+
+	// fastDone closes once the fast host has fastTotal requests
+	fastDone := make(chan struct{})
+	var fastCount, fastTotal atomic.Int64
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fastCount.Add(1) == fastTotal.Load() {
+			close(fastDone)
+		}
+	}))
+	defer fast.Close()
+
+	// How many requests the fast host gets on its own
+	if err := HandleSendToURL(&model.TestResults{Config: newConfig(fast.URL)}, types.EvasionLevelBasic, true, 8); err != nil {
+		t.Fatalf("HandleSendToURL() error: %v", err)
+	}
+	fastTotal.Store(fastCount.Swap(0))
+
+	// The slow host answers nothing until the fast host has every request,
+	// which it only gets if workers not held by the slow host keep it moving
+	var slowInFlight, slowMax, slowCount, slowStarved atomic.Int64
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight := slowInFlight.Add(1)
+		defer slowInFlight.Add(-1)
+		for {
+			max := slowMax.Load()
+			if inFlight <= max || slowMax.CompareAndSwap(max, inFlight) {
+				break
+			}
+		}
+		if slowStarved.Load() == 0 {
+			select {
+			case <-fastDone:
+			case <-time.After(5 * time.Second):
+				slowStarved.Add(1)
+			}
+		}
+		slowCount.Add(1)
+	}))
+	defer slow.Close()
+
+	if err := HandleSendToURL(&model.TestResults{Config: newConfig(slow.URL, fast.URL)}, types.EvasionLevelBasic, true, 8); err != nil {
+		t.Fatalf("HandleSendToURL() error: %v", err)
+	}
+
+	if got := slowMax.Load(); got > perHost {
+		t.Errorf("slow host saw %d concurrent requests, want at most %d", got, perHost)
+	}
+	if slowCount.Load() != fastTotal.Load() || fastCount.Load() != fastTotal.Load() {
+		t.Errorf("requests: slow=%d fast=%d, want %d to each host", slowCount.Load(), fastCount.Load(), fastTotal.Load())
+	}
+	if slowStarved.Load() > 0 {
+		t.Error("fast host stalled behind the slow host's held connections")
+	}
+}
+
+func TestVariantMetadataSurvivesToTestResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	const payload = "../../etc/passwd"
+	dir := withPayloadDir(t, map[string]string{"path.txt": payload + "\n"})
+
+	config := &types.Config{
+		Action:       types.ActionSendToURL,
+		AttackType:   types.AttackTypePath,
+		EvasionLevel: types.EvasionLevelBasic,
+		Payload:      types.Payload{Dir: dir},
+		Target:       types.Target{URL: server.URL},
+	}
+	results := &model.TestResults{Config: config}
+	if err := HandleSendToURL(results, types.EvasionLevelBasic, true, 4); err != nil {
+		t.Fatalf("HandleSendToURL() error: %v", err)
+	}
+
+	generated := map[string]types.Variant{}
+	for _, payloadResult := range results.PayloadResults {
+		for _, variant := range payloadResult.Variants {
+			if variant.ID != model.VariantID(payloadResult.OriginalPayload, payloadResult.EvasionType, variant.Value) {
+				t.Errorf("variant %q has ID %q, want the stable variant ID", variant.Value, variant.ID)
+			}
+			if variant.SourcePayload != payloadResult.OriginalPayload || variant.Encoding != payloadResult.EvasionType ||
+				variant.Level != payloadResult.Level || variant.Technique == "" {
+				t.Errorf("generated variant lost metadata: %+v", variant)
+			}
+			generated[variant.ID] = variant
+		}
+	}
+
+	labeled := false
+	for _, result := range results.RequestResults {
+		want, ok := generated[result.Variant.ID]
+		if !ok {
+			t.Fatalf("result for %q carries unknown variant %+v", result.Payload, result.Variant)
+		}
+		if result.Variant != want {
+			t.Errorf("result variant = %+v, want %+v", result.Variant, want)
+		}
+		if result.Variant.Technique != result.Variant.Encoding {
+			labeled = true
+		}
+	}
+	if len(results.RequestResults) == 0 {
+		t.Fatal("no requests sent")
+	}
+	// Path traversal labels its techniques, which used to be lost after generation
+	if !labeled {
+		t.Error("no result carried a technique label finer than its encoding")
+	}
+}
+
+func TestEncodingLevelsOverrideLevelPerEncoding(t *testing.T) {
+	const payload = "../../etc/passwd"
+	generate := func(config *types.Config, level types.EvasionLevel) map[string]model.PayloadResults {
+		results := &model.TestResults{Config: config}
+		if err := GenerateVariantsForPayload(results, payload, types.AttackTypePath, level); err != nil {
+			t.Fatalf("GenerateVariantsForPayload() error: %v", err)
+		}
+		byEncoding := map[string]model.PayloadResults{}
+		for _, payloadResult := range results.PayloadResults {
+			byEncoding[payloadResult.EvasionType] = payloadResult
+		}
+		return byEncoding
+	}
+
+	config := &types.Config{
+		Seed:           1,
+		EncodingLevels: map[string]string{"pathtraversal": "advanced", "Base64": "basic"},
+	}
+	got := generate(config, types.EvasionLevelMedium)
+	advanced := generate(&types.Config{Seed: 1}, types.EvasionLevelAdvanced)
+	basic := generate(&types.Config{Seed: 1}, types.EvasionLevelBasic)
+
+	tests := []struct {
+		encoding types.PayloadEncoding
+		level    types.EvasionLevel
+		want     model.PayloadResults
+	}{
+		{types.PayloadEncodingPathTraversal, types.EvasionLevelAdvanced, advanced[string(types.PayloadEncodingPathTraversal)]},
+		{types.PayloadEncodingBase64, types.EvasionLevelBasic, basic[string(types.PayloadEncodingBase64)]},
+	}
+	for _, tt := range tests {
+		result, ok := got[string(tt.encoding)]
+		if !ok {
+			t.Fatalf("no %s variants generated", tt.encoding)
+		}
+		if result.Level != string(tt.level) {
+			t.Errorf("%s generated at %s, want %s", tt.encoding, result.Level, tt.level)
+		}
+		for _, variant := range result.Variants {
+			if variant.Level != string(tt.level) {
+				t.Errorf("%s variant %q labelled %s, want %s", tt.encoding, variant.Value, variant.Level, tt.level)
+			}
+		}
+		if !slices.Equal(result.Values(), tt.want.Values()) {
+			t.Errorf("%s: %d variants, want the %d generated at %s", tt.encoding, len(result.Variants), len(tt.want.Variants), tt.level)
+		}
+	}
+
+	// Encodings without an override stay at the global level
+	if hex := got[string(types.PayloadEncodingHex)]; hex.Level != string(types.EvasionLevelMedium) {
+		t.Errorf("HexVariants generated at %q, want Medium", hex.Level)
+	}
+}
+
+func TestShowNormalizedTargetsMatchesInjectorURL(t *testing.T) {
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("no IPv6 loopback: %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	// Scheme-less, as typed on the command line
+	target := strings.TrimPrefix(server.URL, "http://") + "/search?q=1#top"
+	config := &types.Config{Target: types.Target{URL: target}}
+
+	var out strings.Builder
+	if err := ShowNormalizedTargets(&out, config); err != nil {
+		t.Fatalf("ShowNormalizedTargets() error: %v", err)
+	}
+	_, printed, ok := strings.Cut(out.String(), target+" → ")
+	if !ok {
+		t.Fatalf("output %q does not show %s", out.String(), target)
+	}
+	printed, _, _ = strings.Cut(printed, "\n")
+
+	results := request.NewFastHTTPHeaderInjector().Inject(target, "x", request.NewLoggerWithLevel(os.Stderr, request.LogLevelError))
+	if len(results) == 0 {
+		t.Fatal("header injector sent nothing")
+	}
+	for _, result := range results {
+		if sent := request.RecordRequest(result.Request).URL; sent != printed {
+			t.Errorf("%s requested %q, but %q was shown", result.EvasionTechnique, sent, printed)
+		}
+	}
+	if !strings.HasPrefix(printed, "http://[::1]:") || strings.Contains(printed, "#") {
+		t.Errorf("normalized form %q, want an http:// IPv6 URL without the fragment", printed)
+	}
+}
+
+func TestScopeRefusesOutOfScopeURLFileTargets(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n"})
+
+	// As read from a -url-file with a stray entry
+	const outOfScope = "http://typo.example.net/search"
+	config := &types.Config{
+		Action:       types.ActionSendToURL,
+		AttackType:   types.AttackTypeXSS,
+		EvasionLevel: types.EvasionLevelBasic,
+		Payload:      types.Payload{Dir: dir},
+		Target: types.Target{
+			Method: types.TargetMethodFile,
+			URL:    server.URL,
+			URLs:   []string{server.URL, outOfScope},
+			Scope:  []string{"127.0.0.0/8", "*.example.com"},
+		},
+	}
+
+	scope, err := request.ParseScope(config.Target.Scope)
+	if err != nil {
+		t.Fatalf("ParseScope() error: %v", err)
+	}
+	allowed, rejected := scopedTargets(config.Target.URLs, scope)
+	if len(allowed) != 1 || allowed[0] != server.URL {
+		t.Errorf("allowed targets = %v, want only %s", allowed, server.URL)
+	}
+	if len(rejected) != 1 || !errors.Is(rejected[0], request.ErrOutOfScope) ||
+		!strings.Contains(rejected[0].Error(), outOfScope) {
+		t.Fatalf("rejected = %v, want one out-of-scope error naming %s", rejected, outOfScope)
+	}
+
+	results := &model.TestResults{Config: config}
+	if err := HandleSendToURL(results, types.EvasionLevelBasic, false, 4); err != nil {
+		t.Fatalf("HandleSendToURL() error: %v", err)
+	}
+	if requests.Load() == 0 {
+		t.Error("in-scope target received no requests")
+	}
+	for _, result := range results.RequestResults {
+		if sent := request.RecordRequest(result.Request).URL; !strings.HasPrefix(sent, server.URL) {
+			t.Fatalf("%s sent to %q, outside scope", result.EvasionTechnique, sent)
+		}
+	}
+
+	// Every request is checked before sending, not just the configured targets
+	narrow, err := request.ParseScope([]string{"*.example.com"})
+	if err != nil {
+		t.Fatalf("ParseScope() error: %v", err)
+	}
+	opts := request.DefaultInjectorOptions()
+	opts.Scope = narrow
+	sent := requests.Load()
+	request.NewFastHTTPHeaderInjectorWithOptions(opts).Inject(server.URL, "x", request.NewLoggerWithLevel(os.Stderr, request.LogLevelError))
+	if got := requests.Load(); got != sent {
+		t.Errorf("%d requests reached a host outside the injector's scope", got-sent)
+	}
+}
+
+func TestRequireTechniquesFailsOnlyWhenOneIsMissing(t *testing.T) {
+	dir := withPayloadDir(t, map[string]string{"path.txt": "../../etc/passwd\n"})
+
+	generate := func(required ...string) error {
+		config := &types.Config{
+			Action:            types.ActionGeneratePayloads,
+			AttackType:        types.AttackTypePath,
+			EvasionLevel:      types.EvasionLevelMedium,
+			Payload:           types.Payload{Dir: dir},
+			RequireTechniques: required,
+		}
+		return HandleGeneratePayloads(&model.TestResults{Config: config}, types.EvasionLevelMedium, false, 1)
+	}
+
+	// A technique label, a short encoding name and a full one, all generated
+	if err := generate("dot_slash_prepend", "base64", "PathTraversalVariants"); err != nil {
+		t.Errorf("present techniques: HandleGeneratePayloads() error = %v, want none", err)
+	}
+
+	err := generate("dot_slash_prepend", "overlong_utf8")
+	if err == nil {
+		t.Fatal("missing technique: HandleGeneratePayloads() succeeded, want an error")
+	}
+	if !strings.Contains(err.Error(), "overlong_utf8") || strings.Contains(err.Error(), "dot_slash_prepend") {
+		t.Errorf("error %q should name only the missing technique", err)
+	}
+}
+
+func TestOnlyTechniquesKeepsOnlyThoseVariants(t *testing.T) {
+	dir := withPayloadDir(t, map[string]string{"path.txt": "../../etc/passwd\n"})
+
+	config := &types.Config{
+		Action:         types.ActionGeneratePayloads,
+		AttackType:     types.AttackTypePath,
+		EvasionLevel:   types.EvasionLevelMedium,
+		Payload:        types.Payload{Dir: dir},
+		OnlyTechniques: []string{"nginx_off_by_slash"},
+	}
+	results := &model.TestResults{Config: config}
+	if err := HandleGeneratePayloads(results, types.EvasionLevelMedium, false, 1); err != nil {
+		t.Fatalf("HandleGeneratePayloads() error = %v", err)
+	}
+
+	total := 0
+	for _, payloadResult := range results.PayloadResults {
+		for _, variant := range payloadResult.Variants {
+			total++
+			if variant.Technique != "nginx_off_by_slash" {
+				t.Errorf("variant %q has technique %q, want only nginx_off_by_slash", variant.Value, variant.Technique)
+			}
+		}
+	}
+	if total == 0 {
+		t.Fatal("no nginx_off_by_slash variants generated")
+	}
+
+	// Excluding the technique leaves the rest of the encoding in place
+	config.OnlyTechniques = nil
+	config.ExcludeTechniques = []string{"nginx_off_by_slash"}
+	results = &model.TestResults{Config: config}
+	if err := HandleGeneratePayloads(results, types.EvasionLevelMedium, false, 1); err != nil {
+		t.Fatalf("HandleGeneratePayloads() error = %v", err)
+	}
+	if GetTotalVariants(results) == 0 {
+		t.Fatal("excluding one technique dropped every variant")
+	}
+	for _, payloadResult := range results.PayloadResults {
+		for _, variant := range payloadResult.Variants {
+			if variant.Technique == "nginx_off_by_slash" {
+				t.Errorf("excluded technique still produced %q", variant.Value)
+			}
+		}
+	}
+}
+
+func TestUnconfiguredAIFallsBackWithOneWarning(t *testing.T) {
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n", "sqli.txt": "' OR 1=1 --\n"})
+	newConfig := func(required bool) *types.Config {
+		return &types.Config{
+			Action:                types.ActionGeneratePayloads,
+			AttackType:            types.AttackTypeXSS,
+			AdditionalAttackTypes: []types.AttackType{types.AttackTypeSQLI},
+			EvasionLevel:          types.EvasionLevelBasic,
+			Payload:               types.Payload{Dir: dir},
+			EnableAI:              true,
+			// No API key
+			AIConfig:   &genai.Config{Provider: "openai", Model: "gpt-4o-mini"},
+			AIRequired: required,
+		}
+	}
+
+	// Capture stderr, where the warning is printed
+	stderr := os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	os.Stderr = w
+	results := &model.TestResults{Config: newConfig(false)}
+	err = HandleGeneratePayloads(results, types.EvasionLevelBasic, false, 1)
+	os.Stderr = stderr
+	w.Close()
+	printed := <-output
+
+	if err != nil {
+		t.Fatalf("HandleGeneratePayloads() error = %v, want generation without AI", err)
+	}
+	if GetTotalVariants(results) == 0 {
+		t.Error("no variants generated without AI")
+	}
+	if warnings := strings.Count(printed, "Warning: AI provider openai is not configured"); warnings != 1 {
+		t.Errorf("printed %d AI warnings for two attack types, want 1:\n%s", warnings, printed)
+	}
+
+	err = HandleGeneratePayloads(&model.TestResults{Config: newConfig(true)}, types.EvasionLevelBasic, false, 1)
+	if err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("with AIRequired, HandleGeneratePayloads() error = %v, want the provider reported as not configured", err)
+	}
+}
+
+func TestExhaustiveIsReproducibleSupersetOfMedium(t *testing.T) {
+	generate := func(ctx context.Context, payload string, attackType types.AttackType, exhaustive bool) map[string]string {
+		results := &model.TestResults{Config: &types.Config{Exhaustive: exhaustive}}
+		if err := generateVariantsForPayload(ctx, results, payload, attackType, types.EvasionLevelMedium); err != nil {
+			t.Fatalf("generateVariantsForPayload(%s) error = %v", attackType, err)
+		}
+		techniques := make(map[string]string)
+		for _, payloadResult := range results.PayloadResults {
+			for _, variant := range payloadResult.Variants {
+				techniques[variant.Value] = payloadResult.EvasionType + "/" + variant.Technique
+			}
+		}
+		return techniques
+	}
+
+	for _, tc := range []struct {
+		attackType types.AttackType
+		payload    string
+	}{
+		{types.AttackTypePath, "../../etc/passwd"},
+		{types.AttackTypeUnixCMDI, "cat /etc/passwd"},
+		{types.AttackTypeWinCMDI, "type C:\\boot.ini"},
+		{types.AttackTypeXSS, "<script>alert(1)</script>"},
+	} {
+		first := generate(context.Background(), tc.payload, tc.attackType, true)
+		second := generate(evasions.WithRand(context.Background(), evasions.NewRand(9, 0)), tc.payload, tc.attackType, true)
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("%s: exhaustive runs differ: %d and %d variants", tc.attackType, len(first), len(second))
+		}
+
+		// Medium drawing the first alternative of every choice, and medium
+		// drawing the second of one, are both covered
+		for _, rng := range []evasions.Rand{firstAlternative{}, &secondAlternativeAt{draw: 3}} {
+			medium := generate(evasions.WithRand(context.Background(), rng), tc.payload, tc.attackType, false)
+			for value, technique := range medium {
+				if _, ok := first[value]; !ok {
+					t.Errorf("%s: exhaustive output is missing medium %s variant %q", tc.attackType, technique, value)
+				}
+			}
+			if len(first) <= len(medium) {
+				t.Errorf("%s: exhaustive produced %d variants, medium %d; want more", tc.attackType, len(first), len(medium))
+			}
+		}
+	}
+}
+
+// firstAlternative draws the first alternative of every choice
+type firstAlternative struct{}
+
+func (firstAlternative) Intn(int) int { return 0 }
+
+// secondAlternativeAt draws the first alternative of every choice but one
+type secondAlternativeAt struct {
+	draw, draws int
+}
+
+func (r *secondAlternativeAt) Intn(n int) int {
+	r.draws++
+	if r.draws-1 == r.draw && n > 1 {
+		return 1
+	}
+	return 0
+}
+
+func TestFuzzPlacesPayloadsAtMarkerAndPrintsFindings(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("view") != "full" {
+			t.Errorf("request %s lost the other parameter", r.URL)
+		}
+		mu.Lock()
+		received[query.Get("id")] = true
+		mu.Unlock()
+		if strings.Contains(strings.ToLower(query.Get("id")), "union") {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sqli.txt"), []byte("' UNION SELECT 1--\n' OR 1=1--\n"), 0644); err != nil {
+		t.Fatalf("write payloads: %v", err)
+	}
+
+	results, err := Fuzz(FuzzOptions{
+		URL:         server.URL + "/item?id=FUZZ&view=full",
+		AttackType:  types.AttackTypeSQLI,
+		Level:       types.EvasionLevelBasic,
+		PayloadsDir: dir,
+		Threads:     4,
+	})
+	if err != nil {
+		t.Fatalf("Fuzz() error: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("no requests sent")
+	}
+	mu.Lock()
+	for _, result := range results {
+		if !received[result.Payload] {
+			t.Errorf("variant %q did not arrive as the id parameter", result.Payload)
+		}
+	}
+	if len(received) != len(results) {
+		t.Errorf("server saw %d distinct id values for %d requests", len(received), len(results))
+	}
+	mu.Unlock()
+
+	var out strings.Builder
+	if err := report.WriteFuzzFindings(&out, results); err != nil {
+		t.Fatalf("WriteFuzzFindings() error: %v", err)
+	}
+	for _, want := range []string{"blocked", "STATUS", "ENCODING", "' OR 1=1--"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("findings output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "UNION") {
+		t.Errorf("blocked UNION payload listed as a finding:\n%s", out.String())
+	}
+
+	if _, err := Fuzz(FuzzOptions{URL: server.URL + "/item?id=1", AttackType: types.AttackTypeSQLI}); err == nil ||
+		!strings.Contains(err.Error(), request.FuzzMarker) {
+		t.Errorf("Fuzz() without a marker: error = %v, want one naming %s", err, request.FuzzMarker)
+	}
+}
+
+func TestVariantCacheReusesEncoderOutputForDuplicatePayloads(t *testing.T) {
+	calls := map[types.PayloadEncoding]int{}
+	var mu sync.Mutex
+	original, originalCache := explainEvasion, variantCache
+	defer func() { explainEvasion, variantCache = original, originalCache }()
+	variantCache = NewVariantCache(DefaultVariantCacheSize)
+	explainEvasion = func(ctx context.Context, payload string, encoding types.PayloadEncoding, level types.EvasionLevel) ([]types.Variant, error) {
+		mu.Lock()
+		calls[encoding]++
+		mu.Unlock()
+		return original(ctx, payload, encoding, level)
+	}
+
+	results := &model.TestResults{Config: &types.Config{}}
+	payload := "<script>alert(1)</script>"
+	if err := GenerateVariantsForPayload(results, payload, types.AttackTypeXSS, types.EvasionLevelAdvanced); err != nil {
+		t.Fatalf("first generation: %v", err)
+	}
+	first := slices.Clone(results.PayloadResults)
+	if len(first) == 0 {
+		t.Fatal("no variants generated")
+	}
+	if err := GenerateVariantsForPayload(results, payload, types.AttackTypeXSS, types.EvasionLevelAdvanced); err != nil {
+		t.Fatalf("second generation: %v", err)
+	}
+
+	for encoding, n := range calls {
+		if n != 1 {
+			t.Errorf("%s encoded %d times, want 1 with the second call served from the cache", encoding, n)
+		}
+	}
+	second := results.PayloadResults[len(first):]
+	if len(second) != len(first) {
+		t.Fatalf("second generation produced %d results, want %d", len(second), len(first))
+	}
+	for i := range first {
+		if !slices.Equal(first[i].Variants, second[i].Variants) {
+			t.Errorf("%s variants differ between the encoded and the cached run", first[i].EvasionType)
+		}
+	}
+
+	// The cache is bounded, evicting the least recently used entry
+	small := NewVariantCache(2)
+	small.Add(variantKey{payload: "a"}, nil)
+	small.Add(variantKey{payload: "b"}, nil)
+	small.Get(variantKey{payload: "a"})
+	small.Add(variantKey{payload: "c"}, nil)
+	if _, ok := small.Get(variantKey{payload: "b"}); ok || small.Len() != 2 {
+		t.Errorf("after overflow the cache holds %d entries, b cached = %v; want 2 with b evicted", small.Len(), ok)
+	}
+}
+
+func TestGenerateToWriterPrintsOnlyVariants(t *testing.T) {
+	dir := withPayloadDir(t, map[string]string{"xss.txt": "<script>alert(1)</script>\n"})
+
+	// Anything printed to stdout besides w would land here
+	stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatalf("create stdout: %v", err)
+	}
+	defer stdout.Close()
+	realStdout := os.Stdout
+	os.Stdout = stdout
+	defer func() { os.Stdout = realStdout }()
+
+	config := &types.Config{
+		Action:       types.ActionGeneratePayloads,
+		AttackType:   types.AttackTypeXSS,
+		EvasionLevel: types.EvasionLevelMedium,
+		Payload:      types.Payload{Dir: dir},
+		Stdout:       true,
+	}
+	results := &model.TestResults{Config: config}
+	var out strings.Builder
+	if err := GenerateToWriter(&out, results, types.EvasionLevelMedium, 2); err != nil {
+		t.Fatalf("GenerateToWriter() error = %v", err)
+	}
+	os.Stdout = realStdout
+
+	var want strings.Builder
+	for _, payloadResult := range results.PayloadResults {
+		for _, variant := range payloadResult.Variants {
+			want.WriteString(variant.Value + "\n")
+		}
+	}
+	if want.Len() == 0 {
+		t.Fatal("no variants generated")
+	}
+	if out.String() != want.String() {
+		t.Errorf("output is not exactly the variant lines:\n%s", out.String())
+	}
+
+	if printed, _ := os.ReadFile(stdout.Name()); len(printed) > 0 {
+		t.Errorf("status output leaked to stdout: %q", printed)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != "xss.txt" {
+			t.Errorf("unexpected file written: %s", entry.Name())
+		}
+	}
+}
+
+func TestGeneratedPayloadResultsCarryTechniques(t *testing.T) {
+	dir := withPayloadDir(t, map[string]string{"path.txt": "../../etc/passwd\n"})
+
+	config := &types.Config{
+		Action:       types.ActionGeneratePayloads,
+		AttackType:   types.AttackTypePath,
+		EvasionLevel: types.EvasionLevelMedium,
+		Payload:      types.Payload{Dir: dir},
+	}
+	results := &model.TestResults{Config: config}
+	if err := HandleGeneratePayloads(results, types.EvasionLevelMedium, false, 2); err != nil {
+		t.Fatalf("HandleGeneratePayloads() error = %v", err)
+	}
+	if len(results.PayloadResults) == 0 {
+		t.Fatal("no payload results generated")
+	}
+
+	labeled := false
+	for _, payloadResult := range results.PayloadResults {
+		techniques := payloadResult.Techniques()
+		if len(techniques) != len(payloadResult.Variants) {
+			t.Fatalf("%s: %d techniques for %d variants", payloadResult.EvasionType, len(techniques), len(payloadResult.Variants))
+		}
+		for i, technique := range techniques {
+			if technique == "" {
+				t.Errorf("%s variant %q has no technique", payloadResult.EvasionType, payloadResult.Variants[i].Value)
+			}
+			if technique != payloadResult.EvasionType {
+				labeled = true
+			}
+		}
+	}
+	if !labeled {
+		t.Error("every technique is just the encoding name; want the path techniques labeled")
+	}
+}
+
+func TestHostQueuePushBlocksWhileTheHostIsFull(t *testing.T) {
+	q := newHostQueue(request.NewHostLimiter(0), 2)
+	q.push(workItem{host: "a", variantIndex: 0})
+	q.push(workItem{host: "a", variantIndex: 1})
+
+	pushed := make(chan struct{})
+	go func() {
+		q.push(workItem{host: "a", variantIndex: 2})
+		close(pushed)
+	}()
+	// Other hosts still take items
+	q.push(workItem{host: "b"})
+
+	select {
+	case <-pushed:
+		t.Fatal("push returned while the host's queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if item, ok := q.pop(); !ok || item.host != "a" || item.variantIndex != 0 {
+		t.Fatalf("pop() = %+v, %v, want the first item for a", item, ok)
+	}
+	select {
+	case <-pushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("push stayed blocked after a pop made room")
+	}
+}
+
+func TestRunSettingsDoNotCarryOverToTheNextRun(t *testing.T) {
+	// A bare traversal is aimed at -traversal-target when one is set
+	dir := withPayloadDir(t, map[string]string{"path.txt": "../../etc/passwd\n../../\n"})
+	generate := func(maxDepth int, target string) []string {
+		config := &types.Config{
+			Action:            types.ActionGeneratePayloads,
+			AttackType:        types.AttackTypePath,
+			EvasionLevel:      types.EvasionLevelMedium,
+			Payload:           types.Payload{Dir: dir},
+			MaxTraversalDepth: maxDepth,
+			TraversalTarget:   target,
+			OnlyTechniques:    []string{"traversal_depth"},
+		}
+		results := &model.TestResults{Config: config}
+		if err := HandleGeneratePayloads(results, types.EvasionLevelMedium, false, 1); err != nil {
+			t.Fatalf("HandleGeneratePayloads() error = %v", err)
+		}
+		var values []string
+		for _, payloadResult := range results.PayloadResults {
+			for _, variant := range payloadResult.Variants {
+				values = append(values, variant.Value)
+			}
+		}
+		return values
+	}
+
+	deep := strings.Repeat("../", 12) + "etc/passwd"
+	values := generate(12, "windows")
+	if !slices.Contains(values, deep) {
+		t.Fatalf("-max-depth 12 run lacks %q", deep)
+	}
+	if !slices.ContainsFunc(values, func(v string) bool { return strings.Contains(v, "win.ini") }) {
+		t.Fatalf("-traversal-target windows run never aims at win.ini: %q", values)
+	}
+
+	// Later runs without the flags are back at the defaults
+	for _, value := range generate(12, "") {
+		if strings.Contains(value, "win.ini") {
+			t.Errorf("run without -traversal-target emitted %q, aimed at the previous run's target", value)
+		}
+	}
+	tooDeep := strings.Repeat("../", path.DefaultMaxTraversalDepth+1) + "etc/passwd"
+	for _, value := range generate(0, "") {
+		if value == deep || value == tooDeep {
+			t.Errorf("run without -max-depth emitted %q, past the default depth of %d", value, path.DefaultMaxTraversalDepth)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package payload
+
+import (
+	"sync"
+
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+// workItem is one variant to send to one target with every injector
+type workItem struct {
+	variant      types.Variant
+	attackType   string
+	payloadIndex int
+	variantIndex int
+	target       string
+	host         string
+	// state is shared by every variant of the same base payload and target
+	state *payloadState
+}
+
+// hostQueue hands work items to workers so that no host has more items in
+// flight than its limiter allows. Hosts are served round-robin, and a worker
+// only waits when every host with pending work is at its limit, so a slow
+// host never starves the others. Each host holds at most capacity pending
+// items; push blocks until a worker takes one, so a large run never holds
+// all of its variants in memory.
+type hostQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	limiter *request.HostLimiter
+	// capacity caps the pending items of each host
+	capacity int
+
+	hosts     []string
+	pending   map[string][]workItem
+	remaining int
+	next      int
+	// closed is set once no more items will be pushed
+	closed bool
+}
+
+// hostQueueCapacity is how many items each host queues ahead of the workers
+const hostQueueCapacity = 256
+
+func newHostQueue(limiter *request.HostLimiter, capacity int) *hostQueue {
+	q := &hostQueue{limiter: limiter, capacity: capacity, pending: make(map[string][]workItem)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push queues item behind the other items for its host, blocking while that
+// host's queue is full
+func (q *hostQueue) push(item workItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.pending[item.host]) >= q.capacity {
+		q.cond.Wait()
+	}
+	if _, ok := q.pending[item.host]; !ok {
+		q.hosts = append(q.hosts, item.host)
+	}
+	q.pending[item.host] = append(q.pending[item.host], item)
+	q.remaining++
+	q.cond.Broadcast()
+}
+
+// close marks the queue as complete: once the pending items are taken, pop
+// stops blocking and reports false
+func (q *hostQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// pop takes the next item from a host with a free slot, blocking while every
+// host with pending work is busy, or while none has any and the queue is not
+// closed. It reports false once the queue is closed and empty; callers must
+// call done with the item's host when they finish with it.
+func (q *hostQueue) pop() (workItem, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.remaining > 0 || !q.closed {
+		for i := range q.hosts {
+			index := (q.next + i) % len(q.hosts)
+			host := q.hosts[index]
+			items := q.pending[host]
+			if len(items) == 0 || !q.limiter.TryAcquire(host) {
+				continue
+			}
+			q.pending[host] = items[1:]
+			q.remaining--
+			q.next = (index + 1) % len(q.hosts)
+			// Wake a push waiting for room on this host
+			q.cond.Broadcast()
+			return items[0], true
+		}
+		q.cond.Wait()
+	}
+	return workItem{}, false
+}
+
+// done releases the slot host held for a popped item
+func (q *hostQueue) done(host string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.limiter.Release(host)
+	q.cond.Broadcast()
+}
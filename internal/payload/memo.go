@@ -0,0 +1,115 @@
+package payload
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"obfuskit/cmd"
+	"obfuskit/internal/evasions"
+	"obfuskit/internal/evasions/path"
+	"obfuskit/types"
+)
+
+// DefaultVariantCacheSize bounds how many encoder results generation keeps
+const DefaultVariantCacheSize = 4096
+
+// variantKey identifies one encoder call
+type variantKey struct {
+	payload  string
+	encoding types.PayloadEncoding
+	level    types.EvasionLevel
+	// exhaustive marks the enumerated variants -exhaustive generates
+	exhaustive bool
+}
+
+type variantEntry struct {
+	key      variantKey
+	variants []types.Variant
+}
+
+// VariantCache memoizes encoder output by (payload, encoding, level), so a
+// payload file with duplicates does not redo expensive encodings such as
+// best-fit at advanced level. It evicts the least recently used entry once
+// full and is safe for concurrent use.
+type VariantCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front is most recently used
+	entries map[variantKey]*list.Element
+}
+
+// NewVariantCache returns a cache holding at most size results
+func NewVariantCache(size int) *VariantCache {
+	return &VariantCache{size: size, order: list.New(), entries: make(map[variantKey]*list.Element)}
+}
+
+// Get returns the cached variants for key. They are shared and must not be
+// modified.
+func (c *VariantCache) Get(key variantKey) ([]types.Variant, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*variantEntry).variants, true
+}
+
+// Add caches variants for key, evicting the least recently used entry when full
+func (c *VariantCache) Add(key variantKey, variants []types.Variant) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*variantEntry).variants = variants
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&variantEntry{key: key, variants: variants})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*variantEntry).key)
+	}
+}
+
+// Len returns the number of cached results
+func (c *VariantCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+var (
+	// variantCache is shared by every generation in the process
+	variantCache = NewVariantCache(DefaultVariantCacheSize)
+	// explainEvasion is the encoder call variantCache memoizes
+	explainEvasion = cmd.ExplainEvasion
+)
+
+// cachedExplainEvasion is cmd.ExplainEvasion through variantCache. Seeded
+// runs bypass the cache: a hit would skip the draws a miss makes from the
+// worker's source, so later variants would depend on which worker encoded a
+// duplicate first. So do path variants under non-default path settings,
+// which the key does not record.
+func cachedExplainEvasion(ctx context.Context, payload string, encoding types.PayloadEncoding, level types.EvasionLevel) ([]types.Variant, error) {
+	if evasions.RandFrom(ctx) != evasions.DefaultRand || (encoding == types.PayloadEncodingPathTraversal && path.Customized(ctx)) {
+		return explainEvasion(ctx, payload, encoding, level)
+	}
+	key := variantKey{
+		payload:    payload,
+		encoding:   encoding,
+		level:      level,
+		exhaustive: evasions.Exhaustive(ctx),
+	}
+	if variants, ok := variantCache.Get(key); ok {
+		return variants, nil
+	}
+	variants, err := explainEvasion(ctx, payload, encoding, level)
+	if err != nil {
+		return nil, err
+	}
+	variantCache.Add(key, variants)
+	return variants, nil
+}
@@ -0,0 +1,56 @@
+package payload
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+// probeNormalization runs the -probe-normalization probe against the target
+// and records which encodings it decodes in config.TargetNormalizations. A
+// failed probe only warns: generation then keeps its usual order.
+func probeNormalization(config *types.Config, opts *request.InjectorOptions) {
+	applied, err := request.ProbeNormalizationWithOptions(config.Target.URL, opts)
+	if err != nil {
+		fmt.Printf("⚠️  Normalization probe skipped: %v\n", err)
+		return
+	}
+	config.TargetNormalizations = applied
+
+	var decoded, kept []string
+	for encoding, ok := range applied {
+		if ok {
+			decoded = append(decoded, string(encoding))
+		} else {
+			kept = append(kept, string(encoding))
+		}
+	}
+	sort.Strings(decoded)
+	sort.Strings(kept)
+	fmt.Printf("🔬 Target decodes: %s\n", orNone(decoded))
+	fmt.Printf("🔬 Target leaves encoded: %s\n", orNone(kept))
+}
+
+func orNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// prioritizeDecoded moves the encodings the target was found to decode to the
+// front, keeping the order within each group
+func prioritizeDecoded(encodings []types.PayloadEncoding, config *types.Config) []types.PayloadEncoding {
+	if config == nil || len(config.TargetNormalizations) == 0 {
+		return encodings
+	}
+	ordered := slices.Clone(encodings)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return config.TargetNormalizations[ordered[i]] && !config.TargetNormalizations[ordered[j]]
+	})
+	return ordered
+}
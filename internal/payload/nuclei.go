@@ -0,0 +1,132 @@
+package payload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"obfuskit/internal/model"
+	"obfuskit/internal/util"
+	"obfuskit/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NucleiTechnique labels the variants imported from a nuclei payload list
+const NucleiTechnique = "nuclei"
+
+// nucleiTemplate holds the parts of a nuclei template that carry payloads:
+// the top-level payloads obfuskit's own templates use, and the per-request
+// payloads of requests (older templates) and http (current ones). A payload
+// set is either a list or the path of a file holding one payload per line.
+type nucleiTemplate struct {
+	Payloads map[string]yaml.Node `yaml:"payloads"`
+	Requests []struct {
+		Payloads map[string]yaml.Node `yaml:"payloads"`
+	} `yaml:"requests"`
+	HTTP []struct {
+		Payloads map[string]yaml.Node `yaml:"payloads"`
+	} `yaml:"http"`
+}
+
+// LoadNucleiPayloads reads the payloads of a nuclei template (.yaml or .yml),
+// from every payloads section, or of a plain list with one payload per line.
+// Payload files a template names are resolved against its directory.
+// Duplicates are dropped; sets are read in name order.
+func LoadNucleiPayloads(path string) ([]string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" {
+		return util.LoadPayloadsFromFile(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var template nucleiTemplate
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return nil, fmt.Errorf("parsing nuclei template %s: %w", path, err)
+	}
+	sets := []map[string]yaml.Node{template.Payloads}
+	for _, request := range template.Requests {
+		sets = append(sets, request.Payloads)
+	}
+	for _, request := range template.HTTP {
+		sets = append(sets, request.Payloads)
+	}
+
+	var payloads []string
+	seen := make(map[string]bool)
+	for _, set := range sets {
+		names := make([]string, 0, len(set))
+		for name := range set {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			loaded, err := nucleiPayloadSet(set[name], filepath.Dir(path))
+			if err != nil {
+				return nil, fmt.Errorf("nuclei template %s, payloads %q: %w", path, name, err)
+			}
+			for _, payload := range loaded {
+				if !seen[payload] {
+					seen[payload] = true
+					payloads = append(payloads, payload)
+				}
+			}
+		}
+	}
+	if len(payloads) == 0 {
+		return nil, fmt.Errorf("nuclei template %s has no payloads", path)
+	}
+	return payloads, nil
+}
+
+// nucleiPayloadSet returns the payloads of one set: a list, or a file
+// relative to dir
+func nucleiPayloadSet(node yaml.Node, dir string) ([]string, error) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var payloads []string
+		if err := node.Decode(&payloads); err != nil {
+			return nil, err
+		}
+		return payloads, nil
+	case yaml.ScalarNode:
+		path := node.Value
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return util.LoadPayloadsFromFile(path)
+	default:
+		return nil, fmt.Errorf("want a list or a file path")
+	}
+}
+
+// importNucleiPayloads loads the configured nuclei payload list into
+// results as variants sent exactly as written, without generation
+func importNucleiPayloads(results *model.TestResults, config *types.Config) error {
+	payloads, err := LoadNucleiPayloads(config.Payload.FilePath)
+	if err != nil {
+		return err
+	}
+	for _, payload := range payloads {
+		// A nuclei corpus may mix attacks; -attack all tells them apart
+		attackType := config.AttackType
+		if attackType == "" || attackType == types.AttackTypeAll {
+			attackType = util.DetectAttackType(payload)
+		}
+		payloadResult := model.PayloadResults{
+			OriginalPayload: payload,
+			AttackType:      string(attackType),
+			EvasionType:     NucleiTechnique,
+			Variants:        model.NewVariants([]string{payload}),
+		}
+		payloadResult.AssignVariantIDs()
+		results.PayloadResults = append(results.PayloadResults, payloadResult)
+	}
+	fmt.Printf("📥 Imported %d payloads from %s\n", len(payloads), config.Payload.FilePath)
+	return nil
+}
@@ -0,0 +1,53 @@
+package payload
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"obfuskit/internal/model"
+	"obfuskit/types"
+)
+
+// GenerateToWriter is generation for -stdout: it generates the variants of
+// the given payloads (or of the base payloads when none are given), discards
+// the status output generation prints, writes no payload files, and writes
+// only the variants to w, one per line
+func GenerateToWriter(w io.Writer, results *model.TestResults, level types.EvasionLevel, threads int) error {
+	config, ok := results.Config.(*types.Config)
+	if !ok {
+		return fmt.Errorf("invalid config type in TestResults")
+	}
+	config.Stdout = true
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	switch config.Payload.Source {
+	case types.PayloadSourceEnterManually, types.PayloadSourceFromFile:
+		err = HandleExistingPayloads(results, level, false, threads)
+	default:
+		err = HandleGeneratePayloads(results, level, false, threads)
+	}
+	os.Stdout = stdout
+	devNull.Close()
+	if err != nil {
+		return err
+	}
+	return WriteVariants(w, results)
+}
+
+// WriteVariants writes every generated variant to w, one per line
+func WriteVariants(w io.Writer, results *model.TestResults) error {
+	out := bufio.NewWriter(w)
+	for _, payloadResult := range results.PayloadResults {
+		for _, variant := range payloadResult.Variants {
+			fmt.Fprintln(out, variant.Value)
+		}
+	}
+	return out.Flush()
+}
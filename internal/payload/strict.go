@@ -0,0 +1,27 @@
+package payload
+
+import (
+	"obfuskit/internal/canon"
+	"obfuskit/internal/intent"
+	"obfuskit/types"
+)
+
+// PreservesIntent reports whether variant, once canon.Normalize peels off the
+// encodings a target might undo, still carries the structure that makes
+// original an attack of the given type. It is deliberately lenient: it only
+// rejects variants no plausible decoder turns back into the attack. Attack
+// types without a check keep every variant.
+func PreservesIntent(attackType types.AttackType, original, variant string) bool {
+	return intent.Carries(attackType, original, canon.Normalize(variant))
+}
+
+// FilterIntact drops the variants that fail PreservesIntent (-strict-variants)
+func FilterIntact(attackType types.AttackType, original string, variants []types.Variant) []types.Variant {
+	var kept []types.Variant
+	for _, variant := range variants {
+		if PreservesIntent(attackType, original, variant.Value) {
+			kept = append(kept, variant)
+		}
+	}
+	return kept
+}
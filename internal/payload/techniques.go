@@ -0,0 +1,45 @@
+package payload
+
+import (
+	"strings"
+
+	"obfuskit/types"
+)
+
+// FilterTechniques keeps the variants of encoding whose technique is named in
+// only (all of them when only is empty) and not named in exclude. Names match
+// as in MissingTechniques: a technique label, or the encoding by full or
+// short name, ignoring case.
+func FilterTechniques(variants []types.Variant, encoding types.PayloadEncoding, only, exclude []string) []types.Variant {
+	if len(only) == 0 && len(exclude) == 0 {
+		return variants
+	}
+	var kept []types.Variant
+	for _, variant := range variants {
+		if len(only) > 0 && !namesTechnique(only, variant.Technique, encoding) {
+			continue
+		}
+		if namesTechnique(exclude, variant.Technique, encoding) {
+			continue
+		}
+		kept = append(kept, variant)
+	}
+	return kept
+}
+
+// namesTechnique reports whether any of names is technique or encoding
+func namesTechnique(names []string, technique string, encoding types.PayloadEncoding) bool {
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.EqualFold(name, technique) || strings.EqualFold(name, string(encoding)) {
+			return true
+		}
+		if parsed, ok := types.ParsePayloadEncoding(name); ok && parsed == encoding {
+			return true
+		}
+	}
+	return false
+}
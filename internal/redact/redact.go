@@ -0,0 +1,164 @@
+// Package redact masks secrets (API keys, bearer tokens, cookie values) before
+// config or requests are logged or serialized.
+package redact
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask replaces every redacted value
+const Mask = "****"
+
+var (
+	mu      sync.RWMutex
+	secrets []string
+)
+
+// Register adds literal secrets, such as a configured API key, that String
+// masks wherever they appear. Empty values are ignored.
+func Register(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, value := range values {
+		if value == "" || value == Mask {
+			continue
+		}
+		secrets = append(secrets, value)
+	}
+	// Longest first so a secret containing another is masked whole
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+// Reset forgets every registered secret. A run calls it before registering
+// its own, so a later run in the same process does not keep masking the
+// previous run's secrets.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	secrets = nil
+}
+
+var (
+	// bearerPattern matches bearer tokens anywhere in the text
+	bearerPattern = regexp.MustCompile(`(?i)\b(Bearer)\s+[A-Za-z0-9._~+/=-]{8,}`)
+	// authorizationPattern matches Authorization header lines in dumps
+	authorizationPattern = regexp.MustCompile(`(?im)^(\s*((?:proxy-)?authorization):\s*)(.*)$`)
+	// secretParamPattern matches key=value and "key": "value" pairs whose key names a secret
+	secretParamPattern = regexp.MustCompile(`(?i)("?(?:api[_-]?key|access[_-]?token|auth[_-]?token|secret|password)"?\s*[=:]\s*"?)([^"&\s,}]+)`)
+	// cookieHeaderPattern matches Cookie/Set-Cookie header lines in dumps
+	cookieHeaderPattern = regexp.MustCompile(`(?im)^(\s*((?:set-)?cookie):\s*)(.*)$`)
+)
+
+// String masks registered secrets, bearer tokens, secret-named parameters and
+// Authorization/Cookie header lines in free text such as log lines
+func String(s string) string {
+	mu.RLock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+	mu.RUnlock()
+
+	s = bearerPattern.ReplaceAllString(s, "$1 "+Mask)
+	s = secretParamPattern.ReplaceAllString(s, "${1}"+Mask)
+	for _, pattern := range []*regexp.Regexp{authorizationPattern, cookieHeaderPattern} {
+		s = pattern.ReplaceAllStringFunc(s, func(line string) string {
+			parts := pattern.FindStringSubmatch(line)
+			return parts[1] + Header(parts[2], parts[3])
+		})
+	}
+	return s
+}
+
+// sensitiveHeaders are masked whole by Header
+var sensitiveHeaders = map[string]bool{
+	"x-api-key":            true,
+	"api-key":              true,
+	"x-auth-token":         true,
+	"x-csrf-token":         true,
+	"x-xsrf-token":         true,
+	"x-amz-security-token": true,
+}
+
+// Header masks the value of a sensitive request or response header: the
+// credentials of Authorization headers (keeping the scheme), every cookie
+// value, and API key headers. Other values go through String.
+func Header(name, value string) string {
+	switch lower := strings.ToLower(name); {
+	case lower == "authorization" || lower == "proxy-authorization":
+		if scheme, _, ok := strings.Cut(value, " "); ok {
+			return scheme + " " + Mask
+		}
+		return Mask
+	case lower == "cookie" || lower == "set-cookie":
+		return Cookies(value)
+	case sensitiveHeaders[lower]:
+		return Mask
+	default:
+		return String(value)
+	}
+}
+
+// Cookies masks the values of a Cookie or Set-Cookie header, keeping names
+// and Set-Cookie attributes
+func Cookies(value string) string {
+	parts := strings.Split(value, ";")
+	for i, part := range parts {
+		name, _, ok := strings.Cut(part, "=")
+		if !ok || (i > 0 && isCookieAttribute(name)) {
+			continue
+		}
+		parts[i] = name + "=" + Mask
+	}
+	return strings.Join(parts, ";")
+}
+
+func isCookieAttribute(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "expires", "max-age", "domain", "path", "samesite", "priority":
+		return true
+	}
+	return false
+}
+
+// URL masks the password of user:password@ URLs and secret-named query
+// parameters. Unparseable URLs go through String.
+func URL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return String(raw)
+	}
+	if parsed.User == nil {
+		return String(raw)
+	}
+	if _, hasPassword := parsed.User.Password(); !hasPassword {
+		return String(raw)
+	}
+	// url escapes '*' in userinfo, so mask with a placeholder and swap it back
+	parsed.User = url.UserPassword(parsed.User.Username(), "REDACTED")
+	return String(strings.Replace(parsed.String(), ":REDACTED@", ":"+Mask+"@", 1))
+}
+
+// Writer wraps w so everything written through it goes through String.
+// Each Write is redacted on its own, which suits line-oriented loggers.
+func Writer(w io.Writer) io.Writer {
+	if _, ok := w.(*writer); ok {
+		return w
+	}
+	return &writer{w: w}
+}
+
+type writer struct {
+	w io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
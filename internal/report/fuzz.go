@@ -0,0 +1,48 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"obfuskit/report"
+	"obfuskit/request"
+)
+
+// maxFindingPayloadWidth truncates payloads in the fuzz findings table
+const maxFindingPayloadWidth = 60
+
+// WriteFuzzFindings prints a one-line summary of a fuzz run followed by a
+// table of the variants that got through, or a note that none did
+func WriteFuzzFindings(w io.Writer, results []request.TestResult) error {
+	var findings []request.TestResult
+	blocked := 0
+	for _, result := range results {
+		switch {
+		case report.IsBypass(result):
+			findings = append(findings, result)
+		case result.Blocked:
+			blocked++
+		}
+	}
+	fmt.Fprintf(w, "%d requests: %d blocked, %d bypassed\n", len(results), blocked, len(findings))
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(w, "No bypasses found.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tENCODING\tTECHNIQUE\tPAYLOAD")
+	for _, finding := range findings {
+		status := fmt.Sprint(finding.StatusCode)
+		if finding.CandidateBypass {
+			status += " (candidate)"
+		}
+		payload := finding.Payload
+		if len(payload) > maxFindingPayloadWidth {
+			payload = payload[:maxFindingPayloadWidth-3] + "..."
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", status, finding.Variant.Encoding, finding.Variant.Technique, payload)
+	}
+	return tw.Flush()
+}
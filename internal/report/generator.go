@@ -1,12 +1,18 @@
 package report
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"obfuskit/internal/model"
+	"obfuskit/internal/redact"
+	"obfuskit/internal/version"
 	"obfuskit/report"
+	"obfuskit/request"
 	"obfuskit/types"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -38,12 +44,20 @@ func GenerateSummary(results *model.TestResults) {
 	}
 
 	for _, reqResult := range baseRequests {
-		if !reqResult.Blocked {
+		if reqResult.Challenge {
+			summary.ChallengeTests++
+		} else if !reqResult.Blocked {
 			summary.SuccessfulTests++
 		} else {
 			summary.FailedTests++
 		}
 	}
+	weaknesses := report.GroupWeaknesses(baseRequests)
+	summary.UniqueWeaknesses = len(weaknesses)
+	summary.Bypasses = 0
+	for _, weakness := range weaknesses {
+		summary.Bypasses += weakness.Bypasses
+	}
 
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("TEST SUMMARY")
@@ -56,12 +70,40 @@ func GenerateSummary(results *model.TestResults) {
 	if len(baseRequests) > 0 {
 		fmt.Printf("Successful Tests: %d\n", summary.SuccessfulTests)
 		fmt.Printf("Failed Tests: %d\n", summary.FailedTests)
+		if shares := report.BlockDistribution(baseRequests); len(shares) > 0 {
+			fmt.Printf("Blocked By: %s\n", report.FormatBlockDistribution(shares))
+		}
+		if summary.ChallengeTests > 0 {
+			fmt.Printf("Challenge Pages: %d\n", summary.ChallengeTests)
+		}
+		if summary.Bypasses > 0 {
+			fmt.Printf("Bypasses: %d (%d unique weaknesses)\n", summary.Bypasses, summary.UniqueWeaknesses)
+		}
 		fmt.Printf("Success Rate: %.2f%%\n",
 			float64(summary.SuccessfulTests)/float64(len(baseRequests))*100)
+		if request.ChallengesDominate(baseRequests) {
+			fmt.Println(challengeWarning)
+		}
+		if warning := request.CacheWarning(baseRequests); warning != "" {
+			fmt.Println(warning)
+		}
+		if advice := report.DecodeDepthAdvice(report.DecodeDepths(baseRequests)); advice != "" {
+			fmt.Println(advice)
+		}
+		if recommendations := report.Recommendations(baseRequests); len(recommendations) > 0 {
+			fmt.Println("Recommendations:")
+			for _, recommendation := range recommendations {
+				fmt.Println("  - " + report.FormatRecommendation(recommendation))
+			}
+		}
 	}
 	fmt.Println(strings.Repeat("=", 60))
 }
 
+// challengeWarning is printed when most responses were challenge pages
+const challengeWarning = "⚠️  Most responses were WAF challenge pages (JS challenge/captcha); " +
+	"block and bypass counts may be unreliable. Consider an allowlisted source or a browser-backed client."
+
 func GenerateReports(results *model.TestResults) error {
 	fmt.Println("\n📊 Generating reports...")
 
@@ -82,11 +124,12 @@ func GenerateReports(results *model.TestResults) error {
 	} else {
 		reportTypes = []types.ReportType{config.ReportType}
 	}
+	snapshot := configSnapshot(results)
 
 	for _, reportType := range reportTypes {
 		switch reportType {
 		case types.ReportTypeHTML:
-			err := report.GenerateHTMLReport(results.RequestResults, "waf_test_report.html")
+			err := report.GenerateHTMLReport(results.RequestResults, snapshot, "waf_test_report.html")
 			if err != nil {
 				fmt.Printf("Warning: Failed to generate HTML report: %v\n", err)
 			} else {
@@ -98,10 +141,10 @@ func GenerateReports(results *model.TestResults) error {
 			if len(results.AllRequestResults) > 0 {
 				baseRequests = results.AllRequestResults
 			}
-			report.PrintTerminalReportWithBaseline(results.RequestResults, baseRequests)
+			report.PrintTerminalReportWithBaseline(results.RequestResults, baseRequests, snapshot)
 			fmt.Println("✅ Terminal report displayed above")
 		case types.ReportTypePDF:
-			err := report.GeneratePDFReport(results.RequestResults, "waf_test_report.pdf")
+			err := report.GeneratePDFReport(results.RequestResults, snapshot, "waf_test_report.pdf")
 			if err != nil {
 				fmt.Printf("Warning: Failed to generate PDF report: %v\n", err)
 			} else {
@@ -112,10 +155,10 @@ func GenerateReports(results *model.TestResults) error {
 			if err != nil {
 				fmt.Printf("Warning: Failed to generate CSV report: %v\n", err)
 			} else {
-				fmt.Println("✅ CSV report generated: waf_test_report.csv")
+				fmt.Println("✅ CSV report generated: waf_test_report.csv (configuration in " + CSVConfigFilename + ")")
 			}
 		case types.ReportTypeNuclei:
-			err := report.GenerateNucleiTemplates(results.RequestResults, "nuclei_templates")
+			err := report.GenerateNucleiTemplates(results.RequestResults, snapshot, "nuclei_templates")
 			if err != nil {
 				fmt.Printf("Warning: Failed to generate nuclei templates: %v\n", err)
 			} else {
@@ -135,19 +178,103 @@ func GenerateReports(results *model.TestResults) error {
 }
 
 func GenerateNucleiTemplatesFromPayloads(results *model.TestResults, level types.EvasionLevel) error {
+	return report.GenerateNucleiTemplatesFromPayloads(payloadResults(results, level), configSnapshot(results), "nuclei_templates")
+}
+
+// GenerateEncodingReport writes the raw-to-encoded mapping of every generated
+// variant to outputPath (HTML for .html paths, text otherwise)
+func GenerateEncodingReport(results *model.TestResults, outputPath string) error {
+	var level types.EvasionLevel
+	if config, ok := results.Config.(*types.Config); ok && config != nil {
+		level = config.EvasionLevel
+	}
+	return report.GenerateEncodingReport(payloadResults(results, level), configSnapshot(results), outputPath)
+}
+
+// RankEncodings ranks the encodings of the generated variants by how often
+// the requests sent for them bypassed the WAF (-compare-encodings). Filtered
+// runs are ranked on every request sent.
+func RankEncodings(results *model.TestResults) []report.EncodingRank {
+	requestResults := results.AllRequestResults
+	if len(requestResults) == 0 {
+		requestResults = results.RequestResults
+	}
+	return report.RankEncodings(requestResults)
+}
+
+// ExportBypassRecipes writes a recipe (see report.ExportRecipe) for every
+// request that bypassed the WAF into dir, returning how many were written
+func ExportBypassRecipes(results *model.TestResults, dir string) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create recipe directory: %w", err)
+	}
+	config, _ := results.Config.(*types.Config)
+	requestResults := results.AllRequestResults
+	if len(requestResults) == 0 {
+		requestResults = results.RequestResults
+	}
+
+	written := 0
+	for i, result := range requestResults {
+		if !report.IsBypass(result) {
+			continue
+		}
+		name := result.Variant.ID
+		if name == "" {
+			name = fmt.Sprintf("result%d", i+1)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("recipe_%s_%s.yaml", name, result.EvasionTechnique))
+		if err := report.ExportRecipe(result, config, path); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+// ReplayRecipe re-sends the request in the recipe file at path and writes
+// the full response to w. targetURL, when set, replaces the recorded scheme
+// and host.
+func ReplayRecipe(path, targetURL string, w io.Writer) error {
+	recipe, err := report.LoadRecipe(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\n🔁 Replaying %s %s (technique: %s, part: %s, originally %d)\n",
+		recipe.Request.Method, recipe.Request.URL, recipe.Technique, recipe.RequestPart, recipe.StatusCode)
+	fmt.Fprintln(w, strings.Repeat("-", 60))
+	if err := recipe.Replay(targetURL, w); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// WriteEncodingComparison writes the RankEncodings table to w
+func WriteEncodingComparison(w io.Writer, results *model.TestResults) error {
+	return report.WriteEncodingRanking(w, RankEncodings(results))
+}
+
+// payloadResults converts the generated variants for the report package
+func payloadResults(results *model.TestResults, level types.EvasionLevel) []report.PayloadResult {
 	var payloadResults []report.PayloadResult
 	for _, payloadResult := range results.PayloadResults {
 		payloadResults = append(payloadResults, report.PayloadResult{
 			OriginalPayload: payloadResult.OriginalPayload,
 			AttackType:      payloadResult.AttackType,
 			EvasionType:     payloadResult.EvasionType,
-			Variants:        payloadResult.Variants,
+			Variants:        payloadResult.Values(),
 			Level:           string(level),
 		})
 	}
-	return report.GenerateNucleiTemplatesFromPayloads(payloadResults, "nuclei_templates")
+	return payloadResults
 }
 
+// CSVConfigFilename is the sidecar holding the configuration of the run a
+// CSV report came from; comment lines in the CSV itself would break CSV
+// readers and spreadsheet imports
+const CSVConfigFilename = "waf_test_report_config.json"
+
 func GenerateCSVReport(results *model.TestResults) error {
 	filename := "waf_test_report.csv"
 	file, err := os.Create(filename)
@@ -156,84 +283,220 @@ func GenerateCSVReport(results *model.TestResults) error {
 	}
 	defer file.Close()
 
-	_, err = file.WriteString("Original Payload,Attack Type,Evasion Type,Variant,Level\n")
-	if err != nil {
-		return err
+	if snapshot := configSnapshot(results); snapshot != nil {
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(CSVConfigFilename, append(data, '\n'), 0644); err != nil {
+			return err
+		}
 	}
 
+	// encoding/csv quotes payloads the way CSV readers expect ("" for ")
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"Original Payload", "Attack Type", "Evasion Type", "Variant", "Level"}); err != nil {
+		return err
+	}
 	for _, result := range results.PayloadResults {
 		for _, variant := range result.Variants {
-			line := fmt.Sprintf("%q,%s,%s,%q\n",
-				result.OriginalPayload,
-				result.AttackType,
-				result.EvasionType,
-				variant)
-			_, err = file.WriteString(line)
-			if err != nil {
+			if err := writer.Write([]string{result.OriginalPayload, result.AttackType, result.EvasionType, variant.Value, result.Level}); err != nil {
 				return err
 			}
 		}
 	}
-	return nil
+	writer.Flush()
+	return writer.Error()
 }
 
 // JSONReport represents the structure for JSON output
 type JSONReport struct {
-	Metadata struct {
-		Timestamp string `json:"timestamp"`
-		Tool      string `json:"tool"`
-		Version   string `json:"version"`
-	} `json:"metadata"`
-	Config struct {
-		Action       string `json:"action"`
-		AttackType   string `json:"attack_type"`
-		EvasionLevel string `json:"evasion_level"`
-		TargetURL    string `json:"target_url,omitempty"`
-	} `json:"config"`
+	Metadata JSONMetadata `json:"metadata"`
+	// Config is the redacted configuration the run used
+	Config  report.ConfigSnapshot `json:"config"`
 	Summary struct {
 		TotalPayloads   int      `json:"total_payloads"`
 		TotalVariants   int      `json:"total_variants"`
 		SuccessfulTests int      `json:"successful_tests"`
 		FailedTests     int      `json:"failed_tests"`
+		ChallengeTests  int      `json:"challenge_tests,omitempty"`
 		SuccessRate     float64  `json:"success_rate"`
 		AttackTypes     []string `json:"attack_types"`
 		EvasionTypes    []string `json:"evasion_types"`
+		// Bypasses counts requests that got through; UniqueWeaknesses counts
+		// the distinct normalized payloads among them
+		Bypasses         int `json:"bypasses"`
+		UniqueWeaknesses int `json:"unique_weaknesses"`
+		// ChallengesDominate flags runs where most responses were challenge pages
+		ChallengesDominate bool `json:"challenges_dominate,omitempty"`
+		// RecommendedDecodeDepth is the number of URL-decode passes the
+		// WAF needs to see every bypass in DecodeDepths as an attack
+		RecommendedDecodeDepth int `json:"recommended_decode_depth,omitempty"`
 	} `json:"summary"`
-	PayloadResults []struct {
-		OriginalPayload string   `json:"original_payload"`
-		AttackType      string   `json:"attack_type"`
-		EvasionType     string   `json:"evasion_type"`
-		Variants        []string `json:"variants"`
-	} `json:"payload_results"`
-	RequestResults []struct {
-		Payload      string `json:"payload"`
-		URL          string `json:"url"`
-		Method       string `json:"method"`
-		StatusCode   int    `json:"status_code"`
-		Blocked      bool   `json:"blocked"`
-		ResponseTime int64  `json:"response_time_ms"`
-		Technique    string `json:"technique"`
-		Part         string `json:"part"`
-	} `json:"request_results,omitempty"`
+	// Weaknesses groups the bypasses by normalized payload
+	Weaknesses []report.Weakness `json:"weaknesses,omitempty"`
+	// Clusters groups the responses by status code and near-identical body
+	Clusters []report.Cluster `json:"clusters,omitempty"`
+	// BlockDistribution breaks the blocked requests down by status code and
+	// block reason
+	BlockDistribution []report.BlockShare `json:"block_distribution,omitempty"`
+	// DecodeDepths groups the bypasses that decoding would have revealed by
+	// the number of decode passes needed
+	DecodeDepths []report.DecodeAdvice `json:"decode_depths,omitempty"`
+	// Recommendations is the remediation advice for the bypasses' techniques
+	Recommendations []report.Recommendation `json:"recommendations,omitempty"`
+	// Timing averages the response time phases of timed requests (-timing)
+	Timing         []report.PhaseTiming `json:"timing,omitempty"`
+	PayloadResults []JSONPayloadResult  `json:"payload_results"`
+	RequestResults []JSONRequestResult  `json:"request_results,omitempty"`
 }
 
-func GenerateJSONReport(results *model.TestResults) error {
-	filename := "waf_test_report.json"
+// JSONMetadata identifies the tool build that produced a report
+type JSONMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tool      string `json:"tool"`
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+	// Tags are the run's -tag labels
+	Tags []string `json:"tags,omitempty"`
+}
+
+// NewJSONMetadata returns report metadata for the running build, using the
+// same version information as -version
+func NewJSONMetadata() JSONMetadata {
+	return JSONMetadata{
+		Timestamp: time.Now().Format(time.RFC3339),
+		Tool:      "ObfusKit",
+		Version:   version.Version,
+		GitCommit: version.GitCommit,
+		BuildDate: version.BuildDate,
+	}
+}
+
+// JSONPayloadResult is a generated payload and its variants in the JSON report
+type JSONPayloadResult struct {
+	OriginalPayload string   `json:"original_payload"`
+	AttackType      string   `json:"attack_type"`
+	EvasionType     string   `json:"evasion_type"`
+	Variants        []string `json:"variants"`
+	VariantIDs      []string `json:"variant_ids,omitempty"`
+	// Techniques holds the technique behind each variant, aligned with Variants
+	Techniques []string `json:"techniques,omitempty"`
+}
+
+// JSONRequestResult is a single request outcome in the JSON report
+type JSONRequestResult struct {
+	VariantID       string `json:"variant_id,omitempty"`
+	Encoding        string `json:"encoding,omitempty"`
+	EvasionLevel    string `json:"evasion_level,omitempty"`
+	Payload         string `json:"payload"`
+	URL             string `json:"url"`
+	Method          string `json:"method"`
+	StatusCode      int    `json:"status_code"`
+	Blocked         bool   `json:"blocked"`
+	BlockReason     string `json:"block_reason,omitempty"`
+	RuleID          string `json:"rule_id,omitempty"`
+	CandidateBypass bool   `json:"candidate_bypass,omitempty"`
+	Challenge       bool   `json:"challenge,omitempty"`
+	// Deviations lists how the response differs from the -diff-baseline response
+	Deviations []string `json:"deviations,omitempty"`
+	// Canary is the token the payload carried in stored mode (-view-url);
+	// Stored marks it found on the view page
+	Canary       string `json:"canary,omitempty"`
+	Stored       bool   `json:"stored,omitempty"`
+	ResponseTime int64  `json:"response_time_ms"`
+	// Timing breaks ResponseTime down into phases for timed requests (-timing)
+	Timing    *JSONTiming `json:"timing,omitempty"`
+	Technique string      `json:"technique"`
+	Part      string      `json:"part"`
+	// Headers and Body record the exact request so it can be replayed
+	Headers []request.RecordedHeader `json:"headers,omitempty"`
+	Body    string                   `json:"body,omitempty"`
+}
+
+// JSONTiming is a request's response time phases in the JSON report, in
+// milliseconds (see request.Timing)
+type JSONTiming struct {
+	DNS      float64 `json:"dns_ms"`
+	Connect  float64 `json:"connect_ms"`
+	TLS      float64 `json:"tls_ms"`
+	TTFB     float64 `json:"ttfb_ms"`
+	Transfer float64 `json:"transfer_ms"`
+}
+
+// newJSONTiming converts timing for the JSON report, or returns nil for a
+// request that was not timed
+func newJSONTiming(timing request.Timing) *JSONTiming {
+	if timing.IsZero() {
+		return nil
+	}
+	return &JSONTiming{
+		DNS:      report.Milliseconds(timing.DNS),
+		Connect:  report.Milliseconds(timing.Connect),
+		TLS:      report.Milliseconds(timing.TLS),
+		TTFB:     report.Milliseconds(timing.TTFB),
+		Transfer: report.Milliseconds(timing.Transfer),
+	}
+}
+
+// Recorded returns the stored request in the form request.Replay expects
+func (r JSONRequestResult) Recorded() request.RecordedRequest {
+	return request.RecordedRequest{
+		Method:  r.Method,
+		URL:     r.URL,
+		Headers: r.Headers,
+		Body:    r.Body,
+	}
+}
+
+// LoadJSONReport reads a report written by GenerateJSONReport or -format json
+func LoadJSONReport(filename string) (*JSONReport, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var jsonReport JSONReport
+	if err := json.Unmarshal(data, &jsonReport); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	return &jsonReport, nil
+}
 
-	// Create JSON report structure
-	jsonReport := JSONReport{}
+// FindRequestResults returns the stored requests sent for variantID. A variant
+// is sent once per injection technique, so technique optionally narrows the
+// match to a single request.
+func (r *JSONReport) FindRequestResults(variantID, technique string) []JSONRequestResult {
+	var matches []JSONRequestResult
+	for _, result := range r.RequestResults {
+		if result.VariantID != variantID {
+			continue
+		}
+		if technique != "" && result.Technique != technique {
+			continue
+		}
+		matches = append(matches, result)
+	}
+	return matches
+}
+
+// NewJSONOutput builds the -format json console output: the same JSONReport
+// as the file-based report, but listing the filtered request results with
+// credentials masked, since console output is often pasted into tickets and
+// CI logs
+func NewJSONOutput(results *model.TestResults) JSONReport {
+	return newJSONReport(results, results.RequestResults, true)
+}
 
-	// Metadata
-	jsonReport.Metadata.Timestamp = time.Now().Format(time.RFC3339)
-	jsonReport.Metadata.Tool = "ObfusKit"
-	jsonReport.Metadata.Version = "1.0.0"
+// newJSONReport builds the JSON report of results, listing requestResults
+// and masking credentials in them when redacted is set
+func newJSONReport(results *model.TestResults, requestResults []request.TestResult, redacted bool) JSONReport {
+	jsonReport := JSONReport{Metadata: NewJSONMetadata()}
 
 	// Config
-	if config, ok := results.Config.(*types.Config); ok {
-		jsonReport.Config.Action = string(config.Action)
-		jsonReport.Config.AttackType = string(config.AttackType)
-		jsonReport.Config.EvasionLevel = string(config.EvasionLevel)
-		jsonReport.Config.TargetURL = config.Target.URL
+	if snapshot := configSnapshot(results); snapshot != nil {
+		jsonReport.Config = *snapshot
+		jsonReport.Metadata.Tags = snapshot.Tags
 	}
 
 	// Summary
@@ -242,54 +505,95 @@ func GenerateJSONReport(results *model.TestResults) error {
 	jsonReport.Summary.TotalVariants = summary.TotalVariants
 	jsonReport.Summary.SuccessfulTests = summary.SuccessfulTests
 	jsonReport.Summary.FailedTests = summary.FailedTests
+	jsonReport.Summary.ChallengeTests = summary.ChallengeTests
+	jsonReport.Summary.Bypasses = summary.Bypasses
+	jsonReport.Summary.UniqueWeaknesses = summary.UniqueWeaknesses
 	jsonReport.Summary.AttackTypes = summary.AttackTypes
 	jsonReport.Summary.EvasionTypes = summary.EvasionTypes
 
+	// Rates are against the unfiltered baseline
 	baseRequests := results.RequestResults
 	if len(results.AllRequestResults) > 0 {
 		baseRequests = results.AllRequestResults
 	}
 	if len(baseRequests) > 0 {
 		jsonReport.Summary.SuccessRate = float64(summary.SuccessfulTests) / float64(len(baseRequests)) * 100
+		jsonReport.Summary.ChallengesDominate = request.ChallengesDominate(baseRequests)
+		jsonReport.Weaknesses = report.GroupWeaknesses(baseRequests)
+		jsonReport.Clusters = report.ClusterResults(baseRequests)
+		jsonReport.BlockDistribution = report.BlockDistribution(baseRequests)
+		jsonReport.DecodeDepths = report.DecodeDepths(baseRequests)
+		jsonReport.Summary.RecommendedDecodeDepth = report.RecommendedDecodeDepth(jsonReport.DecodeDepths)
+		jsonReport.Recommendations = report.Recommendations(baseRequests)
+		jsonReport.Timing = report.TimingBreakdown(baseRequests)
 	}
 
 	// Payload Results
+	jsonReport.PayloadResults = []JSONPayloadResult{}
 	for _, result := range results.PayloadResults {
-		jsonReport.PayloadResults = append(jsonReport.PayloadResults, struct {
-			OriginalPayload string   `json:"original_payload"`
-			AttackType      string   `json:"attack_type"`
-			EvasionType     string   `json:"evasion_type"`
-			Variants        []string `json:"variants"`
-		}{
+		jsonReport.PayloadResults = append(jsonReport.PayloadResults, JSONPayloadResult{
 			OriginalPayload: result.OriginalPayload,
 			AttackType:      result.AttackType,
 			EvasionType:     result.EvasionType,
-			Variants:        result.Variants,
+			Variants:        result.Values(),
+			VariantIDs:      result.IDs(),
+			Techniques:      result.Techniques(),
 		})
 	}
 
-	// Request Results (use baseline for consistency with summary)
-	for _, result := range baseRequests {
-		jsonReport.RequestResults = append(jsonReport.RequestResults, struct {
-			Payload      string `json:"payload"`
-			URL          string `json:"url"`
-			Method       string `json:"method"`
-			StatusCode   int    `json:"status_code"`
-			Blocked      bool   `json:"blocked"`
-			ResponseTime int64  `json:"response_time_ms"`
-			Technique    string `json:"technique"`
-			Part         string `json:"part"`
-		}{
-			Payload:      result.Payload,
-			URL:          result.Request.URI().String(),
-			Method:       string(result.Request.Header.Method()),
-			StatusCode:   result.StatusCode,
-			Blocked:      result.Blocked,
-			ResponseTime: result.ResponseTime.Milliseconds(),
-			Technique:    result.EvasionTechnique,
-			Part:         result.RequestPart,
+	// Request Results
+	for _, result := range requestResults {
+		recorded := request.RecordRequest(result.Request)
+		if redacted {
+			recorded = redactRecorded(recorded)
+		}
+		jsonReport.RequestResults = append(jsonReport.RequestResults, JSONRequestResult{
+			VariantID:       result.Variant.ID,
+			Encoding:        result.Variant.Encoding,
+			EvasionLevel:    result.Variant.Level,
+			Payload:         result.Payload,
+			URL:             recorded.URL,
+			Method:          recorded.Method,
+			StatusCode:      result.StatusCode,
+			Blocked:         result.Blocked,
+			BlockReason:     result.BlockReason,
+			RuleID:          result.RuleID,
+			CandidateBypass: result.CandidateBypass,
+			Challenge:       result.Challenge,
+			Deviations:      result.Deviations,
+			Canary:          result.Canary,
+			Stored:          result.Stored,
+			ResponseTime:    result.ResponseTime.Milliseconds(),
+			Timing:          newJSONTiming(result.Timing),
+			Technique:       result.EvasionTechnique,
+			Part:            result.RequestPart,
+			Headers:         recorded.Headers,
+			Body:            recorded.Body,
 		})
 	}
+	return jsonReport
+}
+
+// redactRecorded masks credentials in a recorded request's URL and headers
+func redactRecorded(recorded request.RecordedRequest) request.RecordedRequest {
+	recorded.URL = redact.URL(recorded.URL)
+	headers := make([]request.RecordedHeader, len(recorded.Headers))
+	for i, header := range recorded.Headers {
+		headers[i] = request.RecordedHeader{Name: header.Name, Value: redact.Header(header.Name, header.Value)}
+	}
+	recorded.Headers = headers
+	return recorded
+}
+
+func GenerateJSONReport(results *model.TestResults) error {
+	filename := "waf_test_report.json"
+
+	// Request results use the baseline for consistency with the summary
+	baseRequests := results.RequestResults
+	if len(results.AllRequestResults) > 0 {
+		baseRequests = results.AllRequestResults
+	}
+	jsonReport := newJSONReport(results, baseRequests, false)
 
 	// Write JSON to file
 	file, err := os.Create(filename)
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"obfuskit/request"
+)
+
+// SchemaDraft is the JSON Schema dialect -print-schema emits
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema describes obfuskit's JSON output: the report written by -format
+// json and -report json (JSONReport), and each NDJSON line streamed by -sink
+// (request.SinkRecord). It is derived from those structs by reflection, so it
+// cannot drift from what is actually serialized.
+func JSONSchema() map[string]interface{} {
+	defs := make(map[string]interface{})
+	reportRef := schemaFor(reflect.TypeOf(JSONReport{}), defs)
+	recordRef := schemaFor(reflect.TypeOf(request.SinkRecord{}), defs)
+	return map[string]interface{}{
+		"$schema":     SchemaDraft,
+		"title":       "ObfusKit results",
+		"description": "A JSON report (JSONReport) or one NDJSON result line streamed by -sink (SinkRecord)",
+		"oneOf":       []interface{}{reportRef, recordRef},
+		"$defs":       defs,
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor returns the schema of values of type t as encoding/json writes
+// them. Named structs are added to defs once and referenced by name.
+func schemaFor(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(schemaFor(t.Elem(), defs))
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, defs)
+		}
+		if _, ok := defs[t.Name()]; !ok {
+			defs[t.Name()] = nil // placeholder for recursive types
+			defs[t.Name()] = structSchema(t, defs)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), defs)}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	// interface{} and anything else encoding/json handles dynamically
+	return map[string]interface{}{}
+}
+
+// structSchema describes a struct's JSON object. Fields without omitempty
+// are required, and unknown properties are rejected so new output fields
+// must come from the structs.
+func structSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			// encoding/json promotes an untagged embedded struct's fields
+			embedded := structSchema(field.Type, defs)
+			for name, schema := range embedded["properties"].(map[string]interface{}) {
+				properties[name] = schema
+			}
+			required = append(required, embedded["required"].([]string)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitEmpty := strings.Contains(options, "omitempty")
+
+		schema := schemaFor(field.Type, defs)
+		// nil slices and maps are written as null unless omitted
+		if !omitEmpty && (field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Map) {
+			schema = nullable(schema)
+		}
+		properties[name] = schema
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// nullable also allows null in place of schema
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
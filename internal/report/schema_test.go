@@ -0,0 +1,151 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"obfuskit/internal/model"
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+func TestJSONOutputMatchesSchema(t *testing.T) {
+	data, err := json.Marshal(JSONSchema())
+	if err != nil {
+		t.Fatalf("json.Marshal(JSONSchema()) error: %v", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", bytes.NewReader(data)); err != nil {
+		t.Fatalf("AddResource() error: %v", err)
+	}
+	schema, err := compiler.Compile("schema.json")
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+	validate := func(name string, document []byte) {
+		t.Helper()
+		var value interface{}
+		if err := json.Unmarshal(document, &value); err != nil {
+			t.Fatalf("%s: json.Unmarshal() error: %v", name, err)
+		}
+		if err := schema.Validate(value); err != nil {
+			t.Errorf("%s does not match the schema: %#v", name, err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "script") {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	sinkPath := filepath.Join(dir, "results.ndjson")
+	sink, err := request.OpenSink("file:" + sinkPath)
+	if err != nil {
+		t.Fatalf("OpenSink() error: %v", err)
+	}
+
+	const payload = "<script>alert(1)</script>"
+	payloadResult := model.PayloadResults{
+		OriginalPayload: payload,
+		AttackType:      string(types.AttackTypeXSS),
+		EvasionType:     string(types.PayloadEncodingURL),
+		Level:           string(types.EvasionLevelBasic),
+		Variants:        model.NewVariants([]string{payload, "%3Cscript%3Ealert(1)%3C/script%3E"}),
+	}
+	payloadResult.AssignVariantIDs()
+
+	results := &model.TestResults{
+		Config: &types.Config{
+			Action:     types.ActionSendToURL,
+			AttackType: types.AttackTypeXSS,
+			Target:     types.Target{URL: server.URL},
+		},
+		PayloadResults: []model.PayloadResults{payloadResult},
+	}
+	logger := request.NewLoggerWithLevel(os.Stderr, request.LogLevelError)
+	for _, variant := range payloadResult.Variants {
+		for _, result := range request.NewFastHTTPQueryInjector().Inject(server.URL, variant.Value, logger) {
+			result.Variant = variant
+			result.AttackType = payloadResult.AttackType
+			if err := sink.Write(result); err != nil {
+				t.Fatalf("sink.Write() error: %v", err)
+			}
+			results.RequestResults = append(results.RequestResults, result)
+			if result.Blocked {
+				results.Summary.FailedTests++
+			} else {
+				results.Summary.SuccessfulTests++
+				results.Summary.Bypasses++
+			}
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("sink.Close() error: %v", err)
+	}
+	if len(results.RequestResults) == 0 {
+		t.Fatal("no requests sent")
+	}
+	results.Summary.TotalPayloads = 1
+	results.Summary.TotalVariants = len(payloadResult.Variants)
+
+	console, err := json.Marshal(NewJSONOutput(results))
+	if err != nil {
+		t.Fatalf("json.Marshal(NewJSONOutput()) error: %v", err)
+	}
+	validate("-format json output", console)
+
+	if err := GenerateJSONReport(results); err != nil {
+		t.Fatalf("GenerateJSONReport() error: %v", err)
+	}
+	saved, err := os.ReadFile("waf_test_report.json")
+	if err != nil {
+		t.Fatalf("ReadFile() error: %v", err)
+	}
+	validate("JSON report file", saved)
+
+	ndjson, err := os.Open(sinkPath)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer ndjson.Close()
+	lines := 0
+	scanner := bufio.NewScanner(ndjson)
+	for scanner.Scan() {
+		lines++
+		validate("sink record", scanner.Bytes())
+	}
+	if lines != len(results.RequestResults) {
+		t.Errorf("%d sink records, want %d", lines, len(results.RequestResults))
+	}
+
+	// A field the structs do not declare is drift the schema must catch
+	var drifted map[string]interface{}
+	if err := json.Unmarshal(console, &drifted); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	drifted["summary"].(map[string]interface{})["undeclared"] = 1
+	if err := schema.Validate(drifted); err == nil {
+		t.Error("schema accepted a summary field missing from JSONReport")
+	}
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"obfuskit/internal/genai"
+	"obfuskit/internal/model"
+	"obfuskit/internal/redact"
+	"obfuskit/report"
+	"obfuskit/types"
+)
+
+// NewConfigSnapshot captures the effective config for embedding in reports,
+// redacting API keys and credentials in the target URL
+func NewConfigSnapshot(config *types.Config) report.ConfigSnapshot {
+	snapshot := report.ConfigSnapshot{
+		Tags:                   config.Tags,
+		Action:                 string(config.Action),
+		AttackType:             string(config.AttackType),
+		EvasionLevel:           string(config.EvasionLevel),
+		EncodingLevels:         config.EncodingLevels,
+		Seed:                   config.Seed,
+		PayloadMethod:          string(config.Payload.Method),
+		Encoding:               string(config.Payload.Encoding),
+		PayloadSource:          string(config.Payload.Source),
+		PayloadFile:            config.Payload.FilePath,
+		PayloadDir:             config.Payload.Dir,
+		PayloadFiles:           config.Payload.Files,
+		SensitiveFiles:         config.Payload.SensitiveFiles,
+		SavePayloads:           config.SavePayloads,
+		MaxTraversalDepth:      config.MaxTraversalDepth,
+		TraversalTarget:        config.TraversalTarget,
+		DistinctTechniques:     config.DistinctTechniques,
+		RequireTechniques:      config.RequireTechniques,
+		OnlyTechniques:         config.OnlyTechniques,
+		ExcludeTechniques:      config.ExcludeTechniques,
+		StrictVariants:         config.StrictVariants,
+		Exhaustive:             config.Exhaustive,
+		TargetMethod:           string(config.Target.Method),
+		TargetURL:              redact.URL(config.Target.URL),
+		TargetFile:             config.Target.File,
+		Host:                   config.Target.Host,
+		ParamNames:             config.Target.ParamNames,
+		UserAgent:              config.Target.UserAgent,
+		RotateUserAgents:       config.Target.RotateUserAgents,
+		UserAgentSeed:          config.Target.UserAgentSeed,
+		BodyFile:               config.Target.BodyFile,
+		ContentType:            config.Target.ContentType,
+		CookieJar:              config.Target.CookieJar,
+		CacheBust:              config.Target.CacheBust,
+		FollowRedirects:        config.Target.FollowRedirects,
+		MaxRedirects:           config.Target.MaxRedirects,
+		ResetIsBlock:           config.Target.ResetIsBlock,
+		BlockSignaturesFile:    config.Target.BlockSignaturesFile,
+		Timing:                 config.Target.Timing,
+		ReadBufferSize:         config.Target.ReadBufferSize,
+		MaxResponseSize:        config.Target.MaxResponseSize,
+		Diversify:              config.Target.Diversify,
+		DiversifyPadding:       config.Target.DiversifyPadding,
+		SourceIPs:              config.Target.SourceIPs,
+		Scope:                  config.Target.Scope,
+		MaxRequests:            config.MaxRequests,
+		PerHostConns:           config.PerHostConns,
+		StopOnFirstBypass:      config.StopOnFirstBypass,
+		Shuffle:                config.Shuffle,
+		Sink:                   redact.URL(config.Sink),
+		AdaptiveEscalate:       config.AdaptiveEscalate,
+		ProbeNormalization:     config.ProbeNormalization,
+		DiffBaseline:           config.DiffBaseline,
+		ViewURL:                redact.URL(config.ViewURL),
+		MaxEscalations:         config.MaxEscalations,
+		InterestingStatusCodes: config.InterestingStatusCodes,
+		ReportType:             string(config.ReportType),
+		AdaptiveConcurrency:    config.AdaptiveConcurrency,
+		Fingerprinting:         config.EnableFingerprinting,
+		HeaderNameInjection:    config.Target.HeaderNameInjection,
+		Pipeline:               config.Target.Pipeline,
+		SplitParams:            config.Target.SplitParams,
+		SplitAt:                config.Target.SplitAt,
+	}
+
+	for _, targetURL := range config.Target.URLs {
+		snapshot.TargetURLs = append(snapshot.TargetURLs, redact.URL(targetURL))
+	}
+
+	if config.Target.DisableConnect {
+		snapshot.DisabledTechniques = append(snapshot.DisabledTechniques, "connect")
+	}
+	if config.Target.DisableChunked {
+		snapshot.DisabledTechniques = append(snapshot.DisabledTechniques, "chunked")
+	}
+	if config.Target.DisableMultipleContentLength {
+		snapshot.DisabledTechniques = append(snapshot.DisabledTechniques, "multiple-content-length")
+	}
+
+	if config.AttackType != "" {
+		snapshot.AttackTypes = append(snapshot.AttackTypes, string(config.AttackType))
+	}
+	for _, attackType := range config.AdditionalAttackTypes {
+		snapshot.AttackTypes = append(snapshot.AttackTypes, string(attackType))
+	}
+
+	if filter, ok := config.FilterOptions.(interface{ Active() []string }); ok {
+		snapshot.Filters = filter.Active()
+	}
+
+	if config.EnableAI {
+		snapshot.AI = &report.AISnapshot{Required: config.AIRequired}
+		if aiConfig, ok := config.AIConfig.(*genai.Config); ok && aiConfig != nil {
+			snapshot.AI.Provider = aiConfig.Provider
+			snapshot.AI.Model = aiConfig.Model
+			snapshot.AI.Endpoint = redact.URL(aiConfig.APIEndpoint)
+			if aiConfig.APIKey != "" {
+				snapshot.AI.APIKey = report.Redacted
+			}
+		}
+	}
+
+	return snapshot
+}
+
+// configSnapshot returns the snapshot of results.Config, or nil when the
+// results carry no config
+func configSnapshot(results *model.TestResults) *report.ConfigSnapshot {
+	config, ok := results.Config.(*types.Config)
+	if !ok || config == nil {
+		return nil
+	}
+	snapshot := NewConfigSnapshot(config)
+	return &snapshot
+}
@@ -0,0 +1,176 @@
+// Package resultsdb stores run results in a SQLite database so results can be
+// queried across runs. It uses the pure-Go modernc.org/sqlite driver, so no
+// cgo toolchain is required.
+package resultsdb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"obfuskit/internal/model"
+	"obfuskit/internal/version"
+	"obfuskit/types"
+
+	_ "modernc.org/sqlite"
+)
+
+// DriverName is the database/sql driver registered by modernc.org/sqlite
+const DriverName = "sqlite"
+
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at    TEXT NOT NULL,
+	version       TEXT,
+	action        TEXT,
+	attack_type   TEXT,
+	evasion_level TEXT,
+	target_url    TEXT
+);
+
+CREATE TABLE IF NOT EXISTS run_tags (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	tag    TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS payloads (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id           INTEGER NOT NULL REFERENCES runs(id),
+	variant_id       TEXT,
+	original_payload TEXT,
+	attack_type      TEXT,
+	evasion_type     TEXT,
+	level            TEXT,
+	variant          TEXT
+);
+
+CREATE TABLE IF NOT EXISTS results (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id            INTEGER NOT NULL REFERENCES runs(id),
+	variant_id        TEXT,
+	payload           TEXT,
+	attack_type       TEXT,
+	evasion_technique TEXT,
+	request_part      TEXT,
+	status_code       INTEGER,
+	response_time_ms  INTEGER,
+	blocked           INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_run_tags_tag ON run_tags(tag);
+CREATE INDEX IF NOT EXISTS idx_payloads_attack_type ON payloads(attack_type);
+CREATE INDEX IF NOT EXISTS idx_results_attack_type ON results(attack_type);
+CREATE INDEX IF NOT EXISTS idx_results_blocked ON results(blocked);
+CREATE INDEX IF NOT EXISTS idx_results_run ON results(run_id);
+CREATE INDEX IF NOT EXISTS idx_results_variant ON results(variant_id);
+`
+
+// Open opens (creating if necessary) the results database and applies the schema
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open(DriverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results database: %v", err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply results schema: %v", err)
+	}
+
+	return db, nil
+}
+
+// WriteRun stores one run with its payloads and request results, returning the run ID
+func WriteRun(db *sql.DB, results *model.TestResults) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var action, attackType, level, targetURL string
+	var tags []string
+	if config, ok := results.Config.(*types.Config); ok && config != nil {
+		tags = config.Tags
+		action = string(config.Action)
+		attackType = string(config.AttackType)
+		level = string(config.EvasionLevel)
+		targetURL = config.Target.URL
+	}
+
+	res, err := tx.Exec(`INSERT INTO runs (started_at, version, action, attack_type, evasion_level, target_url)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC().Format(time.RFC3339), version.Version, action, attackType, level, targetURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert run: %v", err)
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read run id: %v", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT INTO run_tags (run_id, tag) VALUES (?, ?)`, runID, tag); err != nil {
+			return 0, fmt.Errorf("failed to insert run tag: %v", err)
+		}
+	}
+
+	// Map variants back to their attack type so results can be queried by it
+	variantAttackTypes := make(map[string]string)
+
+	payloadStmt, err := tx.Prepare(`INSERT INTO payloads (run_id, variant_id, original_payload, attack_type, evasion_type, level, variant)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare payload insert: %v", err)
+	}
+	defer payloadStmt.Close()
+
+	for _, pr := range results.PayloadResults {
+		for _, variant := range pr.Variants {
+			variantID := variant.ID
+			if variantID == "" {
+				variantID = model.VariantID(pr.OriginalPayload, pr.EvasionType, variant.Value)
+			}
+			if _, err := payloadStmt.Exec(runID, variantID, pr.OriginalPayload, pr.AttackType, pr.EvasionType, pr.Level, variant.Value); err != nil {
+				return 0, fmt.Errorf("failed to insert payload: %v", err)
+			}
+			if _, exists := variantAttackTypes[variant.Value]; !exists {
+				variantAttackTypes[variant.Value] = pr.AttackType
+			}
+		}
+	}
+
+	resultStmt, err := tx.Prepare(`INSERT INTO results (run_id, variant_id, payload, attack_type, evasion_technique, request_part, status_code, response_time_ms, blocked)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare result insert: %v", err)
+	}
+	defer resultStmt.Close()
+
+	requestResults := results.AllRequestResults
+	if len(requestResults) == 0 {
+		requestResults = results.RequestResults
+	}
+	for _, r := range requestResults {
+		resultAttackType := r.AttackType
+		if resultAttackType == "" {
+			resultAttackType = variantAttackTypes[r.Payload]
+		}
+		if resultAttackType == "" {
+			resultAttackType = attackType
+		}
+		blocked := 0
+		if r.Blocked {
+			blocked = 1
+		}
+		if _, err := resultStmt.Exec(runID, r.Variant.ID, r.Payload, resultAttackType, r.EvasionTechnique, r.RequestPart,
+			r.StatusCode, r.ResponseTime.Milliseconds(), blocked); err != nil {
+			return 0, fmt.Errorf("failed to insert result: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit run: %v", err)
+	}
+	return runID, nil
+}
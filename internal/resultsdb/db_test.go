@@ -0,0 +1,60 @@
+package resultsdb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"obfuskit/internal/model"
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+func TestWriteRunAndQueryBypasses(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "results.sqlite"))
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer db.Close()
+
+	results := &model.TestResults{
+		Config: &types.Config{
+			Action:       types.ActionSendToURL,
+			AttackType:   types.AttackTypeXSS,
+			EvasionLevel: types.EvasionLevelMedium,
+			Target:       types.Target{URL: "http://example.com"},
+		},
+		PayloadResults: []model.PayloadResults{
+			{OriginalPayload: "<script>", AttackType: "xss", EvasionType: "HTMLVariants", Variants: model.NewVariants([]string{"a", "b"})},
+			{OriginalPayload: "' OR 1=1", AttackType: "sqli", EvasionType: "HexVariants", Variants: model.NewVariants([]string{"c"})},
+		},
+		AllRequestResults: []request.TestResult{
+			{Payload: "a", EvasionTechnique: "basic_header", StatusCode: 403, ResponseTime: time.Millisecond, Blocked: true},
+			{Payload: "b", EvasionTechnique: "basic_header", StatusCode: 200, ResponseTime: time.Millisecond},
+			{Payload: "b", EvasionTechnique: "basic_query_param", StatusCode: 200, ResponseTime: time.Millisecond},
+			{Payload: "c", EvasionTechnique: "basic_query_param", StatusCode: 200, ResponseTime: time.Millisecond},
+		},
+	}
+
+	runID, err := WriteRun(db, results)
+	if err != nil {
+		t.Fatalf("WriteRun() error: %v", err)
+	}
+
+	var bypasses int
+	err = db.QueryRow(`SELECT COUNT(*) FROM results WHERE run_id = ? AND attack_type = 'xss' AND blocked = 0`, runID).Scan(&bypasses)
+	if err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if bypasses != 2 {
+		t.Errorf("xss bypasses = %d, want 2", bypasses)
+	}
+
+	var payloads int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM payloads WHERE run_id = ?`, runID).Scan(&payloads); err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if payloads != 3 {
+		t.Errorf("payload rows = %d, want 3", payloads)
+	}
+}
@@ -50,7 +50,7 @@ func ProcessServerRequestHandler(w http.ResponseWriter, r *http.Request, config
 		attackType = config.AttackType
 		level = config.EvasionLevel
 	}
-	evasions, exists := cmd.GetEvasionsForPayload(attackType)
+	evasions, exists := cmd.GetEvasionsForLevel(attackType, level)
 	if !exists {
 		logging.Warnln("No evasions found for attack type:", attackType)
 		evasions = []types.PayloadEncoding{
@@ -75,6 +75,7 @@ func ProcessServerRequestHandler(w http.ResponseWriter, r *http.Request, config
 				EvasionType:     string(evasionType),
 				Level:           string(level),
 				Variant:         variant,
+				VariantID:       model.VariantID(payload, string(evasionType), variant),
 			})
 		}
 	}
@@ -8,6 +8,7 @@ import (
 
 	"obfuskit/internal/model"
 	"obfuskit/request"
+	"obfuskit/types"
 )
 
 // FilterOptions represents advanced filtering configuration
@@ -63,6 +64,41 @@ func CreateFilterOptions(limit int, minSuccessRate float64, complexity string,
 	return filter
 }
 
+// Active lists the filters that are set as name=value pairs, for recording in
+// report headers
+func (f *FilterOptions) Active() []string {
+	if f == nil {
+		return nil
+	}
+	var filters []string
+	if f.Limit > 0 {
+		filters = append(filters, fmt.Sprintf("limit=%d", f.Limit))
+	}
+	if f.MinSuccessRate > 0 {
+		filters = append(filters, fmt.Sprintf("min_success_rate=%g", f.MinSuccessRate))
+	}
+	if f.Complexity != "" {
+		filters = append(filters, "complexity="+f.Complexity)
+	}
+	if f.MaxResponseTime > 0 {
+		filters = append(filters, "max_response_time="+f.MaxResponseTime.String())
+	}
+	if len(f.FilterStatusCodes) > 0 {
+		codes := make([]string, len(f.FilterStatusCodes))
+		for i, code := range f.FilterStatusCodes {
+			codes[i] = fmt.Sprint(code)
+		}
+		filters = append(filters, "status_codes="+strings.Join(codes, ","))
+	}
+	if len(f.ExcludeEncodings) > 0 {
+		filters = append(filters, "exclude_encodings="+strings.Join(f.ExcludeEncodings, ","))
+	}
+	if f.OnlySuccessful {
+		filters = append(filters, "only_successful=true")
+	}
+	return filters
+}
+
 // FilterPayloadResults filters payload results based on criteria
 func FilterPayloadResults(results []model.PayloadResults, filter *FilterOptions) []model.PayloadResults {
 	if filter == nil {
@@ -101,6 +137,37 @@ func FilterPayloadResults(results []model.PayloadResults, filter *FilterOptions)
 	return filtered
 }
 
+// Outcome views applied to request results after sending
+const (
+	OutcomeAll      = ""
+	OutcomeBypassed = "bypassed"
+	OutcomeBlocked  = "blocked"
+)
+
+// FilterByOutcome narrows results.RequestResults to bypassed or blocked
+// requests for reporting. The unfiltered set is kept in AllRequestResults so
+// summary totals and rates still cover every request sent.
+func FilterByOutcome(results *model.TestResults, outcome string) {
+	if outcome == OutcomeAll {
+		return
+	}
+	if len(results.AllRequestResults) == 0 {
+		results.AllRequestResults = append(results.AllRequestResults, results.RequestResults...)
+	}
+
+	wantBlocked := outcome == OutcomeBlocked
+	filtered := []request.TestResult{}
+	for _, result := range results.RequestResults {
+		if result.Challenge {
+			continue
+		}
+		if result.Blocked == wantBlocked {
+			filtered = append(filtered, result)
+		}
+	}
+	results.RequestResults = filtered
+}
+
 // FilterRequestResults filters request results based on response criteria
 func FilterRequestResults(results []request.TestResult, filter *FilterOptions) []request.TestResult {
 	if filter == nil {
@@ -167,13 +234,13 @@ func (f *FilterOptions) matchesComplexity(payload string) bool {
 }
 
 // filterVariants filters payload variants based on criteria
-func (f *FilterOptions) filterVariants(variants []string, originalPayload string) []string {
-	var filtered []string
+func (f *FilterOptions) filterVariants(variants []types.Variant, originalPayload string) []types.Variant {
+	var filtered []types.Variant
 
 	for _, variant := range variants {
 		// Apply complexity filter to variants as well
 		if f.Complexity != "" {
-			variantComplexity := EstimatePayloadComplexity(variant)
+			variantComplexity := EstimatePayloadComplexity(variant.Value)
 			expectedComplexity := f.getExpectedComplexity()
 			if variantComplexity != expectedComplexity {
 				continue
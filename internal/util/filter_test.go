@@ -0,0 +1,66 @@
+package util
+
+import (
+	"testing"
+
+	"obfuskit/internal/model"
+	"obfuskit/internal/report"
+	"obfuskit/request"
+)
+
+func TestFilterByOutcomeKeepsSummaryBaseline(t *testing.T) {
+	newResults := func() *model.TestResults {
+		return &model.TestResults{
+			RequestResults: []request.TestResult{
+				{Payload: "a", StatusCode: 403, Blocked: true},
+				{Payload: "b", StatusCode: 200},
+				{Payload: "c", StatusCode: 403, Blocked: true},
+				{Payload: "d", StatusCode: 404},
+				{Payload: "e", StatusCode: 429, Blocked: true},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		outcome     string
+		wantBlocked bool
+		wantCount   int
+	}{
+		{"only bypassed", OutcomeBypassed, false, 2},
+		{"only blocked", OutcomeBlocked, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := newResults()
+			FilterByOutcome(results, tt.outcome)
+
+			if len(results.RequestResults) != tt.wantCount {
+				t.Errorf("reported %d results, want %d", len(results.RequestResults), tt.wantCount)
+			}
+			for _, result := range results.RequestResults {
+				if result.Blocked != tt.wantBlocked {
+					t.Errorf("result %q has Blocked=%v in the %s view", result.Payload, result.Blocked, tt.outcome)
+				}
+			}
+
+			report.GenerateSummary(results)
+			if results.Summary.SuccessfulTests != 2 || results.Summary.FailedTests != 3 {
+				t.Errorf("summary = %d bypassed / %d blocked, want 2 / 3 from the full run",
+					results.Summary.SuccessfulTests, results.Summary.FailedTests)
+			}
+		})
+	}
+}
+
+func TestFilterByOutcomeAllIsNoop(t *testing.T) {
+	results := &model.TestResults{
+		RequestResults: []request.TestResult{{Blocked: true}, {Blocked: false}},
+	}
+	FilterByOutcome(results, OutcomeAll)
+	if len(results.RequestResults) != 2 || results.AllRequestResults != nil {
+		t.Errorf("OutcomeAll changed results: %d reported, baseline %v",
+			len(results.RequestResults), results.AllRequestResults)
+	}
+}
@@ -85,13 +85,13 @@ func GenerateNucleiTemplatesFromPayloads(results *model.TestResults, level const
 			OriginalPayload: payloadResult.OriginalPayload,
 			AttackType:      payloadResult.AttackType,
 			EvasionType:     payloadResult.EvasionType,
-			Variants:        payloadResult.Variants,
+			Variants:        payloadResult.Values(),
 			Level:           string(level),
 		})
 	}
 
 	// Generate nuclei templates
-	return report.GenerateNucleiTemplatesFromPayloads(payloadResults, "nuclei_templates")
+	return report.GenerateNucleiTemplatesFromPayloads(payloadResults, nil, "nuclei_templates")
 }
 
 func GenerateCSVReport(results *model.TestResults) error {
@@ -115,7 +115,7 @@ func GenerateCSVReport(results *model.TestResults) error {
 				result.OriginalPayload,
 				result.AttackType,
 				result.EvasionType,
-				variant,
+				variant.Value,
 				result.Level)
 			_, err = file.WriteString(line)
 			if err != nil {
@@ -158,7 +158,7 @@ func SavePayloadsToFile(results *model.TestResults) error {
 		fmt.Fprintf(writer, "## Original Payload: %s\n\n", payloadResult.OriginalPayload)
 
 		for _, variant := range payloadResult.Variants {
-			fmt.Fprintf(writer, "%s\n", variant)
+			fmt.Fprintf(writer, "%s\n", variant.Value)
 		}
 		fmt.Fprintf(writer, "\n---\n\n")
 	}
@@ -176,7 +176,7 @@ func SavePayloadsToFile(results *model.TestResults) error {
 	// Write only the payload variants, one per line
 	for _, payloadResult := range results.PayloadResults {
 		for _, variant := range payloadResult.Variants {
-			fmt.Fprintf(simpleWriter, "%s\n", variant)
+			fmt.Fprintf(simpleWriter, "%s\n", variant.Value)
 		}
 	}
 
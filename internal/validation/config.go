@@ -1,12 +1,15 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"obfuskit/request"
 	"obfuskit/types"
 )
 
@@ -75,6 +78,7 @@ func ValidateConfig(config *types.Config) *ValidationResult {
 
 	// Validate Evasion Level
 	validateEvasionLevel(config, result)
+	validateEncodingLevels(config, result)
 
 	// Validate Payload Configuration
 	validatePayload(config, result)
@@ -84,6 +88,8 @@ func ValidateConfig(config *types.Config) *ValidationResult {
 
 	// Validate URL Configuration
 	validateURL(config, result)
+	validateScope(config, result)
+	validateSourceIPs(config, result)
 
 	// Validate Report Configuration
 	validateReport(config, result)
@@ -170,12 +176,42 @@ func validateEvasionLevel(config *types.Config, result *ValidationResult) {
 	}
 }
 
+func validateEncodingLevels(config *types.Config, result *ValidationResult) {
+	names := make([]string, 0, len(config.EncodingLevels))
+	for name := range config.EncodingLevels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[types.PayloadEncoding]string)
+	for _, name := range names {
+		encoding, ok := types.ParsePayloadEncoding(name)
+		if !ok {
+			result.AddError("encoding_levels", name,
+				"Unknown encoding",
+				"Valid encodings: url, doubleurl, html, unicode, base64, hex, octal, bestfit, mixedcase, utf8, utf7, interleaved, unixcmd, windowscmd, pathtraversal")
+		} else if first, dup := seen[encoding]; dup {
+			result.AddError("encoding_levels", name,
+				"Encoding level set twice, also as "+first,
+				"Set each encoding's level once")
+		} else {
+			seen[encoding] = name
+		}
+		if _, ok := types.ParseEvasionLevel(config.EncodingLevels[name]); !ok {
+			result.AddError("encoding_levels."+name, config.EncodingLevels[name],
+				"Invalid evasion level",
+				"Valid levels: basic, medium, advanced")
+		}
+	}
+}
+
 func validatePayload(config *types.Config, result *ValidationResult) {
 	// Validate payload source
 	validSources := []types.PayloadSource{
 		types.PayloadSourceGenerated,
 		types.PayloadSourceFromFile,
 		types.PayloadSourceEnterManually,
+		types.PayloadSourceNuclei,
 	}
 	sourceValid := false
 	for _, source := range validSources {
@@ -188,7 +224,7 @@ func validatePayload(config *types.Config, result *ValidationResult) {
 	if !sourceValid {
 		result.AddError("payload.source", string(config.Payload.Source),
 			"Invalid payload source",
-			"Valid sources: Generated, From File, Enter Manually")
+			"Valid sources: Generated, From File, Enter Manually, Nuclei")
 	}
 
 	// Validate file path if source is "From File"
@@ -204,6 +240,19 @@ func validatePayload(config *types.Config, result *ValidationResult) {
 		}
 	}
 
+	// Validate the template or list if source is "Nuclei" (-nuclei-payloads)
+	if config.Payload.Source == types.PayloadSourceNuclei {
+		if config.Payload.FilePath == "" {
+			result.AddError("payload.file_path", "",
+				"A nuclei template or payload list is required when source is 'Nuclei'",
+				"Pass the template with -nuclei-payloads")
+		} else if !fileExists(config.Payload.FilePath) {
+			result.AddError("payload.file_path", config.Payload.FilePath,
+				"Nuclei payload file does not exist",
+				"Ensure the file path is correct and accessible")
+		}
+	}
+
 	// Validate custom payloads if source is "Enter Manually"
 	if config.Payload.Source == types.PayloadSourceEnterManually {
 		if len(config.Payload.Custom) == 0 {
@@ -302,6 +351,25 @@ func validateURL(config *types.Config, result *ValidationResult) {
 	}
 }
 
+func validateScope(config *types.Config, result *ValidationResult) {
+	if _, err := request.ParseScope(config.Target.Scope); err != nil {
+		result.AddError("target.scope", strings.Join(config.Target.Scope, ","),
+			err.Error(),
+			"Use host globs (example.com, *.example.com) or CIDRs (10.0.0.0/8)")
+	}
+}
+
+func validateSourceIPs(config *types.Config, result *ValidationResult) {
+	if len(config.Target.SourceIPs) == 0 {
+		return
+	}
+	if _, err := request.NewSourceAddressRotator(config.Target.SourceIPs, config.Seed); err != nil {
+		result.AddError("target.source_ips", strings.Join(config.Target.SourceIPs, ","),
+			err.Error(),
+			"List IP addresses assigned to this host's interfaces (see 'ip addr')")
+	}
+}
+
 func validateReport(config *types.Config, result *ValidationResult) {
 	validReportTypes := []types.ReportType{
 		types.ReportTypePretty,
@@ -344,6 +412,10 @@ func validateCrossFieldRules(config *types.Config, result *ValidationResult) {
 		}
 	}
 
+	// Rule: An explicit encoding must apply to the selected attack types,
+	// otherwise generation silently produces no variants for them
+	validateEncodingApplicability(config, result)
+
 	// Rule: Multiple attack types validation
 	if len(config.AdditionalAttackTypes) > 0 {
 		if config.AttackType == types.AttackTypeGeneric || config.AttackType == types.AttackTypeAll {
@@ -354,6 +426,45 @@ func validateCrossFieldRules(config *types.Config, result *ValidationResult) {
 	}
 }
 
+func validateEncodingApplicability(config *types.Config, result *ValidationResult) {
+	if config.Payload.Encoding == "" || config.Payload.Method != types.PayloadMethodEncodings {
+		return
+	}
+
+	attackTypes := append([]types.AttackType{config.AttackType}, config.AdditionalAttackTypes...)
+	var inapplicable []error
+	for _, attackType := range attackTypes {
+		if err := types.CheckEvasionApplicable(attackType, config.Payload.Encoding); err != nil {
+			inapplicable = append(inapplicable, err)
+		}
+	}
+	if len(inapplicable) == 0 {
+		return
+	}
+
+	for _, err := range inapplicable {
+		hint := "Choose an encoding listed as supported, or omit -encoding"
+		var evasionErr *types.InapplicableEvasionError
+		if errors.As(err, &evasionErr) {
+			hint = fmt.Sprintf("Supported encodings for %s: %s", evasionErr.AttackType, joinEncodings(evasionErr.Supported))
+		}
+		// Fatal only if no selected attack type can use the encoding
+		if len(inapplicable) == len(attackTypes) {
+			result.AddError("payload.encoding", string(config.Payload.Encoding), err.Error(), hint)
+		} else {
+			result.AddWarning("payload.encoding", string(config.Payload.Encoding), err.Error(), hint)
+		}
+	}
+}
+
+func joinEncodings(encodings []types.PayloadEncoding) string {
+	names := make([]string, len(encodings))
+	for i, encoding := range encodings {
+		names[i] = string(encoding)
+	}
+	return strings.Join(names, ", ")
+}
+
 // Helper functions
 func fileExists(path string) bool {
 	if path == "" {
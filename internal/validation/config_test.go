@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"obfuskit/types"
+)
+
+func TestValidateEncodingApplicability(t *testing.T) {
+	newConfig := func(encoding types.PayloadEncoding, attackTypes ...types.AttackType) *types.Config {
+		return &types.Config{
+			Action:                types.ActionGeneratePayloads,
+			AttackType:            attackTypes[0],
+			AdditionalAttackTypes: attackTypes[1:],
+			EvasionLevel:          types.EvasionLevelMedium,
+			Payload: types.Payload{
+				Method:   types.PayloadMethodEncodings,
+				Source:   types.PayloadSourceGenerated,
+				Encoding: encoding,
+			},
+			Target:     types.Target{Method: types.TargetMethodFile},
+			ReportType: types.ReportTypePretty,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		config      *types.Config
+		wantError   bool
+		wantWarning bool
+	}{
+		{"applicable", newConfig(types.PayloadEncodingHex, types.AttackTypeLDAP), false, false},
+		{"inapplicable", newConfig(types.PayloadEncodingPathTraversal, types.AttackTypeLDAP), true, false},
+		{"applicable to one of several", newConfig(types.PayloadEncodingPathTraversal, types.AttackTypeLDAP, types.AttackTypePath), false, true},
+		{"unmapped attack type is not checked", newConfig(types.PayloadEncodingPathTraversal, types.AttackTypeSSRF), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{Valid: true}
+			validateEncodingApplicability(tt.config, result)
+
+			if got := hasField(result.Errors, "payload.encoding"); got != tt.wantError {
+				t.Errorf("error reported = %v, want %v (errors: %v)", got, tt.wantError, result.Errors)
+			}
+			if got := hasField(result.Warnings, "payload.encoding"); got != tt.wantWarning {
+				t.Errorf("warning reported = %v, want %v (warnings: %v)", got, tt.wantWarning, result.Warnings)
+			}
+		})
+	}
+}
+
+func TestValidateConfigReportsInapplicableEncoding(t *testing.T) {
+	config := &types.Config{
+		Action:       types.ActionGeneratePayloads,
+		AttackType:   types.AttackTypeLDAP,
+		EvasionLevel: types.EvasionLevelMedium,
+		Payload: types.Payload{
+			Method:   types.PayloadMethodEncodings,
+			Source:   types.PayloadSourceGenerated,
+			Encoding: types.PayloadEncodingPathTraversal,
+		},
+		Target:     types.Target{Method: types.TargetMethodFile},
+		ReportType: types.ReportTypePretty,
+	}
+
+	result := ValidateConfig(config)
+	if !result.HasErrors() {
+		t.Fatal("expected ldapi + pathtraversal to be reported as an error")
+	}
+	for _, err := range result.Errors {
+		if err.Field == "payload.encoding" {
+			if err.Value != string(types.PayloadEncodingPathTraversal) || err.Hint == "" {
+				t.Errorf("error = %+v, want the encoding as value and a hint listing supported encodings", err)
+			}
+			return
+		}
+	}
+	t.Errorf("no payload.encoding error in %v", result.Errors)
+}
+
+func TestValidateEncodingLevels(t *testing.T) {
+	config := &types.Config{
+		EncodingLevels: map[string]string{"pathtraversal": "advanced", "rot13": "basic", "base64": "extreme"},
+	}
+	result := &ValidationResult{Valid: true}
+	validateEncodingLevels(config, result)
+
+	if len(result.Errors) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(result.Errors), result.Errors)
+	}
+	if !hasField(result.Errors, "encoding_levels") || !hasField(result.Errors, "encoding_levels.base64") {
+		t.Errorf("errors = %v, want the unknown encoding and the invalid level", result.Errors)
+	}
+}
+
+func TestValidateEncodingLevelsRejectsAliasesOfOneEncoding(t *testing.T) {
+	config := &types.Config{
+		EncodingLevels: map[string]string{"url": "basic", "URLVariants": "advanced", "b64": "medium"},
+	}
+	result := &ValidationResult{Valid: true}
+	validateEncodingLevels(config, result)
+
+	if len(result.Errors) != 1 || !hasField(result.Errors, "encoding_levels") {
+		t.Errorf("errors = %v, want one for url and URLVariants both setting URL encoding", result.Errors)
+	}
+}
+
+func TestValidateConfigAcceptsNucleiPayloads(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "xss.yaml")
+	if err := os.WriteFile(template, []byte("payloads:\n  xss:\n    - <svg onload=alert(1)>\n"), 0644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	newConfig := func(path string) *types.Config {
+		// As -nuclei-payloads sets it up
+		return &types.Config{
+			Action:       types.ActionSendToURL,
+			AttackType:   types.AttackTypeXSS,
+			EvasionLevel: types.EvasionLevelMedium,
+			Payload: types.Payload{
+				Method:   types.PayloadMethodAuto,
+				Source:   types.PayloadSourceNuclei,
+				FilePath: path,
+			},
+			Target:     types.Target{Method: types.TargetMethodURL, URL: "http://example.com/"},
+			ReportType: types.ReportTypePretty,
+		}
+	}
+
+	if result := ValidateConfig(newConfig(template)); result.HasErrors() {
+		t.Errorf("ValidateConfig() errors = %v, want none", result.Errors)
+	}
+	for _, path := range []string{"", filepath.Join(t.TempDir(), "missing.yaml")} {
+		if result := ValidateConfig(newConfig(path)); !hasField(result.Errors, "payload.file_path") {
+			t.Errorf("path %q: errors = %v, want a payload.file_path error", path, result.Errors)
+		}
+	}
+}
+
+func hasField(errs []ValidationError, field string) bool {
+	for _, err := range errs {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
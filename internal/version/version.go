@@ -3,6 +3,7 @@ package version
 import (
 	"fmt"
 	"runtime"
+	"strings"
 )
 
 // These variables are meant to be overridden at build time via -ldflags, e.g.:
@@ -40,7 +41,15 @@ func GetBuildInfo() BuildInfo {
 
 // GetVersionString returns a formatted version string
 func GetVersionString() string {
-	return fmt.Sprintf("ObfusKit v%s (built %s)", Version, BuildDate)
+	return fmt.Sprintf("ObfusKit %s (commit %s, built %s)", displayVersion(), GitCommit, BuildDate)
+}
+
+// displayVersion prefixes Version with "v" unless the release tag already has it
+func displayVersion() string {
+	if strings.HasPrefix(Version, "v") || Version == "dev" {
+		return Version
+	}
+	return "v" + Version
 }
 
 // GetDetailedVersionString returns comprehensive version information
@@ -66,12 +75,12 @@ Compiler: %s`,
 func GetStartupBanner() string {
 	return fmt.Sprintf(`
 ╔═══════════════════════════════════════════════════════════╗
-║                    🛡️  OBFUSKIT v%s                     ║
+║                    🛡️  OBFUSKIT %s                     ║
 ║              Enterprise WAF Testing Platform              ║
 ║                                                           ║
 ║  🎯 Multi-Attack Testing    🚀 Parallel Processing       ║
 ║  🧠 WAF Intelligence       📊 Advanced Analytics         ║
 ║  🔧 Batch Operations       ⚡ 10x Performance Boost      ║
 ╚═══════════════════════════════════════════════════════════╝
-`, Version)
+`, displayVersion())
 }
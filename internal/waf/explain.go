@@ -0,0 +1,67 @@
+package waf
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// BlockEvidence is what a block response's headers reveal about who blocked
+// it: the WAF, and the rule or incident ID when the WAF sends one
+type BlockEvidence struct {
+	WAF    WAFType
+	RuleID string
+}
+
+// blockSignatures are the signatures ExplainBlock matches against, compiled once
+var blockSignatures = GetWAFSignatures()
+
+// ExplainBlock recognizes the WAF behind a block response from the signature
+// DB. A signature's rule header identifies both the WAF and the rule; failing
+// that, its header patterns identify the WAF alone. Headers the platform sets
+// on every response only count when the status and body are the WAF's block
+// page too, so a 403 from an application behind the CDN is not put down to
+// its WAF. It returns false when no signature matches.
+func ExplainBlock(resp *fasthttp.Response) (BlockEvidence, bool) {
+	for _, sig := range blockSignatures {
+		for _, name := range sig.RuleHeaders {
+			if value := resp.Header.Peek(name); len(value) > 0 && countsAsEvidence(sig, name, resp) {
+				return BlockEvidence{WAF: sig.Name, RuleID: string(value)}, true
+			}
+		}
+	}
+	for _, sig := range blockSignatures {
+		names := make([]string, 0, len(sig.Headers))
+		for name := range sig.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if value := resp.Header.Peek(name); len(value) > 0 && sig.Headers[name].Match(value) && countsAsEvidence(sig, name, resp) {
+				return BlockEvidence{WAF: sig.Name}, true
+			}
+		}
+	}
+	return BlockEvidence{}, false
+}
+
+// countsAsEvidence reports whether header tells that sig's WAF blocked resp:
+// any header of its own does, and a platform header does when resp has one
+// of the signature's block statuses and a body matching its block page
+func countsAsEvidence(sig WAFSignature, header string, resp *fasthttp.Response) bool {
+	if !slices.ContainsFunc(sig.PlatformHeaders, func(name string) bool { return strings.EqualFold(name, header) }) {
+		return true
+	}
+	if !slices.Contains(sig.StatusCodes, resp.StatusCode()) {
+		return false
+	}
+	body := resp.Body()
+	for _, pattern := range sig.Content {
+		if pattern.Match(body) {
+			return true
+		}
+	}
+	return false
+}
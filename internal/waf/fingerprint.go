@@ -59,6 +59,14 @@ type WAFSignature struct {
 	StatusCodes  []int
 	TestPayloads []string
 	Confidence   float64
+	// RuleHeaders name the block-response headers carrying the ID of the
+	// rule or incident behind a block, most specific first
+	RuleHeaders []string
+	// PlatformHeaders name the headers among Headers and RuleHeaders that
+	// the CDN or cloud platform sets on every response, blocked or not, such
+	// as its request ID. A block only counts as the WAF's through them when
+	// it has one of StatusCodes and a body matching Content.
+	PlatformHeaders []string
 }
 
 // GetWAFSignatures returns known WAF signatures for detection
@@ -82,7 +90,9 @@ func GetWAFSignatures() []WAFSignature {
 				"' OR 1=1 --",
 				"../../../etc/passwd",
 			},
-			Confidence: 0.9,
+			Confidence:      0.9,
+			RuleHeaders:     []string{"CF-Ray"},
+			PlatformHeaders: []string{"Server", "CF-Ray", "CF-Cache-Status"},
 		},
 		{
 			Name: WAFTypeAWSWAF,
@@ -95,8 +105,10 @@ func GetWAFSignatures() []WAFSignature {
 				regexp.MustCompile(`(?i)aws`),
 				regexp.MustCompile(`(?i)request blocked`),
 			},
-			StatusCodes: []int{403},
-			Confidence:  0.8,
+			StatusCodes:     []int{403},
+			Confidence:      0.8,
+			RuleHeaders:     []string{"X-Amzn-Waf-Rule", "X-Amzn-RequestId"},
+			PlatformHeaders: []string{"Server", "X-Amzn-RequestId", "X-Amz-Cf-Id"},
 		},
 		{
 			Name: WAFTypeModSecurity,
@@ -110,6 +122,7 @@ func GetWAFSignatures() []WAFSignature {
 			},
 			StatusCodes: []int{403, 406},
 			Confidence:  0.85,
+			RuleHeaders: []string{"X-Mod-Security-Id", "X-ModSecurity-Rule-Id"},
 		},
 		{
 			Name: WAFTypeImperva,
@@ -122,6 +135,7 @@ func GetWAFSignatures() []WAFSignature {
 			},
 			StatusCodes: []int{403},
 			Confidence:  0.9,
+			RuleHeaders: []string{"X-Iinfo"},
 		},
 		{
 			Name: WAFTypeF5BigIP,
@@ -136,6 +150,7 @@ func GetWAFSignatures() []WAFSignature {
 			},
 			StatusCodes: []int{403},
 			Confidence:  0.85,
+			RuleHeaders: []string{"X-WA-Info"},
 		},
 		{
 			Name: WAFTypeAkamai,
@@ -148,8 +163,10 @@ func GetWAFSignatures() []WAFSignature {
 				regexp.MustCompile(`(?i)akamai`),
 				regexp.MustCompile(`(?i)reference.*\d+`),
 			},
-			StatusCodes: []int{403},
-			Confidence:  0.9,
+			StatusCodes:     []int{403},
+			Confidence:      0.9,
+			RuleHeaders:     []string{"X-Akamai-Request-ID"},
+			PlatformHeaders: []string{"Server", "Akamai-Ghost-IP", "X-Akamai-Request-ID"},
 		},
 		{
 			Name: WAFTypeBarracuda,
@@ -173,12 +190,17 @@ func GetWAFSignatures() []WAFSignature {
 			},
 			StatusCodes: []int{403},
 			Confidence:  0.9,
+			RuleHeaders: []string{"X-Sucuri-Block", "X-Sucuri-ID"},
 		},
 	}
 }
 
-// FingerprintWAF attempts to identify the WAF protecting a URL
-func FingerprintWAF(targetURL string) (*WAFFingerprint, error) {
+// Sender sends req and reads the response into resp
+type Sender func(req *fasthttp.Request, resp *fasthttp.Response) error
+
+// FingerprintWAF attempts to identify the WAF protecting a URL, sending its
+// probes with send
+func FingerprintWAF(targetURL string, send Sender) (*WAFFingerprint, error) {
 	fmt.Printf("🔍 Fingerprinting WAF at %s...\n", targetURL)
 
 	fingerprint := &WAFFingerprint{
@@ -193,7 +215,7 @@ func FingerprintWAF(targetURL string) (*WAFFingerprint, error) {
 	signatures := GetWAFSignatures()
 
 	// Test with benign request first
-	normalResponse, err := makeRequest(targetURL, "")
+	normalResponse, err := makeRequest(send, targetURL, "")
 	if err != nil {
 		return fingerprint, fmt.Errorf("failed to make normal request: %w", err)
 	}
@@ -217,7 +239,7 @@ func FingerprintWAF(targetURL string) (*WAFFingerprint, error) {
 
 	var maliciousResponses []*fasthttp.Response
 	for _, payload := range testPayloads {
-		resp, err := makeRequest(targetURL, payload)
+		resp, err := makeRequest(send, targetURL, payload)
 		if err == nil {
 			maliciousResponses = append(maliciousResponses, resp)
 			fingerprint.StatusCodes = append(fingerprint.StatusCodes, resp.StatusCode())
@@ -245,7 +267,7 @@ func FingerprintWAF(targetURL string) (*WAFFingerprint, error) {
 }
 
 // makeRequest makes an HTTP request with optional payload injection
-func makeRequest(targetURL, payload string) (*fasthttp.Response, error) {
+func makeRequest(send Sender, targetURL, payload string) (*fasthttp.Response, error) {
 	req := fasthttp.AcquireRequest()
 	resp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseRequest(req)
@@ -263,12 +285,9 @@ func makeRequest(targetURL, payload string) (*fasthttp.Response, error) {
 	req.Header.SetMethod("GET")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 
-	client := &fasthttp.Client{
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-	}
+	req.SetTimeout(10 * time.Second)
 
-	err := client.Do(req, resp)
+	err := send(req, resp)
 	if err != nil {
 		fasthttp.ReleaseResponse(resp)
 		return nil, err
@@ -2,52 +2,140 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"obfuskit/cmd"
+	"obfuskit/internal/evasions/encoders"
 	"obfuskit/internal/genai"
 	"obfuskit/internal/logging"
 	"obfuskit/internal/model"
+	"obfuskit/internal/notify"
 	"obfuskit/internal/payload"
 	"obfuskit/internal/performance"
+	"obfuskit/internal/redact"
 	"obfuskit/internal/report"
+	"obfuskit/internal/resultsdb"
 	"obfuskit/internal/server"
 	"obfuskit/internal/util"
 	"obfuskit/internal/validation"
 	"obfuskit/internal/version"
+	"obfuskit/request"
 	"obfuskit/types"
 )
 
 func main() {
 	// Initialize logging (default ERROR, override via env)
 	logging.InitFromEnv()
+
+	// `obfuskit fuzz` is a streamlined path with its own flags
+	if len(os.Args) > 1 && os.Args[1] == "fuzz" {
+		if err := runFuzz(os.Args[2:]); err != nil {
+			log.Fatalf("Fuzz failed: %v", err)
+		}
+		return
+	}
+
 	// Define command line flags
 	helpFlag := flag.Bool("help", false, "Show help information")
 	versionFlag := flag.Bool("version", false, "Show version information")
 	verboseVersionFlag := flag.Bool("version-full", false, "Show detailed version and build information")
 	configFlag := flag.String("config", "", "Path to configuration file (YAML or JSON)")
 	generateConfigFlag := flag.String("generate-config", "", "Generate example config file (yaml or json)")
+	printSchemaFlag := flag.Bool("print-schema", false, "Print the JSON Schema of the JSON report and -sink records, then exit")
 	serverFlag := flag.Bool("server", false, "Start integration webservice")
 
 	// Simple CLI flags for common use cases
 	attackTypeFlag := flag.String("attack", "", "Attack type(s) - single: xss, or multiple: xss,sqli,unixcmdi")
 	payloadFlag := flag.String("payload", "", "Single payload to generate evasions for")
 	payloadFileFlag := flag.String("payload-file", "", "File containing payloads (one per line)")
+	nucleiPayloadsFlag := flag.String("nuclei-payloads", "", "Send the payloads of a nuclei template (.yaml) or list as they are, without generating variants (needs -url)")
 	urlFlag := flag.String("url", "", "Target URL to test payloads against")
 	urlFileFlag := flag.String("url-file", "", "File containing URLs to test (one per line)")
+	showNormalizedFlag := flag.Bool("show-normalized", false, "Print the normalized target URL(s) the injectors will request before sending")
 	outputFlag := flag.String("output", "", "Output file path (default: print to console)")
 	levelFlag := flag.String("level", "medium", "Evasion level (basic, medium, advanced)")
+	maxDepthFlag := flag.Int("max-depth", 0, "Maximum ../ depth for path traversal expansion (0 = default)")
+	traversalTargetFlag := flag.String("traversal-target", "", "File path traversal techniques aim at, or unix/windows for the OS default (default: etc/passwd)")
+	seedFlag := flag.Int64("seed", 0, "Seed for randomized evasions so runs are reproducible (0 = random)")
 	encodingFlag := flag.String("encoding", "", "Specific encoding method (url, html, unicode, base64, hex, etc.)")
+	encodingLevelFlag := encodingLevelsValue{}
+	flag.Var(encodingLevelFlag, "encoding-level", "Evasion level for one encoding, as name=level (repeatable), e.g. pathtraversal=advanced")
 	reportFlag := flag.String("report", "pretty", "Report format (pretty, html, pdf, csv, nuclei, json)")
-	threadsFlag := flag.Int("threads", 1, "Number of concurrent threads for parallel processing")
+	compareEncodingsFlag := flag.Bool("compare-encodings", false, "Send every encoding of one -payload to -url and rank the encodings by bypass rate")
+	encodingReportFlag := flag.String("encoding-report", "", "Write each payload's encodings and resulting variants to this file (.html for HTML, text otherwise)")
+	threadsFlag := flag.String("threads", "1", "Number of concurrent threads for parallel processing (0 or auto = one per CPU)")
 	formatFlag := flag.String("format", "text", "Output format (text, json, csv)")
 	progressFlag := flag.Bool("progress", false, "Show progress bar for long operations")
+	dbFlag := flag.String("db", "", "SQLite database file to record run results in (e.g. results.sqlite)")
+	var tagFlag listFlag
+	flag.Var(&tagFlag, "tag", "Label the run in reports, the results database and notifications (repeatable)")
+	webhookFlag := flag.String("webhook", "", "URL to POST a JSON run summary to on completion")
+	slackWebhookFlag := flag.String("slack-webhook", "", "Slack incoming webhook URL to notify on completion")
+	payloadsDirFlag := flag.String("payloads-dir", "", "Directory with base payload files named <attack>.txt (default: payloads)")
+	fileWordlistFlag := flag.String("fileaccess-wordlist", "", "Wordlist of target files for fileaccess payloads (one per line)")
+
+	// Request options
+	paramFlag := flag.String("param", "", "Parameter name(s) to inject payloads into, comma-separated (default: param)")
+	adaptiveFlag := flag.Bool("adaptive", false, "Adapt concurrency to target health, using -threads as the maximum")
+	hostFlag := flag.String("host", "", "Host header to send on every request (connection still goes to -url)")
+	userAgentFlag := flag.String("user-agent", "", "User-Agent header to send on every request")
+	uaRotateFlag := flag.Bool("ua-rotate", false, "Rotate through built-in browser User-Agents per request")
+	uaSeedFlag := flag.Int64("ua-seed", 0, "Seed for the -ua-rotate order (same seed, same sequence)")
+	bodyFileFlag := flag.String("body-file", "", "Request body template with a §PAYLOAD§ marker, sent instead of form/JSON bodies")
+	contentTypeFlag := flag.String("content-type", "", "Content-Type for -body-file (default: guessed from the file extension)")
+	cookieJarFlag := flag.Bool("cookie-jar", false, "Carry cookies set by the target (Set-Cookie) on later requests to the same host")
+	cacheBustFlag := flag.Bool("cache-bust", false, "Add no-cache headers and a random query nonce to every request")
+	followRedirectsFlag := flag.Bool("follow-redirects", false, "Follow redirects and classify the final response instead of the 3xx")
+	maxRedirectsFlag := flag.Int("max-redirects", 0, "Redirect hops to follow with -follow-redirects (default 5)")
+	resetIsBlockFlag := flag.Bool("reset-is-block", false, "Count connections reset or closed before a response as blocked instead of failed")
+	blockSignaturesFlag := flag.String("block-signatures-file", "", "File of block page signatures (substrings, or re:regex); a matching response body is blocked whatever its status")
+	timingFlag := flag.Bool("timing", false, "Send each request on a fresh connection and record its DNS, connect, TLS and TTFB times")
+	readBufferFlag := flag.Int("read-buffer-size", 0, "HTTP client read buffer in bytes; raise it for block pages with very long headers (default 4096)")
+	maxResponseFlag := flag.Int("max-response-size", 0, "Read at most this many bytes of each response body, classifying larger ones by status (0 = no cap)")
+	diversifyFlag := flag.Bool("diversify", false, "Vary benign headers, their order and the User-Agent per request (order set by -seed)")
+	diversifyPaddingFlag := flag.Bool("diversify-padding", false, "With -diversify, also add a random-length padding header to every request")
+	sourceIPsFlag := flag.String("source-ips", "", "Comma-separated local addresses to send requests from in turn (order set by -seed)")
+	scopeFlag := flag.String("scope", "", "Comma-separated host globs and CIDRs requests may go to; other hosts are refused (e.g. '*.example.com,10.0.0.0/8')")
+	noConnectFlag := flag.Bool("no-connect", false, "Do not send CONNECT requests in the protocol injection tests")
+	noChunkedFlag := flag.Bool("no-chunked", false, "Do not send chunked Transfer-Encoding requests in the protocol injection tests")
+	pipelineFlag := flag.Bool("pipeline", false, "Also send payloads in HTTP/1.1 pipelined batches on one raw connection")
+	splitParamsFlag := flag.String("split-params", "", "Also send each payload split across these comma-separated params in one request")
+	splitAtFlag := flag.String("split-at", "", "Comma-separated character offsets to split payloads at for -split-params (even split by default)")
+	headerNamesFlag := flag.Bool("header-name-injection", false, "Also inject payloads into header names, not just values (strict servers answer 400)")
+	noMultiCLFlag := flag.Bool("no-multiple-content-length", false, "Do not send conflicting Content-Length headers in the protocol injection tests")
+	maxRequestsFlag := flag.Int("max-requests", 0, "Stop after sending this many requests in total (0 = no cap)")
+	perHostConnsFlag := flag.Int("per-host-conns", 0, "Maximum simultaneous requests to any one host (0 = no per-host cap)")
+	stopOnBypassFlag := flag.Bool("stop-on-first-bypass", false, "Skip a payload's remaining variants once one of them bypasses")
+	shuffleFlag := flag.Bool("shuffle", false, "Send the variants in a random order (seeded by -seed) instead of payload by payload")
+	escalateFlag := flag.Bool("adaptive-escalate", false, "Resend payloads that were only blocked, regenerated at the next evasion level")
+	maxEscalationsFlag := flag.Int("max-escalations", 0, "Escalation rounds for -adaptive-escalate (0 = until advanced)")
+	probeNormalizationFlag := flag.Bool("probe-normalization", false, "Probe which encodings the target decodes and generate those first")
+	diffBaselineFlag := flag.String("diff-baseline", "", "Clean response JSON file; flag results whose responses deviate from it")
+	viewURLFlag := flag.String("view-url", "", "Page to fetch after sending; payloads whose canaries appear on it are flagged stored")
+	sinkFlag := flag.String("sink", "", "Stream every result as it is produced: stdout, file:<path> or an NDJSON file path")
+	distinctFlag := flag.Int("distinct-techniques", 0, "Keep at most this many variants per technique for each payload (0 = all)")
+	onlyTechniquesFlag := flag.String("only-techniques", "", "Keep only variants of these comma-separated techniques or encodings (e.g. 'nginx_off_by_slash')")
+	excludeTechniquesFlag := flag.String("exclude-techniques", "", "Drop variants of these comma-separated techniques or encodings")
+	requireTechniquesFlag := flag.String("require-techniques", "", "Fail unless each of these comma-separated techniques or encodings produced a variant (e.g. 'utf8,url_encoding')")
+	exhaustiveFlag := flag.Bool("exhaustive", false, "Generate every alternative of every technique at the advanced level, deterministically")
+	stdoutFlag := flag.Bool("stdout", false, "Print only the generated variants, one per line, and write no files")
+	savePayloadsFlag := flag.Bool("save-payloads", false, "Also write the payload files when sending to a URL")
+	strictVariantsFlag := flag.Bool("strict-variants", false, "Drop variants that no longer decode back to the payload's attack structure")
+	ladderFlag := flag.String("ladder", "", "Preview how a payload looks after each URL-decode pass and exit")
+	explainFlag := flag.Bool("explain", false, "Print each variant with the technique that produced it and exit")
+	replayFlag := flag.String("replay", "", "Re-send the stored request(s) for a variant ID from a saved JSON report")
+	replayFromFlag := flag.String("replay-from", "waf_test_report.json", "Saved JSON report to replay requests from")
+	replayRecipeFlag := flag.String("replay-recipe", "", "Re-send the request described by a recipe file (see -recipes)")
+	recipesFlag := flag.String("recipes", "", "Write a replayable recipe file for every bypass to this directory")
+	replayTechniqueFlag := flag.String("replay-technique", "", "Only replay the request sent with this technique (e.g. basic_query_param)")
 
 	// Advanced filtering options
 	limitFlag := flag.Int("limit", 0, "Limit number of payloads to generate (0 = no limit)")
@@ -57,6 +145,8 @@ func main() {
 	statusCodesFlag := flag.String("filter-status", "", "Filter by status codes (e.g., '200,404' to only show these)")
 	excludeEncodingsFlag := flag.String("exclude-encodings", "", "Exclude specific encodings (e.g., 'base64,hex')")
 	onlySuccessfulFlag := flag.Bool("only-successful", false, "Only show payloads that successfully bypassed WAF")
+	onlyBypassedFlag := flag.Bool("only-bypassed", false, "Only report requests that bypassed the WAF (summary still counts all)")
+	onlyBlockedFlag := flag.Bool("only-blocked", false, "Only report requests that were blocked (summary still counts all)")
 
 	// WAF fingerprinting options
 	fingerprintFlag := flag.Bool("fingerprint", false, "Enable WAF fingerprinting and adaptive evasion")
@@ -68,12 +158,13 @@ func main() {
 
 	// AI/GenAI options
 	enableAIFlag := flag.Bool("ai", false, "Enable AI-powered payload generation")
-	aiProviderFlag := flag.String("ai-provider", "openai", "AI provider (openai, anthropic, local, huggingface)")
+	aiProviderFlag := flag.String("ai-provider", "", "AI provider (openai, azure, anthropic, local, huggingface)")
 	aiModelFlag := flag.String("ai-model", "", "AI model to use (auto-detected if not specified)")
 	aiConfigFlag := flag.String("ai-config", "", "Path to AI configuration file")
 	aiCountFlag := flag.Int("ai-count", 10, "Number of AI-generated payloads")
 	aiCreativityFlag := flag.Float64("ai-creativity", 0.7, "AI creativity level (0.0-1.0)")
 	aiContextFlag := flag.String("ai-context", "", "Additional context for AI generation")
+	aiRequiredFlag := flag.Bool("ai-required", false, "Abort when the AI provider is not configured instead of generating without AI")
 
 	flag.Parse()
 
@@ -83,6 +174,15 @@ func main() {
 		return
 	}
 
+	threads, threadsErr := types.ParseThreads(*threadsFlag)
+	if threadsErr != nil {
+		log.Fatalf("Invalid -threads: %v", threadsErr)
+	}
+
+	if *onlyBypassedFlag && *onlyBlockedFlag {
+		log.Fatalf("-only-bypassed and -only-blocked cannot be used together")
+	}
+
 	if *versionFlag {
 		fmt.Println(version.GetVersionString())
 		return
@@ -93,6 +193,15 @@ func main() {
 		return
 	}
 
+	if *printSchemaFlag {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report.JSONSchema()); err != nil {
+			log.Fatalf("Error printing schema: %v", err)
+		}
+		return
+	}
+
 	// Generate example config if requested
 	if *generateConfigFlag != "" {
 		config, err := cmd.GenerateExampleConfig(*generateConfigFlag)
@@ -132,18 +241,41 @@ func main() {
 		return
 	}
 
+	// Preview the decode ladder for a payload
+	if *ladderFlag != "" {
+		printDecodeLadder(*ladderFlag)
+		return
+	}
+
+	// Replay a stored request instead of running a test
+	if *replayFlag != "" {
+		if err := replayResult(*replayFromFlag, *replayFlag, *replayTechniqueFlag, *urlFlag); err != nil {
+			log.Fatalf("Replay failed: %v", err)
+		}
+		return
+	}
+
+	if *replayRecipeFlag != "" {
+		if err := report.ReplayRecipe(*replayRecipeFlag, *urlFlag, os.Stdout); err != nil {
+			log.Fatalf("Replay failed: %v", err)
+		}
+		return
+	}
+
 	var config *types.Config
 	var configErr error
 
 	// Check if simple CLI flags are used
 	if hasSimpleCLIFlags(*attackTypeFlag, *payloadFlag, *payloadFileFlag, *urlFlag, *urlFileFlag) {
 		config, configErr = createConfigFromCLIFlags(*attackTypeFlag, *payloadFlag, *payloadFileFlag,
-			*urlFlag, *urlFileFlag, *outputFlag, *levelFlag, *encodingFlag, *reportFlag, *threadsFlag, *formatFlag, *progressFlag,
-			*enableAIFlag, *aiProviderFlag, *aiModelFlag, *aiConfigFlag, *aiCountFlag, *aiCreativityFlag, *aiContextFlag)
+			*urlFlag, *urlFileFlag, *outputFlag, *levelFlag, *encodingFlag, *reportFlag, threads, *formatFlag, *progressFlag,
+			*enableAIFlag, *aiProviderFlag, *aiModelFlag, *aiConfigFlag, *aiCountFlag, *aiCreativityFlag, *aiContextFlag, *aiRequiredFlag)
 		if configErr != nil {
 			log.Fatalf("Invalid CLI arguments: %v", configErr)
 		}
-		fmt.Print(version.GetStartupBanner())
+		if !*stdoutFlag {
+			fmt.Print(version.GetStartupBanner())
+		}
 		// Reduce verbosity by default; only info if user opts in via env
 		logging.Infoln("🚀 Starting ObfusKit with command line arguments...")
 
@@ -166,38 +298,269 @@ func main() {
 		if configErr != nil {
 			log.Fatalf("Invalid config: %v", configErr)
 		}
-		fmt.Println("Configuration loaded successfully!")
+		if !*stdoutFlag {
+			fmt.Println("Configuration loaded successfully!")
+		}
 	} else {
 		fmt.Println("Initializing interactive configuration...")
 		finalSelection := cmd.GetFinalSelection()
 		fmt.Println("Interactive configuration completed successfully!")
 		config = cmd.ConvertSelectionToConfig(finalSelection)
+		if finalSelection.SaveConfig {
+			if err := cmd.WriteConfig(config, cmd.DefaultInteractiveConfigPath); err != nil {
+				log.Fatalf("Failed to save config: %v", err)
+			}
+			fmt.Printf("✅ Configuration saved to %s; run it with -config %s\n", cmd.DefaultInteractiveConfigPath, cmd.DefaultInteractiveConfigPath)
+			return
+		}
 	}
 
-	evasionLevel := types.EvasionLevelMedium
+	// These flags apply to both CLI and config file runs
+	if *paramFlag != "" {
+		config.Target.ParamNames = request.ParseParamNames(*paramFlag)
+	}
+	config.AdaptiveConcurrency = *adaptiveFlag
+	if *hostFlag != "" {
+		config.Target.Host = *hostFlag
+	}
+	if *userAgentFlag != "" {
+		config.Target.UserAgent = *userAgentFlag
+	}
+	if *uaRotateFlag {
+		config.Target.RotateUserAgents = true
+		config.Target.UserAgentSeed = *uaSeedFlag
+	}
+	if *bodyFileFlag != "" {
+		config.Target.BodyFile = *bodyFileFlag
+	}
+	if *contentTypeFlag != "" {
+		config.Target.ContentType = *contentTypeFlag
+	}
+	if *cookieJarFlag {
+		config.Target.CookieJar = true
+	}
+	if *cacheBustFlag {
+		config.Target.CacheBust = true
+	}
+	if *followRedirectsFlag {
+		config.Target.FollowRedirects = true
+	}
+	if *maxRedirectsFlag > 0 {
+		config.Target.MaxRedirects = *maxRedirectsFlag
+	}
+	if *resetIsBlockFlag {
+		config.Target.ResetIsBlock = true
+	}
+	if *blockSignaturesFlag != "" {
+		config.Target.BlockSignaturesFile = *blockSignaturesFlag
+	}
+	if *timingFlag {
+		config.Target.Timing = true
+	}
+	if *readBufferFlag > 0 {
+		config.Target.ReadBufferSize = *readBufferFlag
+	}
+	if *maxResponseFlag > 0 {
+		config.Target.MaxResponseSize = *maxResponseFlag
+	}
+	if *diversifyFlag || *diversifyPaddingFlag {
+		config.Target.Diversify = true
+	}
+	if *diversifyPaddingFlag {
+		config.Target.DiversifyPadding = true
+	}
+	if *sourceIPsFlag != "" {
+		config.Target.SourceIPs = request.ParseParamNames(*sourceIPsFlag)
+	}
+	if *scopeFlag != "" {
+		config.Target.Scope = strings.Split(*scopeFlag, ",")
+	}
+	if *noConnectFlag {
+		config.Target.DisableConnect = true
+	}
+	if *noChunkedFlag {
+		config.Target.DisableChunked = true
+	}
+	if *noMultiCLFlag {
+		config.Target.DisableMultipleContentLength = true
+	}
+	if *headerNamesFlag {
+		config.Target.HeaderNameInjection = true
+	}
+	if *pipelineFlag {
+		config.Target.Pipeline = true
+	}
+	if *splitParamsFlag != "" {
+		config.Target.SplitParams = request.ParseParamNames(*splitParamsFlag)
+	}
+	if *splitAtFlag != "" {
+		boundaries, err := request.ParseSplitBoundaries(*splitAtFlag)
+		if err != nil {
+			log.Fatalf("Invalid -split-at: %v", err)
+		}
+		config.Target.SplitAt = boundaries
+	}
+	if *fileWordlistFlag != "" {
+		config.Payload.SensitiveFiles = *fileWordlistFlag
+	}
+	if *payloadsDirFlag != "" {
+		config.Payload.Dir = *payloadsDirFlag
+	}
+	if *maxDepthFlag > 0 {
+		config.MaxTraversalDepth = *maxDepthFlag
+	}
+	if *traversalTargetFlag != "" {
+		config.TraversalTarget = *traversalTargetFlag
+	}
+	if len(tagFlag) > 0 {
+		config.Tags = tagFlag
+	}
+	for name, level := range encodingLevelFlag {
+		if config.EncodingLevels == nil {
+			config.EncodingLevels = make(map[string]string)
+		}
+		// The flag replaces the config file's level for the encoding,
+		// whatever alias the file used
+		if encoding, ok := types.ParsePayloadEncoding(name); ok {
+			for existing := range config.EncodingLevels {
+				if other, ok := types.ParsePayloadEncoding(existing); ok && other == encoding {
+					delete(config.EncodingLevels, existing)
+				}
+			}
+		}
+		config.EncodingLevels[name] = level
+	}
+	if *seedFlag != 0 {
+		config.Seed = *seedFlag
+	}
+	// A run without a seed draws its own, which its reports record so the
+	// run can be repeated with -seed
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	if *maxRequestsFlag > 0 {
+		config.MaxRequests = *maxRequestsFlag
+	}
+	if *perHostConnsFlag > 0 {
+		config.PerHostConns = *perHostConnsFlag
+	}
+	if *stopOnBypassFlag {
+		config.StopOnFirstBypass = true
+	}
+	if *shuffleFlag {
+		config.Shuffle = true
+	}
+	if *escalateFlag {
+		config.AdaptiveEscalate = true
+	}
+	if *maxEscalationsFlag > 0 {
+		config.MaxEscalations = *maxEscalationsFlag
+	}
+	if *probeNormalizationFlag {
+		config.ProbeNormalization = true
+	}
+	if *diffBaselineFlag != "" {
+		config.DiffBaseline = *diffBaselineFlag
+	}
+	if *viewURLFlag != "" {
+		config.ViewURL = *viewURLFlag
+	}
+	if *sinkFlag != "" {
+		config.Sink = *sinkFlag
+	}
+	if *distinctFlag > 0 {
+		config.DistinctTechniques = *distinctFlag
+	}
+	if *onlyTechniquesFlag != "" {
+		config.OnlyTechniques = request.ParseParamNames(*onlyTechniquesFlag)
+	}
+	if *excludeTechniquesFlag != "" {
+		config.ExcludeTechniques = request.ParseParamNames(*excludeTechniquesFlag)
+	}
+	if *requireTechniquesFlag != "" {
+		config.RequireTechniques = request.ParseParamNames(*requireTechniquesFlag)
+	}
+	if *exhaustiveFlag {
+		config.Exhaustive = true
+	}
+	if *stdoutFlag {
+		config.Stdout = true
+	}
+	if *savePayloadsFlag {
+		config.SavePayloads = true
+	}
+	if *nucleiPayloadsFlag != "" {
+		if config.Action != types.ActionSendToURL {
+			log.Fatalf("-nuclei-payloads sends payloads as they are; it needs -url or -url-file")
+		}
+		if config.Payload.Source == types.PayloadSourceEnterManually || config.Payload.Source == types.PayloadSourceFromFile {
+			log.Fatalf("-nuclei-payloads cannot be combined with -payload or -payload-file")
+		}
+		config.Payload.Source = types.PayloadSourceNuclei
+		config.Payload.FilePath = *nucleiPayloadsFlag
+	}
+	if *strictVariantsFlag {
+		config.StrictVariants = true
+	}
+
+	// Annotate the variants instead of running the action
+	if *explainFlag {
+		if err := printExplainedVariants(config); err != nil {
+			log.Fatalf("Explain failed: %v", err)
+		}
+		return
+	}
+
+	if config.Stdout && config.Action == types.ActionSendToURL {
+		log.Fatalf("-stdout only generates payloads; it cannot be used with -url")
+	}
+	if *compareEncodingsFlag {
+		if config.Action != types.ActionSendToURL || len(config.Payload.Custom) != 1 {
+			log.Fatalf("-compare-encodings needs a single -payload and a -url")
+		}
+		if config.StopOnFirstBypass {
+			log.Fatalf("-compare-encodings and -stop-on-first-bypass cannot be used together")
+		}
+	}
 
-	// Validate configuration
+	evasionLevel := config.EvasionLevel
+	if evasionLevel == "" {
+		evasionLevel = types.EvasionLevelMedium
+	}
+
+	// Validate configuration; -stdout keeps stdout for the variants
 	validationResult := validation.ValidateConfig(config)
 	if validationResult.HasWarnings() || validationResult.HasErrors() {
-		fmt.Println("\n==============================")
-		fmt.Println("CONFIGURATION VALIDATION")
-		fmt.Println("==============================")
-		fmt.Print(validation.FormatValidationReport(validationResult))
-		fmt.Println()
+		report := os.Stdout
+		if config.Stdout {
+			report = os.Stderr
+		}
+		fmt.Fprintln(report, "\n==============================")
+		fmt.Fprintln(report, "CONFIGURATION VALIDATION")
+		fmt.Fprintln(report, "==============================")
+		fmt.Fprint(report, validation.FormatValidationReport(validationResult))
+		fmt.Fprintln(report)
 
 		if validationResult.HasErrors() {
 			os.Exit(1)
 		}
 	}
 
+	// Print only the variants, for piping into other tools
+	if config.Stdout {
+		results := &model.TestResults{Config: config}
+		if err := payload.GenerateToWriter(os.Stdout, results, evasionLevel, threads); err != nil {
+			log.Fatalf("Error generating payloads: %v", err)
+		}
+		return
+	}
+
 	// Initialize performance monitor if requested
 	var perfMonitor *performance.Monitor
 	if *showPerfStatsFlag || *benchmarkFlag {
 		perfMonitor = performance.NewMonitor()
 		perfMonitor.Start()
-		if *threadsFlag > 0 {
-			perfMonitor.SetThreads(*threadsFlag)
-		}
+		perfMonitor.SetThreads(threads)
 	}
 
 	fmt.Println("\n==============================")
@@ -215,19 +578,27 @@ func main() {
 	}
 	fmt.Println("==============================")
 
+	if *showNormalizedFlag && config.Action == types.ActionSendToURL {
+		if err := payload.ShowNormalizedTargets(os.Stdout, config); err != nil {
+			log.Fatalf("Invalid target: %v", err)
+		}
+	}
+
 	// Prepare results
 	results := &model.TestResults{
 		Config: config,
 	}
 
 	var err error
-	switch config.Action {
-	case types.ActionGeneratePayloads:
-		err = payload.HandleGeneratePayloads(results, evasionLevel, *progressFlag, *threadsFlag)
-	case types.ActionSendToURL:
-		err = payload.HandleSendToURL(results, evasionLevel, *progressFlag, *threadsFlag)
-	case types.ActionUseExistingPayloads:
-		err = payload.HandleExistingPayloads(results, evasionLevel, *progressFlag, *threadsFlag)
+	switch {
+	case *compareEncodingsFlag:
+		err = payload.HandleCompareEncodings(results, evasionLevel, *progressFlag, threads)
+	case config.Action == types.ActionGeneratePayloads:
+		err = payload.HandleGeneratePayloads(results, evasionLevel, *progressFlag, threads)
+	case config.Action == types.ActionSendToURL:
+		err = payload.HandleSendToURL(results, evasionLevel, *progressFlag, threads)
+	case config.Action == types.ActionUseExistingPayloads:
+		err = payload.HandleExistingPayloads(results, evasionLevel, *progressFlag, threads)
 	default:
 		err = fmt.Errorf("unknown action: %s", config.Action)
 	}
@@ -236,6 +607,37 @@ func main() {
 		log.Fatalf("Error processing action: %v", err)
 	}
 
+	if *compareEncodingsFlag {
+		fmt.Println("\n==============================")
+		fmt.Println("ENCODING COMPARISON")
+		fmt.Println("==============================")
+		if err := report.WriteEncodingComparison(os.Stdout, results); err != nil {
+			log.Fatalf("Error writing encoding comparison: %v", err)
+		}
+	}
+
+	if *encodingReportFlag != "" {
+		if err := report.GenerateEncodingReport(results, *encodingReportFlag); err != nil {
+			log.Fatalf("Error generating encoding report: %v", err)
+		}
+		fmt.Printf("📝 Encoding report written to %s\n", *encodingReportFlag)
+	}
+
+	if *recipesFlag != "" {
+		written, err := report.ExportBypassRecipes(results, *recipesFlag)
+		if err != nil {
+			log.Fatalf("Error writing recipes: %v", err)
+		}
+		fmt.Printf("📝 Wrote %d bypass recipe(s) to %s\n", written, *recipesFlag)
+	}
+
+	// Narrow the reported requests without changing the summary baseline
+	if *onlyBypassedFlag {
+		util.FilterByOutcome(results, util.OutcomeBypassed)
+	} else if *onlyBlockedFlag {
+		util.FilterByOutcome(results, util.OutcomeBlocked)
+	}
+
 	// Handle different output formats
 	if *formatFlag == "json" {
 		outputJSON(results)
@@ -254,6 +656,19 @@ func main() {
 		}
 	}
 
+	if *dbFlag != "" {
+		writeResultsDB(*dbFlag, results)
+	}
+
+	if *webhookFlag != "" || *slackWebhookFlag != "" {
+		sendNotifications(*webhookFlag, *slackWebhookFlag, results)
+	}
+
+	// Summarize the GenAI requests made while generating
+	if analytics, ok := config.AIAnalytics.(*genai.AnalyticsCollector); ok && analytics.Summary().Requests > 0 {
+		fmt.Print(analytics.FormatSummary())
+	}
+
 	// Stop performance monitoring and show statistics
 	if perfMonitor != nil {
 		perfMonitor.Stop()
@@ -265,6 +680,174 @@ func main() {
 	fmt.Println("\n✅ WAF testing completed successfully!")
 }
 
+// printDecodeLadder shows a payload after 0, 1, 2... URL-decode passes
+func printDecodeLadder(payload string) {
+	ladder := encoders.DecodeLadder(payload, encoders.DefaultLadderPasses)
+	fmt.Println("Decode ladder:")
+	for pass, value := range ladder {
+		fmt.Printf("  pass %d: %s\n", pass, value)
+	}
+	if final := ladder[len(ladder)-1]; len(encoders.DecodeLadder(final, 1)) == 1 {
+		fmt.Printf("Stable after %d pass(es)\n", len(ladder)-1)
+	} else {
+		fmt.Printf("Still changing after %d passes\n", encoders.DefaultLadderPasses)
+	}
+}
+
+// printExplainedVariants lists every variant generated for the configured
+// payloads, grouped by encoding, with the technique behind each one
+func printExplainedVariants(config *types.Config) error {
+	payloads := config.Payload.Custom
+	if config.Payload.FilePath != "" {
+		var err error
+		if payloads, err = util.LoadPayloadsFromFile(config.Payload.FilePath); err != nil {
+			return fmt.Errorf("failed to load payloads from file: %w", err)
+		}
+	}
+	if len(payloads) == 0 {
+		return fmt.Errorf("-explain needs -payload or -payload-file")
+	}
+
+	level := config.EvasionLevel
+	if level == "" {
+		level = types.EvasionLevelMedium
+	}
+	ctx := payload.GenerationContext(config, 0)
+
+	for _, p := range payloads {
+		attackType := config.AttackType
+		if attackType == "" {
+			attackType = util.DetectAttackType(p)
+		}
+		encodings, _ := cmd.GetEvasionsForLevel(attackType, level)
+		if config.Payload.Encoding != "" && config.Payload.Encoding != types.PayloadEncodingAuto {
+			encodings = []types.PayloadEncoding{config.Payload.Encoding}
+		}
+
+		fmt.Printf("Payload: %s (%s, %s)\n", p, attackType, level)
+		for _, encoding := range encodings {
+			variants, err := cmd.ExplainEvasion(ctx, p, encoding, level)
+			if err != nil {
+				return err
+			}
+			if config.StrictVariants {
+				variants = payload.FilterIntact(attackType, p, variants)
+			}
+			fmt.Printf("\n%s (%d variants)\n", encoding, len(variants))
+			for _, v := range variants {
+				fmt.Printf("  [%s] %s\n", v.Technique, v.Value)
+				fmt.Printf("      %s\n", v.Explanation)
+			}
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// runFuzz implements `obfuskit fuzz -url 'http://host/p?id=FUZZ' -attack sqli`:
+// every encoded variant of the attack's payloads is sent in place of the
+// FUZZ marker and the bypasses are printed as a table
+func runFuzz(args []string) error {
+	fs := flag.NewFlagSet("fuzz", flag.ExitOnError)
+	urlFlag := fs.String("url", "", "Target URL with a FUZZ marker where payloads go, e.g. 'http://host/p?id=FUZZ'")
+	attackFlag := fs.String("attack", "", "Attack type whose payloads and encodings to use (xss, sqli, path, ...)")
+	levelFlag := fs.String("level", "medium", "Evasion level: basic, medium, advanced")
+	threadsFlag := fs.Int("threads", 1, "Number of concurrent requests")
+	limitFlag := fs.Int("limit", 0, "Use at most this many base payloads (0 = all)")
+	payloadsDirFlag := fs.String("payloads-dir", "", "Directory with base payload files named <attack>.txt")
+	fs.Parse(args)
+
+	if *urlFlag == "" || *attackFlag == "" {
+		return fmt.Errorf("usage: obfuskit fuzz -url 'http://host/path?id=%s' -attack <type>", request.FuzzMarker)
+	}
+	attackType := parseAttackType(*attackFlag)
+	if attackType == "" {
+		return fmt.Errorf("invalid attack type: %s", *attackFlag)
+	}
+	level, ok := types.ParseEvasionLevel(*levelFlag)
+	if !ok {
+		return fmt.Errorf("invalid evasion level: %s (use basic, medium, or advanced)", *levelFlag)
+	}
+
+	results, err := payload.Fuzz(payload.FuzzOptions{
+		URL:         *urlFlag,
+		AttackType:  attackType,
+		Level:       level,
+		PayloadsDir: *payloadsDirFlag,
+		Limit:       *limitFlag,
+		Threads:     *threadsFlag,
+	})
+	if err != nil {
+		return err
+	}
+	return report.WriteFuzzFindings(os.Stdout, results)
+}
+
+// replayResult re-sends the requests stored for variantID in a saved JSON
+// report and prints each full response. targetURL, when set, replaces the
+// recorded scheme and host.
+func replayResult(reportPath, variantID, technique, targetURL string) error {
+	saved, err := report.LoadJSONReport(reportPath)
+	if err != nil {
+		return err
+	}
+
+	matches := saved.FindRequestResults(variantID, technique)
+	if len(matches) == 0 {
+		return fmt.Errorf("no stored request for variant %s in %s", variantID, reportPath)
+	}
+
+	for _, match := range matches {
+		fmt.Printf("\n🔁 Replaying %s %s (technique: %s, part: %s, originally %d)\n",
+			match.Method, match.URL, match.Technique, match.Part, match.StatusCode)
+		fmt.Println(strings.Repeat("-", 60))
+		if err := request.Replay(match.Recorded(), targetURL, os.Stdout); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// writeResultsDB appends this run to the SQLite results database
+func writeResultsDB(path string, results *model.TestResults) {
+	db, err := resultsdb.Open(path)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to open results database: %v\n", err)
+		return
+	}
+	defer db.Close()
+
+	runID, err := resultsdb.WriteRun(db, results)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to write results database: %v\n", err)
+		return
+	}
+	fmt.Printf("🗄️  Results recorded in %s (run %d)\n", path, runID)
+}
+
+// sendNotifications delivers the run summary to the configured webhooks.
+// Delivery failures are reported but never fail the run.
+func sendNotifications(webhookURL, slackURL string, results *model.TestResults) {
+	summary := notify.BuildSummary(results)
+
+	if webhookURL != "" {
+		if err := notify.SendWebhook(webhookURL, summary, notify.DefaultTimeout); err != nil {
+			fmt.Printf("⚠️  Webhook notification failed: %v\n", err)
+		} else {
+			fmt.Println("📣 Run summary sent to webhook")
+		}
+	}
+
+	if slackURL != "" {
+		if err := notify.SendSlack(slackURL, summary, notify.DefaultTimeout); err != nil {
+			fmt.Printf("⚠️  Slack notification failed: %v\n", err)
+		} else {
+			fmt.Println("📣 Run summary sent to Slack")
+		}
+	}
+}
+
 // hasSimpleCLIFlags checks if any of the simple CLI flags are provided
 func hasSimpleCLIFlags(attackType, payload, payloadFile, url, urlFile string) bool {
 	return attackType != "" || payload != "" || payloadFile != "" || url != "" || urlFile != ""
@@ -272,8 +855,15 @@ func hasSimpleCLIFlags(attackType, payload, payloadFile, url, urlFile string) bo
 
 // createConfigFromCLIFlags creates a configuration from CLI flags
 func createConfigFromCLIFlags(attackType, payload, payloadFile, url, urlFile, output, level, encoding, report string, threads int, format string, progress bool,
-	enableAI bool, aiProvider, aiModel, aiConfig string, aiCount int, aiCreativity float64, aiContext string) (*types.Config, error) {
+	enableAI bool, aiProvider, aiModel, aiConfig string, aiCount int, aiCreativity float64, aiContext string, aiRequired bool) (*types.Config, error) {
 	config := &types.Config{}
+	// Only this run's secrets are registered below
+	redact.Reset()
+
+	// Thread count, already resolved from -threads (see types.ParseThreads)
+	if threads < 1 {
+		return nil, fmt.Errorf("thread count must be at least 1, got %d (use -threads auto for one per CPU)", threads)
+	}
 
 	// Validate attack type
 	if attackType == "" {
@@ -324,8 +914,8 @@ func createConfigFromCLIFlags(attackType, payload, payloadFile, url, urlFile, ou
 				URL:    url,
 			}
 		} else {
-			// For URL files, we'll use the first URL as primary target
-			// and handle multiple URLs in the execution logic
+			// Every variant is sent to each URL in the file; the first
+			// doubles as the primary target
 			urls, err := readURLsFromFile(urlFile)
 			if err != nil {
 				return nil, fmt.Errorf("error reading URL file: %w", err)
@@ -333,7 +923,8 @@ func createConfigFromCLIFlags(attackType, payload, payloadFile, url, urlFile, ou
 			config.Target = types.Target{
 				Method: types.TargetMethodURL,
 				URL:    urls[0], // Primary URL
-				File:   urlFile, // Store file path for batch processing
+				File:   urlFile,
+				URLs:   urls,
 			}
 		}
 	} else {
@@ -379,49 +970,19 @@ func createConfigFromCLIFlags(attackType, payload, payloadFile, url, urlFile, ou
 	// Set encoding if specified
 	if encoding != "" {
 		config.Payload.Method = types.PayloadMethodEncodings
-		switch strings.ToLower(encoding) {
-		case "url":
-			config.Payload.Encoding = types.PayloadEncodingURL
-		case "doubleurl", "double-url":
-			config.Payload.Encoding = types.PayloadEncodingDoubleURL
-		case "html":
-			config.Payload.Encoding = types.PayloadEncodingHTML
-		case "unicode":
-			config.Payload.Encoding = types.PayloadEncodingUnicode
-		case "base64", "b64":
-			config.Payload.Encoding = types.PayloadEncodingBase64
-		case "hex":
-			config.Payload.Encoding = types.PayloadEncodingHex
-		case "octal":
-			config.Payload.Encoding = types.PayloadEncodingOctal
-		case "bestfit", "best-fit":
-			config.Payload.Encoding = types.PayloadEncodingBestFit
-		case "mixedcase", "mixed-case":
-			config.Payload.Encoding = types.PayloadEncodingMixedCase
-		case "utf8", "utf-8":
-			config.Payload.Encoding = types.PayloadEncodingUTF8
-		case "unixcmd", "unix-cmd":
-			config.Payload.Encoding = types.PayloadEncodingUnixCmd
-		case "windowscmd", "windows-cmd":
-			config.Payload.Encoding = types.PayloadEncodingWindowsCmd
-		case "pathtraversal", "path-traversal":
-			config.Payload.Encoding = types.PayloadEncodingPathTraversal
-		default:
-			return nil, fmt.Errorf("unsupported encoding '%s'. Supported encodings: url, html, unicode, base64, hex, octal, bestfit, mixedcase, utf8, unixcmd, windowscmd, pathtraversal", encoding)
+		parsed, ok := types.ParsePayloadEncoding(encoding)
+		if !ok {
+			return nil, fmt.Errorf("unsupported encoding '%s'. Supported encodings: url, html, unicode, base64, hex, octal, bestfit, mixedcase, utf8, utf7, interleaved, unixcmd, windowscmd, pathtraversal", encoding)
 		}
+		config.Payload.Encoding = parsed
 	}
 
 	// Set evasion level
-	switch strings.ToLower(level) {
-	case "basic":
-		config.EvasionLevel = types.EvasionLevelBasic
-	case "medium":
-		config.EvasionLevel = types.EvasionLevelMedium
-	case "advanced":
-		config.EvasionLevel = types.EvasionLevelAdvanced
-	default:
+	evasionLevel, ok := types.ParseEvasionLevel(level)
+	if !ok {
 		return nil, fmt.Errorf("unsupported evasion level '%s'. Supported levels: basic, medium, advanced", level)
 	}
+	config.EvasionLevel = evasionLevel
 
 	// Set report type
 	switch strings.ToLower(report) {
@@ -449,6 +1010,7 @@ func createConfigFromCLIFlags(attackType, payload, payloadFile, url, urlFile, ou
 	if enableAI {
 		config.EnableAI = true
 		config.AIContext = aiContext
+		config.AIRequired = aiRequired
 
 		// Load AI configuration
 		aiConfigObj, err := genai.LoadConfig(aiConfig)
@@ -456,20 +1018,22 @@ func createConfigFromCLIFlags(attackType, payload, payloadFile, url, urlFile, ou
 			return nil, fmt.Errorf("failed to load AI config: %v", err)
 		}
 
-		// Override with CLI flags if provided
-		if aiProvider != "" {
-			aiConfigObj.Provider = aiProvider
-		}
+		// Override with CLI flags if provided. The provider-specific
+		// environment variables are read for whichever provider wins.
+		aiConfigObj = genai.UseProvider(aiConfigObj, aiProvider)
 		if aiModel != "" {
 			aiConfigObj.Model = aiModel
 		}
 
-		// Validate AI configuration
-		if err := genai.ValidateConfig(aiConfigObj); err != nil {
+		// Validate AI configuration. A provider missing its key or endpoint
+		// only aborts with -ai-required; otherwise generation goes on
+		// without AI.
+		if err := genai.ValidateConfig(aiConfigObj); err != nil && (aiRequired || !errors.Is(err, genai.ErrNotConfigured)) {
 			return nil, fmt.Errorf("invalid AI configuration: %v", err)
 		}
 
 		config.AIConfig = aiConfigObj
+		redact.Register(aiConfigObj.APIKey)
 
 		fmt.Printf("🤖 AI Generation: %s (%s) | Count: %d | Creativity: %.1f\n",
 			aiConfigObj.Provider, aiConfigObj.Model, aiCount, aiCreativity)
@@ -510,6 +1074,49 @@ func parseAttackType(attackType string) types.AttackType {
 	}
 }
 
+// encodingLevelsValue collects the repeatable -encoding-level name=level
+// flag. Names are keyed by the encoding they resolve to, so aliases such as
+// url and URLVariants override each other in command-line order; unknown
+// names are kept as written for validation to report.
+type encodingLevelsValue map[string]string
+
+func (f encodingLevelsValue) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, value := range f {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f encodingLevelsValue) Set(value string) error {
+	name, v, ok := strings.Cut(value, "=")
+	name, v = strings.TrimSpace(name), strings.TrimSpace(v)
+	if !ok || name == "" || v == "" {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+	if encoding, ok := types.ParsePayloadEncoding(name); ok {
+		name = string(encoding)
+	}
+	f[name] = v
+	return nil
+}
+
+// listFlag collects a repeatable flag's values
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	if value = strings.TrimSpace(value); value == "" {
+		return fmt.Errorf("empty value")
+	}
+	*f = append(*f, value)
+	return nil
+}
+
 // readURLsFromFile reads URLs from a file (one per line)
 func readURLsFromFile(filename string) ([]string, error) {
 	data, err := os.ReadFile(filename)
@@ -535,76 +1142,9 @@ func readURLsFromFile(filename string) ([]string, error) {
 
 // outputJSON outputs results in JSON format to console
 func outputJSON(results *model.TestResults) {
-	// Create JSON report structure similar to the file-based JSON report
-	jsonOutput := map[string]interface{}{
-		"metadata": map[string]interface{}{
-			"timestamp": time.Now().Format(time.RFC3339),
-			"tool":      "ObfusKit",
-			"version":   "1.0.0",
-		},
-		"config": map[string]interface{}{},
-		"summary": map[string]interface{}{
-			"total_payloads":   results.Summary.TotalPayloads,
-			"total_variants":   results.Summary.TotalVariants,
-			"successful_tests": results.Summary.SuccessfulTests,
-			"failed_tests":     results.Summary.FailedTests,
-			"attack_types":     results.Summary.AttackTypes,
-			"evasion_types":    results.Summary.EvasionTypes,
-		},
-		"payload_results": []map[string]interface{}{},
-		"request_results": []map[string]interface{}{},
-	}
-
-	// Add config information
-	if config, ok := results.Config.(*types.Config); ok {
-		jsonOutput["config"] = map[string]interface{}{
-			"action":        string(config.Action),
-			"attack_type":   string(config.AttackType),
-			"evasion_level": string(config.EvasionLevel),
-			"target_url":    config.Target.URL,
-		}
-	}
-
-	// Add success rate if we have request results
-	if len(results.RequestResults) > 0 {
-		successRate := float64(results.Summary.SuccessfulTests) / float64(len(results.RequestResults)) * 100
-		if summary, ok := jsonOutput["summary"].(map[string]interface{}); ok {
-			summary["success_rate"] = successRate
-		}
-	}
-
-	// Add payload results
-	payloadResults := []map[string]interface{}{}
-	for _, result := range results.PayloadResults {
-		payloadResults = append(payloadResults, map[string]interface{}{
-			"original_payload": result.OriginalPayload,
-			"attack_type":      result.AttackType,
-			"evasion_type":     result.EvasionType,
-			"variants":         result.Variants,
-		})
-	}
-	jsonOutput["payload_results"] = payloadResults
-
-	// Add request results
-	requestResults := []map[string]interface{}{}
-	for _, result := range results.RequestResults {
-		requestResults = append(requestResults, map[string]interface{}{
-			"payload":          result.Payload,
-			"url":              result.Request.URI().String(),
-			"method":           string(result.Request.Header.Method()),
-			"status_code":      result.StatusCode,
-			"blocked":          result.Blocked,
-			"response_time_ms": result.ResponseTime.Milliseconds(),
-			"technique":        result.EvasionTechnique,
-			"part":             result.RequestPart,
-		})
-	}
-	jsonOutput["request_results"] = requestResults
-
-	// Output JSON to console
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
-	encoder.Encode(jsonOutput)
+	encoder.Encode(report.NewJSONOutput(results))
 }
 
 // showHelp displays usage information
@@ -621,21 +1161,92 @@ func showHelp() {
 	fmt.Println("  -version-full               Show detailed version and build information")
 	fmt.Println("  -config <file>              Use configuration file (YAML or JSON)")
 	fmt.Println("  -generate-config <fmt>      Generate example config (yaml or json)")
+	fmt.Println("  -print-schema               Print the JSON Schema of the JSON report and -sink records")
 	fmt.Println("  -server                     Start integration webservice")
+	fmt.Println("  fuzz -url <url> -attack <t> Fuzz the FUZZ marker in -url with the attack's encoded payloads")
 	fmt.Println("")
 	fmt.Println("Simple CLI Flags (can be used without config):")
 	fmt.Println("  -attack <type(s)>           Attack type(s): xss, or multiple: xss,sqli,unixcmdi")
 	fmt.Println("  -payload <string>           Single payload to generate evasions for")
 	fmt.Println("  -payload-file <file>        File containing payloads (one per line)")
+	fmt.Println("  -nuclei-payloads <file>     Send a nuclei template's payloads (or a list) as is, without generation")
 	fmt.Println("  -url <url>                  Target URL to test payloads against")
 	fmt.Println("  -url-file <file>            File containing URLs to test (one per line)")
+	fmt.Println("  -show-normalized            Print the normalized target URL(s) the injectors will request")
 	fmt.Println("  -output <file>              Output file path (default: print to console)")
 	fmt.Println("  -level <level>              Evasion level: basic, medium, advanced (default: medium)")
+	fmt.Println("  -max-depth <num>            Maximum ../ depth for traversal expansion (default: 8)")
+	fmt.Println("  -traversal-target <file>    File traversal techniques aim at, or unix/windows (default: etc/passwd)")
+	fmt.Println("  -seed <num>                 Seed for randomized evasions; same seed, same variants")
+	fmt.Println("  -explain                    Print each variant with the technique behind it and exit")
 	fmt.Println("  -encoding <method>          Specific encoding: url, html, unicode, base64, hex, etc.")
+	fmt.Println("  -encoding-level <name=lvl>  Evasion level for one encoding, overriding -level (repeatable)")
 	fmt.Println("  -report <format>            Report format: pretty, html, pdf, csv, nuclei, json (default: pretty)")
-	fmt.Println("  -threads <num>              Number of concurrent threads (default: 1)")
+	fmt.Println("  -encoding-report <file>     Write a before/after table of every encoding per payload (.html or text)")
+	fmt.Println("  -compare-encodings          Send every encoding of one -payload to -url and rank encodings by bypass rate")
+	fmt.Println("  -threads <num|auto>         Number of concurrent threads; 0 or auto = one per CPU (default: 1)")
 	fmt.Println("  -format <fmt>               Output format: text, json, csv (default: text)")
 	fmt.Println("  -progress                   Show progress bar for long operations")
+	fmt.Println("  -db <file>                  Record run results in a SQLite database (e.g. results.sqlite)")
+	fmt.Println("  -tag <label>                Label the run in reports, the database and notifications (repeatable)")
+	fmt.Println("  -webhook <url>              POST a JSON run summary to this URL on completion")
+	fmt.Println("  -slack-webhook <url>        Post a run summary to a Slack incoming webhook")
+	fmt.Println("  -payloads-dir <dir>         Directory with base payload files, <attack>.txt (default: payloads, else built-in)")
+	fmt.Println("  -fileaccess-wordlist <file> Target files for fileaccess payloads (default: built-in list)")
+	fmt.Println("")
+	fmt.Println("Request Options:")
+	fmt.Println("  -param <names>              Parameter name(s) to inject into, e.g. 'q' or 'id,search' (default: param)")
+	fmt.Println("  -adaptive                   Back off concurrency when the target errors (-threads is the max)")
+	fmt.Println("  -host <value>               Host header to send; the connection still goes to -url")
+	fmt.Println("  -user-agent <ua>            User-Agent to send on every request")
+	fmt.Println("  -ua-rotate                  Rotate through built-in browser User-Agents per request")
+	fmt.Println("  -ua-seed <num>              Seed for the -ua-rotate order (default: 0)")
+	fmt.Println("  -body-file <file>           Body template with a §PAYLOAD§ marker (e.g. a SOAP envelope)")
+	fmt.Println("  -content-type <type>        Content-Type sent with -body-file (default: from the file extension)")
+	fmt.Println("  -cookie-jar                 Carry cookies set by the target on later requests to the same host")
+	fmt.Println("  -cache-bust                 Add no-cache headers and a random query nonce to every request")
+	fmt.Println("  -follow-redirects           Follow redirects and classify the final response instead of the 3xx")
+	fmt.Println("  -max-redirects <n>          Redirect hops to follow with -follow-redirects (default: 5)")
+	fmt.Println("  -reset-is-block             Count connections reset or closed before a response as blocked")
+	fmt.Println("  -block-signatures-file <file> Block page signatures; a matching body is blocked whatever its status")
+	fmt.Println("  -timing                     Record each request's DNS, connect, TLS and TTFB times (fresh connections)")
+	fmt.Println("  -read-buffer-size <bytes>   HTTP read buffer; raise for very long response headers (default: 4096)")
+	fmt.Println("  -max-response-size <bytes>  Cap each response body read; larger bodies are still classified by status")
+	fmt.Println("  -diversify                  Vary benign headers, their order and the User-Agent per request")
+	fmt.Println("  -diversify-padding          With -diversify, also add a random-length padding header")
+	fmt.Println("  -source-ips <ips>           Send requests from these local addresses in turn, e.g. 10.0.0.5,10.0.0.6")
+	fmt.Println("  -scope <hosts>              Host globs and CIDRs requests may go to; out-of-scope targets are refused")
+	fmt.Println("  -no-connect                 Skip the CONNECT protocol injection request")
+	fmt.Println("  -no-chunked                 Skip the chunked Transfer-Encoding protocol injection request")
+	fmt.Println("  -no-multiple-content-length Skip the conflicting Content-Length protocol injection request")
+	fmt.Println("  -header-name-injection      Also put payloads in header names (X-<payload>, X_<payload>, ...)")
+	fmt.Println("  -pipeline                   Also send payloads in HTTP/1.1 pipelined batches behind a benign request")
+	fmt.Println("  -split-params <a,b,...>     Also send each payload split across these params in one request")
+	fmt.Println("  -split-at <n,m,...>         Character offsets to split at for -split-params (default: even split)")
+	fmt.Println("  -max-requests <num>         Stop after sending this many requests in total (default: 0, no cap)")
+	fmt.Println("  -per-host-conns <num>       Maximum simultaneous requests to any one host (default: 0, no cap)")
+	fmt.Println("  -stop-on-first-bypass       Skip a payload's remaining variants once one of them bypasses")
+	fmt.Println("  -shuffle                    Send the variants in a random order (seeded by -seed)")
+	fmt.Println("  -adaptive-escalate          Resend blocked payloads regenerated at the next evasion level")
+	fmt.Println("  -max-escalations <num>      Escalation rounds for -adaptive-escalate (default: until advanced)")
+	fmt.Println("  -probe-normalization        Probe which encodings the target decodes and generate those first")
+	fmt.Println("  -diff-baseline <file>       Flag results whose responses deviate from a clean baseline response")
+	fmt.Println("  -view-url <url>             Flag payloads whose canaries appear on this page after sending (stored)")
+	fmt.Println("  -sink <spec>                Stream each result as NDJSON: stdout, file:<path> or <path>")
+	fmt.Println("  -distinct-techniques <k>    Keep at most k variants per technique for each payload (default: 0, all)")
+	fmt.Println("  -only-techniques <list>     Keep only variants of these techniques or encodings")
+	fmt.Println("  -exclude-techniques <list>  Drop variants of these techniques or encodings")
+	fmt.Println("  -require-techniques <list>  Exit non-zero unless each technique or encoding produced a variant")
+	fmt.Println("  -exhaustive                 Generate every alternative of every technique, deterministically")
+	fmt.Println("  -stdout                     Print only the variants, one per line; no files or status output")
+	fmt.Println("  -save-payloads              Also write the payload files when sending to a URL")
+	fmt.Println("  -strict-variants            Drop variants that no longer decode back to the payload's attack")
+	fmt.Println("  -ladder <payload>           Show the payload after each URL-decode pass (e.g. '%252e%252e')")
+	fmt.Println("  -replay <variant-id>        Re-send the stored request(s) for a variant and print the response")
+	fmt.Println("  -replay-from <file>         Saved JSON report to replay from (default: waf_test_report.json)")
+	fmt.Println("  -replay-technique <name>    Only replay the request sent with this technique")
+	fmt.Println("  -recipes <dir>              Write a replayable recipe file for every bypass to this directory")
+	fmt.Println("  -replay-recipe <file>       Re-send the request described by a recipe file")
 	fmt.Println("")
 	fmt.Println("Advanced Filtering Options:")
 	fmt.Println("  -limit <num>                Limit number of payloads to generate (0 = no limit)")
@@ -645,6 +1256,8 @@ func showHelp() {
 	fmt.Println("  -filter-status <codes>      Filter by status codes (e.g., '200,404')")
 	fmt.Println("  -exclude-encodings <list>   Exclude encodings (e.g., 'base64,hex')")
 	fmt.Println("  -only-successful            Only show payloads that bypassed WAF")
+	fmt.Println("  -only-bypassed              Only report bypassed requests (summary still counts all)")
+	fmt.Println("  -only-blocked               Only report blocked requests (summary still counts all)")
 	fmt.Println("")
 	fmt.Println("WAF Intelligence Options:")
 	fmt.Println("  -fingerprint                Enable WAF fingerprinting and adaptive evasion")
@@ -656,12 +1269,13 @@ func showHelp() {
 	fmt.Println("")
 	fmt.Println("🤖 AI-Powered Generation:")
 	fmt.Println("  -ai                         Enable AI-powered payload generation")
-	fmt.Println("  -ai-provider <provider>     AI provider (openai, anthropic, local, huggingface)")
+	fmt.Println("  -ai-provider <provider>     AI provider (openai, azure, anthropic, local, huggingface; default: from -ai-config or environment)")
 	fmt.Println("  -ai-model <model>           Specific AI model to use")
 	fmt.Println("  -ai-config <file>           Path to AI configuration file")
 	fmt.Println("  -ai-count <number>          Number of AI-generated payloads (default: 10)")
 	fmt.Println("  -ai-creativity <0.0-1.0>    AI creativity level (default: 0.7)")
 	fmt.Println("  -ai-context <text>          Additional context for AI generation")
+	fmt.Println("  -ai-required                Abort when the AI provider is not configured instead of generating without AI")
 	fmt.Println("")
 	fmt.Println("Features:")
 	fmt.Println("  • Interactive menu-driven interface (when no flags provided)")
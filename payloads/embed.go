@@ -0,0 +1,10 @@
+// Package payloads embeds the default base payload files so the binary works
+// without a payloads/ directory next to it.
+package payloads
+
+import "embed"
+
+// Files holds the default <attack type>.txt payload files
+//
+//go:embed *.txt
+var Files embed.FS
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"obfuskit/request"
+)
+
+// BlockShare is one bucket of the blocked-reason distribution: the blocked
+// results sharing a status code and block reason (see request.TestResult.BlockReason)
+type BlockShare struct {
+	StatusCode int    `json:"status_code"`
+	Reason     string `json:"reason,omitempty"`
+	Count      int    `json:"count"`
+	// Percent is the bucket's share of all blocked results
+	Percent float64 `json:"percent"`
+}
+
+// String describes the share as in "80% 403 ModSecurity"
+func (s BlockShare) String() string {
+	parts := []string{fmt.Sprintf("%.0f%%", s.Percent)}
+	if s.StatusCode != 0 {
+		parts = append(parts, fmt.Sprint(s.StatusCode))
+	}
+	if s.Reason != "" {
+		parts = append(parts, s.Reason)
+	}
+	return strings.Join(parts, " ")
+}
+
+// BlockDistribution breaks the blocked results down by status code and block
+// reason, largest share first, to show why requests were blocked
+func BlockDistribution(results []request.TestResult) []BlockShare {
+	type bucket struct {
+		statusCode int
+		reason     string
+	}
+	var shares []BlockShare
+	index := make(map[bucket]int)
+	blocked := 0
+	for _, result := range results {
+		if !result.Blocked || result.Challenge {
+			continue
+		}
+		blocked++
+		key := bucket{result.StatusCode, result.BlockReason}
+		i, ok := index[key]
+		if !ok {
+			i = len(shares)
+			index[key] = i
+			shares = append(shares, BlockShare{StatusCode: result.StatusCode, Reason: result.BlockReason})
+		}
+		shares[i].Count++
+	}
+	for i := range shares {
+		shares[i].Percent = float64(shares[i].Count) / float64(blocked) * 100
+	}
+	sort.SliceStable(shares, func(i, j int) bool {
+		return shares[i].Count > shares[j].Count
+	})
+	return shares
+}
+
+// FormatBlockDistribution joins shares into one summary line, e.g.
+// "80% 403 ModSecurity, 20% 406"
+func FormatBlockDistribution(shares []BlockShare) string {
+	parts := make([]string, len(shares))
+	for i, share := range shares {
+		parts[i] = share.String()
+	}
+	return strings.Join(parts, ", ")
+}
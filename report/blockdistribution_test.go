@@ -0,0 +1,39 @@
+package report
+
+import (
+	"testing"
+
+	"obfuskit/request"
+)
+
+func TestBlockDistributionPercentagesByStatusAndReason(t *testing.T) {
+	var results []request.TestResult
+	for i := 0; i < 8; i++ {
+		results = append(results, request.TestResult{StatusCode: 403, Blocked: true, BlockReason: "ModSecurity", RuleID: "942100"})
+	}
+	for i := 0; i < 2; i++ {
+		results = append(results, request.TestResult{StatusCode: 406, Blocked: true})
+	}
+	// Neither bypasses nor challenges count toward the distribution
+	results = append(results,
+		request.TestResult{StatusCode: 200},
+		request.TestResult{StatusCode: 403, Blocked: true, Challenge: true},
+	)
+
+	shares := BlockDistribution(results)
+	if len(shares) != 2 {
+		t.Fatalf("got %d shares, want 2: %+v", len(shares), shares)
+	}
+	want := []BlockShare{
+		{StatusCode: 403, Reason: "ModSecurity", Count: 8, Percent: 80},
+		{StatusCode: 406, Count: 2, Percent: 20},
+	}
+	for i := range want {
+		if shares[i] != want[i] {
+			t.Errorf("share %d = %+v, want %+v", i, shares[i], want[i])
+		}
+	}
+	if got := FormatBlockDistribution(shares); got != "80% 403 ModSecurity, 20% 406" {
+		t.Errorf("FormatBlockDistribution() = %q", got)
+	}
+}
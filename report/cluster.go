@@ -0,0 +1,149 @@
+package report
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"sort"
+	"strings"
+
+	"obfuskit/request"
+)
+
+const (
+	// MaxClusterRepresentatives is how many example results each cluster keeps
+	MaxClusterRepresentatives = 3
+	// clusterDistance is the largest SimHash Hamming distance treated as the same page
+	clusterDistance = 6
+)
+
+// Cluster groups results whose responses share a status code and a
+// near-identical body, such as a WAF block page that only differs by its
+// request ID or timestamp
+type Cluster struct {
+	StatusCode      int                  `json:"status_code"`
+	Blocked         bool                 `json:"blocked"`
+	Count           int                  `json:"count"`
+	Fingerprint     uint64               `json:"-"`
+	Representatives []request.TestResult `json:"-"`
+	// Examples are the payloads of Representatives
+	Examples []string `json:"examples"`
+}
+
+// String describes the cluster as in "5 blocked requests (status 403, same response)"
+func (c Cluster) String() string {
+	label := "allowed"
+	if c.Blocked {
+		label = "blocked"
+	}
+	if c.Count > 1 {
+		return fmt.Sprintf("%d %s requests (status %d, same response)", c.Count, label, c.StatusCode)
+	}
+	return fmt.Sprintf("1 %s request (status %d)", label, c.StatusCode)
+}
+
+// Example returns the first example payload, shortened for one-line listings
+func (c Cluster) Example() string {
+	if len(c.Examples) == 0 {
+		return ""
+	}
+	example := c.Examples[0]
+	if len(example) > 40 {
+		example = example[:37] + "..."
+	}
+	return example
+}
+
+// volatileTokenPattern matches tokens that change between otherwise identical
+// responses (request IDs, timestamps, hashes, counters)
+var volatileTokenPattern = regexp.MustCompile(`[0-9a-fA-F]*[0-9][0-9a-fA-F-]*`)
+
+// ClusterResults groups results by status code and response body similarity.
+// Clusters are returned largest first; results with the same status and no
+// captured body fall into a single cluster.
+func ClusterResults(results []request.TestResult) []Cluster {
+	var clusters []Cluster
+
+	for _, result := range results {
+		fingerprint := bodyFingerprint(result.ResponseBody)
+
+		matched := false
+		for i := range clusters {
+			c := &clusters[i]
+			if c.StatusCode != result.StatusCode || c.Blocked != result.Blocked {
+				continue
+			}
+			if bits.OnesCount64(c.Fingerprint^fingerprint) > clusterDistance {
+				continue
+			}
+			c.Count++
+			if len(c.Representatives) < MaxClusterRepresentatives {
+				c.Representatives = append(c.Representatives, result)
+				c.Examples = append(c.Examples, result.Payload)
+			}
+			matched = true
+			break
+		}
+
+		if !matched {
+			clusters = append(clusters, Cluster{
+				StatusCode:      result.StatusCode,
+				Blocked:         result.Blocked,
+				Count:           1,
+				Fingerprint:     fingerprint,
+				Representatives: []request.TestResult{result},
+				Examples:        []string{result.Payload},
+			})
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Count > clusters[j].Count
+	})
+	return clusters
+}
+
+// collapsedClusters is ClusterResults for the report sections listing the
+// clusters, or nil when every result has a cluster of its own and the list
+// would only repeat the results
+func collapsedClusters(results []request.TestResult) []Cluster {
+	clusters := ClusterResults(results)
+	if len(clusters) == len(results) {
+		return nil
+	}
+	return clusters
+}
+
+// bodyFingerprint computes a 64-bit SimHash over the normalized words of body
+func bodyFingerprint(body string) uint64 {
+	normalized := volatileTokenPattern.ReplaceAllString(strings.ToLower(body), "#")
+	tokens := strings.FieldsFunc(normalized, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r == '#')
+	})
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	for _, token := range tokens {
+		h := fnv.New64a()
+		h.Write([]byte(token))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
@@ -0,0 +1,102 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"obfuskit/request"
+)
+
+func TestClusterResults(t *testing.T) {
+	blockPage := `<html><head><title>Access Denied</title></head><body>
+<h1>Access Denied</h1>
+<p>Your request was blocked by the web application firewall because it matched a security rule.</p>
+<p>If you believe this is an error, contact the site administrator and quote the reference below.</p>
+<p>Reference ID: %s</p><p>Time: %s</p></body></html>`
+
+	var results []request.TestResult
+	references := []string{"a1b2c3d4e5f6", "0f9e8d7c6b5a", "1234abcd5678", "deadbeef0042", "77aa88bb99cc"}
+	for i, ref := range references {
+		results = append(results, request.TestResult{
+			Payload:      fmt.Sprintf("payload-%d", i),
+			StatusCode:   403,
+			Blocked:      true,
+			ResponseBody: fmt.Sprintf(blockPage, ref, fmt.Sprintf("2024-05-0%d 12:3%d:00", i+1, i)),
+		})
+	}
+
+	distinct := request.TestResult{
+		Payload:    "distinct",
+		StatusCode: 403,
+		Blocked:    true,
+		ResponseBody: `{"error":"forbidden","message":"Request rejected by upstream policy engine",` +
+			`"hint":"check your api token scopes","docs":"https://example.com/docs/errors"}`,
+	}
+	results = append(results, distinct)
+
+	clusters := ClusterResults(results)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d: %+v", len(clusters), clusters)
+	}
+
+	if clusters[0].Count != len(references) {
+		t.Errorf("block page cluster count = %d, want %d", clusters[0].Count, len(references))
+	}
+	if got := len(clusters[0].Representatives); got != MaxClusterRepresentatives {
+		t.Errorf("block page cluster kept %d representatives, want %d", got, MaxClusterRepresentatives)
+	}
+	if clusters[1].Count != 1 || clusters[1].Representatives[0].Payload != distinct.Payload {
+		t.Errorf("distinct body cluster = %+v, want the distinct result alone", clusters[1])
+	}
+}
+
+func TestClusterResultsSeparatesStatusCodes(t *testing.T) {
+	results := []request.TestResult{
+		{StatusCode: 200, ResponseBody: "ok"},
+		{StatusCode: 403, Blocked: true, ResponseBody: "ok"},
+		{StatusCode: 200, ResponseBody: "ok"},
+	}
+
+	clusters := ClusterResults(results)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(clusters))
+	}
+	if clusters[0].StatusCode != 200 || clusters[0].Count != 2 {
+		t.Errorf("largest cluster = status %d count %d, want status 200 count 2",
+			clusters[0].StatusCode, clusters[0].Count)
+	}
+}
+
+func TestReportsListResponseClusters(t *testing.T) {
+	var results []request.TestResult
+	for i := 0; i < 4; i++ {
+		results = append(results, request.TestResult{
+			Payload:      fmt.Sprintf("<script>alert(%d)</script>", i),
+			StatusCode:   403,
+			Blocked:      true,
+			ResponseBody: fmt.Sprintf("Access denied. Request ID %d", 1000+i),
+		})
+	}
+	results = append(results, request.TestResult{Payload: "passed", StatusCode: 200, ResponseBody: "welcome"})
+
+	dir := t.TempDir()
+	htmlPath := filepath.Join(dir, "report.html")
+	if err := GenerateHTMLReport(results, nil, htmlPath); err != nil {
+		t.Fatalf("GenerateHTMLReport() error: %v", err)
+	}
+	html, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "4 blocked requests (status 403, same response) e.g. &lt;script&gt;alert(0)&lt;/script&gt;"
+	if !strings.Contains(string(html), "Response Clusters") || !strings.Contains(string(html), want) {
+		t.Errorf("HTML report lacks the response clusters:\n%s", html)
+	}
+
+	if err := GeneratePDFReport(results, nil, filepath.Join(dir, "report.pdf")); err != nil {
+		t.Fatalf("GeneratePDFReport() error: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"obfuskit/internal/canon"
+	"obfuskit/internal/evasions/encoders"
+	"obfuskit/internal/intent"
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+// DecodeDepth returns how many URL-decode passes turn variant back into the
+// attack of original: 0 when the variant is recognizable as generated, 2 for
+// a double-encoded payload. It walks the decode ladder and returns -1 when no
+// pass reveals the attack or the attack type has no signature check.
+func DecodeDepth(attackType types.AttackType, original, variant string) int {
+	if !intent.Known(attackType) {
+		return -1
+	}
+	for depth, step := range encoders.DecodeLadder(variant, encoders.DefaultLadderPasses) {
+		if intent.Carries(attackType, original, step) {
+			return depth
+		}
+	}
+	return -1
+}
+
+// DecodeAdvice counts the bypasses a WAF would have caught by decoding its
+// input Depth times before matching signatures
+type DecodeAdvice struct {
+	Depth    int `json:"depth"`
+	Bypasses int `json:"bypasses"`
+	// Example is one bypassing payload needing this depth
+	Example string `json:"example"`
+}
+
+// DecodeDepths groups the bypasses in results by their DecodeDepth, shallowest
+// first. Bypasses seen as attacks without decoding, or not revealed by URL
+// decoding at all, are left out: more decode passes would not catch them.
+func DecodeDepths(results []request.TestResult) []DecodeAdvice {
+	index := make(map[int]int)
+	var advice []DecodeAdvice
+	for _, result := range results {
+		if !IsBypass(result) {
+			continue
+		}
+		original := result.Variant.SourcePayload
+		if original == "" {
+			original = canon.Normalize(result.Payload)
+		}
+		depth := DecodeDepth(types.AttackType(result.AttackType), original, result.Payload)
+		if depth < 1 {
+			continue
+		}
+		i, ok := index[depth]
+		if !ok {
+			i = len(advice)
+			index[depth] = i
+			advice = append(advice, DecodeAdvice{Depth: depth, Example: result.Payload})
+		}
+		advice[i].Bypasses++
+	}
+	sort.Slice(advice, func(i, j int) bool {
+		return advice[i].Depth < advice[j].Depth
+	})
+	return advice
+}
+
+// RecommendedDecodeDepth returns the number of decode passes a WAF needs to
+// see every bypass in advice (from DecodeDepths) as an attack, or 0 when
+// decoding more would not help
+func RecommendedDecodeDepth(advice []DecodeAdvice) int {
+	if len(advice) == 0 {
+		return 0
+	}
+	return advice[len(advice)-1].Depth
+}
+
+// DecodeDepthAdvice returns the tuning advice printed for advice (from
+// DecodeDepths), or "" when there is none
+func DecodeDepthAdvice(advice []DecodeAdvice) string {
+	depth := RecommendedDecodeDepth(advice)
+	if depth == 0 {
+		return ""
+	}
+	bypasses := 0
+	for _, a := range advice {
+		bypasses += a.Bypasses
+	}
+	return fmt.Sprintf("💡 Recommended decode depth: %d (%d bypasses only match a signature after URL-decoding)",
+		depth, bypasses)
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"testing"
+
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+func TestDecodeDepthOfDoubleURLEncodedPayloadIsTwo(t *testing.T) {
+	original := "<script>alert(1)</script>"
+	if depth := DecodeDepth(types.AttackTypeXSS, original, "%253Cscript%253Ealert(1)%253C%252Fscript%253E"); depth != 2 {
+		t.Errorf("double-URL-encoded depth = %d, want 2", depth)
+	}
+
+	results := []request.TestResult{
+		{Payload: "%253Cscript%253Ealert(1)%253C%252Fscript%253E", AttackType: "xss", StatusCode: 200,
+			Variant: types.Variant{SourcePayload: original}},
+		{Payload: "%3Cscript%3Ealert(1)%3C%2Fscript%3E", AttackType: "xss", StatusCode: 200,
+			Variant: types.Variant{SourcePayload: original}},
+		// Recognizable as sent, so decoding more would not catch it
+		{Payload: "<ScRiPt>alert(1)</sCrIpT>", AttackType: "xss", StatusCode: 200,
+			Variant: types.Variant{SourcePayload: original}},
+		// Blocked, so not a bypass to advise on
+		{Payload: "%25253Cscript%25253E", AttackType: "xss", StatusCode: 403, Blocked: true,
+			Variant: types.Variant{SourcePayload: original}},
+	}
+	advice := DecodeDepths(results)
+	if len(advice) != 2 || advice[0].Depth != 1 || advice[1].Depth != 2 || advice[1].Bypasses != 1 {
+		t.Fatalf("DecodeDepths = %+v, want depths 1 and 2 with one bypass each", advice)
+	}
+	if depth := RecommendedDecodeDepth(advice); depth != 2 {
+		t.Errorf("recommended decode depth = %d, want 2", depth)
+	}
+}
@@ -0,0 +1,200 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// encodingGroup is one base payload with every encoding applied to it
+type encodingGroup struct {
+	OriginalPayload string
+	AttackType      string
+	Rows            []encodingRow
+}
+
+// encodingRow is one before/after pair of the encoding report
+type encodingRow struct {
+	Encoding string
+	Variant  string
+}
+
+// groupByPayload collects variants under their base payload, keeping the
+// order payloads and encodings were generated in
+func groupByPayload(payloadResults []PayloadResult) []encodingGroup {
+	var groups []encodingGroup
+	index := make(map[string]int)
+	for _, result := range payloadResults {
+		key := result.AttackType + "\x00" + result.OriginalPayload
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, encodingGroup{OriginalPayload: result.OriginalPayload, AttackType: result.AttackType})
+		}
+		for _, variant := range result.Variants {
+			groups[i].Rows = append(groups[i].Rows, encodingRow{Encoding: result.EvasionType, Variant: variant})
+		}
+	}
+	return groups
+}
+
+// GenerateEncodingReport writes the raw-to-encoded mapping of every variant
+// to outputPath, as HTML for .html/.htm paths and as text otherwise
+func GenerateEncodingReport(payloadResults []PayloadResult, config *ConfigSnapshot, outputPath string) error {
+	if len(payloadResults) == 0 {
+		return fmt.Errorf("no payload results provided")
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".html", ".htm":
+		return WriteEncodingReportHTML(file, payloadResults, config)
+	default:
+		return WriteEncodingReportText(file, payloadResults, config)
+	}
+}
+
+// WriteEncodingReportText writes, for each base payload, a table of every
+// encoding applied and the variant it produced
+func WriteEncodingReportText(w io.Writer, payloadResults []PayloadResult, config *ConfigSnapshot) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Encoding Report")
+	if config != nil {
+		for _, line := range config.Lines() {
+			fmt.Fprintf(tw, "# %s\n", line)
+		}
+	}
+	for _, group := range groupByPayload(payloadResults) {
+		fmt.Fprintf(tw, "\nPayload: %s", group.OriginalPayload)
+		if group.AttackType != "" {
+			fmt.Fprintf(tw, " (%s)", group.AttackType)
+		}
+		fmt.Fprintf(tw, "\n  ENCODING\tVARIANT\n")
+		for _, row := range group.Rows {
+			fmt.Fprintf(tw, "  %s\t%s\n", row.Encoding, row.Variant)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteEncodingReportHTML writes the encoding report as an HTML page with
+// one before/after table per base payload
+func WriteEncodingReportHTML(w io.Writer, payloadResults []PayloadResult, config *ConfigSnapshot) error {
+	data := struct {
+		Groups      []encodingGroup
+		Config      []string
+		GeneratedAt string
+	}{
+		Groups:      groupByPayload(payloadResults),
+		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}
+	if config != nil {
+		data.Config = config.Lines()
+	}
+
+	tmpl := `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Encoding Report</title>
+    <style>
+        body {
+            font-family: Arial, sans-serif;
+            margin: 20px;
+            line-height: 1.6;
+        }
+        h1, h2 {
+            color: #333;
+        }
+        .summary {
+            background-color: #f5f5f5;
+            padding: 15px;
+            border-radius: 5px;
+            margin-bottom: 20px;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            margin-bottom: 30px;
+        }
+        th, td {
+            border: 1px solid #ddd;
+            padding: 10px;
+            text-align: left;
+        }
+        th {
+            background-color: #f2f2f2;
+        }
+        td code {
+            word-break: break-all;
+        }
+        .footer {
+            margin-top: 30px;
+            text-align: center;
+            font-size: 0.8em;
+            color: #666;
+        }
+    </style>
+</head>
+<body>
+    <h1>Encoding Report</h1>
+    {{if .Config}}
+    <div class="summary">
+        <h2>Configuration</h2>
+        <ul>
+            {{range .Config}}<li>{{.}}</li>
+            {{end}}
+        </ul>
+    </div>
+    {{end}}
+
+    {{range .Groups}}
+    <h2>{{if .AttackType}}[{{.AttackType}}] {{end}}<code>{{.OriginalPayload}}</code></h2>
+    <table>
+        <thead>
+            <tr>
+                <th>Encoding</th>
+                <th>Before</th>
+                <th>After</th>
+            </tr>
+        </thead>
+        <tbody>
+            {{$original := .OriginalPayload}}
+            {{range .Rows}}
+            <tr>
+                <td>{{.Encoding}}</td>
+                <td><code>{{$original}}</code></td>
+                <td><code>{{.Variant}}</code></td>
+            </tr>
+            {{end}}
+        </tbody>
+    </table>
+    {{end}}
+
+    <div class="footer">
+        <p>Report generated at {{.GeneratedAt}}</p>
+    </div>
+</body>
+</html>`
+
+	t, err := template.New("encoding").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %v", err)
+	}
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to execute template: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestEncodingReportShowsOriginalAndEncodedForms(t *testing.T) {
+	payloadResults := []PayloadResult{
+		{OriginalPayload: "<script>alert(1)</script>", AttackType: "xss", EvasionType: "URLVariants", Variants: []string{"%3Cscript%3Ealert(1)%3C%2Fscript%3E"}},
+		{OriginalPayload: "<script>alert(1)</script>", AttackType: "xss", EvasionType: "HTMLVariants", Variants: []string{"&lt;script&gt;alert(1)&lt;/script&gt;"}},
+	}
+
+	var text bytes.Buffer
+	if err := WriteEncodingReportText(&text, payloadResults, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(text.String(), "Payload: <script>alert(1)</script>") != 1 {
+		t.Errorf("text report should list the base payload once:\n%s", text.String())
+	}
+	if !regexp.MustCompile(`URLVariants\s+%3Cscript%3Ealert\(1\)`).MatchString(text.String()) {
+		t.Errorf("text report lacks the URL-encoded form next to its encoding:\n%s", text.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "encodings.html")
+	if err := GenerateEncodingReport(payloadResults, nil, path); err != nil {
+		t.Fatal(err)
+	}
+	html, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<td>HTMLVariants</td>",
+		"<code>&lt;script&gt;alert(1)&lt;/script&gt;</code>",
+		"<code>&amp;lt;script&amp;gt;alert(1)&amp;lt;/script&amp;gt;</code>",
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("HTML report lacks %q", want)
+		}
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"obfuskit/request"
 )
 
-func GenerateHTMLReport(results []request.TestResult, outputPath string) error {
+// GenerateHTMLReport writes an HTML report; config, when non-nil, is shown
+// as a "Configuration" section under the title
+func GenerateHTMLReport(results []request.TestResult, config *ConfigSnapshot, outputPath string) error {
 	// Count statistics
 	total := len(results)
 	blocked := 0
@@ -33,6 +35,11 @@ func GenerateHTMLReport(results []request.TestResult, outputPath string) error {
 		Unblocked   int
 		BlockRate   float64
 		GeneratedAt string
+		Config      []string
+		// Clusters collapse repeated responses, such as one block page
+		Clusters []Cluster
+		// Recommendations are remediation advice for the bypasses
+		Recommendations []string
 	}{
 		Results:     results,
 		Total:       total,
@@ -40,6 +47,13 @@ func GenerateHTMLReport(results []request.TestResult, outputPath string) error {
 		Unblocked:   total - blocked,
 		BlockRate:   blockRate,
 		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+		Clusters:    collapsedClusters(results),
+	}
+	if config != nil {
+		data.Config = config.Lines()
+	}
+	for _, recommendation := range Recommendations(results) {
+		data.Recommendations = append(data.Recommendations, FormatRecommendation(recommendation))
 	}
 
 	// HTML template
@@ -118,6 +132,15 @@ func GenerateHTMLReport(results []request.TestResult, outputPath string) error {
 </head>
 <body>
     <h1>Security Test Results Report</h1>
+    {{if .Config}}
+    <div class="summary">
+        <h2>Configuration</h2>
+        <ul>
+            {{range .Config}}<li>{{.}}</li>
+            {{end}}
+        </ul>
+    </div>
+    {{end}}
     
     <div class="summary">
         <h2>Summary</h2>
@@ -137,6 +160,24 @@ func GenerateHTMLReport(results []request.TestResult, outputPath string) error {
         </div>
         <h3>Block Rate: {{printf "%.2f" .BlockRate}}%</h3>
     </div>
+    {{if .Clusters}}
+    <div class="summary">
+        <h2>Response Clusters</h2>
+        <ul>
+            {{range .Clusters}}<li>{{.}} e.g. {{.Example}}</li>
+            {{end}}
+        </ul>
+    </div>
+    {{end}}
+    {{if .Recommendations}}
+    <div class="summary">
+        <h2>Recommendations</h2>
+        <ul>
+            {{range .Recommendations}}<li>{{.}}</li>
+            {{end}}
+        </ul>
+    </div>
+    {{end}}
 
     <h2>Detailed Results</h2>
     <table>
@@ -159,7 +200,7 @@ func GenerateHTMLReport(results []request.TestResult, outputPath string) error {
                 <td>{{.StatusCode}}</td>
                 <td>{{.ResponseTime.Milliseconds}}</td>
                 <td class="{{if .Blocked}}blocked-yes{{else}}blocked-no{{end}}">
-                    {{if .Blocked}}Yes{{else}}No{{end}}
+                    {{if .Blocked}}Yes{{if .BlockReason}} ({{.BlockReason}}{{with .RuleID}} {{.}}{{end}}){{end}}{{else}}No{{end}}
                 </td>
             </tr>
             {{end}}
@@ -215,7 +256,7 @@ func main() {
 		},
 	}
 
-	err := GenerateHTMLReport(results, "security_report.html")
+	err := GenerateHTMLReport(results, nil, "security_report.html")
 	if err != nil {
 		fmt.Printf("Error generating report: %v\n", err)
 	} else {
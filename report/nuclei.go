@@ -3,6 +3,7 @@ package report
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,22 +27,108 @@ type NucleiInfo struct {
 }
 
 type NucleiRequest struct {
-	Method   string            `yaml:"method"`
-	Path     []string          `yaml:"path"`
-	Headers  map[string]string `yaml:"headers,omitempty"`
-	Body     string            `yaml:"body,omitempty"`
-	Matchers []NucleiMatcher   `yaml:"matchers"`
+	Method  string            `yaml:"method"`
+	Path    []string          `yaml:"path"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body,omitempty"`
+	// Matchers must all match (matchers-condition: and)
+	Matchers []NucleiMatcher `yaml:"matchers"`
 }
 
 type NucleiMatcher struct {
-	Type     string   `yaml:"type"`
-	Status   []int    `yaml:"status,omitempty"`
-	Words    []string `yaml:"words,omitempty"`
-	Negative bool     `yaml:"negative,omitempty"`
+	Type      string   `yaml:"type"`
+	Name      string   `yaml:"name,omitempty"`
+	Part      string   `yaml:"part,omitempty"`
+	Condition string   `yaml:"condition,omitempty"`
+	Status    []int    `yaml:"status,omitempty"`
+	Words     []string `yaml:"words,omitempty"`
+	Regex     []string `yaml:"regex,omitempty"`
+	DSL       []string `yaml:"dsl,omitempty"`
+	Negative  bool     `yaml:"negative,omitempty"`
 }
 
-// GenerateNucleiTemplates creates nuclei templates from test results
-func GenerateNucleiTemplates(results []request.TestResult, outputPath string) error {
+// AttackEvidence holds, per attack type, the nuclei DSL expressions that show
+// a payload reached the application and took effect (a database error, a
+// reflected script, a leaked file). Every generated request for that attack
+// type requires a success status and at least one of its expressions; change
+// or extend the table to tune detection for a target.
+var AttackEvidence = map[string][]string{
+	"xss": {
+		"contains(body, payload)",
+		dslRegex(`(?i)<script[^>]*>[^<]*alert\(`),
+		dslRegex(`(?i)<[a-z]+[^>]*\son[a-z]+\s*=`),
+		dslRegex(`(?i)javascript:[^"'\s]*alert`),
+	},
+	"sqli": {
+		dslRegex(`(?i)you have an error in your sql syntax`),
+		dslRegex(`(?i)warning.*\bmysqli?_`),
+		dslRegex(`(?i)unclosed quotation mark after the character string`),
+		dslRegex(`(?i)quoted string not properly terminated`),
+		dslRegex(`ORA-[0-9]{5}`),
+		dslRegex(`(?i)pg_query\(\)|PostgreSQL.*ERROR|syntax error at or near`),
+		dslRegex(`(?i)SQLite(3)?::|sqlite3?\.OperationalError|SQLITE_ERROR`),
+		dslRegex(`(?i)microsoft ole db provider for (sql server|odbc)`),
+	},
+	"unixcmdi":   commandOutputEvidence,
+	"oscmdi":     commandOutputEvidence,
+	"wincmdi":    {dslRegex(`(?i)Windows IP Configuration`), dslRegex(`(?i)Volume Serial Number is`), dslRegex(`(?i)nt authority\\`)},
+	"path":       fileDisclosureEvidence,
+	"fileaccess": fileDisclosureEvidence,
+	"xxe":        fileDisclosureEvidence,
+	"ssrf":       {dslContains("ami-id"), dslContains("instance-id"), dslContains("computeMetadata")},
+	"ldapi":      {dslRegex(`(?i)javax\.naming\.(directory|NameNotFound)`), dslRegex(`(?i)LDAPException|ldap_search\(\)|Bad search filter`)},
+}
+
+var (
+	commandOutputEvidence  = []string{dslRegex(`uid=[0-9]+\([a-z_][a-z0-9_-]*\)`), dslRegex(`root:.*:0:0:`)}
+	fileDisclosureEvidence = []string{dslRegex(`root:.*:0:0:`), dslRegex(`(?i)\[(boot loader|fonts|extensions)\]`)}
+)
+
+// dslRegex returns a DSL expression matching pattern against the body
+func dslRegex(pattern string) string {
+	return fmt.Sprintf("regex(%s, body)", dslString(pattern))
+}
+
+// dslContains returns a DSL expression looking for word in the body
+func dslContains(word string) string {
+	return fmt.Sprintf("contains(body, %s)", dslString(word))
+}
+
+// dslString quotes s as a DSL string literal, in which a backslash escapes
+// the character after it
+func dslString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// bypassMatchers returns the matchers of a request that bypassed the WAF:
+// one of the success statuses, none of the blocked ones and, when the attack
+// types have any, evidence that the payload took effect. Requests carry them
+// with matchers-condition: and, so a plain 200 is not enough on its own.
+func bypassMatchers(successStatus, blockedStatus []int, attackTypes ...string) []NucleiMatcher {
+	matchers := []NucleiMatcher{
+		{Type: "status", Status: successStatus},
+		{Type: "status", Status: blockedStatus, Negative: true},
+	}
+	var evidence []string
+	seen := make(map[string]bool)
+	for _, attackType := range attackTypes {
+		key := strings.ToLower(attackType)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		evidence = append(evidence, AttackEvidence[key]...)
+	}
+	if len(evidence) > 0 {
+		// Any one piece of evidence will do
+		matchers = append(matchers, NucleiMatcher{Type: "dsl", Name: "attack-evidence", Condition: "or", DSL: evidence})
+	}
+	return matchers
+}
+
+// GenerateNucleiTemplates creates nuclei templates from test results; config,
+// when non-nil, is recorded as comments at the top of each template
+func GenerateNucleiTemplates(results []request.TestResult, config *ConfigSnapshot, outputPath string) error {
 	if len(results) == 0 {
 		return fmt.Errorf("no test results provided")
 	}
@@ -57,7 +144,7 @@ func GenerateNucleiTemplates(results []request.TestResult, outputPath string) er
 	// Generate individual template files
 	for i, template := range templates {
 		filename := fmt.Sprintf("%s/template_%d_%s.yaml", outputPath, i+1, sanitizeFilename(template.ID))
-		if err := writeNucleiTemplate(template, filename); err != nil {
+		if err := writeNucleiTemplate(template, config, filename); err != nil {
 			return fmt.Errorf("failed to write template %s: %v", filename, err)
 		}
 	}
@@ -65,7 +152,7 @@ func GenerateNucleiTemplates(results []request.TestResult, outputPath string) er
 	// Generate a master template that includes all payloads
 	masterTemplate := generateMasterTemplate(results)
 	masterFilename := fmt.Sprintf("%s/master_template.yaml", outputPath)
-	if err := writeNucleiTemplate(masterTemplate, masterFilename); err != nil {
+	if err := writeNucleiTemplate(masterTemplate, config, masterFilename); err != nil {
 		return fmt.Errorf("failed to write master template: %v", err)
 	}
 
@@ -125,10 +212,17 @@ func generateRequestsForGroup(results []request.TestResult) []NucleiRequest {
 			Method: "GET", // Default method
 		}
 
-		// Collect unique payloads
+		// Collect unique payloads, attack types, and any candidate-bypass
+		// statuses seen
 		payloadMap := make(map[string]bool)
+		var attackTypes []string
+		successStatus := []int{200, 201, 202}
 		for _, result := range partResults {
 			payloadMap[result.Payload] = true
+			attackTypes = append(attackTypes, result.AttackType)
+			if result.CandidateBypass && !slices.Contains(successStatus, result.StatusCode) {
+				successStatus = append(successStatus, result.StatusCode)
+			}
 		}
 
 		var payloads []string
@@ -158,18 +252,7 @@ func generateRequestsForGroup(results []request.TestResult) []NucleiRequest {
 		}
 
 		// Add matchers to detect successful bypass
-		request.Matchers = []NucleiMatcher{
-			{
-				Type:     "status",
-				Status:   []int{200, 201, 202},
-				Negative: false,
-			},
-			{
-				Type:     "status",
-				Status:   []int{403, 406, 429},
-				Negative: true,
-			},
-		}
+		request.Matchers = bypassMatchers(successStatus, []int{403, 406, 429}, attackTypes...)
 
 		requests = append(requests, request)
 	}
@@ -233,7 +316,7 @@ func generateMasterTemplate(results []request.TestResult) NucleiTemplate {
 }
 
 // writeNucleiTemplate writes a nuclei template to a YAML file
-func writeNucleiTemplate(template NucleiTemplate, filename string) error {
+func writeNucleiTemplate(template NucleiTemplate, config *ConfigSnapshot, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
@@ -241,18 +324,25 @@ func writeNucleiTemplate(template NucleiTemplate, filename string) error {
 	defer file.Close()
 
 	// Write YAML content manually (avoiding external YAML library dependency)
-	yamlContent := generateYAMLContent(template)
+	yamlContent := generateYAMLContent(template, config)
 	_, err = file.WriteString(yamlContent)
 	return err
 }
 
 // generateYAMLContent creates YAML content for the nuclei template
-func generateYAMLContent(template NucleiTemplate) string {
+func generateYAMLContent(template NucleiTemplate, config *ConfigSnapshot) string {
 	var builder strings.Builder
 
 	// Template header comment
 	builder.WriteString(fmt.Sprintf("# Generated by obfuskit on %s\n", time.Now().Format("2006-01-02 15:04:05")))
-	builder.WriteString("# WAF Bypass Nuclei Template\n\n")
+	builder.WriteString("# WAF Bypass Nuclei Template\n")
+	if config != nil {
+		builder.WriteString("#\n# Configuration:\n")
+		for _, line := range config.Lines() {
+			builder.WriteString("#   " + line + "\n")
+		}
+	}
+	builder.WriteString("\n")
 
 	// Template ID
 	builder.WriteString(fmt.Sprintf("id: %s\n\n", template.ID))
@@ -267,8 +357,14 @@ func generateYAMLContent(template NucleiTemplate) string {
 	builder.WriteString(fmt.Sprintf("  severity: %s\n", template.Info.Severity))
 	builder.WriteString(fmt.Sprintf("  description: \"%s\"\n", template.Info.Description))
 	builder.WriteString("  tags:\n")
-	for _, tag := range template.Info.Tags {
-		builder.WriteString(fmt.Sprintf("    - %s\n", tag))
+	tags := template.Info.Tags
+	if config != nil {
+		// The run's tags make its templates searchable alongside its reports
+		tags = append(slices.Clone(tags), config.Tags...)
+	}
+	for _, tag := range tags {
+		// Quoted, since a -tag may hold YAML syntax such as ": " or " #"
+		builder.WriteString(fmt.Sprintf("    - %q\n", tag))
 	}
 	builder.WriteString("\n")
 
@@ -306,13 +402,23 @@ func generateYAMLContent(template NucleiTemplate) string {
 
 		// Body
 		if req.Body != "" {
-			builder.WriteString(fmt.Sprintf("    body: \"%s\"\n", req.Body))
+			builder.WriteString(fmt.Sprintf("    body: %q\n", req.Body))
 		}
 
-		// Matchers
+		// Matchers, all of which must match
+		builder.WriteString("    matchers-condition: and\n")
 		builder.WriteString("    matchers:\n")
 		for _, matcher := range req.Matchers {
 			builder.WriteString(fmt.Sprintf("      - type: %s\n", matcher.Type))
+			if matcher.Name != "" {
+				builder.WriteString(fmt.Sprintf("        name: %s\n", matcher.Name))
+			}
+			if matcher.Part != "" {
+				builder.WriteString(fmt.Sprintf("        part: %s\n", matcher.Part))
+			}
+			if matcher.Condition != "" {
+				builder.WriteString(fmt.Sprintf("        condition: %s\n", matcher.Condition))
+			}
 			if len(matcher.Status) > 0 {
 				builder.WriteString("        status:\n")
 				for _, status := range matcher.Status {
@@ -325,6 +431,18 @@ func generateYAMLContent(template NucleiTemplate) string {
 					builder.WriteString(fmt.Sprintf("          - \"%s\"\n", word))
 				}
 			}
+			if len(matcher.Regex) > 0 {
+				builder.WriteString("        regex:\n")
+				for _, pattern := range matcher.Regex {
+					builder.WriteString(fmt.Sprintf("          - '%s'\n", strings.ReplaceAll(pattern, "'", "''")))
+				}
+			}
+			if len(matcher.DSL) > 0 {
+				builder.WriteString("        dsl:\n")
+				for _, expr := range matcher.DSL {
+					builder.WriteString(fmt.Sprintf("          - '%s'\n", strings.ReplaceAll(expr, "'", "''")))
+				}
+			}
 			if matcher.Negative {
 				builder.WriteString("        negative: true\n")
 			}
@@ -362,8 +480,9 @@ type PayloadResult struct {
 	Level           string
 }
 
-// GenerateNucleiTemplatesFromPayloads creates nuclei templates from payload results
-func GenerateNucleiTemplatesFromPayloads(payloadResults []PayloadResult, outputPath string) error {
+// GenerateNucleiTemplatesFromPayloads creates nuclei templates from payload
+// results; config, when non-nil, is recorded as in GenerateNucleiTemplates
+func GenerateNucleiTemplatesFromPayloads(payloadResults []PayloadResult, config *ConfigSnapshot, outputPath string) error {
 	if len(payloadResults) == 0 {
 		return fmt.Errorf("no payload results provided")
 	}
@@ -379,7 +498,7 @@ func GenerateNucleiTemplatesFromPayloads(payloadResults []PayloadResult, outputP
 	// Generate individual template files
 	for i, template := range templates {
 		filename := fmt.Sprintf("%s/template_%d_%s.yaml", outputPath, i+1, sanitizeFilename(template.ID))
-		if err := writeNucleiTemplate(template, filename); err != nil {
+		if err := writeNucleiTemplate(template, config, filename); err != nil {
 			return fmt.Errorf("failed to write template %s: %v", filename, err)
 		}
 	}
@@ -387,7 +506,7 @@ func GenerateNucleiTemplatesFromPayloads(payloadResults []PayloadResult, outputP
 	// Generate a comprehensive master template
 	masterTemplate := generateMasterTemplateFromPayloads(payloadResults)
 	masterFilename := fmt.Sprintf("%s/master_template.yaml", outputPath)
-	if err := writeNucleiTemplate(masterTemplate, masterFilename); err != nil {
+	if err := writeNucleiTemplate(masterTemplate, config, masterFilename); err != nil {
 		return fmt.Errorf("failed to write master template: %v", err)
 	}
 
@@ -489,18 +608,7 @@ func generateRequestsForPayloads(payloads []string, attackType string) []NucleiR
 		}
 
 		// Add matchers to detect successful bypass
-		request.Matchers = []NucleiMatcher{
-			{
-				Type:     "status",
-				Status:   []int{200, 201, 202},
-				Negative: false,
-			},
-			{
-				Type:     "status",
-				Status:   []int{403, 406, 429, 451},
-				Negative: true,
-			},
-		}
+		request.Matchers = bypassMatchers([]int{200, 201, 202}, []int{403, 406, 429, 451}, attackType)
 
 		requests = append(requests, request)
 	}
@@ -537,11 +645,13 @@ func generateMasterTemplateFromPayloads(payloadResults []PayloadResult) NucleiTe
 		},
 	}
 
-	// Collect all payloads from all results
-	var allPayloads []string
+	// Collect all payloads and attack types from all results
+	var allPayloads, attackTypes []string
 	for _, result := range payloadResults {
 		allPayloads = append(allPayloads, result.Variants...)
+		attackTypes = append(attackTypes, result.AttackType)
 	}
+	matchers := bypassMatchers([]int{200, 201, 202}, []int{403, 406, 429, 451}, attackTypes...)
 
 	// Add payloads section
 	template.Payloads = map[string][]string{
@@ -552,12 +662,9 @@ func generateMasterTemplateFromPayloads(payloadResults []PayloadResult) NucleiTe
 	requests := []NucleiRequest{
 		// Query parameter injection
 		{
-			Method: "GET",
-			Path:   []string{"/?test={{payload}}", "/?q={{payload}}", "/?search={{payload}}"},
-			Matchers: []NucleiMatcher{
-				{Type: "status", Status: []int{200, 201, 202}},
-				{Type: "status", Status: []int{403, 406, 429, 451}, Negative: true},
-			},
+			Method:   "GET",
+			Path:     []string{"/?test={{payload}}", "/?q={{payload}}", "/?search={{payload}}"},
+			Matchers: matchers,
 		},
 		// Header injection
 		{
@@ -568,10 +675,7 @@ func generateMasterTemplateFromPayloads(payloadResults []PayloadResult) NucleiTe
 				"User-Agent":      "{{payload}}",
 				"X-Forwarded-For": "{{payload}}",
 			},
-			Matchers: []NucleiMatcher{
-				{Type: "status", Status: []int{200, 201, 202}},
-				{Type: "status", Status: []int{403, 406, 429, 451}, Negative: true},
-			},
+			Matchers: matchers,
 		},
 		// Form body injection
 		{
@@ -580,11 +684,8 @@ func generateMasterTemplateFromPayloads(payloadResults []PayloadResult) NucleiTe
 			Headers: map[string]string{
 				"Content-Type": "application/x-www-form-urlencoded",
 			},
-			Body: "param={{payload}}&test={{payload}}",
-			Matchers: []NucleiMatcher{
-				{Type: "status", Status: []int{200, 201, 202}},
-				{Type: "status", Status: []int{403, 406, 429, 451}, Negative: true},
-			},
+			Body:     "param={{payload}}&test={{payload}}",
+			Matchers: matchers,
 		},
 		// JSON body injection
 		{
@@ -593,11 +694,8 @@ func generateMasterTemplateFromPayloads(payloadResults []PayloadResult) NucleiTe
 			Headers: map[string]string{
 				"Content-Type": "application/json",
 			},
-			Body: `{"test": "{{payload}}", "param": "{{payload}}"}`,
-			Matchers: []NucleiMatcher{
-				{Type: "status", Status: []int{200, 201, 202}},
-				{Type: "status", Status: []int{403, 406, 429, 451}, Negative: true},
-			},
+			Body:     `{"test": "{{payload}}", "param": "{{payload}}"}`,
+			Matchers: matchers,
 		},
 	}
 
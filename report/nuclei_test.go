@@ -0,0 +1,182 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNucleiTemplatesCarryAttackTypeMatchers(t *testing.T) {
+	payloadResults := []PayloadResult{
+		{OriginalPayload: "<script>alert(1)</script>", AttackType: "xss", EvasionType: "URLVariants", Variants: []string{"%3Cscript%3Ealert(1)%3C%2Fscript%3E"}},
+		{OriginalPayload: "' OR 1=1 --", AttackType: "sqli", EvasionType: "URLVariants", Variants: []string{"%27%20OR%201%3D1%20--"}},
+	}
+
+	dir := t.TempDir()
+	if err := GenerateNucleiTemplatesFromPayloads(payloadResults, nil, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(attackType string) string {
+		t.Helper()
+		matches, err := filepath.Glob(filepath.Join(dir, "template_*_waf-bypass-"+attackType+"-*.yaml"))
+		if err != nil || len(matches) != 1 {
+			t.Fatalf("expected one %s template, found %v (%v)", attackType, matches, err)
+		}
+		data, err := os.ReadFile(matches[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// The templates as nuclei reads them
+	type template struct {
+		Requests []struct {
+			MatchersCondition string `yaml:"matchers-condition"`
+			Matchers          []struct {
+				Type      string   `yaml:"type"`
+				Condition string   `yaml:"condition"`
+				Status    []int    `yaml:"status"`
+				Words     []string `yaml:"words"`
+				Regex     []string `yaml:"regex"`
+				DSL       []string `yaml:"dsl"`
+				Negative  bool     `yaml:"negative"`
+			} `yaml:"matchers"`
+		} `yaml:"requests"`
+	}
+	// matches applies nuclei's matching to the first request of a template:
+	// matchers OR together unless matchers-condition is and, and the values
+	// of a matcher OR together unless its condition is and
+	matches := func(yamlText string, status int, body, payload string) bool {
+		t.Helper()
+		var parsed template
+		if err := yaml.Unmarshal([]byte(yamlText), &parsed); err != nil {
+			t.Fatalf("template is not valid YAML: %v", err)
+		}
+		req := parsed.Requests[0]
+		all := req.MatchersCondition == "and"
+		for _, matcher := range req.Matchers {
+			var results []bool
+			for _, want := range matcher.Status {
+				results = append(results, status == want)
+			}
+			for _, word := range matcher.Words {
+				results = append(results, strings.Contains(body, strings.ReplaceAll(word, "{{payload}}", payload)))
+			}
+			for _, pattern := range matcher.Regex {
+				results = append(results, regexp.MustCompile(pattern).MatchString(body))
+			}
+			for _, expr := range matcher.DSL {
+				results = append(results, evalDSL(t, expr, body, payload))
+			}
+			matched := matcher.Condition == "and"
+			for _, result := range results {
+				if matcher.Condition == "and" {
+					matched = matched && result
+				} else {
+					matched = matched || result
+				}
+			}
+			if matcher.Negative {
+				matched = !matched
+			}
+			if all && !matched {
+				return false
+			}
+			if !all && matched {
+				return true
+			}
+		}
+		return all
+	}
+
+	xss := read("xss")
+	xssPayload := "%3Cscript%3Ealert(1)%3C%2Fscript%3E"
+	for _, tt := range []struct {
+		status int
+		body   string
+		want   bool
+	}{
+		{200, "<html>nothing to see</html>", false},
+		{200, "<p>" + xssPayload + "</p>", true},
+		{200, "<script>alert(1)</script>", true},
+		{403, "<script>alert(1)</script>", false},
+	} {
+		if got := matches(xss, tt.status, tt.body, xssPayload); got != tt.want {
+			t.Errorf("XSS template on %d %q: matched = %v, want %v", tt.status, tt.body, got, tt.want)
+		}
+	}
+
+	sqli := read("sqli")
+	for _, tt := range []struct {
+		status int
+		body   string
+		want   bool
+	}{
+		{200, "<html>ok</html>", false},
+		{200, "You have an error in your SQL syntax near ''", true},
+		{200, "<script>alert(1)</script>", false},
+		{500, "You have an error in your SQL syntax", false},
+	} {
+		if got := matches(sqli, tt.status, tt.body, "%27%20OR%201%3D1%20--"); got != tt.want {
+			t.Errorf("SQLi template on %d %q: matched = %v, want %v", tt.status, tt.body, got, tt.want)
+		}
+	}
+}
+
+// evalDSL evaluates the DSL expressions the templates use: regex('...', body),
+// contains(body, '...') and contains(body, payload)
+func evalDSL(t *testing.T, expr, body, payload string) bool {
+	t.Helper()
+	literal := `'((?:[^'\\]|\\.)*)'`
+	unquote := func(s string) string { return regexp.MustCompile(`\\(.)`).ReplaceAllString(s, "$1") }
+	if expr == "contains(body, payload)" {
+		return strings.Contains(body, payload)
+	}
+	if m := regexp.MustCompile(`^regex\(` + literal + `, body\)$`).FindStringSubmatch(expr); m != nil {
+		return regexp.MustCompile(unquote(m[1])).MatchString(body)
+	}
+	if m := regexp.MustCompile(`^contains\(body, ` + literal + `\)$`).FindStringSubmatch(expr); m != nil {
+		return strings.Contains(body, unquote(m[1]))
+	}
+	t.Fatalf("unexpected DSL expression %q", expr)
+	return false
+}
+
+func TestNucleiTemplateTagsAreQuoted(t *testing.T) {
+	payloadResults := []PayloadResult{
+		{OriginalPayload: "' OR 1=1 --", AttackType: "sqli", EvasionType: "URLVariants", Variants: []string{"%27%20OR%201%3D1%20--"}},
+	}
+	tags := []string{"ticket: SEC-42", "run #7", `say "hi"`}
+	dir := t.TempDir()
+	if err := GenerateNucleiTemplatesFromPayloads(payloadResults, &ConfigSnapshot{Tags: tags}, dir); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no templates written (%v)", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parsed struct {
+			Info struct {
+				Tags []string `yaml:"tags"`
+			} `yaml:"info"`
+		}
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			t.Fatalf("%s is not valid YAML: %v", filepath.Base(file), err)
+		}
+		if got := parsed.Info.Tags; len(got) < len(tags) || !slices.Equal(got[len(got)-len(tags):], tags) {
+			t.Errorf("%s tags = %q, want them to end with %q", filepath.Base(file), got, tags)
+		}
+	}
+}
@@ -8,8 +8,9 @@ import (
 	"github.com/jung-kurt/gofpdf"
 )
 
-// GeneratePDFReport creates a PDF report from a list of test results
-func GeneratePDFReport(results []request.TestResult, outputPath string) error {
+// GeneratePDFReport creates a PDF report from a list of test results; config,
+// when non-nil, is listed before the summary
+func GeneratePDFReport(results []request.TestResult, config *ConfigSnapshot, outputPath string) error {
 	// Count statistics
 	total := len(results)
 	blocked := 0
@@ -35,6 +36,17 @@ func GeneratePDFReport(results []request.TestResult, outputPath string) error {
 	// Add title
 	pdf.CellFormat(190, 10, "Security Test Results Report", "", 1, "C", false, 0, "")
 
+	// Add configuration section
+	if config != nil {
+		pdf.SetFont("Arial", "B", 14)
+		pdf.CellFormat(190, 10, "Configuration", "", 1, "", false, 0, "")
+		pdf.SetFont("Arial", "", 10)
+		for _, line := range config.Lines() {
+			pdf.MultiCell(190, 6, line, "", "", false)
+		}
+		pdf.Ln(5)
+	}
+
 	// Add summary section
 	pdf.SetFont("Arial", "B", 14)
 	pdf.CellFormat(190, 10, "Summary", "", 1, "", false, 0, "")
@@ -61,6 +73,17 @@ func GeneratePDFReport(results []request.TestResult, outputPath string) error {
 	pdf.CellFormat(130, 8, fmt.Sprintf("%.2f%%", blockRate), "1", 1, "", false, 0, "")
 	pdf.Ln(15)
 
+	// Add response clusters section
+	if clusters := collapsedClusters(results); len(clusters) > 0 {
+		pdf.SetFont("Arial", "B", 14)
+		pdf.CellFormat(190, 10, "Response Clusters", "", 1, "", false, 0, "")
+		pdf.SetFont("Arial", "", 10)
+		for _, cluster := range clusters {
+			pdf.MultiCell(190, 6, fmt.Sprintf("%s e.g. %s", cluster, cluster.Example()), "", "", false)
+		}
+		pdf.Ln(10)
+	}
+
 	// Add detailed results section
 	pdf.SetFont("Arial", "B", 14)
 	pdf.CellFormat(190, 10, "Detailed Results", "", 1, "", false, 0, "")
@@ -11,18 +11,22 @@ import (
 
 // PrintTerminalReport prints a formatted report to the terminal
 func PrintTerminalReport(results []request.TestResult) {
-	PrintTerminalReportWithBaseline(results, results)
+	PrintTerminalReportWithBaseline(results, results, nil)
 }
 
 // PrintTerminalReportWithBaseline prints a report where the summary is computed from
 // the provided baseline slice, while the detailed section is printed from results.
 // This allows filtered views (e.g., only-successful) without altering summary totals.
-func PrintTerminalReportWithBaseline(results []request.TestResult, baseline []request.TestResult) {
+// config, when non-nil, is printed as a CONFIGURATION section before the summary.
+func PrintTerminalReportWithBaseline(results []request.TestResult, baseline []request.TestResult, config *ConfigSnapshot) {
 	// Count statistics from baseline
 	total := len(baseline)
 	blocked := 0
+	challenges := 0
 	for _, result := range baseline {
-		if result.Blocked {
+		if result.Challenge {
+			challenges++
+		} else if result.Blocked {
 			blocked++
 		}
 	}
@@ -46,6 +50,16 @@ func PrintTerminalReportWithBaseline(results []request.TestResult, baseline []re
 	headerColor.Println(strings.Repeat(" ", 80))
 	fmt.Println()
 
+	// Print the configuration the results were produced with
+	if config != nil {
+		sectionColor.Println(" CONFIGURATION ")
+		fmt.Println()
+		for _, line := range config.Lines() {
+			fmt.Printf("  %s\n", line)
+		}
+		fmt.Println()
+	}
+
 	// Print summary
 	sectionColor.Println(" SUMMARY ")
 	fmt.Println()
@@ -53,10 +67,77 @@ func PrintTerminalReportWithBaseline(results []request.TestResult, baseline []re
 	fmt.Printf("  Blocked:      ")
 	successColor.Printf("%d\n", blocked)
 	fmt.Printf("  Unblocked:    ")
-	failColor.Printf("%d\n", total-blocked)
+	failColor.Printf("%d\n", total-blocked-challenges)
+	if challenges > 0 {
+		fmt.Printf("  Challenges:   ")
+		infoColor.Printf("%d\n", challenges)
+	}
 	fmt.Printf("  Block Rate:   %.2f%%\n", blockRate)
+	if request.ChallengesDominate(baseline) {
+		infoColor.Println("  Most responses were challenge pages (JS challenge/captcha); results may be unreliable.")
+	}
+	if cached := request.CachedCount(baseline); cached > 0 {
+		infoColor.Printf("  %d responses look cached (Age/X-Cache); the WAF may not have evaluated them.\n", cached)
+	}
+	if anomalous := request.AnomalousCount(baseline); anomalous > 0 {
+		failColor.Printf("  %d responses deviate from the clean baseline (-diff-baseline).\n", anomalous)
+	}
+	if stored := request.StoredCount(baseline); stored > 0 {
+		failColor.Printf("  %d payloads were stored: their canaries appeared on the view page (-view-url).\n", stored)
+	}
 	fmt.Println()
 
+	// Collapse repeated responses (typically the same block page) into clusters
+	if clusters := collapsedClusters(baseline); len(clusters) > 0 {
+		sectionColor.Println(" RESPONSE CLUSTERS ")
+		fmt.Println()
+		for _, cluster := range clusters {
+			fmt.Printf("  %s", cluster)
+			infoColor.Printf(" e.g. %s\n", cluster.Example())
+		}
+		fmt.Println()
+	}
+
+	// Bypasses that decode to the same payload share one root cause
+	if weaknesses := GroupWeaknesses(baseline); len(weaknesses) > 0 {
+		bypasses := 0
+		for _, weakness := range weaknesses {
+			bypasses += weakness.Bypasses
+		}
+		sectionColor.Println(" UNIQUE WEAKNESSES ")
+		fmt.Println()
+		fmt.Printf("  %d bypasses, %d unique weaknesses\n", bypasses, len(weaknesses))
+		for _, weakness := range weaknesses {
+			normalized := weakness.Normalized
+			if len(normalized) > 40 {
+				normalized = normalized[:37] + "..."
+			}
+			fmt.Printf("  %-40s %d bypasses via %d techniques\n", normalized, weakness.Bypasses, len(weakness.Techniques))
+		}
+		if advice := DecodeDepthAdvice(DecodeDepths(baseline)); advice != "" {
+			fmt.Printf("  %s\n", advice)
+		}
+		fmt.Println()
+	}
+
+	if phases := TimingBreakdown(baseline); phases != nil {
+		sectionColor.Println(" TIMING ")
+		fmt.Println()
+		for _, phase := range phases {
+			fmt.Printf("  %s\n", phase)
+		}
+		fmt.Println()
+	}
+
+	if recommendations := Recommendations(baseline); len(recommendations) > 0 {
+		sectionColor.Println(" RECOMMENDATIONS ")
+		fmt.Println()
+		for _, recommendation := range recommendations {
+			fmt.Printf("  - %s\n", FormatRecommendation(recommendation))
+		}
+		fmt.Println()
+	}
+
 	// Print detailed results
 	sectionColor.Println(" DETAILED RESULTS ")
 	fmt.Println()
@@ -80,8 +161,18 @@ func PrintTerminalReportWithBaseline(results []request.TestResult, baseline []re
 			result.StatusCode, result.ResponseTime.Milliseconds())
 
 		// Print blocked status with color
-		if result.Blocked {
-			successColor.Println("YES")
+		if result.Challenge {
+			infoColor.Println("CHALLENGE")
+		} else if result.Blocked {
+			if why := strings.TrimSpace(result.BlockReason + " " + result.RuleID); why != "" {
+				successColor.Printf("YES (%s)\n", why)
+			} else {
+				successColor.Println("YES")
+			}
+		} else if result.CandidateBypass {
+			failColor.Println("NO (candidate)")
+		} else if len(result.Deviations) > 0 {
+			failColor.Printf("NO (deviates: %s)\n", strings.Join(result.Deviations, ", "))
 		} else {
 			failColor.Println("NO")
 		}
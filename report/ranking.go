@@ -0,0 +1,93 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"obfuskit/request"
+)
+
+// EncodingRank is one row of the -compare-encodings table: how often the
+// variants of a single encoding got through the WAF
+type EncodingRank struct {
+	Encoding string `json:"encoding"`
+	// Variants counts the variants of this encoding that were sent, and
+	// Bypassed those with at least one request that got through
+	Variants int `json:"variants"`
+	Bypassed int `json:"bypassed"`
+	// Requests and Bypasses count individual requests (one per injection
+	// technique and variant)
+	Requests int `json:"requests"`
+	Bypasses int `json:"bypasses"`
+}
+
+// BypassRate is the fraction of requests that bypassed. It is measured per
+// request rather than per variant: with many injection techniques nearly
+// every variant gets through somewhere, which hides the differences.
+func (r EncodingRank) BypassRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Bypasses) / float64(r.Requests)
+}
+
+// RankEncodings groups results by the encoding of their variant and ranks
+// the encodings by bypass rate, then by bypassed variants. Results whose
+// variant has no encoding are skipped.
+func RankEncodings(results []request.TestResult) []EncodingRank {
+	var ranks []EncodingRank
+	index := make(map[string]int)
+	variantBypassed := make(map[string]bool)
+
+	for _, result := range results {
+		encoding := result.Variant.Encoding
+		if encoding == "" {
+			continue
+		}
+		i, ok := index[encoding]
+		if !ok {
+			i = len(ranks)
+			index[encoding] = i
+			ranks = append(ranks, EncodingRank{Encoding: encoding})
+		}
+		r := &ranks[i]
+		r.Requests++
+
+		bypassed, seen := variantBypassed[result.Variant.ID]
+		if !seen {
+			r.Variants++
+		}
+		if IsBypass(result) {
+			r.Bypasses++
+			if !bypassed {
+				r.Bypassed++
+			}
+			bypassed = true
+		}
+		variantBypassed[result.Variant.ID] = bypassed
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ri, rj := ranks[i].BypassRate(), ranks[j].BypassRate(); ri != rj {
+			return ri > rj
+		}
+		if ranks[i].Bypassed != ranks[j].Bypassed {
+			return ranks[i].Bypassed > ranks[j].Bypassed
+		}
+		return ranks[i].Encoding < ranks[j].Encoding
+	})
+	return ranks
+}
+
+// WriteEncodingRanking writes ranks as a table, best encoding first
+func WriteEncodingRanking(w io.Writer, ranks []EncodingRank) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tENCODING\tBYPASS RATE\tREQUESTS BYPASSED\tVARIANTS BYPASSED")
+	for i, rank := range ranks {
+		fmt.Fprintf(tw, "%d\t%s\t%.1f%%\t%d/%d\t%d/%d\n", i+1, rank.Encoding, rank.BypassRate()*100,
+			rank.Bypasses, rank.Requests, rank.Bypassed, rank.Variants)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,99 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"obfuskit/internal/evasions/encoders"
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+// Recipe is a self-contained description of one request that obfuskit sent:
+// where it went, how the variant was produced, the exact request, and how the
+// payload decodes. It is written as YAML so a finding can be shared and
+// re-run with -replay-recipe.
+type Recipe struct {
+	GeneratedAt  time.Time `yaml:"generated_at"`
+	Target       string    `yaml:"target"`
+	Seed         int64     `yaml:"seed,omitempty"`
+	EvasionLevel string    `yaml:"evasion_level,omitempty"`
+	AttackType   string    `yaml:"attack_type,omitempty"`
+	VariantID    string    `yaml:"variant_id,omitempty"`
+	// Payload is the exact variant that was sent
+	Payload     string `yaml:"payload"`
+	Technique   string `yaml:"technique"`
+	RequestPart string `yaml:"request_part"`
+	// StatusCode and Blocked are what the target answered originally
+	StatusCode int  `yaml:"status_code"`
+	Blocked    bool `yaml:"blocked"`
+	// Request is replayed verbatim, headers included
+	Request request.RecordedRequest `yaml:"request"`
+	// DecodeLadder shows the payload after each URL-decode pass
+	DecodeLadder []string `yaml:"decode_ladder,omitempty"`
+}
+
+// NewRecipe builds the recipe for result; cfg supplies the target, seed and
+// evasion level and may be nil
+func NewRecipe(result request.TestResult, cfg *types.Config) Recipe {
+	recipe := Recipe{
+		GeneratedAt:  time.Now().UTC(),
+		AttackType:   result.AttackType,
+		VariantID:    result.Variant.ID,
+		Payload:      result.Payload,
+		Technique:    result.EvasionTechnique,
+		RequestPart:  result.RequestPart,
+		StatusCode:   result.StatusCode,
+		Blocked:      result.Blocked,
+		Request:      request.RecordRequest(result.Request),
+		DecodeLadder: encoders.DecodeLadder(result.Payload, encoders.DefaultLadderPasses),
+	}
+	if cfg != nil {
+		recipe.Target = cfg.Target.URL
+		recipe.Seed = cfg.Seed
+		recipe.EvasionLevel = string(cfg.EvasionLevel)
+	}
+	if recipe.Target == "" {
+		recipe.Target = recipe.Request.URL
+	}
+	return recipe
+}
+
+// ExportRecipe writes the recipe for result to path
+func ExportRecipe(result request.TestResult, cfg *types.Config, path string) error {
+	data, err := yaml.Marshal(NewRecipe(result, cfg))
+	if err != nil {
+		return fmt.Errorf("failed to encode recipe: %w", err)
+	}
+	header := "# obfuskit attack recipe; re-run with: obfuskit -replay-recipe " + path + "\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write recipe: %w", err)
+	}
+	return nil
+}
+
+// LoadRecipe reads a recipe written by ExportRecipe
+func LoadRecipe(path string) (*Recipe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var recipe Recipe
+	if err := yaml.Unmarshal(data, &recipe); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if recipe.Request.Method == "" || recipe.Request.URL == "" {
+		return nil, fmt.Errorf("%s has no request to replay", path)
+	}
+	return &recipe, nil
+}
+
+// Replay re-sends the recipe's request and writes the raw response to w.
+// targetURL, when set, replaces the recorded scheme and host.
+func (r *Recipe) Replay(targetURL string, w io.Writer) error {
+	return request.Replay(r.Request, targetURL, w)
+}
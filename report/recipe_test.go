@@ -0,0 +1,84 @@
+package report
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+func TestRecipeRoundTripReproducesRequest(t *testing.T) {
+	type recorded struct {
+		method, path, rawQuery, body, header string
+	}
+	var mu sync.Mutex
+	var got []recorded
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, recorded{r.Method, r.URL.Path, r.URL.RawQuery, string(body), r.Header.Get("X-Test-Header")})
+	}))
+	defer server.Close()
+
+	// Produce a real result to export
+	payload := "%3Cscript%3Ealert(1)%3C%2Fscript%3E"
+	var result request.TestResult
+	for _, r := range request.NewFastHTTPBodyInjector().Inject(server.URL+"/api/items?id=7", payload, request.NewLoggerWithLevel(os.Stderr, request.LogLevelError)) {
+		if r.EvasionTechnique == "basic_json_param" {
+			result = r
+		}
+	}
+	if result.Request == nil {
+		t.Fatal("body injector sent no basic_json_param request")
+	}
+	result.Variant.ID = "abc123"
+	result.AttackType = "xss"
+	result.Request.Header.Set("X-Test-Header", "kept")
+	mu.Lock()
+	original := got[len(got)-1]
+	got = nil
+	mu.Unlock()
+	original.header = "kept"
+
+	config := &types.Config{Seed: 42, EvasionLevel: types.EvasionLevelMedium, Target: types.Target{URL: server.URL}}
+	path := filepath.Join(t.TempDir(), "recipe.yaml")
+	if err := ExportRecipe(result, config, path); err != nil {
+		t.Fatal(err)
+	}
+
+	recipe, err := LoadRecipe(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Seed != 42 || recipe.VariantID != "abc123" || recipe.Payload != payload || recipe.Technique != "basic_json_param" || recipe.RequestPart != "body" {
+		t.Errorf("recipe lost run details: %+v", recipe)
+	}
+	if len(recipe.DecodeLadder) < 2 || recipe.DecodeLadder[1] != "<script>alert(1)</script>" {
+		t.Errorf("decode ladder = %q, want the decoded script after one pass", recipe.DecodeLadder)
+	}
+
+	var out bytes.Buffer
+	if err := recipe.Replay("", &out); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 {
+		t.Fatalf("replay sent %d requests, want 1", len(got))
+	}
+	if got[0] != original {
+		t.Errorf("replayed request %+v, want %+v", got[0], original)
+	}
+	if !strings.Contains(out.String(), "200 OK") {
+		t.Errorf("replay output lacks the response status:\n%s", out.String())
+	}
+}
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"obfuskit/request"
+)
+
+// Remediations maps what made a request get through to the advice for
+// closing the gap: a variant's technique label (e.g. "overlong_utf8"), or its
+// encoding when the technique has no entry (e.g. "DoubleURLVariants"), and
+// the injector's request technique (e.g. "duplicate_query_param"). Keys are
+// matched case-insensitively. Change or extend the table to tune the advice.
+var Remediations = map[string]string{
+	// Encodings
+	"URLVariants":                 "Decode URL encoding (%XX) in every request part before matching signatures.",
+	"DoubleURLVariants":           "Enable recursive (multi-pass) URL decoding, or reject double-encoded sequences such as %25XX.",
+	"MixedCaseVariants":           "Match signatures case-insensitively, lowercasing input before matching.",
+	"Base64Variants":              "Decode base64 values before matching, or restrict the parameters that accept encoded blobs.",
+	"BestFitVariants":             "Map best-fit and look-alike Unicode characters to ASCII (NFKC) before matching, as the backend's code page conversion will.",
+	"HexVariants":                 "Decode hex escapes (\\xNN, 0xNN) before matching, or add rules for hex-escaped keywords.",
+	"HTMLVariants":                "Decode HTML entities (named, decimal and hex, with or without the ;) before matching XSS signatures.",
+	"OctalVariants":               "Decode octal escapes (\\NNN) before matching, or add rules for octal-escaped keywords.",
+	"UnicodeVariants":             "Decode \\uXXXX, %uXXXX and &#x...; escapes before matching; reject %uXXXX if the application never uses it.",
+	"UnixCmdVariants":             "Normalize shell syntax before matching: strip quotes and backslashes inside words, expand $IFS and brace expansion, and block $( ) and backticks.",
+	"WindowsCmdVariants":          "Strip ^ carets and %VAR:~x,y% substring expansions and collapse quoting before matching Windows command signatures.",
+	"PathTraversalVariants":       "Canonicalize paths (decode, normalize separators, resolve ./ and ../) before matching traversal rules.",
+	"UTF8Variants":                "Reject invalid and overlong UTF-8 and normalize Unicode before matching.",
+	"UTF7Variants":                "Reject UTF-7 input, and never let a request choose or sniff the UTF-7 charset.",
+	"InterleavedEncodingVariants": "Decode every supported encoding in one normalization pass rather than one encoding per rule.",
+
+	// Path traversal techniques
+	"overlong_utf8":            "Add a rule for overlong UTF-8 (%c0%ae, %c0%af, %e0%80%ae) or enable strict UTF-8 validation before path normalization.",
+	"percent_utf8":             "Decode percent-encoded UTF-8 sequences before normalizing paths.",
+	"unicode_normalization":    "Apply Unicode normalization (NFKC) and map look-alike dots and slashes before normalizing paths.",
+	"unicode_width":            "Strip direction marks and map fullwidth characters before normalizing paths.",
+	"non_standard_charset":     "Reject invalid UTF-8 bytes in paths.",
+	"encoded_backslash":        "Treat \\ and %5c as path separators when normalizing paths.",
+	"encoded_backslash_at":     "Decode %5c and parse the URL authority strictly before matching, rejecting @ after a backslash.",
+	"iis_backslash":            "Normalize backslashes and trailing dots the way IIS does before matching paths.",
+	"java_servlet_bypass":      "Strip ;-path parameters (..;/) before matching, as servlet containers do.",
+	"tomcat_bypass":            "Strip ;-path parameters before matching, as Tomcat does.",
+	"path_parameter_confusion": "Strip ;-path parameters before matching paths.",
+	"nginx_off_by_slash":       "Fix alias locations without a trailing slash, and match traversal after alias resolution.",
+	"php_null_byte_alternate":  "Reject null bytes (%00) and overlong paths in file parameters.",
+	"control_characters":       "Reject control characters in paths.",
+	"stacked_encoding":         "Decode repeatedly until the input stops changing before matching.",
+	"nested_encoding":          "Decode repeatedly until the input stops changing before matching.",
+	"html_entity_encoding":     "Decode HTML entities in paths before matching traversal rules.",
+	"multi_protocol":           "Block file:// and other protocol handler prefixes in file parameters.",
+
+	// Request techniques
+	"duplicate_query_param":   "Inspect every occurrence of a repeated parameter (HTTP parameter pollution), not only the first or last.",
+	"duplicate_form_param":    "Inspect every occurrence of a repeated parameter (HTTP parameter pollution), not only the first or last.",
+	"duplicate_header":        "Inspect every instance of a repeated header.",
+	"semicolon_split_param":   "Treat ; as a query separator where the backend does, or reject it in query strings.",
+	"semicolon_separator":     "Treat ; as a query separator where the backend does, or reject it in query strings.",
+	"multi_param_split":       "Inspect the request's parameters concatenated as well as one by one.",
+	"param_name_case":         "Match parameter names case-insensitively.",
+	"chunked_encoding":        "Reassemble chunked request bodies before inspecting them.",
+	"multiple_content_length": "Reject requests with conflicting Content-Length headers.",
+	"content_type_mismatch":   "Inspect bodies by their actual content, and reject bodies that do not match the declared Content-Type.",
+	"header_line_folding":     "Reject obsolete header line folding.",
+	"manual_line_folding":     "Reject obsolete header line folding.",
+	"pipelined_query":         "Inspect every request on a keep-alive connection, not only the first.",
+	"pipelined_body":          "Inspect every request on a keep-alive connection, not only the first.",
+}
+
+// Recommendation is one piece of remediation advice and the bypasses behind it
+type Recommendation struct {
+	Advice string `json:"advice"`
+	// Causes are the Remediations keys that led to the advice
+	Causes   []string `json:"causes"`
+	Bypasses int      `json:"bypasses"`
+	// Example is one bypassing payload the advice addresses
+	Example string `json:"example"`
+}
+
+// Recommendations maps the bypasses in results to remediation advice from
+// Remediations, most bypasses first. A bypass counts toward the advice for
+// its variant (its technique, else its encoding) and toward the advice for
+// the injector's request technique.
+func Recommendations(results []request.TestResult) []Recommendation {
+	causes := make(map[string]string, len(Remediations))
+	for cause := range Remediations {
+		causes[strings.ToLower(cause)] = cause
+	}
+	// lookup returns the first of candidates with an entry in Remediations
+	lookup := func(candidates ...string) string {
+		for _, candidate := range candidates {
+			if cause, ok := causes[strings.ToLower(candidate)]; ok {
+				return cause
+			}
+		}
+		return ""
+	}
+
+	index := make(map[string]int)
+	var recommendations []Recommendation
+	for _, result := range results {
+		if !IsBypass(result) {
+			continue
+		}
+		for _, cause := range []string{
+			lookup(result.Variant.Technique, result.Variant.Encoding),
+			lookup(result.EvasionTechnique),
+		} {
+			if cause == "" {
+				continue
+			}
+			advice := Remediations[cause]
+			i, ok := index[advice]
+			if !ok {
+				i = len(recommendations)
+				index[advice] = i
+				recommendations = append(recommendations, Recommendation{Advice: advice, Example: result.Payload})
+			}
+			if !slices.Contains(recommendations[i].Causes, cause) {
+				recommendations[i].Causes = append(recommendations[i].Causes, cause)
+			}
+			recommendations[i].Bypasses++
+		}
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Bypasses > recommendations[j].Bypasses
+	})
+	return recommendations
+}
+
+// FormatRecommendation describes one recommendation on a line, e.g.
+// "Reject obsolete header line folding. (header_line_folding: 3 bypasses)"
+func FormatRecommendation(recommendation Recommendation) string {
+	return fmt.Sprintf("%s (%s: %d bypasses)", recommendation.Advice, strings.Join(recommendation.Causes, ", "), recommendation.Bypasses)
+}
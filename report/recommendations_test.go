@@ -0,0 +1,51 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"obfuskit/request"
+	"obfuskit/types"
+)
+
+func TestRecommendationsForOverlongUTF8Bypass(t *testing.T) {
+	results := []request.TestResult{
+		{
+			Payload:          "..%c0%af..%c0%af..%c0%afetc/passwd",
+			AttackType:       "path",
+			EvasionTechnique: "query_param",
+			StatusCode:       200,
+			Variant:          types.Variant{Technique: "overlong_utf8", Encoding: "PathTraversalVariants"},
+		},
+		{
+			Payload:    "../../../etc/passwd",
+			AttackType: "path",
+			StatusCode: 403,
+			Blocked:    true,
+			Variant:    types.Variant{Technique: "basic", Encoding: "PathTraversalVariants"},
+		},
+	}
+	advice := Remediations["overlong_utf8"]
+
+	recommendations := Recommendations(results)
+	if len(recommendations) != 1 {
+		t.Fatalf("got %d recommendations, want 1 for the one bypass: %+v", len(recommendations), recommendations)
+	}
+	if got := recommendations[0]; got.Advice != advice || got.Bypasses != 1 || got.Example != results[0].Payload {
+		t.Errorf("recommendation = %+v, want the overlong UTF-8 advice for one bypass", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := GenerateHTMLReport(results, nil, path); err != nil {
+		t.Fatal(err)
+	}
+	html, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), "<h2>Recommendations</h2>") || !strings.Contains(string(html), "overlong UTF-8 (%c0%ae, %c0%af") {
+		t.Errorf("HTML report lacks the overlong UTF-8 recommendation")
+	}
+}
@@ -0,0 +1,245 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"obfuskit/internal/redact"
+)
+
+// Redacted replaces secret values (API keys, URL credentials) in snapshots
+const Redacted = redact.Mask
+
+// ConfigSnapshot is the effective configuration of a run, embedded in every
+// report so results can be audited later. Secrets are already redacted.
+type ConfigSnapshot struct {
+	Tags         []string `json:"tags,omitempty"`
+	Action       string   `json:"action"`
+	AttackType   string   `json:"attack_type"`
+	AttackTypes  []string `json:"attack_types,omitempty"`
+	EvasionLevel string   `json:"evasion_level"`
+	// EncodingLevels are the per-encoding evasion level overrides
+	EncodingLevels map[string]string `json:"encoding_levels,omitempty"`
+	Seed           int64             `json:"seed"`
+
+	PayloadMethod      string   `json:"payload_method,omitempty"`
+	Encoding           string   `json:"encoding,omitempty"`
+	PayloadSource      string   `json:"payload_source,omitempty"`
+	PayloadFile        string   `json:"payload_file,omitempty"`
+	PayloadDir         string   `json:"payload_dir,omitempty"`
+	SensitiveFiles     string   `json:"sensitive_files,omitempty"`
+	SavePayloads       bool     `json:"save_payloads,omitempty"`
+	MaxTraversalDepth  int      `json:"max_traversal_depth,omitempty"`
+	TraversalTarget    string   `json:"traversal_target,omitempty"`
+	DistinctTechniques int      `json:"distinct_techniques,omitempty"`
+	RequireTechniques  []string `json:"require_techniques,omitempty"`
+	OnlyTechniques     []string `json:"only_techniques,omitempty"`
+	ExcludeTechniques  []string `json:"exclude_techniques,omitempty"`
+	StrictVariants     bool     `json:"strict_variants,omitempty"`
+	Exhaustive         bool     `json:"exhaustive,omitempty"`
+	// PayloadFiles are the per-attack-type payload files overriding PayloadDir
+	PayloadFiles map[string]string `json:"payload_files,omitempty"`
+
+	TargetMethod        string   `json:"target_method,omitempty"`
+	TargetURL           string   `json:"target_url,omitempty"`
+	TargetURLs          []string `json:"target_urls,omitempty"`
+	TargetFile          string   `json:"target_file,omitempty"`
+	Host                string   `json:"host,omitempty"`
+	ParamNames          []string `json:"param_names,omitempty"`
+	UserAgent           string   `json:"user_agent,omitempty"`
+	RotateUserAgents    bool     `json:"rotate_user_agents,omitempty"`
+	UserAgentSeed       int64    `json:"user_agent_seed,omitempty"`
+	BodyFile            string   `json:"body_file,omitempty"`
+	ContentType         string   `json:"content_type,omitempty"`
+	CookieJar           bool     `json:"cookie_jar,omitempty"`
+	CacheBust           bool     `json:"cache_bust,omitempty"`
+	FollowRedirects     bool     `json:"follow_redirects,omitempty"`
+	MaxRedirects        int      `json:"max_redirects,omitempty"`
+	ResetIsBlock        bool     `json:"reset_is_block,omitempty"`
+	BlockSignaturesFile string   `json:"block_signatures_file,omitempty"`
+	Timing              bool     `json:"timing,omitempty"`
+	ReadBufferSize      int      `json:"read_buffer_size,omitempty"`
+	MaxResponseSize     int      `json:"max_response_size,omitempty"`
+	Diversify           bool     `json:"diversify,omitempty"`
+	DiversifyPadding    bool     `json:"diversify_padding,omitempty"`
+	SourceIPs           []string `json:"source_ips,omitempty"`
+	Scope               []string `json:"scope,omitempty"`
+	// DisabledTechniques lists the protocol tricks turned off for safety
+	DisabledTechniques []string `json:"disabled_techniques,omitempty"`
+	// HeaderNameInjection records that payloads were also put in header names
+	HeaderNameInjection bool     `json:"header_name_injection,omitempty"`
+	Pipeline            bool     `json:"pipeline,omitempty"`
+	SplitParams         []string `json:"split_params,omitempty"`
+	SplitAt             []int    `json:"split_at,omitempty"`
+	MaxRequests         int      `json:"max_requests,omitempty"`
+	PerHostConns        int      `json:"per_host_conns,omitempty"`
+	StopOnFirstBypass   bool     `json:"stop_on_first_bypass,omitempty"`
+	Shuffle             bool     `json:"shuffle,omitempty"`
+	Sink                string   `json:"sink,omitempty"`
+	AdaptiveEscalate    bool     `json:"adaptive_escalate,omitempty"`
+	ProbeNormalization  bool     `json:"probe_normalization,omitempty"`
+	DiffBaseline        string   `json:"diff_baseline,omitempty"`
+	ViewURL             string   `json:"view_url,omitempty"`
+	MaxEscalations      int      `json:"max_escalations,omitempty"`
+
+	InterestingStatusCodes map[string][]int `json:"interesting_status_codes,omitempty"`
+	ReportType             string           `json:"report_type,omitempty"`
+	// Filters lists the active result filters as name=value pairs
+	Filters             []string `json:"filters,omitempty"`
+	AdaptiveConcurrency bool     `json:"adaptive_concurrency,omitempty"`
+	Fingerprinting      bool     `json:"fingerprinting,omitempty"`
+
+	AI *AISnapshot `json:"ai,omitempty"`
+}
+
+// AISnapshot records the AI provider settings of a run
+type AISnapshot struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	APIKey   string `json:"api_key,omitempty"`
+	// Required records that the run would have failed without the provider
+	Required bool `json:"required,omitempty"`
+}
+
+// Lines renders the snapshot as "Name: value" lines for text report headers,
+// skipping unset fields
+func (s ConfigSnapshot) Lines() []string {
+	var lines []string
+	add := func(name string, value interface{}) {
+		text := fmt.Sprint(value)
+		switch v := value.(type) {
+		case []string:
+			text = strings.Join(v, ", ")
+		case []int:
+			parts := make([]string, len(v))
+			for i, n := range v {
+				parts[i] = fmt.Sprint(n)
+			}
+			text = strings.Join(parts, ", ")
+		case bool:
+			if !v {
+				return
+			}
+		case int:
+			if v == 0 {
+				return
+			}
+		case int64:
+			if v == 0 {
+				return
+			}
+		}
+		if text != "" {
+			lines = append(lines, name+": "+text)
+		}
+	}
+
+	add("Tags", s.Tags)
+	add("Action", s.Action)
+	add("Attack Type", s.AttackType)
+	add("Attack Types", s.AttackTypes)
+	add("Evasion Level", s.EvasionLevel)
+	if len(s.EncodingLevels) > 0 {
+		var levels []string
+		for encoding, level := range s.EncodingLevels {
+			levels = append(levels, encoding+"="+level)
+		}
+		sort.Strings(levels)
+		add("Encoding Levels", levels)
+	}
+	lines = append(lines, fmt.Sprintf("Seed: %d", s.Seed))
+	add("Payload Method", s.PayloadMethod)
+	add("Encoding", s.Encoding)
+	add("Payload Source", s.PayloadSource)
+	add("Payload File", s.PayloadFile)
+	add("Payload Dir", s.PayloadDir)
+	if len(s.PayloadFiles) > 0 {
+		var files []string
+		for attackType, file := range s.PayloadFiles {
+			files = append(files, attackType+"="+file)
+		}
+		sort.Strings(files)
+		add("Payload Files", files)
+	}
+	add("Sensitive Files", s.SensitiveFiles)
+	add("Save Payloads", s.SavePayloads)
+	add("Max Traversal Depth", s.MaxTraversalDepth)
+	add("Traversal Target", s.TraversalTarget)
+	add("Distinct Techniques", s.DistinctTechniques)
+	add("Required Techniques", s.RequireTechniques)
+	add("Only Techniques", s.OnlyTechniques)
+	add("Excluded Techniques", s.ExcludeTechniques)
+	add("Strict Variants", s.StrictVariants)
+	add("Exhaustive", s.Exhaustive)
+	add("Target Method", s.TargetMethod)
+	add("Target URL", s.TargetURL)
+	add("Target URLs", s.TargetURLs)
+	add("Target File", s.TargetFile)
+	add("Host", s.Host)
+	add("Params", s.ParamNames)
+	add("User-Agent", s.UserAgent)
+	add("Rotate User-Agents", s.RotateUserAgents)
+	add("User-Agent Seed", s.UserAgentSeed)
+	add("Body File", s.BodyFile)
+	add("Content-Type", s.ContentType)
+	add("Cookie Jar", s.CookieJar)
+	add("Cache Bust", s.CacheBust)
+	add("Follow Redirects", s.FollowRedirects)
+	add("Max Redirects", s.MaxRedirects)
+	add("Reset Is Block", s.ResetIsBlock)
+	add("Block Signatures File", s.BlockSignaturesFile)
+	add("Timing", s.Timing)
+	add("Read Buffer Size", s.ReadBufferSize)
+	add("Max Response Size", s.MaxResponseSize)
+	add("Diversify", s.Diversify)
+	add("Diversify Padding", s.DiversifyPadding)
+	add("Source IPs", s.SourceIPs)
+	add("Scope", s.Scope)
+	add("Disabled Techniques", s.DisabledTechniques)
+	add("Header Name Injection", s.HeaderNameInjection)
+	add("Pipeline", s.Pipeline)
+	add("Split Params", s.SplitParams)
+	add("Split At", s.SplitAt)
+	add("Max Requests", s.MaxRequests)
+	add("Per-Host Connections", s.PerHostConns)
+	add("Stop On First Bypass", s.StopOnFirstBypass)
+	add("Shuffle", s.Shuffle)
+	add("Sink", s.Sink)
+	add("Adaptive Escalate", s.AdaptiveEscalate)
+	add("Max Escalations", s.MaxEscalations)
+	add("Probe Normalization", s.ProbeNormalization)
+	add("Diff Baseline", s.DiffBaseline)
+	add("View URL", s.ViewURL)
+	if len(s.InterestingStatusCodes) > 0 {
+		attackTypes := make([]string, 0, len(s.InterestingStatusCodes))
+		for attackType := range s.InterestingStatusCodes {
+			attackTypes = append(attackTypes, attackType)
+		}
+		sort.Strings(attackTypes)
+		var codes []string
+		for _, attackType := range attackTypes {
+			codes = append(codes, fmt.Sprintf("%s %v", attackType, s.InterestingStatusCodes[attackType]))
+		}
+		add("Interesting Status Codes", codes)
+	}
+	add("Report Type", s.ReportType)
+	add("Filters", s.Filters)
+	add("Adaptive Concurrency", s.AdaptiveConcurrency)
+	add("Fingerprinting", s.Fingerprinting)
+	if s.AI != nil {
+		ai := s.AI.Provider
+		if s.AI.Model != "" {
+			ai += " (" + s.AI.Model + ")"
+		}
+		if s.AI.APIKey != "" {
+			ai += ", key " + s.AI.APIKey
+		}
+		if s.AI.Required {
+			ai += ", required"
+		}
+		add("AI", ai)
+	}
+	return lines
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"obfuskit/request"
+)
+
+// PhaseTiming summarizes one phase of the timed requests' response times
+// (see request.Timing), in milliseconds
+type PhaseTiming struct {
+	Phase     string  `json:"phase"`
+	AverageMS float64 `json:"average_ms"`
+	MaxMS     float64 `json:"max_ms"`
+}
+
+// String describes the phase as in "TTFB      avg 12.40ms  max 40.12ms"
+func (p PhaseTiming) String() string {
+	return fmt.Sprintf("%-9s avg %.2fms  max %.2fms", p.Phase, p.AverageMS, p.MaxMS)
+}
+
+// TimingBreakdown averages the phases of the results that carry a timing
+// breakdown (-timing), phase by phase in the order they happen.
+// It returns nil when no result was timed.
+func TimingBreakdown(results []request.TestResult) []PhaseTiming {
+	phases := []PhaseTiming{{Phase: "DNS"}, {Phase: "Connect"}, {Phase: "TLS"}, {Phase: "TTFB"}, {Phase: "Transfer"}}
+	timed := 0
+	for _, result := range results {
+		if result.Timing.IsZero() {
+			continue
+		}
+		timed++
+		for i, duration := range []time.Duration{result.Timing.DNS, result.Timing.Connect, result.Timing.TLS, result.Timing.TTFB, result.Timing.Transfer} {
+			ms := Milliseconds(duration)
+			phases[i].AverageMS += ms
+			if ms > phases[i].MaxMS {
+				phases[i].MaxMS = ms
+			}
+		}
+	}
+	if timed == 0 {
+		return nil
+	}
+	for i := range phases {
+		phases[i].AverageMS /= float64(timed)
+	}
+	return phases
+}
+
+// Milliseconds returns d in fractional milliseconds, since the phases of a
+// local request are often well under one
+func Milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"slices"
+	"sort"
+
+	"obfuskit/internal/canon"
+	"obfuskit/request"
+)
+
+// Weakness is one underlying WAF weakness: every bypass whose payload
+// normalizes to the same attack string, however it was encoded
+type Weakness struct {
+	AttackType string `json:"attack_type,omitempty"`
+	// Normalized is the decoded, lowercased payload shared by the bypasses
+	Normalized string `json:"normalized"`
+	Bypasses   int    `json:"bypasses"`
+	// Techniques lists the distinct evasion techniques that got through
+	Techniques []string `json:"techniques"`
+	// Payloads keeps up to MaxClusterRepresentatives raw bypassing payloads
+	Payloads []string `json:"payloads"`
+}
+
+// IsBypass reports whether a result got through the WAF, as a plain or a
+// candidate bypass
+func IsBypass(result request.TestResult) bool {
+	switch request.Classify(result, result.AttackType, nil) {
+	case request.OutcomeBypassed, request.OutcomeCandidateBypass:
+		return true
+	}
+	return result.CandidateBypass
+}
+
+// GroupWeaknesses groups the bypasses in results by the normalized form of
+// their payload, so ten encodings of the same <script> that all get through
+// count as one weakness. Weaknesses are returned most bypasses first.
+func GroupWeaknesses(results []request.TestResult) []Weakness {
+	var weaknesses []Weakness
+	index := make(map[string]int)
+	techniques := make(map[string]map[string]bool)
+
+	for _, result := range results {
+		if !IsBypass(result) {
+			continue
+		}
+		normalized := canon.Normalize(result.Payload)
+		key := result.AttackType + "\x00" + normalized
+		i, ok := index[key]
+		if !ok {
+			i = len(weaknesses)
+			index[key] = i
+			techniques[key] = make(map[string]bool)
+			weaknesses = append(weaknesses, Weakness{AttackType: result.AttackType, Normalized: normalized})
+		}
+		w := &weaknesses[i]
+		w.Bypasses++
+		if result.EvasionTechnique != "" && !techniques[key][result.EvasionTechnique] {
+			techniques[key][result.EvasionTechnique] = true
+			w.Techniques = append(w.Techniques, result.EvasionTechnique)
+		}
+		if len(w.Payloads) < MaxClusterRepresentatives && !slices.Contains(w.Payloads, result.Payload) {
+			w.Payloads = append(w.Payloads, result.Payload)
+		}
+	}
+
+	sort.SliceStable(weaknesses, func(i, j int) bool {
+		return weaknesses[i].Bypasses > weaknesses[j].Bypasses
+	})
+	return weaknesses
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"testing"
+
+	"obfuskit/request"
+)
+
+func TestGroupWeaknessesCollapsesEncodingsOfOnePayload(t *testing.T) {
+	encodings := map[string]string{
+		"raw":         "<script>alert(1)</script>",
+		"mixed_case":  "<ScRiPt>alert(1)</sCrIpT>",
+		"url":         "%3Cscript%3Ealert(1)%3C%2Fscript%3E",
+		"double_url":  "%253Cscript%253Ealert(1)%253C%252Fscript%253E",
+		"html_entity": "&#x3c;script&#x3e;alert(1)&#x3c;/script&#x3e;",
+		"unicode":     "\\u003cscript\\u003ealert(1)\\u003c/script\\u003e",
+		"base64":      "PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==",
+		"fullwidth":   "＜ｓｃｒｉｐｔ＞alert(1)＜/ｓｃｒｉｐｔ＞",
+	}
+	var results []request.TestResult
+	for technique, payload := range encodings {
+		results = append(results, request.TestResult{
+			Payload: payload, EvasionTechnique: technique, AttackType: "xss", StatusCode: 200,
+		})
+	}
+	results = append(results,
+		request.TestResult{Payload: "<svg onload=alert(1)>", EvasionTechnique: "raw", AttackType: "xss", StatusCode: 200},
+		request.TestResult{Payload: "%3Csvg onload=alert(1)%3E", EvasionTechnique: "url", AttackType: "xss", StatusCode: 403, Blocked: true},
+	)
+
+	weaknesses := GroupWeaknesses(results)
+	if len(weaknesses) != 2 {
+		t.Fatalf("got %d unique weaknesses, want 2: %+v", len(weaknesses), weaknesses)
+	}
+	script := weaknesses[0]
+	if script.Normalized != "<script>alert(1)</script>" {
+		t.Errorf("normalized form = %q, want <script>alert(1)</script>", script.Normalized)
+	}
+	if script.Bypasses != len(encodings) || len(script.Techniques) != len(encodings) {
+		t.Errorf("script weakness has %d bypasses via %d techniques, want %d each",
+			script.Bypasses, len(script.Techniques), len(encodings))
+	}
+	if weaknesses[1].Bypasses != 1 {
+		t.Errorf("blocked svg variant counted as a bypass: %+v", weaknesses[1])
+	}
+}
@@ -0,0 +1,124 @@
+package request
+
+import (
+	"sync"
+)
+
+const (
+	// DefaultAdaptiveWindow is the number of completed requests evaluated per adjustment
+	DefaultAdaptiveWindow = 10
+	// DefaultAdaptiveErrorThreshold is the error rate above which concurrency is halved
+	DefaultAdaptiveErrorThreshold = 0.1
+)
+
+// AdaptiveConcurrency is an AIMD (additive increase, multiplicative decrease)
+// limiter. Callers Acquire a slot before sending a request and Release it with
+// the outcome; after every window of completed requests the limit is halved if
+// the error rate exceeded the threshold, or raised by one otherwise, always
+// staying between 1 and the configured maximum.
+type AdaptiveConcurrency struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	max      int
+	limit    int
+	inFlight int
+
+	window         int
+	errorThreshold float64
+	completed      int
+	failed         int
+
+	logger *Logger
+}
+
+// NewAdaptiveConcurrency creates a limiter that starts at, and never exceeds, max
+func NewAdaptiveConcurrency(max int, logger *Logger) *AdaptiveConcurrency {
+	if max < 1 {
+		max = 1
+	}
+	if logger == nil {
+		logger = defaultLogger
+	}
+	a := &AdaptiveConcurrency{
+		max:            max,
+		limit:          max,
+		window:         DefaultAdaptiveWindow,
+		errorThreshold: DefaultAdaptiveErrorThreshold,
+		logger:         logger,
+	}
+	a.cond = sync.NewCond(&a.mu)
+	return a
+}
+
+// Acquire blocks until a request slot is available under the current limit
+func (a *AdaptiveConcurrency) Acquire() {
+	a.mu.Lock()
+	for a.inFlight >= a.limit {
+		a.cond.Wait()
+	}
+	a.inFlight++
+	a.mu.Unlock()
+}
+
+// Release frees a slot and records whether the request failed (error, timeout
+// or a server-side overload status)
+func (a *AdaptiveConcurrency) Release(failed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.inFlight--
+	a.completed++
+	if failed {
+		a.failed++
+	}
+
+	if a.completed >= a.window {
+		errorRate := float64(a.failed) / float64(a.completed)
+		previous := a.limit
+
+		if errorRate > a.errorThreshold {
+			a.limit = a.limit / 2
+			if a.limit < 1 {
+				a.limit = 1
+			}
+		} else if a.limit < a.max {
+			a.limit++
+		}
+
+		if a.limit < previous {
+			a.logger.warn.Printf("Adaptive concurrency: error rate %.0f%%, reducing concurrency %d -> %d",
+				errorRate*100, previous, a.limit)
+		} else if a.limit > previous {
+			a.logger.info.Printf("Adaptive concurrency: target healthy, increasing concurrency %d -> %d",
+				previous, a.limit)
+		}
+
+		a.completed = 0
+		a.failed = 0
+	}
+
+	a.cond.Broadcast()
+}
+
+// Limit returns the current effective concurrency
+func (a *AdaptiveConcurrency) Limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.limit
+}
+
+// IsFailure reports whether an injector's results indicate the target is
+// struggling: no response at all, or a 5xx status that isn't a candidate
+// bypass or a challenge page
+func IsFailure(results []TestResult) bool {
+	if len(results) == 0 {
+		return true
+	}
+	for _, result := range results {
+		if result.StatusCode >= 500 && !result.CandidateBypass && !result.Challenge {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveConcurrencySettlesBelowFailureLevel(t *testing.T) {
+	const (
+		maxWorkers = 16
+		failAbove  = 4
+		requests   = 600
+	)
+
+	var inFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		time.Sleep(5 * time.Millisecond)
+		if current > failAbove {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	limiter := NewAdaptiveConcurrency(maxWorkers, NewLoggerWithLevel(devNull, LogLevelError))
+	client := server.Client()
+
+	var mu sync.Mutex
+	var lateLimits []int
+	var sent int32
+	var wg sync.WaitGroup
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.AddInt32(&sent, 1) <= requests {
+				limiter.Acquire()
+				resp, err := client.Get(server.URL)
+				failed := err != nil
+				if resp != nil {
+					failed = failed || resp.StatusCode >= 500
+					resp.Body.Close()
+				}
+				limiter.Release(failed)
+
+				if atomic.LoadInt32(&sent) > requests/2 {
+					mu.Lock()
+					lateLimits = append(lateLimits, limiter.Limit())
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(lateLimits) == 0 {
+		t.Fatal("no limits recorded")
+	}
+
+	// AIMD keeps probing one step past the failure level before halving, so the
+	// limit oscillates around failAbove rather than sitting strictly under it
+	total, lowest := 0, maxWorkers
+	for _, l := range lateLimits {
+		total += l
+		if l < lowest {
+			lowest = l
+		}
+	}
+	average := float64(total) / float64(len(lateLimits))
+	if average > failAbove+1 {
+		t.Errorf("average concurrency in second half = %.1f, want <= %d", average, failAbove+1)
+	}
+	if lowest >= failAbove {
+		t.Errorf("concurrency never backed off below the failure level: lowest %d", lowest)
+	}
+	if final := limiter.Limit(); final >= maxWorkers {
+		t.Errorf("concurrency never backed off: limit %d", final)
+	}
+}
+
+func TestAdaptiveConcurrencyRecovers(t *testing.T) {
+	limiter := NewAdaptiveConcurrency(8, nil)
+
+	// A fully failing window halves the limit
+	for i := 0; i < DefaultAdaptiveWindow; i++ {
+		limiter.Acquire()
+		limiter.Release(true)
+	}
+	if got := limiter.Limit(); got != 4 {
+		t.Fatalf("limit after failing window = %d, want 4", got)
+	}
+
+	// Healthy windows raise it back one step at a time, capped at max
+	for round := 0; round < 10; round++ {
+		for i := 0; i < DefaultAdaptiveWindow; i++ {
+			limiter.Acquire()
+			limiter.Release(false)
+		}
+	}
+	if got := limiter.Limit(); got != 8 {
+		t.Errorf("limit after healthy windows = %d, want 8", got)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		results  []TestResult
+		expected bool
+	}{
+		{"no results", nil, true},
+		{"ok", []TestResult{{StatusCode: 200}}, false},
+		{"blocked is not failure", []TestResult{{StatusCode: 403}}, false},
+		{"server error", []TestResult{{StatusCode: 200}, {StatusCode: 503}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsFailure(tt.results); got != tt.expected {
+				t.Errorf("IsFailure() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
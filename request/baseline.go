@@ -0,0 +1,127 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// DefaultLengthTolerance is how far, as a fraction of the baseline body's
+// length, a response body may grow or shrink before it counts as deviating
+const DefaultLengthTolerance = 0.1
+
+// Deviations a response can show against a Baseline
+const (
+	DeviationStatus    = "status"
+	DeviationLength    = "length"
+	DeviationError     = "error"
+	DeviationReflected = "reflected"
+)
+
+// errorSignatures are response fragments that betray an application error:
+// one missing from the baseline but present in a response means the payload
+// broke something
+var errorSignatures = []string{
+	"sql syntax",
+	"syntax error",
+	"unclosed quotation mark",
+	"sqlstate",
+	"ora-0",
+	"odbc",
+	"exception",
+	"stack trace",
+	"traceback (most recent call last)",
+	"fatal error",
+	"warning: ",
+	"internal server error",
+}
+
+// Baseline is a clean response captured from the target, which every
+// result's response is compared with to flag the payloads that changed the
+// application's behavior, whatever the status code says
+type Baseline struct {
+	StatusCode int    `json:"status_code"`
+	Body       string `json:"body"`
+	// LengthTolerance overrides DefaultLengthTolerance when positive
+	LengthTolerance float64 `json:"length_tolerance,omitempty"`
+}
+
+// LoadBaseline reads a baseline JSON file: {"status_code": 200, "body": "..."}
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	if baseline.StatusCode == 0 {
+		return nil, fmt.Errorf("baseline %s has no status_code", path)
+	}
+	return &baseline, nil
+}
+
+// Deviations lists how result's response differs from the baseline: another
+// status code, a body length outside the tolerance, an error signature the
+// baseline lacks, or the payload reflected. Bodies are compared as captured,
+// up to MaxCapturedBodySize bytes. A nil baseline finds none.
+func (b *Baseline) Deviations(result TestResult) []string {
+	if b == nil {
+		return nil
+	}
+	var deviations []string
+	if result.StatusCode != b.StatusCode {
+		deviations = append(deviations, DeviationStatus)
+	}
+
+	baselineBody := b.Body
+	if len(baselineBody) > MaxCapturedBodySize {
+		baselineBody = baselineBody[:MaxCapturedBodySize]
+	}
+	tolerance := b.LengthTolerance
+	if tolerance <= 0 {
+		tolerance = DefaultLengthTolerance
+	}
+	if math.Abs(float64(len(result.ResponseBody)-len(baselineBody))) > tolerance*float64(len(baselineBody)) {
+		deviations = append(deviations, DeviationLength)
+	}
+
+	body := strings.ToLower(result.ResponseBody)
+	baselineLower := strings.ToLower(baselineBody)
+	for _, signature := range errorSignatures {
+		if strings.Contains(body, signature) && !strings.Contains(baselineLower, signature) {
+			deviations = append(deviations, DeviationError)
+			break
+		}
+	}
+
+	if reflects(result, b.Body) {
+		deviations = append(deviations, DeviationReflected)
+	}
+	return deviations
+}
+
+// reflects reports whether result's response contains the payload as sent
+// or as generated from, and the baseline body does not
+func reflects(result TestResult, baselineBody string) bool {
+	for _, payload := range []string{result.Payload, result.Variant.SourcePayload} {
+		if payload != "" && strings.Contains(result.ResponseBody, payload) && !strings.Contains(baselineBody, payload) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnomalousCount returns how many results deviate from the baseline
+func AnomalousCount(results []TestResult) int {
+	anomalous := 0
+	for _, result := range results {
+		if len(result.Deviations) > 0 {
+			anomalous++
+		}
+	}
+	return anomalous
+}
@@ -0,0 +1,51 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBaselineFlagsOnlyDivergentResponses(t *testing.T) {
+	const page = "<html><body>Search results: none</body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "UNION") {
+			w.Write([]byte("<html><body>You have an error in your SQL syntax near 'UNION'</body></html>"))
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte(`{"status_code": 200, "body": "`+page+`"}`), 0644); err != nil {
+		t.Fatalf("write baseline: %v", err)
+	}
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error: %v", err)
+	}
+
+	const divergent = "1 UNION SELECT 1"
+	logger := NewLoggerWithLevel(os.Stderr, LogLevelError)
+	injector := NewFastHTTPQueryInjector()
+	for _, payload := range []string{"hello", divergent, "1 OR 1=1"} {
+		results := injector.Inject(server.URL, payload, logger)
+		if len(results) == 0 {
+			t.Fatalf("%q: no results", payload)
+		}
+		for _, result := range results {
+			deviations := baseline.Deviations(result)
+			if payload == divergent && !slices.Contains(deviations, DeviationError) {
+				t.Errorf("%q %s: deviations %q, want an error deviation", payload, result.EvasionTechnique, deviations)
+			}
+			if payload != divergent && len(deviations) > 0 {
+				t.Errorf("%q %s: flagged anomalous (%q) for the baseline response", payload, result.EvasionTechnique, deviations)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package request
+
+import (
+	"obfuskit/internal/waf"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Block reasons that are not a WAF name
+const (
+	// BlockReasonConnectionReset marks a result blocked because the
+	// connection was reset or closed before a response arrived (see
+	// -reset-is-block)
+	BlockReasonConnectionReset = "connection_reset"
+	// BlockReasonRedirect marks a redirect to a block page (see IsBlockRedirect)
+	BlockReasonRedirect = "block_page_redirect"
+	// BlockReasonSignature marks a response body matching a block page
+	// signature (see BlockSignatures)
+	BlockReasonSignature = "block_signature"
+)
+
+// sendOutcome is what sending a request found out besides its response
+type sendOutcome struct {
+	// reset is set when the peer dropped the connection after the request
+	// was written and ResetIsBlock counts that as a block; the response is
+	// then empty
+	reset bool
+	// signature is set when the response body matched a block page
+	// signature (see BlockSignatures)
+	signature bool
+	// timing is the request's timing in timing mode
+	timing Timing
+}
+
+// statusCode returns resp's status code, or 0 when no response arrived
+// because the connection was reset
+func (s sendOutcome) statusCode(resp *fasthttp.Response) int {
+	if s.reset {
+		return 0
+	}
+	return resp.StatusCode()
+}
+
+// blocked reports whether the request was blocked: by a reset, a block page
+// signature or a blocking response (see IsBlocked)
+func (s sendOutcome) blocked(resp *fasthttp.Response) bool {
+	return s.reset || s.signature || IsBlocked(resp)
+}
+
+// blockReason returns why a blocked request was blocked: the WAF its
+// response headers identify (see waf.ExplainBlock), a dropped connection, a
+// block page signature or a block-page redirect. It is "" for requests that
+// were not blocked or that only have a status code to go on.
+func (s sendOutcome) blockReason(resp *fasthttp.Response) string {
+	if s.reset {
+		return BlockReasonConnectionReset
+	}
+	if !s.blocked(resp) {
+		return ""
+	}
+	if evidence, ok := waf.ExplainBlock(resp); ok {
+		return string(evidence.WAF)
+	}
+	if s.signature {
+		return BlockReasonSignature
+	}
+	if IsBlockRedirect(resp) {
+		return BlockReasonRedirect
+	}
+	return ""
+}
+
+// ruleID returns the rule or incident ID a blocked request's response
+// headers carry
+func (s sendOutcome) ruleID(resp *fasthttp.Response) string {
+	if s.reset || !s.blocked(resp) {
+		return ""
+	}
+	evidence, _ := waf.ExplainBlock(resp)
+	return evidence.RuleID
+}
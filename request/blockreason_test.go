@@ -0,0 +1,73 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestBlockedResultCapturesModSecurityRuleID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("param") == "" {
+			w.Write([]byte("ok"))
+			return
+		}
+		w.Header().Set("X-Mod-Security-Id", "942100")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	results := NewFastHTTPQueryInjector().Inject(server.URL, "' OR 1=1--", NewLoggerWithLevel(os.Stderr, LogLevelError))
+	blocked := 0
+	for _, result := range results {
+		if !result.Blocked {
+			if result.BlockReason != "" || result.RuleID != "" {
+				t.Errorf("%s not blocked but has reason %q rule %q", result.EvasionTechnique, result.BlockReason, result.RuleID)
+			}
+			continue
+		}
+		blocked++
+		if result.BlockReason != "ModSecurity" || result.RuleID != "942100" {
+			t.Errorf("%s: reason %q rule %q, want ModSecurity rule 942100", result.EvasionTechnique, result.BlockReason, result.RuleID)
+		}
+	}
+	if blocked == 0 {
+		t.Fatal("no request was blocked")
+	}
+}
+
+func TestPlatformHeadersNeedTheWAFBlockPage(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantReason string
+		wantRule   string
+	}{
+		{"application 403 behind AWS", `{"message":"Forbidden"}`, "", ""},
+		{"AWS WAF block page", "<h1>403 ERROR</h1>Request blocked. Generated by cloudfront (CloudFront)", "AWS WAF", "2b4c6f0e-req"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// API Gateway and CloudFront tag every response this way
+				w.Header().Set("X-Amzn-RequestId", "2b4c6f0e-req")
+				w.Header().Set("X-Amz-Cf-Id", "Zx9Q")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			results := NewFastHTTPQueryInjector().Inject(server.URL, "' OR 1=1--", NewLoggerWithLevel(os.Stderr, LogLevelError))
+			if len(results) == 0 {
+				t.Fatal("no results")
+			}
+			for _, result := range results {
+				if !result.Blocked || result.BlockReason != tt.wantReason || result.RuleID != tt.wantRule {
+					t.Errorf("%s: blocked %v reason %q rule %q, want blocked with reason %q rule %q",
+						result.EvasionTechnique, result.Blocked, result.BlockReason, result.RuleID, tt.wantReason, tt.wantRule)
+				}
+			}
+		})
+	}
+}
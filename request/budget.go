@@ -0,0 +1,51 @@
+package request
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrBudgetExhausted is returned instead of sending once a RequestBudget is used up
+var ErrBudgetExhausted = errors.New("request budget exhausted")
+
+// RequestBudget is a hard cap on the number of requests sent across all
+// workers. Each send reserves a slot first, so the cap is never exceeded.
+type RequestBudget struct {
+	max  int64
+	sent atomic.Int64
+}
+
+// NewRequestBudget returns a budget allowing max requests
+func NewRequestBudget(max int) *RequestBudget {
+	return &RequestBudget{max: int64(max)}
+}
+
+// Take reserves a request, reporting false once the budget is spent. A nil
+// budget is unlimited.
+func (b *RequestBudget) Take() bool {
+	if b == nil {
+		return true
+	}
+	return b.sent.Add(1) <= b.max
+}
+
+// Exhausted reports whether every request in the budget has been reserved
+func (b *RequestBudget) Exhausted() bool {
+	return b != nil && b.sent.Load() >= b.max
+}
+
+// Sent returns how many requests were issued under the budget
+func (b *RequestBudget) Sent() int {
+	if b == nil {
+		return 0
+	}
+	return int(min(b.sent.Load(), b.max))
+}
+
+// Max returns the configured cap
+func (b *RequestBudget) Max() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.max)
+}
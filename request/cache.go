@@ -0,0 +1,77 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CacheBustParam is the query parameter that carries the per-request nonce
+// added by InjectorOptions.CacheBust
+const CacheBustParam = "_cb"
+
+// cacheStatusHeaders are CDN and proxy headers reporting whether a cache
+// answered the request; a value containing HIT or STALE means it did
+var cacheStatusHeaders = []string{
+	"X-Cache",
+	"X-Cache-Status",
+	"Cf-Cache-Status",
+	"X-Proxy-Cache",
+	"X-Cache-Lookup",
+}
+
+// IsCached reports whether resp looks like it was served from a cache (a
+// non-zero Age, or a cache status header with a hit), in which case the WAF
+// may never have evaluated the request
+func IsCached(resp *fasthttp.Response) bool {
+	if age := resp.Header.Peek("Age"); len(age) > 0 && string(age) != "0" {
+		return true
+	}
+	for _, name := range cacheStatusHeaders {
+		value := bytes.ToUpper(resp.Header.Peek(name))
+		if bytes.Contains(value, []byte("HIT")) || bytes.Contains(value, []byte("STALE")) {
+			return true
+		}
+	}
+	return false
+}
+
+// CachedCount returns how many results were served from a cache
+func CachedCount(results []TestResult) int {
+	cached := 0
+	for _, result := range results {
+		if result.Cached {
+			cached++
+		}
+	}
+	return cached
+}
+
+// CacheWarning returns a warning when any of results came from a cache, or
+// "" when none did
+func CacheWarning(results []TestResult) string {
+	cached := CachedCount(results)
+	if cached == 0 {
+		return ""
+	}
+	return fmt.Sprintf("⚠️  %d of %d responses look cached (Age / X-Cache headers), so the WAF may not have evaluated "+
+		"those payloads. Enable cache busting (-cache-bust) so every request reaches it.", cached, len(results))
+}
+
+// cacheBust asks caches to pass req through and makes its URL unique with a
+// random CacheBustParam, appended to the raw query so payloads already in it
+// are left as they are
+func cacheBust(req *fasthttp.Request) {
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Pragma", "no-cache")
+
+	uri := req.URI()
+	query := string(uri.QueryString())
+	if query != "" {
+		query += "&"
+	}
+	uri.SetQueryString(query + CacheBustParam + "=" + strconv.FormatUint(rand.Uint64(), 36))
+}
@@ -0,0 +1,64 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCachedResponsesWarnAndRequestsCarryCacheBuster(t *testing.T) {
+	var mu sync.Mutex
+	var queries, cacheControls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		cacheControls = append(cacheControls, r.Header.Get("Cache-Control"))
+		mu.Unlock()
+		w.Header().Set("X-Cache", "HIT from cdn")
+	}))
+	defer server.Close()
+
+	payload := "<script>alert(1)</script>"
+	opts := DefaultInjectorOptions()
+	opts.CacheBust = true
+	results := NewFastHTTPQueryInjectorWithOptions(opts).Inject(server.URL+"?page=1", payload, NewLoggerWithLevel(os.Stderr, LogLevelError))
+	if len(results) == 0 {
+		t.Fatal("no results")
+	}
+
+	for _, result := range results {
+		if !result.Cached {
+			t.Errorf("%s: X-Cache HIT response not marked cached", result.EvasionTechnique)
+		}
+	}
+	if warning := CacheWarning(results); !strings.Contains(warning, "cached") {
+		t.Errorf("CacheWarning() = %q, want a cache warning", warning)
+	}
+	if warning := CacheWarning(results[:0]); warning != "" {
+		t.Errorf("CacheWarning(no results) = %q, want none", warning)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	nonces := map[string]bool{}
+	for i, query := range queries {
+		if !strings.Contains(query, "page=1") {
+			t.Errorf("request %d query %q lost the existing parameter", i, query)
+		}
+		_, nonce, ok := strings.Cut(query, CacheBustParam+"=")
+		if !ok {
+			t.Errorf("request %d query %q has no %s cache buster", i, query, CacheBustParam)
+			continue
+		}
+		nonces[nonce] = true
+		if cacheControls[i] != "no-cache" {
+			t.Errorf("request %d Cache-Control = %q, want no-cache", i, cacheControls[i])
+		}
+	}
+	if len(nonces) != len(queries) {
+		t.Errorf("%d distinct nonces for %d requests, want one per request", len(nonces), len(queries))
+	}
+}
@@ -0,0 +1,104 @@
+package request
+
+import (
+	"bytes"
+
+	"github.com/valyala/fasthttp"
+)
+
+// challengeBodyMarkers are fragments of the interstitial pages WAFs serve
+// instead of blocking outright (JS challenges, captchas). Matching is case
+// insensitive and limited to the captured part of the body.
+var challengeBodyMarkers = [][]byte{
+	[]byte("checking your browser"),
+	[]byte("just a moment..."),
+	[]byte("cf-chl-"),
+	[]byte("/cdn-cgi/challenge-platform/"),
+	[]byte("_incapsula_resource"),
+	[]byte("sec-if-cpt-container"),
+	[]byte("awswaf.com"),
+	[]byte("ddos protection by"),
+}
+
+// captchaWidgetMarkers are fragments of captcha widgets. Applications embed
+// them in their own login and contact forms too, so they only mark a
+// challenge on a response whose status is one of challengeStatuses.
+var captchaWidgetMarkers = [][]byte{
+	[]byte("g-recaptcha"),
+	[]byte("h-captcha"),
+	[]byte("hcaptcha.com"),
+	[]byte("challenges.cloudflare.com/turnstile"),
+}
+
+// challengeStatuses are the statuses WAFs serve challenge pages with
+var challengeStatuses = []int{
+	fasthttp.StatusForbidden,
+	fasthttp.StatusTooManyRequests,
+	fasthttp.StatusServiceUnavailable,
+}
+
+// challengeHeaders maps response headers to a value that marks a challenge;
+// an empty value matches any non-empty header.
+var challengeHeaders = map[string]string{
+	"Cf-Mitigated":       "challenge",
+	"X-Amzn-Waf-Action":  "captcha",
+	"X-Datadome-Captcha": "",
+}
+
+// ChallengeWarningRatio is the share of challenge responses above which
+// reports warn that results may be unreliable
+const ChallengeWarningRatio = 0.5
+
+// IsChallenge reports whether resp is a WAF challenge page (JS challenge or
+// captcha) rather than a block or the application's own response
+func IsChallenge(resp *fasthttp.Response) bool {
+	for name, value := range challengeHeaders {
+		got := resp.Header.Peek(name)
+		if len(got) == 0 {
+			continue
+		}
+		if value == "" || bytes.EqualFold(got, []byte(value)) {
+			return true
+		}
+	}
+
+	body := resp.Body()
+	if len(body) > MaxCapturedBodySize {
+		body = body[:MaxCapturedBodySize]
+	}
+	body = bytes.ToLower(body)
+	for _, marker := range challengeBodyMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+	if !containsStatus(challengeStatuses, resp.StatusCode()) {
+		return false
+	}
+	for _, marker := range captchaWidgetMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChallengeRatio returns the fraction of results that were challenge pages
+func ChallengeRatio(results []TestResult) float64 {
+	if len(results) == 0 {
+		return 0
+	}
+	challenges := 0
+	for _, result := range results {
+		if result.Challenge {
+			challenges++
+		}
+	}
+	return float64(challenges) / float64(len(results))
+}
+
+// ChallengesDominate reports whether enough results were challenge pages that
+// block/bypass counts are unreliable
+func ChallengesDominate(results []TestResult) bool {
+	return ChallengeRatio(results) >= ChallengeWarningRatio
+}
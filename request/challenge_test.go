@@ -0,0 +1,89 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+const cloudflareChallengeBody = `<!DOCTYPE html><html><head><title>Just a moment...</title></head>
+<body><div id="cf-chl-widget">Checking your browser before accessing the site.</div>
+<script src="/cdn-cgi/challenge-platform/h/g/orchestrate/chl_page/v1"></script></body></html>`
+
+func TestChallengePageIsDetected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(cloudflareChallengeBody))
+	}))
+	defer server.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	results := NewFastHTTPQueryInjector().Inject(server.URL, "<script>alert(1)</script>", NewLoggerWithLevel(devNull, LogLevelError))
+	if len(results) == 0 {
+		t.Fatal("no results")
+	}
+	for i, result := range results {
+		if !result.Challenge {
+			t.Errorf("result %d (%s): Challenge = false, want true", i, result.EvasionTechnique)
+		}
+		if got := Classify(result, "xss", nil); got != OutcomeChallenge {
+			t.Errorf("result %d: Classify() = %q, want %q", i, got, OutcomeChallenge)
+		}
+	}
+	if !ChallengesDominate(results) {
+		t.Error("ChallengesDominate() = false for an all-challenge run")
+	}
+}
+
+func TestChallengeHeaderAndPlainBlock(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header string
+		value  string
+		body   string
+		want   bool
+	}{
+		{"cf-mitigated header", http.StatusForbidden, "Cf-Mitigated", "challenge", "", true},
+		{"aws waf captcha", http.StatusForbidden, "X-Amzn-Waf-Action", "CAPTCHA", "", true},
+		{"recaptcha widget", http.StatusForbidden, "", "", `<div class="g-recaptcha" data-sitekey="x"></div>`, true},
+		{"hcaptcha on a 429", http.StatusTooManyRequests, "", "", `<div class="h-captcha" data-sitekey="x"></div>`, true},
+		{"login form with recaptcha", http.StatusOK, "", "", `<form action="/login"><div class="g-recaptcha" data-sitekey="x"></div></form>`, false},
+		{"plain block page", http.StatusForbidden, "", "", "<h1>403 Forbidden</h1>Request blocked.", false},
+		{"unrelated header value", http.StatusForbidden, "Cf-Mitigated", "block", "", false},
+	}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set(tt.header, tt.value)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			results := NewFastHTTPQueryInjector().Inject(server.URL, "x", NewLoggerWithLevel(devNull, LogLevelError))
+			if len(results) == 0 {
+				t.Fatal("no results")
+			}
+			if results[0].Challenge != tt.want {
+				t.Errorf("Challenge = %v, want %v", results[0].Challenge, tt.want)
+			}
+		})
+	}
+}
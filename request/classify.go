@@ -0,0 +1,64 @@
+package request
+
+import "strings"
+
+// Outcome is how a single request's response is interpreted
+type Outcome string
+
+const (
+	// OutcomeBlocked means the WAF rejected the request
+	OutcomeBlocked Outcome = "blocked"
+	// OutcomeBypassed means the request got through to the application
+	OutcomeBypassed Outcome = "bypassed"
+	// OutcomeCandidateBypass means the request got through and the response
+	// suggests the payload had an effect (e.g. a 500 from a SQL error)
+	OutcomeCandidateBypass Outcome = "candidate_bypass"
+	// OutcomeChallenge means the WAF answered with a JS challenge or captcha
+	// instead of blocking or passing the request
+	OutcomeChallenge Outcome = "challenge"
+	// OutcomeError means no usable response or a server error unrelated to the payload
+	OutcomeError Outcome = "error"
+)
+
+// DefaultInterestingStatusCodes lists, per attack type, the error statuses
+// that indicate a payload reached and broke the backend rather than a
+// failing target. Keys are attack type names as used in the config.
+var DefaultInterestingStatusCodes = map[string][]int{
+	"sqli":  {500, 502},
+	"ldapi": {500},
+	"xxe":   {500},
+	"ssrf":  {500, 502, 504},
+}
+
+// Classify interprets a result for the given attack type. interesting maps
+// attack types to candidate-bypass status codes; attack types missing from it
+// fall back to DefaultInterestingStatusCodes, and an empty list disables them.
+func Classify(result TestResult, attackType string, interesting map[string][]int) Outcome {
+	key := strings.ToLower(attackType)
+	codes, ok := interesting[key]
+	if !ok {
+		codes = DefaultInterestingStatusCodes[key]
+	}
+
+	switch {
+	case result.Challenge:
+		return OutcomeChallenge
+	case result.Blocked:
+		return OutcomeBlocked
+	case containsStatus(codes, result.StatusCode):
+		return OutcomeCandidateBypass
+	case result.StatusCode == 0 || result.StatusCode >= 500:
+		return OutcomeError
+	default:
+		return OutcomeBypassed
+	}
+}
+
+func containsStatus(codes []int, statusCode int) bool {
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      TestResult
+		attackType  string
+		interesting map[string][]int
+		expected    Outcome
+	}{
+		{"blocked", TestResult{StatusCode: 403, Blocked: true}, "sqli", nil, OutcomeBlocked},
+		{"challenge wins over blocked", TestResult{StatusCode: 403, Blocked: true, Challenge: true}, "sqli", nil, OutcomeChallenge},
+		{"ok", TestResult{StatusCode: 200}, "sqli", nil, OutcomeBypassed},
+		{"sqli 500 is candidate", TestResult{StatusCode: 500}, "sqli", nil, OutcomeCandidateBypass},
+		{"attack type is case insensitive", TestResult{StatusCode: 502}, "SQLI", nil, OutcomeCandidateBypass},
+		{"xss 500 is error", TestResult{StatusCode: 500}, "xss", nil, OutcomeError},
+		{"no response is error", TestResult{}, "sqli", nil, OutcomeError},
+		{"configured codes", TestResult{StatusCode: 500}, "xss", map[string][]int{"xss": {500}}, OutcomeCandidateBypass},
+		{"other types keep defaults", TestResult{StatusCode: 500}, "sqli", map[string][]int{"xss": {500}}, OutcomeCandidateBypass},
+		{"empty list disables", TestResult{StatusCode: 500}, "sqli", map[string][]int{"sqli": {}}, OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.result, tt.attackType, tt.interesting); got != tt.expected {
+				t.Errorf("Classify() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSQLiServerErrorIsCandidateBypass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "You have an error in your SQL syntax", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	results := NewFastHTTPQueryInjector().Inject(server.URL, "' OR 1=1 --", NewLoggerWithLevel(devNull, LogLevelError))
+	if len(results) == 0 {
+		t.Fatal("no results")
+	}
+
+	for i := range results {
+		if got := Classify(results[i], "sqli", nil); got != OutcomeCandidateBypass {
+			t.Errorf("result %d (%d): Classify() = %q, want %q", i, results[i].StatusCode, got, OutcomeCandidateBypass)
+		}
+		results[i].CandidateBypass = true
+	}
+	if IsFailure(results) {
+		t.Error("IsFailure() = true for candidate bypasses, want false")
+	}
+}
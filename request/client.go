@@ -0,0 +1,60 @@
+package request
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+)
+
+// client sends every injector request. It is fasthttp's default client
+// except that it also dials IPv6, so [::1]-style targets work.
+var client = &fasthttp.Client{DialDualStack: true}
+
+// clientSettings are the client settings InjectorOptions can change
+type clientSettings struct {
+	readBufferSize      int
+	maxResponseBodySize int
+	sourceAddresses     *SourceAddressRotator
+}
+
+var (
+	configuredClientsMu sync.Mutex
+	configuredClients   = make(map[clientSettings]*fasthttp.Client)
+)
+
+// clientFor returns the client for settings, shared by every caller using
+// the same ones so they share its connection pool
+func clientFor(settings clientSettings) *fasthttp.Client {
+	if settings == (clientSettings{}) {
+		return client
+	}
+	configuredClientsMu.Lock()
+	defer configuredClientsMu.Unlock()
+	c, ok := configuredClients[settings]
+	if !ok {
+		c = &fasthttp.Client{
+			DialDualStack:       true,
+			ReadBufferSize:      settings.readBufferSize,
+			MaxResponseBodySize: settings.maxResponseBodySize,
+		}
+		if settings.sourceAddresses != nil {
+			c.Dial = settings.sourceAddresses.Dial
+		}
+		configuredClients[settings] = c
+	}
+	return c
+}
+
+// tolerateBodyTooLarge turns the error for a response body over
+// MaxResponseBodySize into success when the status line and headers were
+// read, so a huge block page is still classified by its status instead of
+// being recorded as a transport failure. fasthttp defaults the status code
+// to 200, so the headers count as read when they gave the body a length or
+// chunked or unbounded framing, which an unread header never has.
+func tolerateBodyTooLarge(err error, resp *fasthttp.Response) error {
+	if errors.Is(err, fasthttp.ErrBodyTooLarge) && resp.Header.ContentLength() != 0 {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestLargeBlockPageIsClassifiedWithoutTransportError(t *testing.T) {
+	blockPage := "<html><body>Request blocked" + strings.Repeat(" <!-- padding -->", 30000) + "</body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A long header too, which the default read buffer cannot hold
+		w.Header().Set("X-Block-Reference", strings.Repeat("a", 8192))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(blockPage))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		maxBody         int
+		wantFullCapture bool
+	}{
+		{"read in full", 0, true},
+		{"capped", 1024, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultInjectorOptions()
+			opts.ReadBufferSize = 16 * 1024
+			opts.MaxResponseBodySize = tt.maxBody
+
+			results := NewFastHTTPQueryInjectorWithOptions(opts).Inject(server.URL, "x", NewLoggerWithLevel(os.Stderr, LogLevelError))
+			if len(results) == 0 {
+				t.Fatal("no results: every request failed")
+			}
+			for _, result := range results {
+				if result.StatusCode != http.StatusForbidden || !result.Blocked {
+					t.Errorf("%s: status %d blocked=%v, want the blocked 403", result.EvasionTechnique, result.StatusCode, result.Blocked)
+				}
+				full := len(result.ResponseBody) == MaxCapturedBodySize && strings.HasPrefix(blockPage, result.ResponseBody)
+				if full != tt.wantFullCapture {
+					t.Errorf("%s: captured %d body bytes, want full capture %v", result.EvasionTechnique, len(result.ResponseBody), tt.wantFullCapture)
+				}
+			}
+		})
+	}
+
+	// Without a bigger read buffer the long header fails the request outright
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	if results := NewFastHTTPQueryInjector().Inject(server.URL, "x", NewLoggerWithLevel(devNull, LogLevelError)); len(results) != 0 {
+		t.Errorf("default read buffer: %d results, want the oversized header to fail the request", len(results))
+	}
+}
+
+func TestBodyTooLargeWithoutHeadersStaysAnError(t *testing.T) {
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	if err := tolerateBodyTooLarge(fasthttp.ErrBodyTooLarge, resp); !errors.Is(err, fasthttp.ErrBodyTooLarge) {
+		t.Errorf("unread response: error = %v, want ErrBodyTooLarge", err)
+	}
+	resp.Header.SetContentLength(1 << 20)
+	if err := tolerateBodyTooLarge(fasthttp.ErrBodyTooLarge, resp); err != nil {
+		t.Errorf("response with headers: error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,68 @@
+package request
+
+import (
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CookieJar carries cookies set by the target across requests, so a session
+// established by the first response is sent with later payloads. Cookies are
+// kept per host; paths, domains and Secure flags are not enforced.
+type CookieJar struct {
+	mu      sync.Mutex
+	cookies map[string]map[string]string
+}
+
+// NewCookieJar returns an empty jar
+func NewCookieJar() *CookieJar {
+	return &CookieJar{cookies: make(map[string]map[string]string)}
+}
+
+// Apply adds the jar's cookies for the request's host. Cookies the injector
+// set itself (such as a payload in a cookie) are left alone. A nil jar does
+// nothing.
+func (j *CookieJar) Apply(req *fasthttp.Request) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for name, value := range j.cookies[string(req.URI().Host())] {
+		if len(req.Header.Cookie(name)) == 0 {
+			req.Header.SetCookie(name, value)
+		}
+	}
+}
+
+// Capture stores the Set-Cookie headers of resp for the request's host,
+// dropping cookies the response deletes or expires. A nil jar does nothing.
+func (j *CookieJar) Capture(req *fasthttp.Request, resp *fasthttp.Response) {
+	if j == nil {
+		return
+	}
+	host := string(req.URI().Host())
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	resp.Header.VisitAllCookie(func(_, value []byte) {
+		cookie.Reset()
+		if cookie.ParseBytes(value) != nil || len(cookie.Key()) == 0 {
+			return
+		}
+		name := string(cookie.Key())
+		expired := cookie.MaxAge() < 0 ||
+			(cookie.Expire() != fasthttp.CookieExpireUnlimited && cookie.Expire().Before(time.Now()))
+		if expired {
+			delete(j.cookies[host], name)
+			return
+		}
+		if j.cookies[host] == nil {
+			j.cookies[host] = make(map[string]string)
+		}
+		j.cookies[host][name] = string(cookie.Value())
+	})
+}
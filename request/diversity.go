@@ -0,0 +1,78 @@
+package request
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+)
+
+// benignHeaders are headers browsers send that carry no payload, each with
+// the values a diversified request picks from
+var benignHeaders = []struct {
+	name   string
+	values []string
+}{
+	{"Accept", []string{
+		"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8",
+		"*/*",
+	}},
+	{"Accept-Language", []string{"en-US,en;q=0.9", "en-GB,en;q=0.8", "de-DE,de;q=0.9,en;q=0.7", "fr-FR,fr;q=0.9", "en"}},
+	{"Cache-Control", []string{"max-age=0", "no-cache"}},
+	{"DNT", []string{"1"}},
+	{"Upgrade-Insecure-Requests", []string{"1"}},
+	{"Sec-Fetch-Dest", []string{"document", "empty"}},
+	{"Sec-Fetch-Mode", []string{"navigate", "cors", "no-cors"}},
+	{"Sec-Fetch-Site", []string{"none", "same-origin", "cross-site"}},
+	{"Sec-GPC", []string{"1"}},
+}
+
+// paddingHeaders name the random-length padding header added with Padding
+var paddingHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Trace-Id"}
+
+// HeaderDiversifier varies the benign headers of each request so hundreds of
+// payload requests do not share one structure a WAF can profile: a random
+// subset of browser headers with random values, in random order, and
+// optionally a padding header of random length. The choices come from one
+// seeded source, so the same seed gives the same sequence of header sets; it
+// is safe for concurrent use, though concurrent requests then draw from the
+// sequence in scheduling order.
+//
+// fasthttp limits the ordering: User-Agent, Host, Content-Type and
+// Content-Length are always written first and Cookie and Connection last,
+// whatever order they were set in. Only the other headers keep the order they
+// were added in, so the diversified headers are shuffled among themselves and
+// come before the headers an injector sets for its payload.
+type HeaderDiversifier struct {
+	// Padding adds a padding header with a random hex value to every request
+	Padding bool
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewHeaderDiversifier returns a diversifier drawing from seed
+func NewHeaderDiversifier(seed int64, padding bool) *HeaderDiversifier {
+	return &HeaderDiversifier{Padding: padding, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Apply adds the next set of benign headers to req
+func (d *HeaderDiversifier) Apply(req *fasthttp.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, i := range d.rng.Perm(len(benignHeaders)) {
+		if d.rng.Intn(2) == 0 {
+			continue
+		}
+		header := benignHeaders[i]
+		req.Header.Set(header.name, header.values[d.rng.Intn(len(header.values))])
+	}
+	if d.Padding {
+		padding := make([]byte, 8+d.rng.Intn(120))
+		d.rng.Read(padding)
+		req.Header.Set(paddingHeaders[d.rng.Intn(len(paddingHeaders))], hex.EncodeToString(padding))
+	}
+}
@@ -0,0 +1,45 @@
+package request
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestHeaderDiversifierVariesHeadersReproducibly(t *testing.T) {
+	headerSets := func(seed int64) []string {
+		d := NewHeaderDiversifier(seed, true)
+		var sets []string
+		for i := 0; i < 20; i++ {
+			req := fasthttp.AcquireRequest()
+			d.Apply(req)
+			var names []string
+			req.Header.VisitAll(func(key, value []byte) {
+				names = append(names, string(key))
+			})
+			fasthttp.ReleaseRequest(req)
+			sets = append(sets, strings.Join(names, ","))
+		}
+		return sets
+	}
+
+	first := headerSets(42)
+	distinct := make(map[string]bool)
+	for _, set := range first {
+		distinct[set] = true
+	}
+	if len(distinct) < 5 {
+		t.Errorf("only %d distinct header sets over %d requests: %v", len(distinct), len(first), first)
+	}
+
+	again := headerSets(42)
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatalf("request %d differs under the same seed: %q vs %q", i, first[i], again[i])
+		}
+	}
+	if other := headerSets(7); strings.Join(other, "|") == strings.Join(first, "|") {
+		t.Error("a different seed gave the same header sequence")
+	}
+}
@@ -0,0 +1,97 @@
+package request
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// FuzzMarker marks where `obfuskit fuzz` places payloads in the target URL
+const FuzzMarker = "FUZZ"
+
+// FuzzTechnique labels results sent by the marker injector
+const FuzzTechnique = "fuzz_marker"
+
+// FastHTTPMarkerInjector sends one GET per payload with the payload in place
+// of every FuzzMarker in the target URL, escaped for the part of the URL the
+// marker is in, so the server decodes exactly the payload
+type FastHTTPMarkerInjector struct {
+	options *InjectorOptions
+}
+
+// NewFastHTTPMarkerInjectorWithOptions returns a marker injector using opts
+func NewFastHTTPMarkerInjectorWithOptions(opts *InjectorOptions) *FastHTTPMarkerInjector {
+	return &FastHTTPMarkerInjector{options: opts}
+}
+
+func (i *FastHTTPMarkerInjector) Name() string {
+	return "fasthttp_marker_injection"
+}
+
+// FuzzURL returns targetURL, normalized, with payload in place of each
+// FuzzMarker: query-escaped after the '?', path-escaped before it
+func FuzzURL(targetURL, payload string) (string, error) {
+	if !strings.Contains(targetURL, FuzzMarker) {
+		return "", fmt.Errorf("target URL %s has no %s marker", targetURL, FuzzMarker)
+	}
+	normalizedURL, err := NormalizeURL(targetURL)
+	if err != nil {
+		return "", err
+	}
+	path, query, hasQuery := strings.Cut(normalizedURL, "?")
+	path = strings.ReplaceAll(path, FuzzMarker, url.PathEscape(payload))
+	if !hasQuery {
+		return path, nil
+	}
+	return path + "?" + strings.ReplaceAll(query, FuzzMarker, url.QueryEscape(payload)), nil
+}
+
+func (i *FastHTTPMarkerInjector) Inject(targetURL string, payload string, logger *Logger) []TestResult {
+	results := []TestResult{}
+
+	sent, canary := i.options.withCanary(payload)
+	testURL, err := FuzzURL(targetURL, sent)
+	if err != nil {
+		logger.error.Printf("Failed to place payload in %s: %v", targetURL, err)
+		return results
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(testURL)
+	i.options.prepare(req)
+
+	logger.debug.Printf("Sending request to %s with the payload at the %s marker", testURL, FuzzMarker)
+	start := time.Now()
+	outcome, err := i.options.do(req, resp)
+	duration := time.Since(start)
+
+	if err != nil {
+		logger.error.Printf("Marker injection test failed: %v", err)
+		return results
+	}
+	result := TestResult{
+		Request:          snapshotRequest(req),
+		Payload:          payload,
+		EvasionTechnique: FuzzTechnique,
+		RequestPart:      "url",
+		StatusCode:       outcome.statusCode(resp),
+		ResponseTime:     duration,
+		Blocked:          outcome.blocked(resp),
+		BlockReason:      outcome.blockReason(resp),
+		RuleID:           outcome.ruleID(resp),
+		ResponseBody:     capturedBody(resp),
+		Challenge:        IsChallenge(resp),
+		Cached:           IsCached(resp),
+		Timing:           outcome.timing,
+		Canary:           canary,
+	}
+	logger.info.Printf("Marker injection test result: %s", result.String())
+	return append(results, result)
+}
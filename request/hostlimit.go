@@ -0,0 +1,82 @@
+package request
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// HostLimiter caps the requests in flight to each destination host with a
+// per-host semaphore, so one slow host cannot tie up every worker while the
+// others sit idle. A nil limiter, or one with a limit below 1, is unlimited.
+type HostLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// NewHostLimiter returns a limiter allowing limit concurrent requests per host
+func NewHostLimiter(limit int) *HostLimiter {
+	return &HostLimiter{limit: limit, slots: make(map[string]chan struct{})}
+}
+
+// semaphore returns the semaphore for host, creating it on first use
+func (l *HostLimiter) semaphore(host string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sem, ok := l.slots[host]
+	if !ok {
+		sem = make(chan struct{}, l.limit)
+		l.slots[host] = sem
+	}
+	return sem
+}
+
+// Acquire blocks until a slot for host is free
+func (l *HostLimiter) Acquire(host string) {
+	if l == nil || l.limit < 1 {
+		return
+	}
+	l.semaphore(host) <- struct{}{}
+}
+
+// TryAcquire takes a slot for host without blocking, reporting whether one
+// was free
+func (l *HostLimiter) TryAcquire(host string) bool {
+	if l == nil || l.limit < 1 {
+		return true
+	}
+	select {
+	case l.semaphore(host) <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees a slot taken with Acquire or TryAcquire
+func (l *HostLimiter) Release(host string) {
+	if l == nil || l.limit < 1 {
+		return
+	}
+	<-l.semaphore(host)
+}
+
+// Limit returns the per-host cap (0 = unlimited)
+func (l *HostLimiter) Limit() int {
+	if l == nil || l.limit < 1 {
+		return 0
+	}
+	return l.limit
+}
+
+// HostKey returns the host (and port) requests to rawURL are accounted
+// against, or rawURL itself when it does not parse
+func HostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,35 @@
+package request
+
+import (
+	"regexp"
+	"strings"
+)
+
+// JSON5Technique labels requests whose body is JSON5 rather than strict JSON
+const JSON5Technique = "json5_param"
+
+var json5Identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// JSON5Body builds a relaxed-JSON body carrying payload in a comment and in a
+// single-quoted value under an unquoted key, with a trailing comma. Lenient
+// parsers accept it while strict WAF JSON parsers reject it and may fall back
+// to not inspecting the body at all.
+func JSON5Body(name, payload string) string {
+	key := name
+	if !json5Identifier.MatchString(key) {
+		key = json5String(key)
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	b.WriteString("  /* " + strings.ReplaceAll(payload, "*/", "* /") + " */\n")
+	b.WriteString("  " + key + ": " + json5String(payload) + ",\n")
+	b.WriteString("}")
+	return b.String()
+}
+
+// json5String single-quotes s, escaping what would end or break the literal
+func json5String(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`)
+	return "'" + r.Replace(s) + "'"
+}
@@ -0,0 +1,68 @@
+package request
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestJSON5Body(t *testing.T) {
+	payload := `<script>alert('x')</script>`
+	body := JSON5Body("q", payload)
+
+	for _, feature := range []string{"/* ", "  q: '", ",\n}"} {
+		if !strings.Contains(body, feature) {
+			t.Errorf("body %q lacks JSON5 feature %q", body, feature)
+		}
+	}
+	if !strings.Contains(body, "/* "+payload+" */") {
+		t.Errorf("body %q does not carry the payload in a comment", body)
+	}
+	if !strings.Contains(body, `'<script>alert(\'x\')</script>'`) {
+		t.Errorf("body %q does not carry the payload in a single-quoted value", body)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(body), &v); err == nil {
+		t.Errorf("encoding/json accepted the JSON5 body %q", body)
+	}
+}
+
+func TestJSON5BodyQuotesInvalidKeysAndClosesComments(t *testing.T) {
+	body := JSON5Body("user-id", "*/ OR 1=1")
+	if !strings.Contains(body, "'user-id': ") {
+		t.Errorf("key not quoted in %q", body)
+	}
+	if comment := strings.Split(body, "\n")[1]; comment != "  /* * / OR 1=1 */" {
+		t.Errorf("payload terminated the comment early: %q", comment)
+	}
+}
+
+func TestBodyInjectorSendsJSON5Variant(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	results := NewFastHTTPBodyInjector().Inject(server.URL, "' OR 1=1 --", NewLoggerWithLevel(devNull, LogLevelError))
+
+	found := false
+	for _, result := range results {
+		if result.EvasionTechnique == JSON5Technique {
+			found = true
+			if got := string(result.Request.Body()); got != JSON5Body(DefaultParamName, "' OR 1=1 --") {
+				t.Errorf("JSON5 request body = %q", got)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no %s result among %d body results", JSON5Technique, len(results))
+	}
+}
@@ -0,0 +1,21 @@
+package request
+
+// NegotiationVariant is an Accept/Content-Type combination sent with a JSON
+// body. Some WAFs pick their body parser, or skip inspection, from these
+// headers while the application parses the body as JSON regardless.
+type NegotiationVariant struct {
+	Technique   string
+	Accept      string
+	ContentType string
+}
+
+// NegotiationVariants are the header combinations the body injector tries
+var NegotiationVariants = []NegotiationVariant{
+	// Ask for XML while sending JSON
+	{Technique: "accept_xml_json_body", Accept: "application/xml", ContentType: "application/json"},
+	// A charset the WAF cannot decode but lenient JSON parsers ignore
+	{Technique: "json_charset_utf7", Accept: "application/json", ContentType: "application/json; charset=utf-7"},
+	{Technique: "json_charset_utf32", Accept: "application/json", ContentType: "application/json; charset=utf-32"},
+	// A vendor media type that an exact "application/json" rule misses
+	{Technique: "vendor_json_content_type", Accept: "*/*", ContentType: "application/vnd.api+json"},
+}
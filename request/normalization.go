@@ -0,0 +1,112 @@
+package request
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"obfuskit/types"
+
+	"github.com/valyala/fasthttp"
+)
+
+// normalizationMarker is the benign text the normalization probes encode. A
+// target that decodes an encoding reflects the marker itself.
+const normalizationMarker = "obfkprobe"
+
+// ErrNoReflection means the target does not reflect the probed parameter, so
+// which decodings it applies cannot be observed
+var ErrNoReflection = errors.New("target does not reflect the probe parameter")
+
+// normalizationProbes encode the marker the way each encoding's variants do,
+// as it goes on the wire: the URL probes are the percent-encoding itself, the
+// others are query-escaped so only the encoding under test is left to undo
+var normalizationProbes = []struct {
+	encoding types.PayloadEncoding
+	encode   func(string) string
+}{
+	{types.PayloadEncodingURL, func(s string) string { return escapeEach(s, "%%%02X") }},
+	{types.PayloadEncodingDoubleURL, func(s string) string { return escapeEach(s, "%%25%02X") }},
+	{types.PayloadEncodingHTML, func(s string) string { return url.QueryEscape(escapeEach(s, "&#x%02x;")) }},
+	{types.PayloadEncodingUnicode, func(s string) string { return url.QueryEscape(escapeEach(s, `\u%04x`)) }},
+	{types.PayloadEncodingHex, func(s string) string { return url.QueryEscape(escapeEach(s, `\x%02x`)) }},
+	{types.PayloadEncodingOctal, func(s string) string { return url.QueryEscape(escapeEach(s, `\%03o`)) }},
+	{types.PayloadEncodingBase64, func(s string) string {
+		return url.QueryEscape(base64.StdEncoding.EncodeToString([]byte(s)))
+	}},
+}
+
+// escapeEach formats every byte of s with format
+func escapeEach(s, format string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		fmt.Fprintf(&b, format, s[i])
+	}
+	return b.String()
+}
+
+// ProbeNormalization learns which encodings the target decodes (see
+// ProbeNormalizationWithOptions)
+func ProbeNormalization(targetURL string) (map[types.PayloadEncoding]bool, error) {
+	return ProbeNormalizationWithOptions(targetURL, DefaultInjectorOptions())
+}
+
+// ProbeNormalizationWithOptions sends a benign marker in the first query
+// parameter, once plain and once per encoding, and reports for each encoding
+// whether the target reflected the marker decoded. An encoding the target
+// decodes is one whose variants land as the payload. The target must reflect
+// the parameter; ErrNoReflection is returned when even the plain marker does
+// not come back.
+func ProbeNormalizationWithOptions(targetURL string, opts *InjectorOptions) (map[types.PayloadEncoding]bool, error) {
+	normalizedURL, err := NormalizeURL(targetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	reflected, err := probeReflects(normalizedURL, normalizationMarker, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !reflected {
+		return nil, ErrNoReflection
+	}
+
+	applied := make(map[types.PayloadEncoding]bool, len(normalizationProbes))
+	for _, probe := range normalizationProbes {
+		reflected, err := probeReflects(normalizedURL, probe.encode(normalizationMarker), opts)
+		if err != nil {
+			return nil, fmt.Errorf("probing %s: %w", probe.encoding, err)
+		}
+		applied[probe.encoding] = reflected
+	}
+	return applied, nil
+}
+
+// probeReflects sends the already-escaped value in the first parameter and
+// reports whether the response contains the decoded marker
+func probeReflects(normalizedURL, value string, opts *InjectorOptions) (bool, error) {
+	parsedURL, err := url.Parse(normalizedURL)
+	if err != nil {
+		return false, err
+	}
+	name := opts.paramNames()[0]
+	query := parsedURL.Query()
+	query.Del(name)
+	// Set raw: fasthttp's query args would escape the probe's '%' again
+	parsedURL.RawQuery = joinQuery(query.Encode(), "&", url.QueryEscape(name)+"="+value)
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(parsedURL.String())
+	opts.prepare(req)
+	if _, err := opts.do(req, resp); err != nil {
+		return false, err
+	}
+	return bytes.Contains(bytes.ToLower(resp.Body()), []byte(normalizationMarker)), nil
+}
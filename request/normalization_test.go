@@ -0,0 +1,44 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"obfuskit/types"
+)
+
+func TestProbeNormalizationReportsSingleButNotDoubleURLDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Query() URL-decodes the parameter exactly once, so a double-encoded
+		// value is reflected still encoded
+		w.Write([]byte("value: " + r.URL.Query().Get("param")))
+	}))
+	defer server.Close()
+
+	applied, err := ProbeNormalization(server.URL)
+	if err != nil {
+		t.Fatalf("ProbeNormalization() error = %v", err)
+	}
+	if !applied[types.PayloadEncodingURL] {
+		t.Errorf("applied[%s] = false, want true", types.PayloadEncodingURL)
+	}
+	if applied[types.PayloadEncodingDoubleURL] {
+		t.Errorf("applied[%s] = true, want false", types.PayloadEncodingDoubleURL)
+	}
+	if applied[types.PayloadEncodingHTML] {
+		t.Errorf("applied[%s] = true, want false", types.PayloadEncodingHTML)
+	}
+}
+
+func TestProbeNormalizationWithoutReflection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	if _, err := ProbeNormalization(server.URL); !errors.Is(err, ErrNoReflection) {
+		t.Fatalf("ProbeNormalization() error = %v, want ErrNoReflection", err)
+	}
+}
@@ -0,0 +1,246 @@
+package request
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultPipelineTimeout bounds writing a pipelined batch and reading all of
+// its responses
+const DefaultPipelineTimeout = 30 * time.Second
+
+// PipelineInjector sends each payload in a batch of HTTP/1.1 requests written
+// back-to-back on one keep-alive connection, behind a benign request, and
+// matches the responses to the requests by order. It stresses how a WAF finds
+// request boundaries: one that only inspects the first request on a
+// connection, or loses track of where a request ends, passes the rest.
+//
+// fasthttp's client never pipelines, so batches go over a raw connection.
+// Servers and proxies are allowed not to pipeline: they may answer only the
+// first request, close the connection early, or send a response without a
+// length that runs to the end of the connection. Responses missing from a
+// batch are logged and left out instead of being guessed at. A result's
+// ResponseTime runs from the start of the batch to its own response.
+type PipelineInjector struct {
+	options *InjectorOptions
+}
+
+// NewPipelineInjectorWithOptions returns a pipeline injector using opts
+func NewPipelineInjectorWithOptions(opts *InjectorOptions) *PipelineInjector {
+	if opts == nil {
+		opts = DefaultInjectorOptions()
+	}
+	return &PipelineInjector{options: opts}
+}
+
+func (i *PipelineInjector) Name() string {
+	return "pipeline_injection"
+}
+
+// pipelinedRequest is one request of a batch and how its result is labeled
+type pipelinedRequest struct {
+	req       *fasthttp.Request
+	technique string
+	part      string
+	canary    string
+}
+
+// pipelinedResponse is the response to one request of a batch
+type pipelinedResponse struct {
+	resp    *fasthttp.Response
+	outcome sendOutcome
+	// elapsed is the time from the batch starting to this response being
+	// read, which includes waiting for the responses before it
+	elapsed time.Duration
+}
+
+func (i *PipelineInjector) Inject(targetURL string, payload string, logger *Logger) []TestResult {
+	results := []TestResult{}
+
+	normalizedURL, err := NormalizeURL(targetURL)
+	if err != nil {
+		logger.error.Printf("Failed to normalize URL %s: %v", targetURL, err)
+		return results
+	}
+	name := i.options.paramNames()[0]
+
+	// A benign request leads so the payloads are not first on the connection
+	benign := fasthttp.AcquireRequest()
+	benign.SetRequestURI(normalizedURL)
+
+	querySent, queryCanary := i.options.withCanary(payload)
+	query := fasthttp.AcquireRequest()
+	query.SetRequestURI(normalizedURL)
+	query.URI().QueryArgs().Set(name, querySent)
+
+	bodySent, bodyCanary := i.options.withCanary(payload)
+	body := fasthttp.AcquireRequest()
+	body.SetRequestURI(normalizedURL)
+	body.Header.SetMethod(fasthttp.MethodPost)
+	body.Header.SetContentType("application/x-www-form-urlencoded")
+	args := fasthttp.AcquireArgs()
+	args.Set(name, bodySent)
+	body.SetBody(args.QueryString())
+	fasthttp.ReleaseArgs(args)
+
+	batch := []pipelinedRequest{
+		{benign, "", "", ""},
+		{query, "pipelined_query", "query", queryCanary},
+		{body, "pipelined_body", "body", bodyCanary},
+	}
+	defer func() {
+		for _, p := range batch {
+			fasthttp.ReleaseRequest(p.req)
+		}
+	}()
+
+	reqs := make([]*fasthttp.Request, len(batch))
+	for n, p := range batch {
+		i.options.prepare(p.req)
+		reqs[n] = p.req
+	}
+
+	logger.debug.Printf("Sending %d pipelined requests to %s", len(reqs), normalizedURL)
+	resps, err := i.options.pipeline(reqs)
+	defer func() {
+		for _, r := range resps {
+			fasthttp.ReleaseResponse(r.resp)
+		}
+	}()
+	if err != nil {
+		logger.error.Printf("Pipelined batch got %d of %d responses: %v", len(resps), len(reqs), err)
+	}
+
+	for n, r := range resps {
+		p := batch[n]
+		if p.technique == "" {
+			continue
+		}
+		resp, outcome := r.resp, r.outcome
+		result := TestResult{
+			Request:          snapshotRequest(p.req),
+			Payload:          payload,
+			EvasionTechnique: p.technique,
+			RequestPart:      p.part,
+			StatusCode:       outcome.statusCode(resp),
+			ResponseTime:     r.elapsed,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Canary:           p.canary,
+		}
+		results = append(results, result)
+		logger.info.Printf("%s test result: %s", p.technique, result.String())
+	}
+	return results
+}
+
+// pipeline writes reqs back-to-back on one new connection to the first
+// request's host and reads their responses in order. Responses are read with
+// fasthttp's HTTP/1.1 parser, which dechunks chunked bodies and consumes
+// their trailers, so each status line is read where its response starts. It
+// returns the responses read before any error; the caller releases them. Under
+// ResetIsBlock a connection dropped mid-batch, which is new and has the
+// batch written to it, leaves empty responses flagged as resets for the
+// unanswered requests instead of an error.
+func (o *InjectorOptions) pipeline(reqs []*fasthttp.Request) ([]pipelinedResponse, error) {
+	start := time.Now()
+	for _, req := range reqs {
+		if err := o.admit(req); err != nil {
+			return nil, err
+		}
+		req.Header.ResetConnectionClose()
+	}
+	// The server closes the connection after answering the last request
+	reqs[len(reqs)-1].SetConnectionClose()
+
+	conn, err := o.dialPipeline(reqs[0].URI())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(DefaultPipelineTimeout)); err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(conn)
+	for _, req := range reqs {
+		if err := req.Write(w); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	var resps []pipelinedResponse
+	r := bufio.NewReader(conn)
+	for n, req := range reqs {
+		resp := fasthttp.AcquireResponse()
+		resp.SkipBody = req.Header.IsHead()
+		var outcome sendOutcome
+		if err := tolerateBodyTooLarge(resp.ReadLimitBody(r, o.MaxResponseBodySize), resp); err != nil {
+			if !o.ResetIsBlock || !IsConnectionReset(err) {
+				fasthttp.ReleaseResponse(resp)
+				return resps, fmt.Errorf("response %d: %w", n+1, err)
+			}
+			resp.Reset()
+			outcome.reset = true
+		} else {
+			outcome.signature = o.matchesSignature(resp)
+			o.CookieJar.Capture(req, resp)
+		}
+		resps = append(resps, pipelinedResponse{resp, outcome, time.Since(start)})
+	}
+	return resps, nil
+}
+
+// dialPipeline opens the raw connection for a batch to uri's host, from the
+// next source address when those rotate, over TLS for https
+func (o *InjectorOptions) dialPipeline(uri *fasthttp.URI) (net.Conn, error) {
+	addr, isTLS := pipelineAddr(uri)
+
+	var conn net.Conn
+	var err error
+	if o.SourceAddresses != nil {
+		conn, err = o.SourceAddresses.Dial(addr)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, DefaultDialTimeout)
+	}
+	if err != nil || !isTLS {
+		return conn, err
+	}
+	serverName, _, _ := net.SplitHostPort(addr)
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: serverName})
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// pipelineAddr returns the host:port to dial for uri, with the scheme's
+// default port when it has none, and whether the connection uses TLS
+func pipelineAddr(uri *fasthttp.URI) (string, bool) {
+	host := string(uri.Host())
+	isTLS := string(uri.Scheme()) == "https"
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host, isTLS
+	}
+	port := "80"
+	if isTLS {
+		port = "443"
+	}
+	// An IPv6 host without a port is still bracketed
+	hostname := strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	return net.JoinHostPort(hostname, port), isTLS
+}
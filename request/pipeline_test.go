@@ -0,0 +1,196 @@
+package request
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestPipelineInjectorDeliversBatchOnOneConnection(t *testing.T) {
+	var connections atomic.Int64
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		value := r.Form.Get("param")
+		mu.Lock()
+		received = append(received, r.Method+" "+value)
+		mu.Unlock()
+		// Echo the request so each response can be matched to its request
+		w.Write([]byte(r.Method + ":" + value))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	payload := "<script>alert(1)</script>"
+	results := NewPipelineInjectorWithOptions(DefaultInjectorOptions()).Inject(server.URL, payload, NewLoggerWithLevel(os.Stderr, LogLevelError))
+
+	want := map[string]string{
+		"pipelined_query": "GET:" + payload,
+		"pipelined_body":  "POST:" + payload,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for _, result := range results {
+		if result.StatusCode != http.StatusOK || result.ResponseBody != want[result.EvasionTechnique] {
+			t.Errorf("%s: status %d body %q, want 200 %q", result.EvasionTechnique, result.StatusCode, result.ResponseBody, want[result.EvasionTechnique])
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 3 || received[0] != "GET " || received[1] != "GET "+payload || received[2] != "POST "+payload {
+		t.Errorf("server received %q, want the benign GET then both payloads", received)
+	}
+	if n := connections.Load(); n != 1 {
+		t.Errorf("batch used %d connections, want 1", n)
+	}
+}
+
+func TestPipelineInjectorParsesChunkedResponsesWithTrailers(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// Answer the batch with chunked responses, chunk extensions and
+	// trailers: a parser that stops at the terminating chunk instead of
+	// after the trailers reads the next status line from trailer bytes
+	responses := []string{
+		"HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n2\r\nok\r\n0\r\n\r\n",
+		"HTTP/1.1 403 Forbidden\r\nTransfer-Encoding: chunked\r\nTrailer: X-Block-Id\r\n\r\n" +
+			"7\r\nblocked\r\n5;reason=waf\r\n by W\r\n3\r\nAF.\r\n0\r\nX-Block-Id: 42\r\n\r\n",
+		"HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\nTrailer: X-Trace\r\n\r\n" +
+			"a\r\npost body \r\n2\r\nok\r\n0\r\nX-Trace: abc\r\n\r\n",
+	}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for range responses {
+			req, err := http.ReadRequest(r)
+			if err != nil {
+				return
+			}
+			io.Copy(io.Discard, req.Body)
+		}
+		conn.Write([]byte(strings.Join(responses, "")))
+	}()
+
+	results := NewPipelineInjectorWithOptions(DefaultInjectorOptions()).Inject("http://"+listener.Addr().String(), "<script>alert(1)</script>", NewLoggerWithLevel(os.Stderr, LogLevelError))
+
+	want := map[string]struct {
+		status  int
+		body    string
+		blocked bool
+	}{
+		"pipelined_query": {http.StatusForbidden, "blocked by WAF.", true},
+		"pipelined_body":  {http.StatusOK, "post body ok", false},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for _, result := range results {
+		w := want[result.EvasionTechnique]
+		if result.StatusCode != w.status || result.ResponseBody != w.body || result.Blocked != w.blocked {
+			t.Errorf("%s: status %d body %q blocked %v, want %d %q %v",
+				result.EvasionTechnique, result.StatusCode, result.ResponseBody, result.Blocked, w.status, w.body, w.blocked)
+		}
+	}
+}
+
+func TestPipelineResultsAreClassifiedAndTimedPerResponse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// The query gets a 200 block page right away; the body's answer comes
+	// late
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for range 3 {
+			req, err := http.ReadRequest(r)
+			if err != nil {
+				return
+			}
+			io.Copy(io.Discard, req.Body)
+		}
+		blockPage := "Request Rejected by Acme Shield"
+		conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"))
+		conn.Write([]byte(fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Length: %d\r\n\r\n%s", len(blockPage), blockPage)))
+		time.Sleep(200 * time.Millisecond)
+		conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"))
+	}()
+
+	path := filepath.Join(t.TempDir(), "signatures.txt")
+	if err := os.WriteFile(path, []byte("request rejected by acme shield\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultInjectorOptions()
+	if opts.BlockSignatures, err = LoadBlockSignatures(path); err != nil {
+		t.Fatal(err)
+	}
+	results := NewPipelineInjectorWithOptions(opts).Inject("http://"+listener.Addr().String(), "<script>alert(1)</script>", NewLoggerWithLevel(os.Stderr, LogLevelError))
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	query, body := results[0], results[1]
+	if !query.Blocked || query.BlockReason != BlockReasonSignature {
+		t.Errorf("query: blocked %v reason %q, want blocked by %s", query.Blocked, query.BlockReason, BlockReasonSignature)
+	}
+	if body.Blocked {
+		t.Errorf("body: blocked by %q, want a pass", body.BlockReason)
+	}
+	if query.ResponseTime <= 0 || body.ResponseTime-query.ResponseTime < 100*time.Millisecond {
+		t.Errorf("response times %s and %s, want the late response timed on its own", query.ResponseTime, body.ResponseTime)
+	}
+}
+
+func TestPipelineAddrDefaultsThePort(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"http://example.com/":      "example.com:80",
+		"https://example.com/":     "example.com:443",
+		"http://example.com:8080/": "example.com:8080",
+		"http://[::1]/":            "[::1]:80",
+		"https://[2001:db8::1]/":   "[2001:db8::1]:443",
+		"http://[2001:db8::1]:81/": "[2001:db8::1]:81",
+	} {
+		uri := fasthttp.AcquireURI()
+		if err := uri.Parse(nil, []byte(rawURL)); err != nil {
+			t.Fatalf("parse %s: %v", rawURL, err)
+		}
+		if addr, _ := pipelineAddr(uri); addr != want {
+			t.Errorf("pipelineAddr(%s) = %q, want %q", rawURL, addr, want)
+		}
+		fasthttp.ReleaseURI(uri)
+	}
+}
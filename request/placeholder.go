@@ -1 +1,62 @@
 package request
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PayloadMarker marks where payloads are placed in a -body-file template
+const PayloadMarker = "§PAYLOAD§"
+
+// BodyTemplateTechnique labels results sent with a body template
+const BodyTemplateTechnique = "body_template"
+
+// defaultTemplateContentType is used when neither the flag nor the file
+// extension gives a content type
+const defaultTemplateContentType = "text/plain"
+
+// BodyTemplate is a raw request body (a SOAP envelope, protobuf text, ...)
+// with one or more PayloadMarker tokens to replace
+type BodyTemplate struct {
+	Body        string
+	ContentType string
+}
+
+// NewBodyTemplate checks that body contains PayloadMarker. An empty
+// contentType falls back to text/plain.
+func NewBodyTemplate(body, contentType string) (*BodyTemplate, error) {
+	if !strings.Contains(body, PayloadMarker) {
+		return nil, fmt.Errorf("body template has no %s marker", PayloadMarker)
+	}
+	if contentType == "" {
+		contentType = defaultTemplateContentType
+	}
+	return &BodyTemplate{Body: body, ContentType: contentType}, nil
+}
+
+// LoadBodyTemplate reads a body template from path. An empty contentType is
+// guessed from the file extension (.xml, .json, ...).
+func LoadBodyTemplate(path, contentType string) (*BodyTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body template: %w", err)
+	}
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(path))
+	}
+	template, err := NewBodyTemplate(string(data), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return template, nil
+}
+
+// Render returns the template with every marker replaced by payload. The
+// payload is inserted verbatim; escaping it for the body format is left to
+// the evasion variants.
+func (t *BodyTemplate) Render(payload string) string {
+	return strings.ReplaceAll(t.Body, PayloadMarker, payload)
+}
@@ -0,0 +1,96 @@
+package request
+
+import (
+	"bytes"
+	"net/url"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultMaxRedirects is the hop limit used when redirects are followed
+// without an explicit -max-redirects
+const DefaultMaxRedirects = 5
+
+// blockPageMarkers are Location path and query fragments of the block pages
+// WAFs redirect blocked requests to instead of answering 403. Matching is
+// case insensitive.
+var blockPageMarkers = [][]byte{
+	[]byte("block"),
+	[]byte("denied"),
+	[]byte("forbidden"),
+	[]byte("reject"),
+	[]byte("captcha"),
+	[]byte("/cdn-cgi/"),
+	[]byte("waf"),
+}
+
+// IsBlocked reports whether resp is a block: a 403 or 429, or a redirect to
+// what looks like a block page (see IsBlockRedirect). Resets under
+// -reset-is-block and -block-signatures-file matches are known only to the
+// sender (see sendOutcome).
+func IsBlocked(resp *fasthttp.Response) bool {
+	status := resp.StatusCode()
+	return status == fasthttp.StatusForbidden || status == fasthttp.StatusTooManyRequests || IsBlockRedirect(resp)
+}
+
+// IsBlockRedirect reports whether resp redirects to a block page, which a WAF
+// may do in place of a 403 when redirects are not followed
+func IsBlockRedirect(resp *fasthttp.Response) bool {
+	if !fasthttp.StatusCodeIsRedirect(resp.StatusCode()) {
+		return false
+	}
+	// Only the path and query name the page; a host such as
+	// waf-docs.example.com or blocked.example.net says nothing
+	location, err := url.Parse(string(resp.Header.Peek("Location")))
+	if err != nil {
+		return false
+	}
+	page := bytes.ToLower([]byte(location.EscapedPath() + "?" + location.RawQuery))
+	for _, marker := range blockPageMarkers {
+		if bytes.Contains(page, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// doRedirects sends req with c and follows up to maxRedirects redirects the way
+// fasthttp's DoRedirects does, leaving the final response in resp. Each hop
+// is checked against scope before it is sent, which DoRedirects cannot do,
+// and req itself is left as sent so results record the original request.
+func doRedirects(c *fasthttp.Client, req *fasthttp.Request, resp *fasthttp.Response, maxRedirects int, scope *Scope) error {
+	hop := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(hop)
+	req.CopyTo(hop)
+
+	for redirects := 0; ; redirects++ {
+		if err := c.Do(hop, resp); err != nil {
+			return err
+		}
+		status := resp.StatusCode()
+		if !fasthttp.StatusCodeIsRedirect(status) {
+			return nil
+		}
+		if redirects >= maxRedirects {
+			return fasthttp.ErrTooManyRedirects
+		}
+		location := resp.Header.Peek("Location")
+		if len(location) == 0 {
+			return fasthttp.ErrMissingLocation
+		}
+
+		host := string(hop.URI().Host())
+		hop.URI().UpdateBytes(location)
+		if err := scope.Check(hop.URI().String()); err != nil {
+			return err
+		}
+		// A -host override only applies to the original target
+		if string(hop.URI().Host()) != host {
+			hop.UseHostHeader = false
+			hop.Header.SetHostBytes(hop.URI().Host())
+		}
+		if string(hop.Header.Method()) == fasthttp.MethodPost && (status == fasthttp.StatusMovedPermanently || status == fasthttp.StatusFound) {
+			hop.Header.SetMethod(fasthttp.MethodGet)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestFollowedRedirectToBlockPageIsBlocked(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/notice", http.StatusFound)
+	})
+	mux.HandleFunc("/notice", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Request rejected by security policy", http.StatusForbidden)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	payload := "<script>alert(1)</script>"
+	logger := NewLoggerWithLevel(os.Stderr, LogLevelError)
+
+	// Without following, the 302 is all that is seen
+	for _, result := range NewFastHTTPQueryInjector().Inject(server.URL, payload, logger) {
+		if result.Blocked || result.StatusCode != http.StatusFound {
+			t.Errorf("%s without following: status %d blocked=%v, want an unblocked 302", result.EvasionTechnique, result.StatusCode, result.Blocked)
+		}
+	}
+
+	opts := DefaultInjectorOptions()
+	opts.FollowRedirects = true
+	results := NewFastHTTPQueryInjectorWithOptions(opts).Inject(server.URL, payload, logger)
+	if len(results) == 0 {
+		t.Fatal("no results")
+	}
+	for _, result := range results {
+		if !result.Blocked || result.StatusCode != http.StatusForbidden {
+			t.Errorf("%s following redirects: status %d blocked=%v, want the blocked 403", result.EvasionTechnique, result.StatusCode, result.Blocked)
+		}
+		if sent := RecordRequest(result.Request).URL; sent == server.URL+"/notice" {
+			t.Errorf("%s recorded the redirect target instead of the request sent", result.EvasionTechnique)
+		}
+	}
+}
+
+func TestRedirectToBlockPageCountsWithoutFollowing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/errors/Access-Denied.html", http.StatusFound)
+	}))
+	defer server.Close()
+
+	for _, result := range NewFastHTTPQueryInjector().Inject(server.URL, "' OR 1=1--", NewLoggerWithLevel(os.Stderr, LogLevelError)) {
+		if !result.Blocked {
+			t.Errorf("%s: redirect to an access-denied page not counted as blocked", result.EvasionTechnique)
+		}
+	}
+}
+
+func TestRedirectsOutOfScopeAreNotFollowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://elsewhere.example.net/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	scope, err := ParseScope([]string{"127.0.0.1"})
+	if err != nil {
+		t.Fatalf("ParseScope() error: %v", err)
+	}
+	opts := DefaultInjectorOptions()
+	opts.FollowRedirects = true
+	opts.Scope = scope
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(server.URL)
+	if _, err := opts.do(req, resp); !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("do() error = %v, want ErrOutOfScope for the redirect hop", err)
+	}
+}
+
+func TestBlockRedirectIgnoresTheLocationHost(t *testing.T) {
+	for location, want := range map[string]bool{
+		"/errors/Access-Denied.html":                true,
+		"https://shop.example.com/?reason=blocked":  true,
+		"https://sso.example.com/cdn-cgi/challenge": true,
+		"https://blocked-users.example.com/home":    false,
+		"https://waf.example.net/login?next=%2F":    false,
+		"/account/login":                            false,
+	} {
+		resp := fasthttp.AcquireResponse()
+		resp.SetStatusCode(http.StatusFound)
+		resp.Header.Set("Location", location)
+		if got := IsBlockRedirect(resp); got != want {
+			t.Errorf("IsBlockRedirect(Location: %s) = %v, want %v", location, got, want)
+		}
+		fasthttp.ReleaseResponse(resp)
+	}
+}
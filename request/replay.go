@@ -0,0 +1,120 @@
+package request
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultReplayTimeout bounds a single replayed request
+const DefaultReplayTimeout = 30 * time.Second
+
+// RecordedHeader is one request header as it was sent
+type RecordedHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// RecordedRequest holds everything needed to re-issue a sent request exactly:
+// method, full URL, headers in order, and the raw body
+type RecordedRequest struct {
+	Method  string           `json:"method"`
+	URL     string           `json:"url"`
+	Headers []RecordedHeader `json:"headers,omitempty"`
+	Body    string           `json:"body,omitempty"`
+}
+
+// RecordRequest captures req in a serializable form; a nil req records nothing
+func RecordRequest(req *fasthttp.Request) RecordedRequest {
+	if req == nil {
+		return RecordedRequest{}
+	}
+	recorded := RecordedRequest{
+		Method: string(req.Header.Method()),
+		URL:    req.URI().String(),
+		Body:   string(req.Body()),
+	}
+	for name, value := range req.Header.All() {
+		recorded.Headers = append(recorded.Headers, RecordedHeader{Name: string(name), Value: string(value)})
+	}
+	return recorded
+}
+
+// Build reconstructs the request. When targetURL is set, its scheme and host
+// replace the recorded ones (e.g. to send through a proxy listener or to a
+// staging copy of the target); path and query are always kept as recorded.
+// The caller must release the returned request.
+func (r RecordedRequest) Build(targetURL string) (*fasthttp.Request, error) {
+	requestURL := r.URL
+	if targetURL != "" {
+		normalizedTarget, err := NormalizeURL(targetURL)
+		if err != nil {
+			return nil, err
+		}
+		target, err := url.Parse(normalizedTarget)
+		if err != nil {
+			return nil, err
+		}
+		recorded, err := url.Parse(r.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recorded URL %q: %w", r.URL, err)
+		}
+		recorded.Scheme = target.Scheme
+		recorded.Host = target.Host
+		recorded.User = target.User
+		requestURL = recorded.String()
+	}
+
+	recordedHost := ""
+	if recorded, err := url.Parse(r.URL); err == nil {
+		recordedHost = recorded.Host
+	}
+
+	req := fasthttp.AcquireRequest()
+	// Recorded names are as sent, including raw ones such as a payload in a
+	// header name, so they must not be re-cased
+	req.Header.DisableNormalizing()
+	req.SetRequestURI(requestURL)
+	req.Header.SetMethod(r.Method)
+	for _, header := range r.Headers {
+		switch {
+		case strings.EqualFold(header.Name, "Host"):
+			// Host normally follows the request URL; keep it only if it was overridden
+			if header.Value != recordedHost {
+				req.UseHostHeader = true
+				req.Header.SetHost(header.Value)
+			}
+		case strings.EqualFold(header.Name, "Content-Length"):
+			// Content-Length follows the body
+		default:
+			req.Header.Add(header.Name, header.Value)
+		}
+	}
+	if r.Body != "" {
+		req.SetBodyString(r.Body)
+	}
+	return req, nil
+}
+
+// Replay re-sends a recorded request and writes the full raw response to w
+func Replay(recorded RecordedRequest, targetURL string, w io.Writer) error {
+	req, err := recorded.Build(targetURL)
+	if err != nil {
+		return err
+	}
+	defer fasthttp.ReleaseRequest(req)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	if err := client.DoTimeout(req, resp, DefaultReplayTimeout); err != nil {
+		return fmt.Errorf("replay request to %s failed: %w", req.URI().String(), err)
+	}
+
+	_, err = resp.WriteTo(w)
+	return err
+}
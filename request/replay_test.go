@@ -0,0 +1,170 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+type recordedCall struct {
+	method  string
+	uri     string
+	headers http.Header
+	body    string
+}
+
+func TestReplayReissuesStoredRequest(t *testing.T) {
+	var mu sync.Mutex
+	var calls []recordedCall
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		calls = append(calls, recordedCall{r.Method, r.RequestURI, r.Header.Clone(), string(body)})
+		mu.Unlock()
+		w.Header().Set("X-Replay-Check", "yes")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("blocked"))
+	}))
+	defer server.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	logger := NewLoggerWithLevel(devNull, LogLevelError)
+
+	payload := `"><svg onload=alert(1)>`
+	var results []TestResult
+	results = append(results, NewFastHTTPBodyInjector().Inject(server.URL+"/submit?x=1", payload, logger)...)
+	results = append(results, NewFastHTTPHeaderInjector().Inject(server.URL, payload, logger)...)
+	if len(results) == 0 {
+		t.Fatal("injectors produced no results")
+	}
+
+	for i, result := range results {
+		t.Run(result.EvasionTechnique, func(t *testing.T) {
+			// Round-trip through JSON as a saved report would
+			encoded, err := json.Marshal(RecordRequest(result.Request))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var stored RecordedRequest
+			if err := json.Unmarshal(encoded, &stored); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			mu.Lock()
+			original := calls[i]
+			before := len(calls)
+			mu.Unlock()
+
+			var out bytes.Buffer
+			if err := Replay(stored, "", &out); err != nil {
+				t.Fatalf("Replay() error: %v", err)
+			}
+
+			mu.Lock()
+			if len(calls) != before+1 {
+				mu.Unlock()
+				t.Fatalf("expected one replayed request, got %d", len(calls)-before)
+			}
+			replayed := calls[len(calls)-1]
+			mu.Unlock()
+
+			if replayed.method != original.method || replayed.uri != original.uri || replayed.body != original.body {
+				t.Errorf("replayed %s %s %q, want %s %s %q",
+					replayed.method, replayed.uri, replayed.body, original.method, original.uri, original.body)
+			}
+			for name, values := range original.headers {
+				if got := replayed.headers[name]; strings.Join(got, "|") != strings.Join(values, "|") {
+					t.Errorf("header %s = %v, want %v", name, got, values)
+				}
+			}
+			if !strings.Contains(out.String(), "403") || !strings.Contains(out.String(), "X-Replay-Check: yes") ||
+				!strings.HasSuffix(out.String(), "blocked") {
+				t.Errorf("replay output missing full response:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestRecordedRequestBuildOverridesTarget(t *testing.T) {
+	recorded := RecordedRequest{
+		Method:  "POST",
+		URL:     "http://waf.example.com:80/login?next=%2Fadmin",
+		Headers: []RecordedHeader{{Name: "Host", Value: "waf.example.com:80"}, {Name: "X-Test", Value: "1"}},
+		Body:    "user=admin",
+	}
+
+	req, err := recorded.Build("https://staging.example.com:8443")
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	defer fasthttp.ReleaseRequest(req)
+
+	if got := req.URI().String(); got != "https://staging.example.com:8443/login?next=%2Fadmin" {
+		t.Errorf("URL = %q", got)
+	}
+	if got := string(req.URI().Host()); got != "staging.example.com:8443" || req.UseHostHeader {
+		t.Errorf("Host = %q (UseHostHeader %v), want the override target", got, req.UseHostHeader)
+	}
+	if got := string(req.Header.Peek("X-Test")); got != "1" {
+		t.Errorf("X-Test = %q, want 1", got)
+	}
+	if got := string(req.Body()); got != recorded.Body {
+		t.Errorf("body = %q, want %q", got, recorded.Body)
+	}
+}
+
+func TestHostOverrideReachesHandler(t *testing.T) {
+	var mu sync.Mutex
+	var hosts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hosts = append(hosts, r.Host)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	logger := NewLoggerWithLevel(devNull, LogLevelError)
+
+	const host = "internal.example.com"
+	opts := &InjectorOptions{Host: host}
+	var results []TestResult
+	for _, injector := range NewInjectors(opts) {
+		results = append(results, injector.Inject(server.URL, "<script>", logger)...)
+	}
+
+	// A replayed result keeps the overridden Host
+	if len(results) == 0 {
+		t.Fatal("injectors produced no results")
+	}
+	if err := Replay(RecordRequest(results[0].Request), "", io.Discard); err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(hosts) == 0 {
+		t.Fatal("no requests received")
+	}
+	for i, got := range hosts {
+		if got != host {
+			t.Errorf("request %d: Host = %q, want %q", i, got, host)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"syscall"
+
+	"github.com/valyala/fasthttp"
+)
+
+// IsConnectionReset reports whether err means the peer dropped the
+// connection: a TCP RST, a broken pipe, or a close before (or in the middle
+// of) the response. Some WAFs block that way, but a stale keep-alive
+// connection fails the same way, so it only means a block for a request
+// written on a connection of its own (see sendFresh).
+func IsConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, fasthttp.ErrConnectionClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,103 @@
+package request
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestResetIsBlockFlagsDroppedConnectionsAsBlocked(t *testing.T) {
+	// Drop the connection with a RST when the decoded query holds the tag,
+	// the way some WAFs block
+	dropped := func(rawQuery string) bool {
+		query, _ := url.QueryUnescape(rawQuery)
+		return strings.Contains(query, "<script>")
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !dropped(r.URL.RawQuery) {
+			w.Write([]byte("ok"))
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		conn.(*net.TCPConn).SetLinger(0)
+		conn.Close()
+	}))
+	defer server.Close()
+
+	payload := "<script>alert(1)</script>"
+	logger := NewLoggerWithLevel(os.Stderr, LogLevelError)
+
+	// Without the option a dropped request is a failed request, not a result
+	for _, result := range NewFastHTTPQueryInjector().Inject(server.URL, payload, logger) {
+		if result.Blocked {
+			t.Errorf("%s blocked without -reset-is-block", result.EvasionTechnique)
+		}
+	}
+
+	opts := DefaultInjectorOptions()
+	opts.ResetIsBlock = true
+	resets := 0
+	for _, result := range NewFastHTTPQueryInjectorWithOptions(opts).Inject(server.URL, payload, logger) {
+		sent, err := url.Parse(RecordRequest(result.Request).URL)
+		if err != nil {
+			t.Fatalf("recorded URL: %v", err)
+		}
+		if !dropped(sent.RawQuery) {
+			if result.Blocked || result.BlockReason != "" {
+				t.Errorf("%s answered 200 but blocked=%v reason=%q", result.EvasionTechnique, result.Blocked, result.BlockReason)
+			}
+			continue
+		}
+		resets++
+		if !result.Blocked || result.BlockReason != BlockReasonConnectionReset || result.StatusCode != 0 {
+			t.Errorf("%s reset: blocked=%v reason=%q status=%d, want blocked by %s with no status",
+				result.EvasionTechnique, result.Blocked, result.BlockReason, result.StatusCode, BlockReasonConnectionReset)
+		}
+	}
+	if resets == 0 {
+		t.Fatal("no request was dropped; the test server did not exercise resets")
+	}
+}
+
+func TestResetIsBlockIgnoresStaleKeepAliveConnections(t *testing.T) {
+	// Answer without Connection: close, then drop the connection the way an
+	// idle keep-alive connection goes stale
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok")
+		buf.Flush()
+		conn.Close()
+	}))
+	defer server.Close()
+
+	opts := DefaultInjectorOptions()
+	opts.ResetIsBlock = true
+	for n := 0; n < 3; n++ {
+		req := fasthttp.AcquireRequest()
+		resp := fasthttp.AcquireResponse()
+		req.SetRequestURI(server.URL)
+		// POST is not retried, so a stale connection would surface
+		req.Header.SetMethod(fasthttp.MethodPost)
+		opts.prepare(req)
+		outcome, err := opts.do(req, resp)
+		if err != nil || outcome.reset || outcome.statusCode(resp) != http.StatusOK {
+			t.Errorf("request %d: reset=%v status=%d err=%v, want a 200", n+1, outcome.reset, outcome.statusCode(resp), err)
+		}
+		fasthttp.ReleaseRequest(req)
+		fasthttp.ReleaseResponse(resp)
+	}
+}
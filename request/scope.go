@@ -0,0 +1,89 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ErrOutOfScope is returned for requests to a host outside the -scope allowlist
+var ErrOutOfScope = errors.New("host is out of scope")
+
+// Scope is an allowlist of hosts requests may be sent to, so a typo in a URL
+// file or a redirect cannot point the tool at someone else's server. Entries
+// are host globs ("example.com", "*.example.com") or CIDRs ("10.0.0.0/8"). A
+// nil Scope allows every host.
+type Scope struct {
+	globs []string
+	nets  []*net.IPNet
+}
+
+// ParseScope builds a scope from host globs and CIDRs; each entry may itself
+// be a comma-separated list. It returns nil (allow all) when entries is empty.
+func ParseScope(entries []string) (*Scope, error) {
+	scope := &Scope{}
+	for _, entry := range entries {
+		for _, pattern := range strings.Split(entry, ",") {
+			pattern = strings.ToLower(strings.TrimSpace(pattern))
+			if pattern == "" {
+				continue
+			}
+			if strings.Contains(pattern, "/") {
+				_, ipNet, err := net.ParseCIDR(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("invalid scope CIDR %q: %w", pattern, err)
+				}
+				scope.nets = append(scope.nets, ipNet)
+				continue
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid scope pattern %q: %w", pattern, err)
+			}
+			scope.globs = append(scope.globs, pattern)
+		}
+	}
+	if len(scope.globs) == 0 && len(scope.nets) == 0 {
+		return nil, nil
+	}
+	return scope, nil
+}
+
+// Allows reports whether host (a hostname or IP, without port) is in scope
+func (s *Scope) Allows(host string) bool {
+	if s == nil {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(strings.Trim(host, "[]"), "."))
+	if ip := net.ParseIP(host); ip != nil {
+		for _, ipNet := range s.nets {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	for _, glob := range s.globs {
+		if ok, _ := path.Match(glob, host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error wrapping ErrOutOfScope when rawURL's host is not in
+// scope
+func (s *Scope) Check(rawURL string) error {
+	if s == nil {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("cannot check scope of %q: %w", rawURL, err)
+	}
+	if !s.Allows(u.Hostname()) {
+		return fmt.Errorf("%w: refusing to send to %s (%s is not in -scope)", ErrOutOfScope, rawURL, u.Hostname())
+	}
+	return nil
+}
@@ -3,12 +3,21 @@ package request
 import (
 	"bufio"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+
+	"obfuskit/internal/redact"
+	"obfuskit/types"
 
 	"github.com/valyala/fasthttp"
 )
@@ -48,15 +57,11 @@ func (ll *levelLogger) Println(v ...interface{}) {
 	}
 }
 
-func (ll *levelLogger) Writer() *os.File {
-	// Underlying Writer may not always be *os.File; fall back to os.Stdout
+func (ll *levelLogger) Writer() io.Writer {
 	if ll == nil || ll.l == nil {
 		return os.Stdout
 	}
-	if w, ok := ll.l.Writer().(*os.File); ok {
-		return w
-	}
-	return os.Stdout
+	return ll.l.Writer()
 }
 
 type Logger struct {
@@ -81,18 +86,23 @@ func NewLoggerWithLevel(out *os.File, level string) *Logger {
 	enableWarn := enableInfo || lvl == LogLevelWarn
 	// error always enabled
 
+	// Everything logged goes through redaction so secrets never reach the output
+	w := redact.Writer(out)
 	return &Logger{
-		debug: &levelLogger{l: log.New(out, "[DEBUG] ", log.Ldate|log.Ltime|log.Lshortfile), enabled: enableDebug},
-		info:  &levelLogger{l: log.New(out, "[INFO] ", log.Ldate|log.Ltime|log.Lshortfile), enabled: enableInfo},
-		warn:  &levelLogger{l: log.New(out, "[WARN] ", log.Ldate|log.Ltime|log.Lshortfile), enabled: enableWarn},
-		error: &levelLogger{l: log.New(out, "[ERROR] ", log.Ldate|log.Ltime|log.Lshortfile), enabled: true},
+		debug: &levelLogger{l: log.New(w, "[DEBUG] ", log.Ldate|log.Ltime|log.Lshortfile), enabled: enableDebug},
+		info:  &levelLogger{l: log.New(w, "[INFO] ", log.Ldate|log.Ltime|log.Lshortfile), enabled: enableInfo},
+		warn:  &levelLogger{l: log.New(w, "[WARN] ", log.Ldate|log.Ltime|log.Lshortfile), enabled: enableWarn},
+		error: &levelLogger{l: log.New(w, "[ERROR] ", log.Ldate|log.Ltime|log.Lshortfile), enabled: true},
 	}
 }
 
 var defaultLogger = NewLogger(os.Stdout)
 
-// normalizeURL ensures the URL has a proper scheme and explicit port
-func normalizeURL(targetURL string) (string, error) {
+// NormalizeURL ensures the URL has a proper scheme and explicit port, and is
+// the form every injector sends to. Userinfo (user:pass@) is preserved so
+// fasthttp can send basic auth, and any #fragment is stripped since it is
+// never sent on the wire.
+func NormalizeURL(targetURL string) (string, error) {
 	// Add scheme if missing
 	if !strings.Contains(targetURL, "://") {
 		targetURL = "http://" + targetURL
@@ -103,6 +113,10 @@ func normalizeURL(targetURL string) (string, error) {
 		return "", err
 	}
 
+	// Fragments are client-side only
+	parsedURL.Fragment = ""
+	parsedURL.RawFragment = ""
+
 	// Add default port if missing
 	if parsedURL.Port() == "" {
 		hostname := parsedURL.Hostname()
@@ -111,12 +125,10 @@ func normalizeURL(targetURL string) (string, error) {
 		}
 
 		switch parsedURL.Scheme {
-		case "http":
-			parsedURL.Host = hostname + ":80"
 		case "https":
-			parsedURL.Host = hostname + ":443"
+			parsedURL.Host = net.JoinHostPort(hostname, "443")
 		default:
-			parsedURL.Host = hostname + ":80"
+			parsedURL.Host = net.JoinHostPort(hostname, "80")
 		}
 	}
 
@@ -124,18 +136,73 @@ func normalizeURL(targetURL string) (string, error) {
 }
 
 type TestResult struct {
-	Request          *fasthttp.Request
+	Request *fasthttp.Request
+	// Variant is the generated variant that was sent, with its metadata;
+	// injectors leave it for the caller to set
+	Variant          types.Variant
 	Payload          string
 	EvasionTechnique string
 	RequestPart      string
 	StatusCode       int
 	ResponseTime     time.Duration
 	Blocked          bool
+	// BlockReason says why a blocked result counts as blocked when that is
+	// more than its status code: the WAF its response headers identify
+	// (e.g. "ModSecurity"), BlockReasonConnectionReset or BlockReasonRedirect
+	BlockReason string
+	// RuleID is the rule or incident ID the block response's headers carry
+	// (X-Mod-Security-Id, CF-Ray, ...), to map results to WAF rules
+	RuleID string
+	// ResponseBody holds up to MaxCapturedBodySize bytes of the response body
+	ResponseBody string
+	// AttackType is the attack the payload belongs to, when known
+	AttackType string
+	// CandidateBypass marks an error status that, for AttackType, suggests the
+	// payload had an effect (see Classify)
+	CandidateBypass bool
+	// Challenge marks a WAF challenge page (JS challenge, captcha), which is
+	// neither a block nor a bypass whatever the status code (see IsChallenge)
+	Challenge bool
+	// Cached marks a response that appears to come from a CDN or proxy
+	// cache rather than a WAF-evaluated request (see IsCached)
+	Cached bool
+	// Deviations lists how the response differs from a recorded clean
+	// baseline (see Baseline.Deviations); a result with any is anomalous
+	Deviations []string
+	// Canary is the token appended to the payload in stored mode, and Stored
+	// marks a result whose canary later appeared on the view page (see
+	// CanaryTracker)
+	Canary string
+	Stored bool
+	// Timing breaks ResponseTime down into phases; it is only recorded in
+	// timing mode (InjectorOptions.Timing)
+	Timing Timing
+}
+
+// MaxCapturedBodySize caps how much of each response body is kept on a TestResult
+const MaxCapturedBodySize = 4096
+
+// snapshotRequest copies req so the result stays valid after req is released
+func snapshotRequest(req *fasthttp.Request) *fasthttp.Request {
+	snapshot := &fasthttp.Request{}
+	req.CopyTo(snapshot)
+	return snapshot
+}
+
+// capturedBody returns the response body truncated to MaxCapturedBodySize
+func capturedBody(resp *fasthttp.Response) string {
+	body := resp.Body()
+	if len(body) > MaxCapturedBodySize {
+		body = body[:MaxCapturedBodySize]
+	}
+	return string(body)
 }
 
 func (r TestResult) String() string {
 	blockedStatus := "Not Blocked"
-	if r.Blocked {
+	if r.Challenge {
+		blockedStatus = "Challenge"
+	} else if r.Blocked {
 		blockedStatus = "Blocked"
 	}
 	return fmt.Sprintf(
@@ -199,17 +266,268 @@ type FastHTTPInjector interface {
 	Inject(targetURL string, payload string, logger *Logger) []TestResult
 }
 
+// DefaultParamName is the parameter payloads are injected into when no
+// explicit name is configured
+const DefaultParamName = "param"
+
+// InjectorOptions holds settings shared by the injectors
+type InjectorOptions struct {
+	// ParamNames lists the query/body parameter names payloads are injected into
+	ParamNames []string
+	// Host overrides the Host header; the connection still goes to the URL's host
+	Host string
+	// UserAgent is set on every request when no rotator is configured
+	UserAgent string
+	// UserAgents, when set, picks a User-Agent per request and overrides UserAgent
+	UserAgents *UserAgentRotator
+	// Diversifier, when set, adds a varying set of benign headers to every
+	// request so they do not share one fingerprint
+	Diversifier *HeaderDiversifier
+	// Budget, when set, caps the total requests sent by every injector sharing it
+	Budget *RequestBudget
+	// CookieJar, when set, replays cookies the target set on later requests
+	CookieJar *CookieJar
+	// BodyTemplate, when set, replaces the body injector's form and JSON tests
+	// with the template, payload placed at its markers
+	BodyTemplate *BodyTemplate
+	// DisableConnect, DisableChunked and DisableMultipleContentLength skip the
+	// protocol injector's CONNECT, chunked and conflicting Content-Length
+	// requests, which can upset proxies in front of the target
+	DisableConnect               bool
+	DisableChunked               bool
+	DisableMultipleContentLength bool
+	// HeaderNameInjection adds the header injector's requests with the
+	// payload in a header name. They are opt-in because strict servers
+	// answer most of them 400, which would otherwise read as bypasses.
+	HeaderNameInjection bool
+	// Pipeline adds the pipeline injector, which sends payloads in HTTP/1.1
+	// pipelined batches on a raw connection
+	Pipeline bool
+	// SplitParams, when it names two or more parameters, adds the split
+	// injector, which sends each payload cut into one fragment per parameter
+	// in a single request. SplitAt sets the fragment boundaries as rune
+	// offsets, one fewer than the parameters; empty splits evenly.
+	SplitParams []string
+	SplitAt     []int
+	// CacheBust sends Cache-Control/Pragma no-cache and a random CacheBustParam
+	// on every request, so a cache in front of the WAF cannot answer it
+	CacheBust bool
+	// FollowRedirects follows up to MaxRedirects redirects (DefaultMaxRedirects
+	// when 0) and classifies the final response, so a WAF redirecting blocked
+	// requests to a 403 page is counted as blocking
+	FollowRedirects bool
+	MaxRedirects    int
+	// ReadBufferSize raises the client's per-connection read buffer, which
+	// also caps the response header size (fasthttp default when 0)
+	ReadBufferSize int
+	// MaxResponseBodySize caps the response body read (0 = unlimited); a
+	// larger body is dropped but the response is still classified by status
+	MaxResponseBodySize int
+	// SourceAddresses, when set, sends each request from the next local
+	// address in its rotation, on a connection of its own
+	SourceAddresses *SourceAddressRotator
+	// ResetIsBlock records a connection reset or closed before the response
+	// as a blocked result with no status (BlockReasonConnectionReset) instead
+	// of a failed request, for WAFs that drop blocked requests
+	ResetIsBlock bool
+	// Scope, when set, refuses every request to a host outside the allowlist
+	// with ErrOutOfScope before anything is sent
+	Scope *Scope
+	// BlockSignatures, when set, counts every response whose body matches
+	// one of its signatures as blocked, whatever the status code
+	BlockSignatures *BlockSignatures
+	// Timing sends every request on a connection of its own and records the
+	// DNS, connect, TLS, TTFB and transfer phases of its response time on
+	// the result (see Timing)
+	Timing bool
+	// Canaries, when set, appends a canary of its own to the payload of
+	// every request (stored mode, see CanaryTracker)
+	Canaries *CanaryTracker
+}
+
+// DefaultInjectorOptions returns the options used by the plain constructors
+func DefaultInjectorOptions() *InjectorOptions {
+	return &InjectorOptions{
+		ParamNames: []string{DefaultParamName},
+	}
+}
+
+// paramNames returns the configured parameter names, falling back to the default
+func (o *InjectorOptions) paramNames() []string {
+	if o == nil {
+		return []string{DefaultParamName}
+	}
+	var names []string
+	for _, name := range o.ParamNames {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return []string{DefaultParamName}
+	}
+	return names
+}
+
+// allows reports whether the protocol injector may send technique
+func (o *InjectorOptions) allows(technique string) bool {
+	if o == nil {
+		return true
+	}
+	switch technique {
+	case "unusual_http_method_CONNECT":
+		return !o.DisableConnect
+	case "chunked_encoding":
+		return !o.DisableChunked
+	case "multiple_content_length":
+		return !o.DisableMultipleContentLength
+	}
+	return true
+}
+
+// prepare applies request-level settings to an outgoing request
+func (o *InjectorOptions) prepare(req *fasthttp.Request) {
+	if o == nil {
+		return
+	}
+	if o.Host != "" {
+		req.UseHostHeader = true
+		req.Header.SetHost(o.Host)
+	}
+	if o.SourceAddresses != nil {
+		// Reused connections would keep the address they were dialed from
+		req.SetConnectionClose()
+	}
+	if o.Timing || o.ResetIsBlock {
+		// A reused connection would skip the phases being timed, and its
+		// reset may just mean it went stale
+		req.SetConnectionClose()
+	}
+	if o.UserAgents != nil {
+		req.Header.SetUserAgent(o.UserAgents.Next())
+	} else if o.UserAgent != "" {
+		req.Header.SetUserAgent(o.UserAgent)
+	}
+	if o.Diversifier != nil {
+		o.Diversifier.Apply(req)
+	}
+}
+
+// do sends req unless the request budget is spent, carrying jar cookies,
+// and reports what it found out about the request besides its response
+func (o *InjectorOptions) do(req *fasthttp.Request, resp *fasthttp.Response) (sendOutcome, error) {
+	if o == nil {
+		return sendOutcome{}, client.Do(req, resp)
+	}
+	if err := o.admit(req); err != nil {
+		return sendOutcome{}, err
+	}
+	outcome, err := o.send(req, resp)
+	if err != nil || outcome.reset {
+		return outcome, err
+	}
+	outcome.signature = o.matchesSignature(resp)
+	o.CookieJar.Capture(req, resp)
+	return outcome, nil
+}
+
+// Send sends req the way the injectors do: checked against the scope,
+// counted against the request budget and with the configured client
+// settings. Probes outside the injectors, such as WAF fingerprinting, send
+// through it so they keep to the same limits.
+func (o *InjectorOptions) Send(req *fasthttp.Request, resp *fasthttp.Response) error {
+	_, err := o.do(req, resp)
+	return err
+}
+
+// admit checks req against the scope and the request budget, then adds jar
+// cookies and cache busting; every request must pass it before it is sent
+func (o *InjectorOptions) admit(req *fasthttp.Request) error {
+	if err := o.Scope.Check(req.URI().String()); err != nil {
+		return err
+	}
+	if !o.Budget.Take() {
+		return ErrBudgetExhausted
+	}
+	o.CookieJar.Apply(req)
+	if o.CacheBust {
+		cacheBust(req)
+	}
+	return nil
+}
+
+// send sends req with the client for the configured response limits,
+// following redirects when FollowRedirects is set, or on a connection of its
+// own in timing mode and under ResetIsBlock
+func (o *InjectorOptions) send(req *fasthttp.Request, resp *fasthttp.Response) (sendOutcome, error) {
+	if o.Timing || o.ResetIsBlock {
+		return o.sendFresh(req, resp)
+	}
+	c := clientFor(clientSettings{o.ReadBufferSize, o.MaxResponseBodySize, o.SourceAddresses})
+	if !o.FollowRedirects {
+		return sendOutcome{}, tolerateBodyTooLarge(c.Do(req, resp), resp)
+	}
+	maxRedirects := o.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+	return sendOutcome{}, tolerateBodyTooLarge(doRedirects(c, req, resp, maxRedirects, o.Scope), resp)
+}
+
+// jsonParamBody builds a JSON object body carrying payload under the
+// parameter name. The name is marshaled, since a -param value may hold
+// quotes or backslashes; the payload only has its quotes escaped, so the
+// rest of it reaches the target as written.
+func jsonParamBody(name, payload string) string {
+	key, _ := json.Marshal(name) // a string always marshals
+	return fmt.Sprintf(`{%s: "%s"}`, key, strings.ReplaceAll(payload, `"`, `\"`))
+}
+
+// ParseParamNames splits a comma-separated list of parameter names
+func ParseParamNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// NewInjectors returns the standard set of injectors configured with opts
+func NewInjectors(opts *InjectorOptions) []FastHTTPInjector {
+	injectors := []FastHTTPInjector{
+		NewFastHTTPHeaderInjectorWithOptions(opts),
+		NewFastHTTPQueryInjectorWithOptions(opts),
+		NewFastHTTPBodyInjectorWithOptions(opts),
+		NewFastHTTPProtocolInjectorWithOptions(opts),
+	}
+	if opts != nil && opts.Pipeline {
+		injectors = append(injectors, NewPipelineInjectorWithOptions(opts))
+	}
+	if opts != nil && len(opts.SplitParams) >= 2 {
+		injectors = append(injectors, NewSplitParamInjectorWithOptions(opts))
+	}
+	return injectors
+}
+
 type FastHTTPHeaderInjector struct {
 	transformers []EncodingTransformer
+	options      *InjectorOptions
 }
 
 func NewFastHTTPHeaderInjector() *FastHTTPHeaderInjector {
+	return NewFastHTTPHeaderInjectorWithOptions(DefaultInjectorOptions())
+}
+
+func NewFastHTTPHeaderInjectorWithOptions(opts *InjectorOptions) *FastHTTPHeaderInjector {
 	return &FastHTTPHeaderInjector{
 		transformers: []EncodingTransformer{
 			&URLEncoder{},
 			&Base64Encoder{},
 			&LineFoldingTransformer{},
 		},
+		options: opts,
 	}
 }
 
@@ -223,7 +541,7 @@ func (i *FastHTTPHeaderInjector) Inject(targetURL string, payload string, logger
 	logger.info.Printf("Starting header injection test with payload: %s", payload)
 
 	// Normalize the URL
-	normalizedURL, err := normalizeURL(targetURL)
+	normalizedURL, err := NormalizeURL(targetURL)
 	if err != nil {
 		logger.error.Printf("Failed to normalize URL %s: %v", targetURL, err)
 		return results
@@ -235,22 +553,31 @@ func (i *FastHTTPHeaderInjector) Inject(targetURL string, payload string, logger
 	defer fasthttp.ReleaseResponse(resp)
 
 	req.SetRequestURI(normalizedURL)
-	req.Header.Set("X-Custom-Header", payload)
+	i.options.prepare(req)
+	sent, canary := i.options.withCanary(payload)
+	req.Header.Set("X-Custom-Header", sent)
 
 	logger.debug.Printf("Sending request to %s with basic header injection", normalizedURL)
 	start := time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err := i.options.do(req, resp)
 	duration := time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "basic_header",
 			RequestPart:      "header",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Basic header test result: %s", result.String())
@@ -260,7 +587,8 @@ func (i *FastHTTPHeaderInjector) Inject(targetURL string, payload string, logger
 
 	// Try different encodings for the header value
 	for _, transformer := range i.transformers {
-		transformedPayload := transformer.Transform(payload)
+		sent, canary := i.options.withCanary(payload)
+		transformedPayload := transformer.Transform(sent)
 
 		req := fasthttp.AcquireRequest()
 		resp := fasthttp.AcquireResponse()
@@ -268,22 +596,30 @@ func (i *FastHTTPHeaderInjector) Inject(targetURL string, payload string, logger
 		defer fasthttp.ReleaseResponse(resp)
 
 		req.SetRequestURI(normalizedURL)
+		i.options.prepare(req)
 		req.Header.Set("X-Custom-Header", transformedPayload)
 
 		logger.debug.Printf("Sending request with %s encoded header: %s", transformer.Name(), transformedPayload)
 		start := time.Now()
-		err := fasthttp.Do(req, resp)
+		outcome, err := i.options.do(req, resp)
 		duration := time.Since(start)
 
 		if err == nil {
 			result := TestResult{
-				Request:          req,
+				Request:          snapshotRequest(req),
 				Payload:          payload,
 				EvasionTechnique: "header_" + transformer.Name(),
 				RequestPart:      "header",
-				StatusCode:       resp.StatusCode(),
+				StatusCode:       outcome.statusCode(resp),
 				ResponseTime:     duration,
-				Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+				Blocked:          outcome.blocked(resp),
+				BlockReason:      outcome.blockReason(resp),
+				RuleID:           outcome.ruleID(resp),
+				ResponseBody:     capturedBody(resp),
+				Challenge:        IsChallenge(resp),
+				Cached:           IsCached(resp),
+				Timing:           outcome.timing,
+				Canary:           canary,
 			}
 			results = append(results, result)
 			logger.info.Printf("%s header test result: %s", transformer.Name(), result.String())
@@ -297,28 +633,37 @@ func (i *FastHTTPHeaderInjector) Inject(targetURL string, payload string, logger
 	resp = fasthttp.AcquireResponse()
 
 	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
 
 	// Directly set the raw header - note the \r\n with space for line folding
-	if len(payload) > 2 {
-		midpoint := len(payload) / 2
-		foldedPayload := payload[:midpoint] + "\r\n " + payload[midpoint:]
+	sent, canary = i.options.withCanary(payload)
+	if len(sent) > 2 {
+		midpoint := len(sent) / 2
+		foldedPayload := sent[:midpoint] + "\r\n " + sent[midpoint:]
 		req.Header.SetBytesKV([]byte("X-Folded-Header"), []byte(foldedPayload))
 		logger.debug.Printf("Sending request with manual line folding header: %s", foldedPayload)
 	}
 
 	start = time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err = i.options.do(req, resp)
 	duration = time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "manual_line_folding",
 			RequestPart:      "header",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Manual line folding test result: %s", result.String())
@@ -331,24 +676,33 @@ func (i *FastHTTPHeaderInjector) Inject(targetURL string, payload string, logger
 	resp = fasthttp.AcquireResponse()
 
 	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
 	// Add header multiple times with different values
+	sent, canary = i.options.withCanary(payload)
 	req.Header.Add("X-Duplicate-Header", "legitimate")
-	req.Header.Add("X-Duplicate-Header", payload)
+	req.Header.Add("X-Duplicate-Header", sent)
 
 	logger.debug.Printf("Sending request with duplicate headers")
 	start = time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err = i.options.do(req, resp)
 	duration = time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "duplicate_header",
 			RequestPart:      "header",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Duplicate header test result: %s", result.String())
@@ -356,21 +710,101 @@ func (i *FastHTTPHeaderInjector) Inject(targetURL string, payload string, logger
 		logger.error.Printf("Duplicate header test failed: %v", err)
 	}
 
-	logger.info.Printf("Completed header injection tests: %d successful, %d total", len(results), len(i.transformers)+3)
+	// Header name injection - the payload is part of the header name itself,
+	// set raw so fasthttp neither normalizes nor validates it
+	for _, variant := range i.headerNameVariants() {
+		req := fasthttp.AcquireRequest()
+		resp := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseRequest(req)
+		defer fasthttp.ReleaseResponse(resp)
+
+		req.SetRequestURI(normalizedURL)
+		i.options.prepare(req)
+		sent, canary := i.options.withCanary(payload)
+		name := headerName(variant.separator, sent)
+		req.Header.SetCanonical([]byte(name), []byte("1"))
+
+		logger.debug.Printf("Sending request with the payload in header name %q", name)
+		start := time.Now()
+		outcome, err := i.options.do(req, resp)
+		duration := time.Since(start)
+
+		if err == nil {
+			result := TestResult{
+				Request:          snapshotRequest(req),
+				Payload:          payload,
+				EvasionTechnique: variant.technique,
+				RequestPart:      "header",
+				StatusCode:       outcome.statusCode(resp),
+				ResponseTime:     duration,
+				Blocked:          outcome.blocked(resp),
+				BlockReason:      outcome.blockReason(resp),
+				RuleID:           outcome.ruleID(resp),
+				ResponseBody:     capturedBody(resp),
+				Challenge:        IsChallenge(resp),
+				Cached:           IsCached(resp),
+				Timing:           outcome.timing,
+				Canary:           canary,
+			}
+			results = append(results, result)
+			logger.info.Printf("%s test result: %s", variant.technique, result.String())
+		} else {
+			logger.error.Printf("%s test failed: %v", variant.technique, err)
+		}
+	}
+
+	logger.info.Printf("Completed header injection tests: %d successful, %d total", len(results), len(i.transformers)+3+len(i.headerNameVariants()))
 	return results
 }
 
+// headerNameVariants returns the header name injections to send, none
+// unless HeaderNameInjection is set
+func (i *FastHTTPHeaderInjector) headerNameVariants() []headerNameSeparator {
+	if i.options == nil || !i.options.HeaderNameInjection {
+		return nil
+	}
+	return headerNameSeparators
+}
+
+type headerNameSeparator struct {
+	separator string
+	technique string
+}
+
+// headerNameSeparators join a benign prefix and the payload into a header
+// name. Strict servers reject names with characters outside the HTTP token
+// set (the space always, most payload characters too), so these mostly probe
+// WAFs and proxies that parse names more leniently than the origin.
+var headerNameSeparators = []headerNameSeparator{
+	{"-", "header_name"},
+	{"_", "header_name_underscore"},
+	{".", "header_name_dot"},
+	{" ", "header_name_space"},
+}
+
+// headerName returns the header name carrying payload after separator. Line
+// breaks are dropped so the payload stays within one header line.
+func headerName(separator, payload string) string {
+	return "X" + separator + strings.NewReplacer("\r", "", "\n", "").Replace(payload)
+}
+
 // FastHTTPQueryInjector injects payloads into URL query parameters
 type FastHTTPQueryInjector struct {
 	transformers []EncodingTransformer
+	options      *InjectorOptions
 }
 
 func NewFastHTTPQueryInjector() *FastHTTPQueryInjector {
+	return NewFastHTTPQueryInjectorWithOptions(DefaultInjectorOptions())
+}
+
+func NewFastHTTPQueryInjectorWithOptions(opts *InjectorOptions) *FastHTTPQueryInjector {
 	return &FastHTTPQueryInjector{
 		transformers: []EncodingTransformer{
 			&URLEncoder{},
 			&DoubleURLEncoder{},
 		},
+		options: opts,
 	}
 }
 
@@ -383,16 +817,39 @@ func (i *FastHTTPQueryInjector) Inject(targetURL string, payload string, logger
 
 	logger.info.Printf("Starting query injection test with payload: %s", payload)
 
-	parsedURL, err := url.Parse(targetURL)
+	// Normalize the URL
+	normalizedURL, err := NormalizeURL(targetURL)
 	if err != nil {
-		logger.error.Printf("Failed to parse URL %s: %v", targetURL, err)
+		logger.error.Printf("Failed to normalize URL %s: %v", targetURL, err)
 		return results
 	}
 
+	for _, name := range i.options.paramNames() {
+		results = append(results, i.injectParam(normalizedURL, name, payload, logger)...)
+	}
+
+	logger.info.Printf("Completed query injection tests: %d successful", len(results))
+	return results
+}
+
+// injectParam runs the query injection tests for a single parameter name
+func (i *FastHTTPQueryInjector) injectParam(normalizedURL, name, payload string, logger *Logger) []TestResult {
+	results := []TestResult{}
+
+	parsedURL, err := url.Parse(normalizedURL)
+	if err != nil {
+		logger.error.Printf("Failed to parse URL %s: %v", normalizedURL, err)
+		return results
+	}
+
+	// Existing query parameters are kept verbatim; injected values are appended
+	baseQuery := parsedURL.RawQuery
+
 	// Basic query parameter injection
-	params := parsedURL.Query()
-	params.Add("param", payload)
-	parsedURL.RawQuery = params.Encode()
+	sent, canary := i.options.withCanary(payload)
+	params := url.Values{}
+	params.Add(name, sent)
+	parsedURL.RawQuery = appendRawQuery(baseQuery, params)
 
 	req := fasthttp.AcquireRequest()
 	resp := fasthttp.AcquireResponse()
@@ -401,21 +858,29 @@ func (i *FastHTTPQueryInjector) Inject(targetURL string, payload string, logger
 
 	testURL := parsedURL.String()
 	req.SetRequestURI(testURL)
+	i.options.prepare(req)
 
 	logger.debug.Printf("Sending request to %s with basic query param", testURL)
 	start := time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err := i.options.do(req, resp)
 	duration := time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "basic_query_param",
 			RequestPart:      "query",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Basic query param test result: %s", result.String())
@@ -424,32 +889,40 @@ func (i *FastHTTPQueryInjector) Inject(targetURL string, payload string, logger
 	}
 
 	// Duplicate parameter test
-	parsedURL, _ = url.Parse(targetURL)
-	params = parsedURL.Query()
-	params.Add("param", "legitimate")
-	params.Add("param", payload)
-	parsedURL.RawQuery = params.Encode()
+	sent, canary = i.options.withCanary(payload)
+	params = url.Values{}
+	params.Add(name, "legitimate")
+	params.Add(name, sent)
+	parsedURL.RawQuery = appendRawQuery(baseQuery, params)
 
 	req = fasthttp.AcquireRequest()
 	resp = fasthttp.AcquireResponse()
 
 	testURL = parsedURL.String()
 	req.SetRequestURI(testURL)
+	i.options.prepare(req)
 
 	logger.debug.Printf("Sending request to %s with duplicate query params", testURL)
 	start = time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err = i.options.do(req, resp)
 	duration = time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "duplicate_query_param",
 			RequestPart:      "query",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Duplicate query param test result: %s", result.String())
@@ -457,21 +930,231 @@ func (i *FastHTTPQueryInjector) Inject(targetURL string, payload string, logger
 		logger.error.Printf("Duplicate query param test failed: %v", err)
 	}
 
-	logger.info.Printf("Completed query injection tests: %d successful, %d total", len(results), 2)
+	// Payload split across ';'-separated repeats of the parameter: servers that
+	// also split on ';' and join repeated values rebuild it, a WAF splitting
+	// only on '&' sees one value with the payload cut in half
+	sent, canary = i.options.withCanary(payload)
+	parsedURL.RawQuery = SemicolonSplitQuery(baseQuery, name, sent)
+
+	req = fasthttp.AcquireRequest()
+	resp = fasthttp.AcquireResponse()
+
+	testURL = parsedURL.String()
+	req.SetRequestURI(testURL)
+	i.options.prepare(req)
+
+	logger.debug.Printf("Sending request to %s with semicolon-split query params", testURL)
+	start = time.Now()
+	outcome, err = i.options.do(req, resp)
+	duration = time.Since(start)
+
+	if err == nil {
+		result := TestResult{
+			Request:          snapshotRequest(req),
+			Payload:          payload,
+			EvasionTechnique: SemicolonSplitTechnique,
+			RequestPart:      "query",
+			StatusCode:       outcome.statusCode(resp),
+			ResponseTime:     duration,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
+		}
+		results = append(results, result)
+		logger.info.Printf("Semicolon split param test result: %s", result.String())
+	} else {
+		logger.error.Printf("Semicolon split param test failed: %v", err)
+	}
+
+	// ';' instead of '&' between every parameter
+	sent, canary = i.options.withCanary(payload)
+	parsedURL.RawQuery = SemicolonQuery(baseQuery, name, sent)
+
+	req = fasthttp.AcquireRequest()
+	resp = fasthttp.AcquireResponse()
+
+	testURL = parsedURL.String()
+	req.SetRequestURI(testURL)
+	i.options.prepare(req)
+
+	logger.debug.Printf("Sending request to %s with semicolon-separated query params", testURL)
+	start = time.Now()
+	outcome, err = i.options.do(req, resp)
+	duration = time.Since(start)
+
+	if err == nil {
+		result := TestResult{
+			Request:          snapshotRequest(req),
+			Payload:          payload,
+			EvasionTechnique: SemicolonSeparatorTechnique,
+			RequestPart:      "query",
+			StatusCode:       outcome.statusCode(resp),
+			ResponseTime:     duration,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
+		}
+		results = append(results, result)
+		logger.info.Printf("Semicolon separator test result: %s", result.String())
+	} else {
+		logger.error.Printf("Semicolon separator test failed: %v", err)
+	}
+
+	// Parameter name in other cases ("Param", "pArAm") for WAFs that key
+	// rules on the exact name while the app matches names case-insensitively
+	for _, caseName := range ParamNameCaseVariants(name) {
+		sent, canary := i.options.withCanary(payload)
+		params = url.Values{}
+		params.Add(caseName, sent)
+		parsedURL.RawQuery = appendRawQuery(baseQuery, params)
+
+		req = fasthttp.AcquireRequest()
+		resp = fasthttp.AcquireResponse()
+
+		testURL = parsedURL.String()
+		req.SetRequestURI(testURL)
+		i.options.prepare(req)
+
+		logger.debug.Printf("Sending request to %s with query param name %q", testURL, caseName)
+		start = time.Now()
+		outcome, err := i.options.do(req, resp)
+		duration = time.Since(start)
+
+		if err == nil {
+			result := TestResult{
+				Request:          snapshotRequest(req),
+				Payload:          payload,
+				EvasionTechnique: ParamNameCaseTechnique,
+				RequestPart:      "query",
+				StatusCode:       outcome.statusCode(resp),
+				ResponseTime:     duration,
+				Blocked:          outcome.blocked(resp),
+				BlockReason:      outcome.blockReason(resp),
+				RuleID:           outcome.ruleID(resp),
+				ResponseBody:     capturedBody(resp),
+				Challenge:        IsChallenge(resp),
+				Cached:           IsCached(resp),
+				Timing:           outcome.timing,
+				Canary:           canary,
+			}
+			results = append(results, result)
+			logger.info.Printf("Param name case test result: %s", result.String())
+		} else {
+			logger.error.Printf("Param name case test failed: %v", err)
+		}
+	}
+
 	return results
 }
 
+// appendRawQuery appends encoded params to an existing raw query string
+// without re-encoding or reordering the parameters already present
+func appendRawQuery(rawQuery string, params url.Values) string {
+	encoded := params.Encode()
+	if rawQuery == "" {
+		return encoded
+	}
+	if encoded == "" {
+		return rawQuery
+	}
+	return rawQuery + "&" + encoded
+}
+
+const (
+	// SemicolonSplitTechnique labels the payload split over ';'-separated params
+	SemicolonSplitTechnique = "semicolon_split_param"
+	// SemicolonSeparatorTechnique labels queries using ';' instead of '&'
+	SemicolonSeparatorTechnique = "semicolon_separator"
+)
+
+// SemicolonSplitQuery appends name twice, joined by ';', with the payload's
+// halves as values: "a=1&name=<scr;name=ipt>". A payload too short to split
+// is sent whole.
+func SemicolonSplitQuery(rawQuery, name, payload string) string {
+	runes := []rune(payload)
+	half := len(runes) / 2
+	parts := []string{string(runes[:half]), string(runes[half:])}
+	if half == 0 {
+		parts = []string{payload}
+	}
+	var pairs []string
+	for _, part := range parts {
+		pairs = append(pairs, url.QueryEscape(name)+"="+url.QueryEscape(part))
+	}
+	return joinQuery(rawQuery, "&", strings.Join(pairs, ";"))
+}
+
+// SemicolonQuery appends name=payload and uses ';' as the only separator,
+// rewriting the '&' of the existing query as well
+func SemicolonQuery(rawQuery, name, payload string) string {
+	pair := url.QueryEscape(name) + "=" + url.QueryEscape(payload)
+	return joinQuery(strings.ReplaceAll(rawQuery, "&", ";"), ";", pair)
+}
+
+// joinQuery appends pairs to rawQuery with sep, leaving rawQuery untouched
+func joinQuery(rawQuery, sep, pairs string) string {
+	if rawQuery == "" {
+		return pairs
+	}
+	return rawQuery + sep + pairs
+}
+
+// ParamNameCaseTechnique labels requests carrying the payload under a
+// differently cased parameter name
+const ParamNameCaseTechnique = "param_name_case"
+
+// ParamNameCaseVariants returns name capitalized and in alternating case
+// ("param" -> "Param", "pArAm"), skipping forms equal to name itself
+func ParamNameCaseVariants(name string) []string {
+	runes := []rune(strings.ToLower(name))
+	if len(runes) == 0 {
+		return nil
+	}
+	title := append([]rune{unicode.ToUpper(runes[0])}, runes[1:]...)
+	alternating := make([]rune, len(runes))
+	for i, r := range runes {
+		if i%2 == 1 {
+			r = unicode.ToUpper(r)
+		}
+		alternating[i] = r
+	}
+
+	var variants []string
+	for _, variant := range []string{string(title), string(alternating)} {
+		if variant != name && !slices.Contains(variants, variant) {
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
 // FastHTTPBodyInjector injects payloads into request bodies
 type FastHTTPBodyInjector struct {
 	transformers []EncodingTransformer
+	options      *InjectorOptions
 }
 
 func NewFastHTTPBodyInjector() *FastHTTPBodyInjector {
+	return NewFastHTTPBodyInjectorWithOptions(DefaultInjectorOptions())
+}
+
+func NewFastHTTPBodyInjectorWithOptions(opts *InjectorOptions) *FastHTTPBodyInjector {
 	return &FastHTTPBodyInjector{
 		transformers: []EncodingTransformer{
 			&URLEncoder{},
 			&Base64Encoder{},
 		},
+		options: opts,
 	}
 }
 
@@ -485,38 +1168,63 @@ func (i *FastHTTPBodyInjector) Inject(targetURL string, payload string, logger *
 	logger.info.Printf("Starting body injection test with payload: %s", payload)
 
 	// Normalize the URL
-	normalizedURL, err := normalizeURL(targetURL)
+	normalizedURL, err := NormalizeURL(targetURL)
 	if err != nil {
 		logger.error.Printf("Failed to normalize URL %s: %v", targetURL, err)
 		return results
 	}
 
+	if i.options != nil && i.options.BodyTemplate != nil {
+		results = append(results, i.injectTemplate(normalizedURL, payload, logger)...)
+	} else {
+		for _, name := range i.options.paramNames() {
+			results = append(results, i.injectParam(normalizedURL, name, payload, logger)...)
+		}
+	}
+
+	logger.info.Printf("Completed body injection tests: %d successful", len(results))
+	return results
+}
+
+// injectParam runs the body injection tests for a single parameter name
+func (i *FastHTTPBodyInjector) injectParam(normalizedURL, name, payload string, logger *Logger) []TestResult {
+	results := []TestResult{}
+
 	// Basic form parameter injection
 	req := fasthttp.AcquireRequest()
 	resp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseRequest(req)
 	defer fasthttp.ReleaseResponse(resp)
 
-	formBody := fmt.Sprintf("param=%s", payload)
+	sent, canary := i.options.withCanary(payload)
+	formBody := fmt.Sprintf("%s=%s", name, sent)
 	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
 	req.Header.SetMethod("POST")
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.SetBodyString(formBody)
 
 	logger.debug.Printf("Sending POST request with form body: %s", formBody)
 	start := time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err := i.options.do(req, resp)
 	duration := time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "basic_form_param",
 			RequestPart:      "body",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Basic form param test result: %s", result.String())
@@ -528,26 +1236,35 @@ func (i *FastHTTPBodyInjector) Inject(targetURL string, payload string, logger *
 	req = fasthttp.AcquireRequest()
 	resp = fasthttp.AcquireResponse()
 
-	jsonBody := fmt.Sprintf(`{"param": "%s"}`, strings.ReplaceAll(payload, `"`, `\"`))
+	sent, canary = i.options.withCanary(payload)
+	jsonBody := jsonParamBody(name, sent)
 	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
 	req.Header.SetMethod("POST")
 	req.Header.Set("Content-Type", "application/json")
 	req.SetBodyString(jsonBody)
 
 	logger.debug.Printf("Sending POST request with JSON body: %s", jsonBody)
 	start = time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err = i.options.do(req, resp)
 	duration = time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "basic_json_param",
 			RequestPart:      "body",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Basic JSON param test result: %s", result.String())
@@ -555,30 +1272,80 @@ func (i *FastHTTPBodyInjector) Inject(targetURL string, payload string, logger *
 		logger.error.Printf("Basic JSON param test failed: %v", err)
 	}
 
+	// JSON5 parameter injection: comments, unquoted keys, single quotes and a
+	// trailing comma that strict JSON parsers reject
+	req = fasthttp.AcquireRequest()
+	resp = fasthttp.AcquireResponse()
+
+	sent, canary = i.options.withCanary(payload)
+	json5Body := JSON5Body(name, sent)
+	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
+	req.Header.SetMethod("POST")
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBodyString(json5Body)
+
+	logger.debug.Printf("Sending POST request with JSON5 body: %s", json5Body)
+	start = time.Now()
+	outcome, err = i.options.do(req, resp)
+	duration = time.Since(start)
+
+	if err == nil {
+		result := TestResult{
+			Request:          snapshotRequest(req),
+			Payload:          payload,
+			EvasionTechnique: JSON5Technique,
+			RequestPart:      "body",
+			StatusCode:       outcome.statusCode(resp),
+			ResponseTime:     duration,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
+		}
+		results = append(results, result)
+		logger.info.Printf("JSON5 param test result: %s", result.String())
+	} else {
+		logger.error.Printf("JSON5 param test failed: %v", err)
+	}
+
 	// Duplicate form parameter
 	req = fasthttp.AcquireRequest()
 	resp = fasthttp.AcquireResponse()
 
-	duplicateFormBody := fmt.Sprintf("param=legitimate&param=%s", payload)
+	sent, canary = i.options.withCanary(payload)
+	duplicateFormBody := fmt.Sprintf("%s=legitimate&%s=%s", name, name, sent)
 	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
 	req.Header.SetMethod("POST")
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.SetBodyString(duplicateFormBody)
 
 	logger.debug.Printf("Sending POST request with duplicate form params: %s", duplicateFormBody)
 	start = time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err = i.options.do(req, resp)
 	duration = time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "duplicate_form_param",
 			RequestPart:      "body",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Duplicate form param test result: %s", result.String())
@@ -586,29 +1353,122 @@ func (i *FastHTTPBodyInjector) Inject(targetURL string, payload string, logger *
 		logger.error.Printf("Duplicate form param test failed: %v", err)
 	}
 
+	// Form parameter name in other cases ("Param", "pArAm")
+	for _, caseName := range ParamNameCaseVariants(name) {
+		req = fasthttp.AcquireRequest()
+		resp = fasthttp.AcquireResponse()
+
+		sent, canary := i.options.withCanary(payload)
+		caseFormBody := fmt.Sprintf("%s=%s", caseName, sent)
+		req.SetRequestURI(normalizedURL)
+		i.options.prepare(req)
+		req.Header.SetMethod("POST")
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBodyString(caseFormBody)
+
+		logger.debug.Printf("Sending POST request with form param name %q", caseName)
+		start = time.Now()
+		outcome, err := i.options.do(req, resp)
+		duration = time.Since(start)
+
+		if err == nil {
+			result := TestResult{
+				Request:          snapshotRequest(req),
+				Payload:          payload,
+				EvasionTechnique: ParamNameCaseTechnique,
+				RequestPart:      "body",
+				StatusCode:       outcome.statusCode(resp),
+				ResponseTime:     duration,
+				Blocked:          outcome.blocked(resp),
+				BlockReason:      outcome.blockReason(resp),
+				RuleID:           outcome.ruleID(resp),
+				ResponseBody:     capturedBody(resp),
+				Challenge:        IsChallenge(resp),
+				Cached:           IsCached(resp),
+				Timing:           outcome.timing,
+				Canary:           canary,
+			}
+			results = append(results, result)
+			logger.info.Printf("Form param name case test result: %s", result.String())
+		} else {
+			logger.error.Printf("Form param name case test failed: %v", err)
+		}
+	}
+
+	// JSON body under unexpected Accept / Content-Type combinations
+	for _, variant := range NegotiationVariants {
+		req = fasthttp.AcquireRequest()
+		resp = fasthttp.AcquireResponse()
+
+		req.SetRequestURI(normalizedURL)
+		i.options.prepare(req)
+		req.Header.SetMethod("POST")
+		req.Header.Set("Accept", variant.Accept)
+		req.Header.Set("Content-Type", variant.ContentType)
+		sent, canary := i.options.withCanary(payload)
+		req.SetBodyString(jsonParamBody(name, sent))
+
+		logger.debug.Printf("Sending POST request with Accept %q and Content-Type %q", variant.Accept, variant.ContentType)
+		start = time.Now()
+		outcome, err := i.options.do(req, resp)
+		duration = time.Since(start)
+
+		if err == nil {
+			result := TestResult{
+				Request:          snapshotRequest(req),
+				Payload:          payload,
+				EvasionTechnique: variant.Technique,
+				RequestPart:      "body",
+				StatusCode:       outcome.statusCode(resp),
+				ResponseTime:     duration,
+				Blocked:          outcome.blocked(resp),
+				BlockReason:      outcome.blockReason(resp),
+				RuleID:           outcome.ruleID(resp),
+				ResponseBody:     capturedBody(resp),
+				Challenge:        IsChallenge(resp),
+				Cached:           IsCached(resp),
+				Timing:           outcome.timing,
+				Canary:           canary,
+			}
+			results = append(results, result)
+			logger.info.Printf("Content negotiation test result: %s", result.String())
+		} else {
+			logger.error.Printf("Content negotiation test failed: %v", err)
+		}
+	}
+
 	// Content-type mismatch evasion
 	req = fasthttp.AcquireRequest()
 	resp = fasthttp.AcquireResponse()
 
 	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
 	req.Header.SetMethod("POST")
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.SetBodyString(fmt.Sprintf(`{"param": "%s"}`, strings.ReplaceAll(payload, `"`, `\"`)))
+	sent, canary = i.options.withCanary(payload)
+	req.SetBodyString(jsonParamBody(name, sent))
 
 	logger.debug.Printf("Sending POST request with content-type mismatch")
 	start = time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err = i.options.do(req, resp)
 	duration = time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "content_type_mismatch",
 			RequestPart:      "body",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Content-type mismatch test result: %s", result.String())
@@ -616,14 +1476,68 @@ func (i *FastHTTPBodyInjector) Inject(targetURL string, payload string, logger *
 		logger.error.Printf("Content-type mismatch test failed: %v", err)
 	}
 
-	logger.info.Printf("Completed body injection tests: %d successful, %d total", len(results), 4)
 	return results
 }
 
-type FastHTTPProtocolInjector struct{}
+// injectTemplate sends the configured body template with the payload in place
+func (i *FastHTTPBodyInjector) injectTemplate(normalizedURL, payload string, logger *Logger) []TestResult {
+	results := []TestResult{}
+	template := i.options.BodyTemplate
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	sent, canary := i.options.withCanary(payload)
+	body := template.Render(sent)
+	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
+	req.Header.SetMethod("POST")
+	req.Header.Set("Content-Type", template.ContentType)
+	req.SetBodyString(body)
+
+	logger.debug.Printf("Sending POST request with body template: %s", body)
+	start := time.Now()
+	outcome, err := i.options.do(req, resp)
+	duration := time.Since(start)
+
+	if err == nil {
+		result := TestResult{
+			Request:          snapshotRequest(req),
+			Payload:          payload,
+			EvasionTechnique: BodyTemplateTechnique,
+			RequestPart:      "body",
+			StatusCode:       outcome.statusCode(resp),
+			ResponseTime:     duration,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
+		}
+		results = append(results, result)
+		logger.info.Printf("Body template test result: %s", result.String())
+	} else {
+		logger.error.Printf("Body template test failed: %v", err)
+	}
+
+	return results
+}
+
+type FastHTTPProtocolInjector struct {
+	options *InjectorOptions
+}
 
 func NewFastHTTPProtocolInjector() *FastHTTPProtocolInjector {
-	return &FastHTTPProtocolInjector{}
+	return NewFastHTTPProtocolInjectorWithOptions(DefaultInjectorOptions())
+}
+
+func NewFastHTTPProtocolInjectorWithOptions(opts *InjectorOptions) *FastHTTPProtocolInjector {
+	return &FastHTTPProtocolInjector{options: opts}
 }
 
 func (i *FastHTTPProtocolInjector) Name() string {
@@ -636,14 +1550,20 @@ func (i *FastHTTPProtocolInjector) Inject(targetURL string, payload string, logg
 	logger.info.Printf("Starting protocol injection test with payload: %s", payload)
 
 	// Normalize the URL
-	normalizedURL, err := normalizeURL(targetURL)
+	normalizedURL, err := NormalizeURL(targetURL)
 	if err != nil {
 		logger.error.Printf("Failed to normalize URL %s: %v", targetURL, err)
 		return results
 	}
 
 	// Test with unusual HTTP methods
-	unusualMethods := []string{"TRACE", "PATCH", "PROPFIND", "CONNECT"}
+	var unusualMethods []string
+	for _, method := range []string{"TRACE", "PATCH", "PROPFIND", "CONNECT"} {
+		if i.options.allows("unusual_http_method_" + method) {
+			unusualMethods = append(unusualMethods, method)
+		}
+	}
+	attempted := len(unusualMethods) + 1
 	for _, method := range unusualMethods {
 		req := fasthttp.AcquireRequest()
 		resp := fasthttp.AcquireResponse()
@@ -651,23 +1571,32 @@ func (i *FastHTTPProtocolInjector) Inject(targetURL string, payload string, logg
 		defer fasthttp.ReleaseResponse(resp)
 
 		req.SetRequestURI(normalizedURL)
+		i.options.prepare(req)
 		req.Header.SetMethod(method)
-		req.Header.Set("X-Payload", payload)
+		sent, canary := i.options.withCanary(payload)
+		req.Header.Set("X-Payload", sent)
 
 		logger.debug.Printf("Sending %s request with payload in X-Payload header", method)
 		start := time.Now()
-		err = fasthttp.Do(req, resp)
+		outcome, err := i.options.do(req, resp)
 		duration := time.Since(start)
 
 		if err == nil {
 			result := TestResult{
-				Request:          req,
+				Request:          snapshotRequest(req),
 				Payload:          payload,
 				EvasionTechnique: "unusual_http_method_" + method,
 				RequestPart:      "method",
-				StatusCode:       resp.StatusCode(),
+				StatusCode:       outcome.statusCode(resp),
 				ResponseTime:     duration,
-				Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+				Blocked:          outcome.blocked(resp),
+				BlockReason:      outcome.blockReason(resp),
+				RuleID:           outcome.ruleID(resp),
+				ResponseBody:     capturedBody(resp),
+				Challenge:        IsChallenge(resp),
+				Cached:           IsCached(resp),
+				Timing:           outcome.timing,
+				Canary:           canary,
 			}
 			results = append(results, result)
 			logger.info.Printf("Unusual HTTP method %s test result: %s", method, result.String())
@@ -681,26 +1610,35 @@ func (i *FastHTTPProtocolInjector) Inject(targetURL string, payload string, logg
 	resp := fasthttp.AcquireResponse()
 
 	req.SetRequestURI(normalizedURL)
+	i.options.prepare(req)
 
 	// Set a raw header with line folding
 	headerName := "X-Custom-Header"
-	headerValue := "part1\r\n part2" + payload
+	sent, canary := i.options.withCanary(payload)
+	headerValue := "part1\r\n part2" + sent
 	req.Header.SetBytesKV([]byte(headerName), []byte(headerValue))
 
 	logger.debug.Printf("Sending request with header line folding: %s", headerValue)
 	start := time.Now()
-	err = fasthttp.Do(req, resp)
+	outcome, err := i.options.do(req, resp)
 	duration := time.Since(start)
 
 	if err == nil {
 		result := TestResult{
-			Request:          req,
+			Request:          snapshotRequest(req),
 			Payload:          payload,
 			EvasionTechnique: "header_line_folding",
 			RequestPart:      "header",
-			StatusCode:       resp.StatusCode(),
+			StatusCode:       outcome.statusCode(resp),
 			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+			Blocked:          outcome.blocked(resp),
+			BlockReason:      outcome.blockReason(resp),
+			RuleID:           outcome.ruleID(resp),
+			ResponseBody:     capturedBody(resp),
+			Challenge:        IsChallenge(resp),
+			Cached:           IsCached(resp),
+			Timing:           outcome.timing,
+			Canary:           canary,
 		}
 		results = append(results, result)
 		logger.info.Printf("Header line folding test result: %s", result.String())
@@ -709,76 +1647,100 @@ func (i *FastHTTPProtocolInjector) Inject(targetURL string, payload string, logg
 	}
 
 	// Chunked encoding evasion
-	req = fasthttp.AcquireRequest()
-	resp = fasthttp.AcquireResponse()
+	if i.options.allows("chunked_encoding") {
+		attempted++
+		req = fasthttp.AcquireRequest()
+		resp = fasthttp.AcquireResponse()
 
-	req.SetRequestURI(normalizedURL)
-	req.Header.SetMethod("POST")
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Transfer-Encoding", "chunked")
+		req.SetRequestURI(normalizedURL)
+		i.options.prepare(req)
+		req.Header.SetMethod("POST")
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Transfer-Encoding", "chunked")
 
-	chunkData := fmt.Sprintf("param=%s", payload)
-	chunkSize := fmt.Sprintf("%x", len(chunkData))
-	chunkedBody := chunkSize + "\r\n" + chunkData + "\r\n0\r\n\r\n"
+		sent, canary := i.options.withCanary(payload)
+		chunkData := fmt.Sprintf("param=%s", sent)
+		chunkSize := fmt.Sprintf("%x", len(chunkData))
+		chunkedBody := chunkSize + "\r\n" + chunkData + "\r\n0\r\n\r\n"
 
-	req.SetBodyString(chunkedBody)
+		req.SetBodyString(chunkedBody)
 
-	logger.debug.Printf("Sending chunked encoding request with body: %s", chunkedBody)
-	start = time.Now()
-	err = fasthttp.Do(req, resp)
-	duration = time.Since(start)
+		logger.debug.Printf("Sending chunked encoding request with body: %s", chunkedBody)
+		start = time.Now()
+		outcome, err := i.options.do(req, resp)
+		duration = time.Since(start)
 
-	if err == nil {
-		result := TestResult{
-			Request:          req,
-			Payload:          payload,
-			EvasionTechnique: "chunked_encoding",
-			RequestPart:      "body",
-			StatusCode:       resp.StatusCode(),
-			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+		if err == nil {
+			result := TestResult{
+				Request:          snapshotRequest(req),
+				Payload:          payload,
+				EvasionTechnique: "chunked_encoding",
+				RequestPart:      "body",
+				StatusCode:       outcome.statusCode(resp),
+				ResponseTime:     duration,
+				Blocked:          outcome.blocked(resp),
+				BlockReason:      outcome.blockReason(resp),
+				RuleID:           outcome.ruleID(resp),
+				ResponseBody:     capturedBody(resp),
+				Challenge:        IsChallenge(resp),
+				Cached:           IsCached(resp),
+				Timing:           outcome.timing,
+				Canary:           canary,
+			}
+			results = append(results, result)
+			logger.info.Printf("Chunked encoding test result: %s", result.String())
+		} else {
+			logger.error.Printf("Chunked encoding test failed: %v", err)
 		}
-		results = append(results, result)
-		logger.info.Printf("Chunked encoding test result: %s", result.String())
-	} else {
-		logger.error.Printf("Chunked encoding test failed: %v", err)
 	}
 
-	req = fasthttp.AcquireRequest()
-	resp = fasthttp.AcquireResponse()
+	if i.options.allows("multiple_content_length") {
+		attempted++
+		req = fasthttp.AcquireRequest()
+		resp = fasthttp.AcquireResponse()
 
-	req.SetRequestURI(normalizedURL)
-	req.Header.SetMethod("POST")
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetRequestURI(normalizedURL)
+		i.options.prepare(req)
+		req.Header.SetMethod("POST")
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	bodyContent := fmt.Sprintf("param=%s", payload)
-	req.SetBodyString(bodyContent)
+		sent, canary := i.options.withCanary(payload)
+		bodyContent := fmt.Sprintf("param=%s", sent)
+		req.SetBodyString(bodyContent)
 
-	req.Header.Set("Content-Length", fmt.Sprintf("%d", len(bodyContent)))
-	req.Header.Add("Content-Length", fmt.Sprintf("%d", len(bodyContent)+10))
+		req.Header.Set("Content-Length", fmt.Sprintf("%d", len(bodyContent)))
+		req.Header.Add("Content-Length", fmt.Sprintf("%d", len(bodyContent)+10))
 
-	logger.debug.Printf("Sending request with multiple content-length headers")
-	start = time.Now()
-	err = fasthttp.Do(req, resp)
-	duration = time.Since(start)
+		logger.debug.Printf("Sending request with multiple content-length headers")
+		start = time.Now()
+		outcome, err := i.options.do(req, resp)
+		duration = time.Since(start)
 
-	if err == nil {
-		result := TestResult{
-			Request:          req,
-			Payload:          payload,
-			EvasionTechnique: "multiple_content_length",
-			RequestPart:      "header",
-			StatusCode:       resp.StatusCode(),
-			ResponseTime:     duration,
-			Blocked:          resp.StatusCode() == 403 || resp.StatusCode() == 429,
+		if err == nil {
+			result := TestResult{
+				Request:          snapshotRequest(req),
+				Payload:          payload,
+				EvasionTechnique: "multiple_content_length",
+				RequestPart:      "header",
+				StatusCode:       outcome.statusCode(resp),
+				ResponseTime:     duration,
+				Blocked:          outcome.blocked(resp),
+				BlockReason:      outcome.blockReason(resp),
+				RuleID:           outcome.ruleID(resp),
+				ResponseBody:     capturedBody(resp),
+				Challenge:        IsChallenge(resp),
+				Cached:           IsCached(resp),
+				Timing:           outcome.timing,
+				Canary:           canary,
+			}
+			results = append(results, result)
+			logger.info.Printf("Multiple content-length headers test result: %s", result.String())
+		} else {
+			logger.error.Printf("Multiple content-length headers test failed: %v", err)
 		}
-		results = append(results, result)
-		logger.info.Printf("Multiple content-length headers test result: %s", result.String())
-	} else {
-		logger.error.Printf("Multiple content-length headers test failed: %v", err)
 	}
 
-	logger.info.Printf("Completed protocol injection tests: %d successful, %d total", len(results), len(unusualMethods)+3)
+	logger.info.Printf("Completed protocol injection tests: %d successful, %d total", len(results), attempted)
 	return results
 }
 
@@ -1025,12 +1987,7 @@ func RunTests(options ...Option) ([]TestResult, error) {
 }
 
 func runSequentialTests(payloads []string, targetURL string, logger *Logger) []TestResult {
-	injectors := []FastHTTPInjector{
-		NewFastHTTPHeaderInjector(),
-		NewFastHTTPQueryInjector(),
-		NewFastHTTPBodyInjector(),
-		NewFastHTTPProtocolInjector(),
-	}
+	injectors := NewInjectors(DefaultInjectorOptions())
 
 	var allResults []TestResult
 	totalPayloads := len(payloads)
@@ -1039,8 +1996,7 @@ func runSequentialTests(payloads []string, targetURL string, logger *Logger) []T
 		logger.info.Printf("Testing payload %d/%d: %s", i+1, totalPayloads, payload)
 
 		for _, injector := range injectors {
-			results := injector.Inject(targetURL, payload, logger)
-			allResults = append(allResults, results...)
+			allResults = append(allResults, safeInject(injector, targetURL, payload, logger)...)
 		}
 
 		if (i+1)%10 == 0 || i == totalPayloads-1 {
@@ -1053,11 +2009,30 @@ func runSequentialTests(payloads []string, targetURL string, logger *Logger) []T
 }
 
 func runConcurrentTests(payloads []string, targetURL string, concurrency int, logger *Logger) []TestResult {
+	newInjectors := func() []FastHTTPInjector {
+		return NewInjectors(DefaultInjectorOptions())
+	}
+	return runConcurrentTestsWith(payloads, targetURL, concurrency, newInjectors, logger)
+}
+
+// runConcurrentTestsWith fans payloads out to workers, each owning the injectors
+// returned by newInjectors. The results channel is closed once every worker has
+// exited, so the collector never waits on a worker that has already died.
+func runConcurrentTestsWith(payloads []string, targetURL string, concurrency int, newInjectors func() []FastHTTPInjector, logger *Logger) []TestResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
 	jobs := make(chan string, len(payloads))
 	results := make(chan []TestResult, len(payloads))
 
+	var wg sync.WaitGroup
 	for w := 1; w <= concurrency; w++ {
-		go worker(w, jobs, results, targetURL, logger)
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			worker(id, jobs, results, targetURL, newInjectors(), logger)
+		}(w)
 	}
 
 	for _, payload := range payloads {
@@ -1065,28 +2040,27 @@ func runConcurrentTests(payloads []string, targetURL string, concurrency int, lo
 	}
 	close(jobs)
 
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
 	var allResults []TestResult
-	for i := 0; i < len(payloads); i++ {
-		batchResults := <-results
+	completed := 0
+	for batchResults := range results {
 		allResults = append(allResults, batchResults...)
+		completed++
 
-		if (i+1)%10 == 0 || i == len(payloads)-1 {
+		if completed%10 == 0 || completed == len(payloads) {
 			logger.info.Printf("Progress: %d/%d payloads (%.1f%%)",
-				i+1, len(payloads), float64(i+1)/float64(len(payloads))*100)
+				completed, len(payloads), float64(completed)/float64(len(payloads))*100)
 		}
 	}
 
 	return allResults
 }
 
-func worker(id int, jobs <-chan string, results chan<- []TestResult, targetURL string, logger *Logger) {
-	injectors := []FastHTTPInjector{
-		NewFastHTTPHeaderInjector(),
-		NewFastHTTPQueryInjector(),
-		NewFastHTTPBodyInjector(),
-		NewFastHTTPProtocolInjector(),
-	}
-
+func worker(id int, jobs <-chan string, results chan<- []TestResult, targetURL string, injectors []FastHTTPInjector, logger *Logger) {
 	workerLogger := &Logger{
 		debug: &levelLogger{l: log.New(logger.debug.Writer(), fmt.Sprintf("[DEBUG][Worker-%d] ", id), log.Ltime), enabled: logger.debug.enabled},
 		info:  &levelLogger{l: log.New(logger.info.Writer(), fmt.Sprintf("[INFO][Worker-%d] ", id), log.Ltime), enabled: logger.info.enabled},
@@ -1099,14 +2073,25 @@ func worker(id int, jobs <-chan string, results chan<- []TestResult, targetURL s
 
 		var batchResults []TestResult
 		for _, injector := range injectors {
-			results := injector.Inject(targetURL, payload, workerLogger)
-			batchResults = append(batchResults, results...)
+			batchResults = append(batchResults, safeInject(injector, targetURL, payload, workerLogger)...)
 		}
 
 		results <- batchResults
 	}
 }
 
+// safeInject runs a single injector, recovering from panics so one bad
+// injector or payload cannot take down the worker
+func safeInject(injector FastHTTPInjector, targetURL, payload string, logger *Logger) (results []TestResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.error.Printf("Injector %s panicked on payload %q: %v", injector.Name(), payload, r)
+			results = nil
+		}
+	}()
+	return injector.Inject(targetURL, payload, logger)
+}
+
 func WriteResultsToFile(results []TestResult, filename string, format string, logger *Logger) error {
 	logger.info.Printf("Writing %d results to %s in %s format", len(results), filename, format)
 
@@ -0,0 +1,82 @@
+package request
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// BlockSignatures recognizes a custom WAF's block page by its body, for WAFs
+// whose block page is not a 403: a response matching any signature counts as
+// blocked whatever its status code.
+type BlockSignatures struct {
+	literals [][]byte
+	patterns []*regexp.Regexp
+}
+
+// LoadBlockSignatures reads block page signatures from path, one per line.
+// A line starting with "re:" is a regular expression; any other line is a
+// substring, matched case-insensitively. Blank lines and lines starting
+// with # are skipped.
+func LoadBlockSignatures(path string) (*BlockSignatures, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	signatures := &BlockSignatures{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		signature := strings.TrimSpace(scanner.Text())
+		if signature == "" || strings.HasPrefix(signature, "#") {
+			continue
+		}
+		if expr, ok := strings.CutPrefix(signature, "re:"); ok {
+			pattern, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid signature pattern: %w", path, line, err)
+			}
+			signatures.patterns = append(signatures.patterns, pattern)
+			continue
+		}
+		signatures.literals = append(signatures.literals, bytes.ToLower([]byte(signature)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(signatures.literals) == 0 && len(signatures.patterns) == 0 {
+		return nil, fmt.Errorf("%s has no block signatures", path)
+	}
+	return signatures, nil
+}
+
+// Match reports whether body matches any of the signatures
+func (s *BlockSignatures) Match(body []byte) bool {
+	if s == nil {
+		return false
+	}
+	lower := bytes.ToLower(body)
+	for _, literal := range s.literals {
+		if bytes.Contains(lower, literal) {
+			return true
+		}
+	}
+	for _, pattern := range s.patterns {
+		if pattern.Match(body) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSignature reports whether resp's body matches the configured block
+// signatures
+func (o *InjectorOptions) matchesSignature(resp *fasthttp.Response) bool {
+	return o != nil && o.BlockSignatures.Match(resp.Body())
+}
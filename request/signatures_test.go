@@ -0,0 +1,54 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBlockSignaturesOverrideStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signatures.txt")
+	signatures := "# Acme Shield block pages\nrequest rejected by acme shield\nre:Incident ID: \\d{6}\n"
+	if err := os.WriteFile(path, []byte(signatures), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultInjectorOptions()
+	var err error
+	if opts.BlockSignatures, err = LoadBlockSignatures(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The WAF answers 200 with its block page for anything script-like
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(strings.ToLower(r.URL.RawQuery), "script") {
+			w.Write([]byte("<h1>Request Rejected by Acme Shield</h1><p>Incident ID: 104233</p>"))
+			return
+		}
+		w.Write([]byte("<p>Search results</p>"))
+	}))
+	defer server.Close()
+	logger := NewLoggerWithLevel(os.Stderr, LogLevelError)
+
+	blocked := NewFastHTTPQueryInjectorWithOptions(opts).Inject(server.URL, "<script>alert(1)</script>", logger)
+	if len(blocked) == 0 {
+		t.Fatal("no results")
+	}
+	for _, result := range blocked {
+		if result.StatusCode != http.StatusOK || !result.Blocked || result.BlockReason != BlockReasonSignature {
+			t.Errorf("%s: status %d blocked=%v reason %q, want a 200 blocked by %s", result.EvasionTechnique, result.StatusCode, result.Blocked, result.BlockReason, BlockReasonSignature)
+		}
+	}
+
+	for _, result := range NewFastHTTPQueryInjectorWithOptions(opts).Inject(server.URL, "hello", logger) {
+		if result.Blocked || result.BlockReason != "" {
+			t.Errorf("%s: a 200 without a matching body was blocked (%q)", result.EvasionTechnique, result.BlockReason)
+		}
+	}
+
+	if opts.BlockSignatures.Match([]byte("Incident ID: 12")) {
+		t.Error("regex signature matched a body it does not match")
+	}
+}
@@ -0,0 +1,166 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"obfuskit/internal/redact"
+)
+
+// ResultSink receives every result as the send pipeline produces it, so runs
+// can stream into a SIEM instead of waiting for the final report. Write is
+// called from several workers at once; implementations must be safe for
+// concurrent use. Close flushes and releases the sink after the last Write.
+type ResultSink interface {
+	Write(result TestResult) error
+	Close() error
+}
+
+// SinkFactory opens a sink for the target part of a -sink spec, e.g.
+// "broker:9092/topic" for "kafka:broker:9092/topic"
+type SinkFactory func(target string) (ResultSink, error)
+
+var (
+	sinkMu        sync.RWMutex
+	sinkFactories = map[string]SinkFactory{
+		"stdout": func(string) (ResultSink, error) { return NewNDJSONSink(nopCloser{os.Stdout}), nil },
+		"file":   openFileSink,
+	}
+)
+
+// RegisterSink adds a sink scheme usable as "<scheme>:<target>" in -sink.
+// This is the extension point for message queues and search clusters: a
+// Kafka or Elasticsearch sink registers itself from an init function (in a
+// build with that client library) and converts each result with
+// NewSinkRecord. Registering an existing scheme replaces it.
+func RegisterSink(scheme string, factory SinkFactory) {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	sinkFactories[scheme] = factory
+}
+
+// OpenSink opens the sink described by spec: "stdout", "file:<path>", any
+// registered "<scheme>:<target>", or a bare path, which is written as NDJSON
+func OpenSink(spec string) (ResultSink, error) {
+	scheme, target, found := strings.Cut(spec, ":")
+	sinkMu.RLock()
+	factory, ok := sinkFactories[scheme]
+	sinkMu.RUnlock()
+	if !ok || (!found && scheme != "stdout") {
+		return openFileSink(spec)
+	}
+	return factory(target)
+}
+
+// openFileSink is NewFileSink as a SinkFactory
+func openFileSink(path string) (ResultSink, error) {
+	sink, err := NewFileSink(path)
+	if err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// SinkRecord is the serialized form of a result sent to sinks. Secrets in the
+// URL and headers are redacted.
+type SinkRecord struct {
+	Timestamp       time.Time        `json:"timestamp"`
+	VariantID       string           `json:"variant_id,omitempty"`
+	Encoding        string           `json:"encoding,omitempty"`
+	EvasionLevel    string           `json:"evasion_level,omitempty"`
+	AttackType      string           `json:"attack_type,omitempty"`
+	Payload         string           `json:"payload"`
+	Technique       string           `json:"technique"`
+	RequestPart     string           `json:"request_part"`
+	Method          string           `json:"method,omitempty"`
+	URL             string           `json:"url,omitempty"`
+	Headers         []RecordedHeader `json:"headers,omitempty"`
+	StatusCode      int              `json:"status_code"`
+	ResponseTimeMS  int64            `json:"response_time_ms"`
+	Blocked         bool             `json:"blocked"`
+	BlockReason     string           `json:"block_reason,omitempty"`
+	RuleID          string           `json:"rule_id,omitempty"`
+	CandidateBypass bool             `json:"candidate_bypass,omitempty"`
+	Challenge       bool             `json:"challenge,omitempty"`
+	Deviations      []string         `json:"deviations,omitempty"`
+	Canary          string           `json:"canary,omitempty"`
+}
+
+// NewSinkRecord converts result for serialization
+func NewSinkRecord(result TestResult) SinkRecord {
+	recorded := RecordRequest(result.Request)
+	record := SinkRecord{
+		Timestamp:       time.Now().UTC(),
+		VariantID:       result.Variant.ID,
+		Encoding:        result.Variant.Encoding,
+		EvasionLevel:    result.Variant.Level,
+		AttackType:      result.AttackType,
+		Payload:         result.Payload,
+		Technique:       result.EvasionTechnique,
+		RequestPart:     result.RequestPart,
+		Method:          recorded.Method,
+		URL:             redact.URL(recorded.URL),
+		StatusCode:      result.StatusCode,
+		ResponseTimeMS:  result.ResponseTime.Milliseconds(),
+		Blocked:         result.Blocked,
+		BlockReason:     result.BlockReason,
+		RuleID:          result.RuleID,
+		CandidateBypass: result.CandidateBypass,
+		Challenge:       result.Challenge,
+		Deviations:      result.Deviations,
+		Canary:          result.Canary,
+	}
+	for _, header := range recorded.Headers {
+		record.Headers = append(record.Headers, RecordedHeader{Name: header.Name, Value: redact.Header(header.Name, header.Value)})
+	}
+	return record
+}
+
+// NDJSONSink writes one JSON SinkRecord per line
+type NDJSONSink struct {
+	mu      sync.Mutex
+	out     io.WriteCloser
+	encoder *json.Encoder
+}
+
+// NewNDJSONSink writes records to out and closes it on Close
+func NewNDJSONSink(out io.WriteCloser) *NDJSONSink {
+	return &NDJSONSink{out: out, encoder: json.NewEncoder(out)}
+}
+
+// NewFileSink appends records to the file at path, creating it if needed
+func NewFileSink(path string) (*NDJSONSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sink file path is empty")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink file: %w", err)
+	}
+	return NewNDJSONSink(file), nil
+}
+
+func (s *NDJSONSink) Write(result TestResult) error {
+	record := NewSinkRecord(result)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encoder.Encode(record)
+}
+
+func (s *NDJSONSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out.Close()
+}
+
+// nopCloser keeps the stdout sink from closing os.Stdout
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,78 @@
+package request
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDialTimeout bounds each connection attempt made from a source address
+const DefaultDialTimeout = 10 * time.Second
+
+// SourceAddressRotator binds each outgoing connection to the next of several
+// local addresses, spreading requests over egress IPs so per-IP rate limits
+// apply to each share. The order is fixed by a seed and wraps around; it is
+// safe for concurrent use.
+type SourceAddressRotator struct {
+	addrs []net.IP
+	next  atomic.Uint64
+}
+
+// NewSourceAddressRotator parses addrs, checks each one is assigned to this
+// host, and shuffles them with seed
+func NewSourceAddressRotator(addrs []string, seed int64) (*SourceAddressRotator, error) {
+	var ips []net.IP
+	for _, addr := range addrs {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid source IP %q", addr)
+		}
+		if err := checkLocalAddress(ip); err != nil {
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no source IPs given")
+	}
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(ips), func(i, j int) {
+		ips[i], ips[j] = ips[j], ips[i]
+	})
+	return &SourceAddressRotator{addrs: ips}, nil
+}
+
+// checkLocalAddress reports an error unless ip can be bound to, that is, it
+// is assigned to a local interface
+func checkLocalAddress(ip net.IP) error {
+	conn, err := net.ListenPacket("udp", net.JoinHostPort(ip.String(), "0"))
+	if err != nil {
+		return fmt.Errorf("source IP %s is not assigned to a local interface: %w", ip, err)
+	}
+	return conn.Close()
+}
+
+// Next returns the next source address in the rotation
+func (r *SourceAddressRotator) Next() net.IP {
+	n := r.next.Add(1) - 1
+	return r.addrs[n%uint64(len(r.addrs))]
+}
+
+// Dial connects to addr from the next source address. It has the signature
+// of fasthttp.Client.Dial.
+func (r *SourceAddressRotator) Dial(addr string) (net.Conn, error) {
+	source := r.Next()
+	network := "tcp6"
+	if source.To4() != nil {
+		network = "tcp4"
+	}
+	dialer := net.Dialer{LocalAddr: &net.TCPAddr{IP: source}, Timeout: DefaultDialTimeout}
+	return dialer.Dial(network, addr)
+}
@@ -0,0 +1,73 @@
+package request
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSourceIPsRotatePerRequest(t *testing.T) {
+	sources := []string{"127.0.0.2", "127.0.0.3"}
+	for _, source := range sources {
+		if err := checkLocalAddress(net.ParseIP(source)); err != nil {
+			t.Skipf("loopback alias unavailable: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		mu.Lock()
+		seen = append(seen, host)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	rotator, err := NewSourceAddressRotator(sources, 7)
+	if err != nil {
+		t.Fatalf("NewSourceAddressRotator() error: %v", err)
+	}
+	opts := DefaultInjectorOptions()
+	opts.SourceAddresses = rotator
+	results := NewFastHTTPQueryInjectorWithOptions(opts).Inject(server.URL, "x", NewLoggerWithLevel(os.Stderr, LogLevelError))
+	if len(results) < 2 {
+		t.Fatalf("%d results, want several requests", len(results))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	counts := map[string]int{}
+	for i, host := range seen {
+		counts[host]++
+		if i > 0 && host == seen[i-1] {
+			t.Errorf("requests %d and %d both came from %s, want the next address each time", i-1, i, host)
+		}
+	}
+	for _, source := range sources {
+		if counts[source] == 0 {
+			t.Errorf("no request came from %s (seen %v)", source, seen)
+		}
+	}
+
+	// Same seed, same order
+	again, _ := NewSourceAddressRotator(sources, 7)
+	if first := again.Next().String(); first != seen[0] {
+		t.Errorf("seed 7 starts at %s, but the run started at %s", first, seen[0])
+	}
+}
+
+func TestSourceIPsMustBeLocal(t *testing.T) {
+	// TEST-NET-3, never assigned to a real interface
+	_, err := NewSourceAddressRotator([]string{"127.0.0.1", "203.0.113.7"}, 0)
+	if err == nil || !strings.Contains(err.Error(), "203.0.113.7") {
+		t.Errorf("NewSourceAddressRotator() error = %v, want one naming the non-local address", err)
+	}
+	if _, err := NewSourceAddressRotator([]string{"not-an-ip"}, 0); err == nil {
+		t.Error("NewSourceAddressRotator() accepted an invalid address")
+	}
+}
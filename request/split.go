@@ -0,0 +1,182 @@
+package request
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// MultiParamSplitTechnique labels requests carrying the payload split into
+// fragments across several parameters
+const MultiParamSplitTechnique = "multi_param_split"
+
+// SplitParamInjector sends each payload split into consecutive fragments,
+// one per parameter of InjectorOptions.SplitParams, in a single request:
+// "a=<scr&b=ipt>". An application that concatenates the parameters rebuilds
+// the payload, while a WAF matching each parameter on its own never sees it
+// whole. The fragments go in the query and, separately, in a form body.
+type SplitParamInjector struct {
+	options *InjectorOptions
+}
+
+// NewSplitParamInjectorWithOptions returns a split-parameter injector using opts
+func NewSplitParamInjectorWithOptions(opts *InjectorOptions) *SplitParamInjector {
+	if opts == nil {
+		opts = DefaultInjectorOptions()
+	}
+	return &SplitParamInjector{options: opts}
+}
+
+func (i *SplitParamInjector) Name() string {
+	return "split_param_injection"
+}
+
+func (i *SplitParamInjector) Inject(targetURL string, payload string, logger *Logger) []TestResult {
+	results := []TestResult{}
+
+	normalizedURL, err := NormalizeURL(targetURL)
+	if err != nil {
+		logger.error.Printf("Failed to normalize URL %s: %v", targetURL, err)
+		return results
+	}
+	parsedURL, err := url.Parse(normalizedURL)
+	if err != nil {
+		logger.error.Printf("Failed to parse URL %s: %v", normalizedURL, err)
+		return results
+	}
+
+	// Existing query parameters are kept verbatim; the fragments are appended
+	querySent, queryCanary := i.options.withCanary(payload)
+	parsedURL.RawQuery = joinQuery(parsedURL.RawQuery, "&", i.pairs(querySent))
+	query := fasthttp.AcquireRequest()
+	query.SetRequestURI(parsedURL.String())
+
+	bodySent, bodyCanary := i.options.withCanary(payload)
+	body := fasthttp.AcquireRequest()
+	body.SetRequestURI(normalizedURL)
+	body.Header.SetMethod(fasthttp.MethodPost)
+	body.Header.SetContentType("application/x-www-form-urlencoded")
+	body.SetBodyString(i.pairs(bodySent))
+
+	for _, p := range []struct {
+		req    *fasthttp.Request
+		part   string
+		canary string
+	}{{query, "query", queryCanary}, {body, "body", bodyCanary}} {
+		results = append(results, i.send(p.req, p.part, payload, p.canary, logger)...)
+		fasthttp.ReleaseRequest(p.req)
+	}
+	return results
+}
+
+// pairs returns payload cut into the configured fragments as name=value pairs
+func (i *SplitParamInjector) pairs(payload string) string {
+	return SplitParamPairs(i.options.SplitParams, SplitPayload(payload, len(i.options.SplitParams), i.options.SplitAt))
+}
+
+// send sends one split request and returns its result, if it got a response
+func (i *SplitParamInjector) send(req *fasthttp.Request, part, payload, canary string, logger *Logger) []TestResult {
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	i.options.prepare(req)
+	logger.debug.Printf("Sending request to %s with the payload split across %d %s params", req.URI(), len(i.options.SplitParams), part)
+	start := time.Now()
+	outcome, err := i.options.do(req, resp)
+	duration := time.Since(start)
+	if err != nil {
+		logger.error.Printf("Split %s params test failed: %v", part, err)
+		return nil
+	}
+
+	result := TestResult{
+		Request:          snapshotRequest(req),
+		Payload:          payload,
+		EvasionTechnique: MultiParamSplitTechnique,
+		RequestPart:      part,
+		StatusCode:       outcome.statusCode(resp),
+		ResponseTime:     duration,
+		Blocked:          outcome.blocked(resp),
+		BlockReason:      outcome.blockReason(resp),
+		RuleID:           outcome.ruleID(resp),
+		ResponseBody:     capturedBody(resp),
+		Challenge:        IsChallenge(resp),
+		Cached:           IsCached(resp),
+		Timing:           outcome.timing,
+		Canary:           canary,
+	}
+	logger.info.Printf("Split %s params test result: %s", part, result.String())
+	return []TestResult{result}
+}
+
+// SplitPayload cuts payload into parts consecutive fragments at the rune
+// offsets in boundaries, or into parts near-equal fragments when boundaries
+// is empty. Boundaries past the end of a short payload leave the trailing
+// fragments empty. Joined, the fragments are always the payload.
+func SplitPayload(payload string, parts int, boundaries []int) []string {
+	runes := []rune(payload)
+	if len(boundaries) == 0 {
+		for n := 1; n < parts; n++ {
+			boundaries = append(boundaries, len(runes)*n/parts)
+		}
+	}
+	fragments := make([]string, 0, len(boundaries)+1)
+	start := 0
+	for _, boundary := range boundaries {
+		end := min(max(boundary, start), len(runes))
+		fragments = append(fragments, string(runes[start:end]))
+		start = end
+	}
+	return append(fragments, string(runes[start:]))
+}
+
+// SplitParamPairs pairs each name with its fragment, query-escaped and joined
+// by '&' in order
+func SplitParamPairs(names, fragments []string) string {
+	pairs := make([]string, len(names))
+	for n, name := range names {
+		pairs[n] = url.QueryEscape(name) + "=" + url.QueryEscape(fragments[n])
+	}
+	return strings.Join(pairs, "&")
+}
+
+// ParseSplitBoundaries parses a comma-separated list of rune offsets, such
+// as "4,8", to split payloads at
+func ParseSplitBoundaries(value string) ([]int, error) {
+	var boundaries []int
+	for _, field := range ParseParamNames(value) {
+		boundary, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid split boundary %q: not a number", field)
+		}
+		boundaries = append(boundaries, boundary)
+	}
+	return boundaries, nil
+}
+
+// ValidateSplit checks that payloads can be split across names at
+// boundaries: at least two names, and either no boundaries or one fewer
+// than the names, positive and increasing
+func ValidateSplit(names []string, boundaries []int) error {
+	if len(names) < 2 {
+		return fmt.Errorf("splitting a payload needs at least two parameters, got %d", len(names))
+	}
+	if len(boundaries) == 0 {
+		return nil
+	}
+	if len(boundaries) != len(names)-1 {
+		return fmt.Errorf("%d parameters need %d split boundaries, got %d", len(names), len(names)-1, len(boundaries))
+	}
+	previous := 0
+	for _, boundary := range boundaries {
+		if boundary <= previous {
+			return fmt.Errorf("split boundaries must be positive and increasing, got %v", boundaries)
+		}
+		previous = boundary
+	}
+	return nil
+}
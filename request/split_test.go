@@ -0,0 +1,77 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSplitParamInjectorSendsFragmentsInOneRequest(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		// The application concatenates the parameters
+		rebuilt := r.Form.Get("a") + r.Form.Get("b")
+		mu.Lock()
+		received = append(received, r.Method+" "+rebuilt)
+		mu.Unlock()
+		w.Write([]byte(rebuilt))
+	}))
+	defer server.Close()
+
+	payload := "<script>alert(1)</script>"
+	opts := DefaultInjectorOptions()
+	opts.SplitParams = []string{"a", "b"}
+	opts.SplitAt = []int{4}
+	results := NewSplitParamInjectorWithOptions(opts).Inject(server.URL, payload, NewLoggerWithLevel(os.Stderr, LogLevelError))
+
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want one query and one body request: %+v", len(results), results)
+	}
+	for _, result := range results {
+		if result.EvasionTechnique != MultiParamSplitTechnique || result.ResponseBody != payload {
+			t.Errorf("%s %s: body %q, want %q", result.EvasionTechnique, result.RequestPart, result.ResponseBody, payload)
+		}
+	}
+	if query := string(results[0].Request.URI().QueryString()); query != "a=%3Cscr&b=ipt%3Ealert%281%29%3C%2Fscript%3E" {
+		t.Errorf("query = %q, want the payload split at 4 across a and b", query)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"GET " + payload, "POST " + payload}; !reflect.DeepEqual(received, want) {
+		t.Errorf("server rebuilt %q, want %q", received, want)
+	}
+}
+
+func TestSplitPayload(t *testing.T) {
+	tests := []struct {
+		name       string
+		parts      int
+		boundaries []int
+		want       []string
+	}{
+		{"even", 2, nil, []string{"<scr", "ipt>"}},
+		{"even three", 3, nil, []string{"<s", "cri", "pt>"}},
+		{"boundaries", 3, []int{1, 4}, []string{"<", "scr", "ipt>"}},
+		{"past the end", 3, []int{6, 20}, []string{"<scrip", "t>", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitPayload("<script>", tt.parts, tt.boundaries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitPayload() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := ValidateSplit([]string{"a", "b"}, []int{4, 8}); err == nil {
+		t.Error("expected an error for more boundaries than the parameters allow")
+	}
+	if err := ValidateSplit([]string{"a"}, nil); err == nil {
+		t.Error("expected an error for a single parameter")
+	}
+}
@@ -0,0 +1,123 @@
+package request
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+
+	"obfuskit/types"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CanaryPrefix starts every canary token
+const CanaryPrefix = "obfk"
+
+// canaryPattern matches a canary token: the prefix, an 8 hex digit run ID and
+// an 8 hex digit sequence number. Only letters and digits, so the token
+// survives whatever decoding and escaping the application applies.
+var canaryPattern = regexp.MustCompile(CanaryPrefix + `[0-9a-f]{16}`)
+
+// CanaryTracker issues the canary tokens appended to payloads in stored mode
+// (-view-url, see InjectorOptions.Canaries). Each request carries its own token, so a payload that shows up
+// on the view page can be traced to the exact request that stored it, however
+// the application transformed the payload around it. Next is safe for
+// concurrent use.
+type CanaryTracker struct {
+	run  string
+	next atomic.Uint32
+}
+
+// NewCanaryTracker returns a tracker whose tokens are unique to this run, so
+// canaries stored by earlier runs are not mistaken for this run's
+func NewCanaryTracker() *CanaryTracker {
+	id := make([]byte, 4)
+	rand.Read(id)
+	return &CanaryTracker{run: hex.EncodeToString(id)}
+}
+
+// Next returns a new canary token
+func (t *CanaryTracker) Next() string {
+	return fmt.Sprintf("%s%s%08x", CanaryPrefix, t.run, t.next.Add(1))
+}
+
+// withCanary returns payload carrying a new canary, and the canary, when
+// Canaries is set; otherwise payload unchanged and no canary. Injectors call
+// it once per request they build, before any encoding of their own.
+func (o *InjectorOptions) withCanary(payload string) (string, string) {
+	if o == nil || o.Canaries == nil {
+		return payload, ""
+	}
+	canary := o.Canaries.Next()
+	return payload + canary, canary
+}
+
+// packedEncodings re-encode the whole payload into an alphabet the canary's
+// letters and digits belong to, so a canary appended to one of their
+// variants would corrupt it instead of surviving its decoding
+var packedEncodings = map[types.PayloadEncoding]bool{
+	types.PayloadEncodingBase64: true,
+	types.PayloadEncodingHex:    true,
+	types.PayloadEncodingUTF7:   true,
+}
+
+// CarriesCanary reports whether variant can be sent with a canary appended
+func CarriesCanary(variant types.Variant) bool {
+	return !packedEncodings[types.PayloadEncoding(variant.Encoding)]
+}
+
+// FindCanaries returns the canary tokens in body
+func FindCanaries(body []byte) map[string]bool {
+	found := make(map[string]bool)
+	for _, canary := range canaryPattern.FindAll(bytes.ToLower(body), -1) {
+		found[string(canary)] = true
+	}
+	return found
+}
+
+// FetchCanaries requests viewURL, the page where stored payloads would be
+// rendered, and returns the canary tokens on it
+func FetchCanaries(viewURL string, opts *InjectorOptions) (map[string]bool, error) {
+	normalizedURL, err := NormalizeURL(viewURL)
+	if err != nil {
+		return nil, err
+	}
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(normalizedURL)
+	opts.prepare(req)
+	if _, err := opts.do(req, resp); err != nil {
+		return nil, err
+	}
+	return FindCanaries(resp.Body()), nil
+}
+
+// MarkStored flags the results whose canary is in found as stored and returns
+// how many it flagged
+func MarkStored(results []TestResult, found map[string]bool) int {
+	stored := 0
+	for i := range results {
+		if results[i].Canary != "" && found[results[i].Canary] {
+			results[i].Stored = true
+			stored++
+		}
+	}
+	return stored
+}
+
+// StoredCount returns how many results were found stored
+func StoredCount(results []TestResult) int {
+	stored := 0
+	for _, result := range results {
+		if result.Stored {
+			stored++
+		}
+	}
+	return stored
+}
@@ -0,0 +1,79 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"obfuskit/types"
+)
+
+func TestCanariesSurviveTransformationAndMatchTheirResults(t *testing.T) {
+	tracker := NewCanaryTracker()
+	first, second := tracker.Next(), tracker.Next()
+	if first == second {
+		t.Fatalf("Next() returned %q twice", first)
+	}
+
+	// The page HTML-escapes the payload and uppercases the whole comment
+	page := "<p>" + strings.ToUpper("&lt;svg onload=alert(1)&gt;"+first) + "</p>"
+	found := FindCanaries([]byte(page))
+	if !found[first] || len(found) != 1 {
+		t.Fatalf("FindCanaries() = %v, want only %s", found, first)
+	}
+
+	results := []TestResult{
+		{Payload: "<svg onload=alert(1)>", Canary: first},
+		{Payload: "<script>alert(1)</script>", Canary: second},
+		{Payload: "<img src=x>"},
+	}
+	if stored := MarkStored(results, found); stored != 1 || !results[0].Stored || results[1].Stored || results[2].Stored {
+		t.Errorf("MarkStored() flagged %d: %+v, want only the first result", stored, results)
+	}
+	if StoredCount(results) != 1 {
+		t.Errorf("StoredCount() = %d, want 1", StoredCount(results))
+	}
+}
+
+func TestEveryRequestCarriesItsOwnCanary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	opts := DefaultInjectorOptions()
+	opts.Canaries = NewCanaryTracker()
+	logger := NewLoggerWithLevel(os.Stderr, LogLevelError)
+	var results []TestResult
+	for _, injector := range NewInjectors(opts) {
+		results = append(results, injector.Inject(server.URL, "<svg onload=alert(1)>", logger)...)
+	}
+	if len(results) < 2 {
+		t.Fatalf("got %d results, want one per request", len(results))
+	}
+
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if result.Canary == "" || seen[result.Canary] {
+			t.Errorf("%s/%s has canary %q, want one no other request carries", result.EvasionTechnique, result.RequestPart, result.Canary)
+		}
+		seen[result.Canary] = true
+		if result.Payload != "<svg onload=alert(1)>" {
+			t.Errorf("%s/%s payload = %q, want the payload without its canary", result.EvasionTechnique, result.RequestPart, result.Payload)
+		}
+	}
+}
+
+func TestPackedEncodingsCarryNoCanary(t *testing.T) {
+	for encoding, want := range map[types.PayloadEncoding]bool{
+		types.PayloadEncodingBase64: false,
+		types.PayloadEncodingHex:    false,
+		types.PayloadEncodingUTF7:   false,
+		types.PayloadEncodingURL:    true,
+		types.PayloadEncodingHTML:   true,
+	} {
+		if got := CarriesCanary(types.Variant{Encoding: string(encoding)}); got != want {
+			t.Errorf("CarriesCanary(%s) = %v, want %v", encoding, got, want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Timing breaks a request's response time into phases. It is only recorded
+// in timing mode (InjectorOptions.Timing), where every request is sent on a
+// connection of its own so that DNS, connect and TLS are not hidden by
+// connection reuse; with redirects followed it adds up every hop.
+type Timing struct {
+	// DNS is the time spent resolving the host
+	DNS time.Duration
+	// Connect is the time spent opening the TCP connection
+	Connect time.Duration
+	// TLS is the time from the connection opening to the request being
+	// written, which on an https connection is the TLS handshake
+	TLS time.Duration
+	// TTFB is the time from the request being written to the first byte of
+	// the response
+	TTFB time.Duration
+	// Transfer is the time spent reading the rest of the response
+	Transfer time.Duration
+}
+
+// Total returns the sum of the phases
+func (t Timing) Total() time.Duration {
+	return t.DNS + t.Connect + t.TLS + t.TTFB + t.Transfer
+}
+
+// IsZero reports whether no timing was recorded
+func (t Timing) IsZero() bool {
+	return t == Timing{}
+}
+
+func (t Timing) String() string {
+	return fmt.Sprintf("dns %s, connect %s, tls %s, ttfb %s, transfer %s", t.DNS, t.Connect, t.TLS, t.TTFB, t.Transfer)
+}
+
+// sendFresh sends req like send, but on a client of its own whose
+// connections record when they were dialed, written and read. Timing mode
+// uses it to time the phases of a request, and ResetIsBlock to tell a WAF
+// dropping a request from a stale keep-alive connection: a reset only counts
+// as a block when it hit a request already written on a connection dialed
+// for it. fasthttp has no tracing hooks, so TLS is approximated as the time
+// between the TCP connection and the request write, and the response phases
+// by when its bytes arrived.
+func (o *InjectorOptions) sendFresh(req *fasthttp.Request, resp *fasthttp.Response) (sendOutcome, error) {
+	recorder := &timingRecorder{sources: o.SourceAddresses}
+	c := &fasthttp.Client{
+		ReadBufferSize:      o.ReadBufferSize,
+		MaxResponseBodySize: o.MaxResponseBodySize,
+		Dial:                recorder.dial,
+		// A retry would send the request again on a new connection
+		MaxIdemponentCallAttempts: 1,
+		// The client lives for one request, so its cleaner need not linger
+		MaxIdleConnDuration: time.Second,
+	}
+	var err error
+	if o.FollowRedirects {
+		maxRedirects := o.MaxRedirects
+		if maxRedirects <= 0 {
+			maxRedirects = DefaultMaxRedirects
+		}
+		err = doRedirects(c, req, resp, maxRedirects, o.Scope)
+	} else {
+		err = c.Do(req, resp)
+	}
+	var outcome sendOutcome
+	if o.Timing {
+		outcome.timing = recorder.timing(time.Now())
+	}
+	if err != nil && o.ResetIsBlock && IsConnectionReset(err) && recorder.written() {
+		resp.Reset()
+		outcome.reset = true
+		return outcome, nil
+	}
+	return outcome, tolerateBodyTooLarge(err, resp)
+}
+
+// timingRecorder dials the connections of one timed request and adds up
+// their phases
+type timingRecorder struct {
+	sources *SourceAddressRotator
+
+	mu      sync.Mutex
+	dns     time.Duration
+	connect time.Duration
+	conns   []*timedConn
+}
+
+// dial resolves and connects to addr, timing each step. It has the
+// signature of fasthttp.Client.Dial.
+func (r *timingRecorder) dial(addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	ips, err := net.DefaultResolver.LookupIPAddr(context.Background(), host)
+	if err != nil {
+		return nil, err
+	}
+	resolved := time.Now()
+
+	dialer := net.Dialer{Timeout: DefaultDialTimeout}
+	var source net.IP
+	if r.sources != nil {
+		source = r.sources.Next()
+		dialer.LocalAddr = &net.TCPAddr{IP: source}
+	}
+	var conn net.Conn
+	for _, ip := range ips {
+		// A source address can only reach addresses of its own family
+		if source != nil && (source.To4() == nil) != (ip.IP.To4() == nil) {
+			continue
+		}
+		if conn, err = dialer.Dial("tcp", net.JoinHostPort(ip.String(), port)); err == nil {
+			break
+		}
+	}
+	if conn == nil {
+		if err == nil {
+			err = fmt.Errorf("no address of %s reachable from source IP %s", host, source)
+		}
+		return nil, err
+	}
+	connected := time.Now()
+
+	timed := &timedConn{Conn: conn, connected: connected}
+	r.mu.Lock()
+	r.dns += resolved.Sub(start)
+	r.connect += connected.Sub(resolved)
+	r.conns = append(r.conns, timed)
+	r.mu.Unlock()
+	return timed, nil
+}
+
+// written reports whether the request went out on the last connection
+// dialed, so that a failure reading the response came from the peer
+func (r *timingRecorder) written() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.conns) == 0 {
+		return false
+	}
+	return r.conns[len(r.conns)-1].wroteRequest()
+}
+
+// timing returns the phases of every connection dialed, the last one's
+// transfer lasting until done
+func (r *timingRecorder) timing(done time.Time) Timing {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	timing := Timing{DNS: r.dns, Connect: r.connect}
+	for i, conn := range r.conns {
+		end := done
+		if i < len(r.conns)-1 {
+			end = conn.lastRead()
+		}
+		conn.addPhases(&timing, end)
+	}
+	return timing
+}
+
+// ioEvent is one Write, or one Read that returned data, on a timedConn
+type ioEvent struct {
+	write      bool
+	n          int
+	start, end time.Time
+}
+
+// timedConn records when each write and read on a connection happened
+type timedConn struct {
+	net.Conn
+	connected time.Time
+
+	mu     sync.Mutex
+	events []ioEvent
+}
+
+func (c *timedConn) Write(b []byte) (int, error) {
+	start := time.Now()
+	n, err := c.Conn.Write(b)
+	c.record(ioEvent{write: true, n: n, start: start, end: time.Now()})
+	return n, err
+}
+
+func (c *timedConn) Read(b []byte) (int, error) {
+	start := time.Now()
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.record(ioEvent{start: start, end: time.Now()})
+	}
+	return n, err
+}
+
+func (c *timedConn) record(event ioEvent) {
+	c.mu.Lock()
+	c.events = append(c.events, event)
+	c.mu.Unlock()
+}
+
+// wroteRequest reports whether any bytes were written on the connection
+func (c *timedConn) wroteRequest() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, event := range c.events {
+		if event.write && event.n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// lastRead returns when the last read on the connection returned
+func (c *timedConn) lastRead() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.events) - 1; i >= 0; i-- {
+		if !c.events[i].write {
+			return c.events[i].end
+		}
+	}
+	return c.connected
+}
+
+// addPhases adds the connection's TLS, TTFB and transfer time to timing.
+// The request is the last run of writes with no read in between: anything
+// before it is the TLS handshake, and the first read after it brings the
+// first response byte.
+func (c *timedConn) addPhases(timing *Timing, end time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last := -1
+	for i := len(c.events) - 1; i >= 0; i-- {
+		if c.events[i].write {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return
+	}
+	first := last
+	for first > 0 && c.events[first-1].write {
+		first--
+	}
+	written := c.events[first].start
+	timing.TLS += written.Sub(c.connected)
+	if last+1 == len(c.events) {
+		// No response arrived; the wait lasted until the request failed
+		timing.TTFB += end.Sub(written)
+		return
+	}
+	firstByte := c.events[last+1].end
+	timing.TTFB += firstByte.Sub(written)
+	if end.After(firstByte) {
+		timing.Transfer += end.Sub(firstByte)
+	}
+}
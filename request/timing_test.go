@@ -0,0 +1,51 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimingBreakdownIsPopulatedAndSumsToResponseTime(t *testing.T) {
+	const delay = 10 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Headers after one delay, the body after another, so both the
+		// first byte and the transfer take measurable time
+		time.Sleep(delay)
+		w.Write([]byte("first"))
+		w.(http.Flusher).Flush()
+		time.Sleep(delay)
+		w.Write([]byte("rest"))
+	}))
+	defer server.Close()
+
+	opts := DefaultInjectorOptions()
+	opts.Timing = true
+	// A host name, so there is something to resolve
+	target := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	results := NewFastHTTPQueryInjectorWithOptions(opts).Inject(target, "<script>alert(1)</script>", NewLoggerWithLevel(os.Stderr, LogLevelError))
+	if len(results) == 0 {
+		t.Fatal("no results")
+	}
+	for _, result := range results {
+		timing := result.Timing
+		if timing.DNS <= 0 || timing.Connect <= 0 || timing.TLS < 0 || timing.TTFB < delay || timing.Transfer < delay {
+			t.Errorf("%s: timing %s, want DNS and connect time, and at least %s to first byte and for the transfer", result.EvasionTechnique, timing, delay)
+		}
+		// The phases cover the request from dialing to the last byte, so
+		// only the injector's own overhead is left out of the sum
+		if total := timing.Total(); total > result.ResponseTime || total < result.ResponseTime*9/10 {
+			t.Errorf("%s: phases sum to %s, want roughly the response time %s", result.EvasionTechnique, total, result.ResponseTime)
+		}
+	}
+
+	// Outside timing mode nothing is recorded
+	for _, result := range NewFastHTTPQueryInjector().Inject(server.URL, "x", NewLoggerWithLevel(os.Stderr, LogLevelError)) {
+		if !result.Timing.IsZero() {
+			t.Errorf("%s: timing %s recorded without timing mode", result.EvasionTechnique, result.Timing)
+		}
+	}
+}
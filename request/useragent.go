@@ -0,0 +1,46 @@
+package request
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// DefaultUserAgents are current desktop and mobile browser User-Agents used for
+// rotation, so requests don't stand out with the HTTP client's default
+var DefaultUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.67",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Safari/605.1.15",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.6367.82 Mobile Safari/537.36",
+}
+
+// UserAgentRotator hands out User-Agents in a fixed, seed-determined order,
+// wrapping around at the end of the list. It is safe for concurrent use.
+type UserAgentRotator struct {
+	agents []string
+	next   atomic.Uint64
+}
+
+// NewUserAgentRotator shuffles agents with seed; an empty list uses DefaultUserAgents
+func NewUserAgentRotator(agents []string, seed int64) *UserAgentRotator {
+	if len(agents) == 0 {
+		agents = DefaultUserAgents
+	}
+	order := append([]string(nil), agents...)
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+	return &UserAgentRotator{agents: order}
+}
+
+// Next returns the next User-Agent in the rotation
+func (r *UserAgentRotator) Next() string {
+	n := r.next.Add(1) - 1
+	return r.agents[n%uint64(len(r.agents))]
+}
@@ -0,0 +1,108 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestInjectorsSetConfiguredUserAgent(t *testing.T) {
+	var mu sync.Mutex
+	var agents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		agents = append(agents, r.UserAgent())
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	logger := NewLoggerWithLevel(devNull, LogLevelError)
+
+	const userAgent = "obfuskit-test/1.0"
+	opts := &InjectorOptions{UserAgent: userAgent}
+	for _, injector := range NewInjectors(opts) {
+		injector.Inject(server.URL, "<script>", logger)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(agents) == 0 {
+		t.Fatal("no requests received")
+	}
+	for i, got := range agents {
+		if got != userAgent {
+			t.Errorf("request %d: User-Agent = %q, want %q", i, got, userAgent)
+		}
+	}
+}
+
+func TestUserAgentRotatorCyclesDeterministically(t *testing.T) {
+	agents := []string{"ua-a", "ua-b", "ua-c", "ua-d"}
+
+	first := NewUserAgentRotator(agents, 42)
+	second := NewUserAgentRotator(agents, 42)
+
+	var sequence []string
+	for i := 0; i < 2*len(agents); i++ {
+		got := first.Next()
+		if want := second.Next(); got != want {
+			t.Fatalf("step %d: same seed gave %q and %q", i, got, want)
+		}
+		sequence = append(sequence, got)
+	}
+
+	// Each pass covers every agent once, then the order repeats
+	seen := map[string]bool{}
+	for i, ua := range sequence[:len(agents)] {
+		if seen[ua] {
+			t.Errorf("agent %q repeated within the first cycle", ua)
+		}
+		seen[ua] = true
+		if sequence[i+len(agents)] != ua {
+			t.Errorf("step %d: second cycle has %q, want %q", i, sequence[i+len(agents)], ua)
+		}
+	}
+	if len(seen) != len(agents) {
+		t.Errorf("first cycle covered %d agents, want %d", len(seen), len(agents))
+	}
+}
+
+func TestInjectorsRotateUserAgents(t *testing.T) {
+	var mu sync.Mutex
+	var agents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		agents = append(agents, r.UserAgent())
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	logger := NewLoggerWithLevel(devNull, LogLevelError)
+
+	opts := &InjectorOptions{UserAgents: NewUserAgentRotator(nil, 7)}
+	NewFastHTTPQueryInjectorWithOptions(opts).Inject(server.URL, "x", logger)
+
+	expected := NewUserAgentRotator(nil, 7)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(agents) == 0 {
+		t.Fatal("no requests received")
+	}
+	for i, got := range agents {
+		if want := expected.Next(); got != want {
+			t.Errorf("request %d: User-Agent = %q, want %q", i, got, want)
+		}
+	}
+}
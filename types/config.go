@@ -50,6 +50,9 @@ const (
 	PayloadSourceGenerated     PayloadSource = "Generated"
 	PayloadSourceFromFile      PayloadSource = "From File"
 	PayloadSourceEnterManually PayloadSource = "Enter Manually"
+	// PayloadSourceNuclei sends the payloads of a nuclei template or list
+	// (FilePath) as they are, without generating variants
+	PayloadSourceNuclei PayloadSource = "Nuclei"
 )
 
 type PayloadEncoding string
@@ -69,6 +72,8 @@ const (
 	PayloadEncodingWindowsCmd    PayloadEncoding = "WindowsCmdVariants"
 	PayloadEncodingPathTraversal PayloadEncoding = "PathTraversalVariants"
 	PayloadEncodingUTF8          PayloadEncoding = "UTF8Variants"
+	PayloadEncodingUTF7          PayloadEncoding = "UTF7Variants"
+	PayloadEncodingInterleaved   PayloadEncoding = "InterleavedEncodingVariants"
 )
 
 type Payload struct {
@@ -77,6 +82,15 @@ type Payload struct {
 	Source   PayloadSource   `yaml:"source" json:"source"`
 	FilePath string          `yaml:"file_path" json:"file_path"`
 	Custom   []string        `yaml:"custom" json:"custom"`
+
+	// SensitiveFiles is an optional wordlist of target files for fileaccess payloads
+	SensitiveFiles string `yaml:"sensitive_files,omitempty" json:"sensitive_files,omitempty"`
+
+	// Dir holds the base payload files, one <attack type>.txt each (default: payloads)
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"`
+	// Files maps attack types to payload files, overriding <attack type>.txt.
+	// Relative paths are resolved against Dir.
+	Files map[string]string `yaml:"files,omitempty" json:"files,omitempty"`
 }
 
 type EvasionLevel string
@@ -98,6 +112,95 @@ type Target struct {
 	Method TargetMethod `yaml:"method" json:"method"`
 	URL    string       `yaml:"url" json:"url"`
 	File   string       `yaml:"file" json:"file"`
+	// URLs are every target read from File; each variant is sent to all of
+	// them, and URL holds the first
+	URLs []string `yaml:"urls,omitempty" json:"urls,omitempty"`
+
+	// ParamNames are the query/body parameters payloads are injected into (default: "param")
+	ParamNames []string `yaml:"param_names,omitempty" json:"param_names,omitempty"`
+
+	// Host overrides the Host header while connecting to the host in URL
+	Host string `yaml:"host,omitempty" json:"host,omitempty"`
+
+	// UserAgent is sent on every request instead of the HTTP client default
+	UserAgent string `yaml:"user_agent,omitempty" json:"user_agent,omitempty"`
+	// RotateUserAgents cycles through built-in browser User-Agents per request
+	RotateUserAgents bool `yaml:"rotate_user_agents,omitempty" json:"rotate_user_agents,omitempty"`
+	// UserAgentSeed fixes the rotation order so runs are reproducible
+	UserAgentSeed int64 `yaml:"user_agent_seed,omitempty" json:"user_agent_seed,omitempty"`
+
+	// BodyFile is a request body template with a §PAYLOAD§ marker, sent by
+	// the body injector instead of its form and JSON bodies
+	BodyFile string `yaml:"body_file,omitempty" json:"body_file,omitempty"`
+	// ContentType is sent with BodyFile (default: guessed from its extension)
+	ContentType string `yaml:"content_type,omitempty" json:"content_type,omitempty"`
+
+	// CookieJar replays cookies set by the target on later requests to the
+	// same host, keeping a session established by the first request
+	CookieJar bool `yaml:"cookie_jar,omitempty" json:"cookie_jar,omitempty"`
+
+	// CacheBust adds no-cache headers and a random query nonce to every
+	// request so a CDN cache cannot answer in place of the WAF
+	CacheBust bool `yaml:"cache_bust,omitempty" json:"cache_bust,omitempty"`
+
+	// FollowRedirects follows redirects (up to MaxRedirects hops, default 5)
+	// and classifies the final response instead of the 3xx
+	FollowRedirects bool `yaml:"follow_redirects,omitempty" json:"follow_redirects,omitempty"`
+	MaxRedirects    int  `yaml:"max_redirects,omitempty" json:"max_redirects,omitempty"`
+
+	// ResetIsBlock counts a connection reset or closed before the response
+	// as a block, for WAFs that drop blocked requests instead of answering
+	ResetIsBlock bool `yaml:"reset_is_block,omitempty" json:"reset_is_block,omitempty"`
+
+	// BlockSignaturesFile lists block page signatures, one substring or
+	// "re:" regular expression per line; a response body matching any of
+	// them is blocked whatever its status code
+	BlockSignaturesFile string `yaml:"block_signatures_file,omitempty" json:"block_signatures_file,omitempty"`
+
+	// Timing sends each request on a fresh connection and records its DNS,
+	// connect, TLS, TTFB and transfer times
+	Timing bool `yaml:"timing,omitempty" json:"timing,omitempty"`
+
+	// ReadBufferSize raises the HTTP client's read buffer, which also limits
+	// response header size; MaxResponseSize caps response bodies in bytes,
+	// still classifying larger ones by status (0 = defaults, unlimited body)
+	ReadBufferSize  int `yaml:"read_buffer_size,omitempty" json:"read_buffer_size,omitempty"`
+	MaxResponseSize int `yaml:"max_response_size,omitempty" json:"max_response_size,omitempty"`
+
+	// Diversify varies benign headers, their order and the User-Agent per
+	// request (seeded by Seed) so requests share no fingerprint;
+	// DiversifyPadding also adds a random-length padding header
+	Diversify        bool `yaml:"diversify,omitempty" json:"diversify,omitempty"`
+	DiversifyPadding bool `yaml:"diversify_padding,omitempty" json:"diversify_padding,omitempty"`
+
+	// SourceIPs are local addresses requests are sent from in turn (order
+	// shuffled by Seed), to spread traffic over several egress IPs
+	SourceIPs []string `yaml:"source_ips,omitempty" json:"source_ips,omitempty"`
+
+	// Scope is an allowlist of host globs ("*.example.com") and CIDRs
+	// ("10.0.0.0/8"); requests to any other host are refused (empty = any host)
+	Scope []string `yaml:"scope,omitempty" json:"scope,omitempty"`
+
+	// DisableConnect, DisableChunked and DisableMultipleContentLength turn off
+	// the protocol tricks that can have side effects on proxies and load
+	// balancers (all enabled by default)
+	DisableConnect               bool `yaml:"disable_connect,omitempty" json:"disable_connect,omitempty"`
+	DisableChunked               bool `yaml:"disable_chunked,omitempty" json:"disable_chunked,omitempty"`
+	DisableMultipleContentLength bool `yaml:"disable_multiple_content_length,omitempty" json:"disable_multiple_content_length,omitempty"`
+
+	// HeaderNameInjection also puts payloads in header names, not just
+	// values (off by default: strict servers answer them 400)
+	HeaderNameInjection bool `yaml:"header_name_injection,omitempty" json:"header_name_injection,omitempty"`
+
+	// Pipeline also sends each payload in an HTTP/1.1 pipelined batch
+	// behind a benign request, to test the WAF's request boundaries
+	Pipeline bool `yaml:"pipeline,omitempty" json:"pipeline,omitempty"`
+
+	// SplitParams also sends each payload split into consecutive fragments,
+	// one per parameter, in a single request (a=<scr&b=ipt>); SplitAt sets
+	// the fragment boundaries as character offsets (even split when empty)
+	SplitParams []string `yaml:"split_params,omitempty" json:"split_params,omitempty"`
+	SplitAt     []int    `yaml:"split_at,omitempty" json:"split_at,omitempty"`
 }
 
 type ReportType string
@@ -117,6 +220,10 @@ type Config struct {
 	// Action specifies what to do: "Generate Payloads", "Send to URL", or "Use Existing Payloads"
 	Action Action `yaml:"action" json:"action"`
 
+	// Tags label the run ("prod-cloudflare-2024Q1") in every report, the
+	// results database and notifications, so runs can be searched
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+
 	// Attack configuration
 	AttackType AttackType `yaml:"attack_type" json:"attack_type"`
 
@@ -126,9 +233,100 @@ type Config struct {
 	// Evasion configuration
 	EvasionLevel EvasionLevel `yaml:"evasion_level" json:"evasion_level"`
 
+	// EncodingLevels overrides EvasionLevel per encoding, keyed by encoding
+	// name (see ParsePayloadEncoding), e.g. {pathtraversal: advanced}
+	EncodingLevels map[string]string `yaml:"encoding_levels,omitempty" json:"encoding_levels,omitempty"`
+
+	// Seed makes randomized evasions reproducible: generation stream n (a
+	// worker or, for existing payloads, the payload's index) draws from its
+	// own source seeded with Seed+n (0 = unseeded, varies per run)
+	Seed int64 `yaml:"seed,omitempty" json:"seed,omitempty"`
+
+	// DistinctTechniques keeps at most this many variants per technique for
+	// each payload, trading volume for breadth (0 = keep all)
+	DistinctTechniques int `yaml:"distinct_techniques,omitempty" json:"distinct_techniques,omitempty"`
+
+	// RequireTechniques fails the run when any of these techniques (technique
+	// labels or encoding names) produced no variant
+	RequireTechniques []string `yaml:"require_techniques,omitempty" json:"require_techniques,omitempty"`
+
+	// OnlyTechniques keeps only the variants of these techniques (technique
+	// labels or encoding names); ExcludeTechniques drops those of these
+	OnlyTechniques    []string `yaml:"only_techniques,omitempty" json:"only_techniques,omitempty"`
+	ExcludeTechniques []string `yaml:"exclude_techniques,omitempty" json:"exclude_techniques,omitempty"`
+
+	// Stdout prints only the generated variants, one per line, to stdout:
+	// no payload files, reports or status output
+	Stdout bool `yaml:"stdout,omitempty" json:"stdout,omitempty"`
+
+	// SavePayloads writes the payload files when sending to a URL too;
+	// only generating payloads writes them by default
+	SavePayloads bool `yaml:"save_payloads,omitempty" json:"save_payloads,omitempty"`
+
+	// Exhaustive generates every encoding at the advanced level with every
+	// alternative of every choice a technique makes, deterministically,
+	// instead of a random pick: a reproducible superset for correctness
+	// testing and coverage
+	Exhaustive bool `yaml:"exhaustive,omitempty" json:"exhaustive,omitempty"`
+
+	// StrictVariants drops generated variants that no longer decode or
+	// normalize back to the payload's attack structure
+	StrictVariants bool `yaml:"strict_variants,omitempty" json:"strict_variants,omitempty"`
+
+	// MaxTraversalDepth caps ../ depth expansion for path payloads (0 = default)
+	MaxTraversalDepth int `yaml:"max_traversal_depth,omitempty" json:"max_traversal_depth,omitempty"`
+
+	// TraversalTarget is the sensitive file path techniques aim at, or an OS
+	// name ("unix", "windows") for its default file (default: etc/passwd)
+	TraversalTarget string `yaml:"traversal_target,omitempty" json:"traversal_target,omitempty"`
+
 	// Target configuration
 	Target Target `yaml:"target" json:"target"`
 
+	// MaxRequests is a hard cap on the requests sent to the target (0 = no cap)
+	MaxRequests int `yaml:"max_requests,omitempty" json:"max_requests,omitempty"`
+
+	// PerHostConns caps the simultaneous requests to any one host when
+	// fanning out across several targets (0 = no per-host cap)
+	PerHostConns int `yaml:"per_host_conns,omitempty" json:"per_host_conns,omitempty"`
+
+	// Sink streams every result as it is produced: "stdout", "file:<path>",
+	// a bare NDJSON path, or a scheme added with request.RegisterSink
+	Sink string `yaml:"sink,omitempty" json:"sink,omitempty"`
+
+	// StopOnFirstBypass skips the remaining variants of a base payload once
+	// one of them bypasses
+	StopOnFirstBypass bool `yaml:"stop_on_first_bypass,omitempty" json:"stop_on_first_bypass,omitempty"`
+
+	// Shuffle dispatches the variants in a random order (seeded by Seed)
+	// instead of payload by payload, technique by technique
+	Shuffle bool `yaml:"shuffle,omitempty" json:"shuffle,omitempty"`
+
+	// AdaptiveEscalate regenerates base payloads that were only ever blocked
+	// at the next evasion level and sends them again, for up to
+	// MaxEscalations rounds (0 = until advanced is reached)
+	AdaptiveEscalate bool `yaml:"adaptive_escalate,omitempty" json:"adaptive_escalate,omitempty"`
+	MaxEscalations   int  `yaml:"max_escalations,omitempty" json:"max_escalations,omitempty"`
+
+	// ProbeNormalization probes which encodings the target decodes before
+	// sending and generates those encodings first; the probe's findings are
+	// kept in TargetNormalizations
+	ProbeNormalization   bool                     `yaml:"probe_normalization,omitempty" json:"probe_normalization,omitempty"`
+	TargetNormalizations map[PayloadEncoding]bool `yaml:"-" json:"-"`
+
+	// InterestingStatusCodes maps attack types to error statuses treated as
+	// candidate bypasses instead of target errors (default: sqli 500/502, ...)
+	InterestingStatusCodes map[string][]int `yaml:"interesting_status_codes,omitempty" json:"interesting_status_codes,omitempty"`
+
+	// DiffBaseline is a JSON file holding a clean response captured from the
+	// target; results whose responses deviate from it are flagged anomalous
+	DiffBaseline string `yaml:"diff_baseline,omitempty" json:"diff_baseline,omitempty"`
+
+	// ViewURL turns on stored mode: every request carries a canary token,
+	// and after sending this page is fetched and the results whose canaries
+	// appear on it are flagged stored
+	ViewURL string `yaml:"view_url,omitempty" json:"view_url,omitempty"`
+
 	// Report configuration
 	ReportType ReportType `yaml:"report_type" json:"report_type"`
 
@@ -140,6 +338,9 @@ type Config struct {
 	ShowWAFReport        bool        `yaml:"-" json:"-"`
 	WAFFingerprint       interface{} `yaml:"-" json:"-"`
 
+	// Adaptive concurrency backs off when the target starts erroring (CLI only)
+	AdaptiveConcurrency bool `yaml:"-" json:"-"`
+
 	// Additional attack types for multi-attack processing (CLI only)
 	AdditionalAttackTypes []AttackType `yaml:"-" json:"-"`
 
@@ -147,4 +348,10 @@ type Config struct {
 	EnableAI  bool        `yaml:"-" json:"-"`
 	AIConfig  interface{} `yaml:"-" json:"-"` // Will hold *genai.Config
 	AIContext string      `yaml:"-" json:"-"`
+	// AIRequired aborts the run when the AI provider is not configured,
+	// instead of generating without AI
+	AIRequired bool `yaml:"-" json:"-"`
+	// AIAnalytics accumulates the run's GenAI requests for the closing
+	// summary; it holds a *genai.AnalyticsCollector once AI generation ran
+	AIAnalytics interface{} `yaml:"-" json:"-"`
 }
@@ -136,6 +136,8 @@ func TestPayloadEncodingConstants(t *testing.T) {
 		PayloadEncodingWindowsCmd,
 		PayloadEncodingPathTraversal,
 		PayloadEncodingUTF8,
+		PayloadEncodingUTF7,
+		PayloadEncodingInterleaved,
 	}
 
 	expectedValues := []string{
@@ -153,6 +155,8 @@ func TestPayloadEncodingConstants(t *testing.T) {
 		"WindowsCmdVariants",
 		"PathTraversalVariants",
 		"UTF8Variants",
+		"UTF7Variants",
+		"InterleavedEncodingVariants",
 	}
 
 	if len(encodings) != len(expectedValues) {
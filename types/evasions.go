@@ -0,0 +1,194 @@
+package types
+
+import "fmt"
+
+// PayloadEvasionMap lists the encodings each attack type's payloads are
+// generated with
+var PayloadEvasionMap = map[AttackType][]PayloadEncoding{
+	AttackTypeXSS: {
+		PayloadEncodingHTML,
+		PayloadEncodingUnicode,
+		PayloadEncodingHex,
+		PayloadEncodingOctal,
+		PayloadEncodingBase64,
+		PayloadEncodingBestFit,
+		PayloadEncodingInterleaved,
+		PayloadEncodingUTF7,
+		PayloadEncodingUTF8,
+	},
+	AttackTypeSQLI: {
+		PayloadEncodingUnixCmd,
+		PayloadEncodingUnicode,
+		PayloadEncodingHex,
+		PayloadEncodingOctal,
+		PayloadEncodingBase64,
+		PayloadEncodingBestFit,
+		PayloadEncodingInterleaved,
+		PayloadEncodingUTF8,
+	},
+	AttackTypeUnixCMDI: {
+		PayloadEncodingUnixCmd,
+		PayloadEncodingUnicode,
+		PayloadEncodingHex,
+		PayloadEncodingOctal,
+		PayloadEncodingBase64,
+		PayloadEncodingBestFit,
+		PayloadEncodingPathTraversal,
+	},
+	AttackTypeWinCMDI: {
+		PayloadEncodingWindowsCmd,
+		PayloadEncodingUnicode,
+		PayloadEncodingHex,
+		PayloadEncodingOctal,
+		PayloadEncodingBase64,
+		PayloadEncodingBestFit,
+		PayloadEncodingPathTraversal,
+	},
+	AttackTypePath: {
+		PayloadEncodingPathTraversal,
+		PayloadEncodingUnicode,
+		PayloadEncodingHex,
+		PayloadEncodingOctal,
+		PayloadEncodingBase64,
+		PayloadEncodingBestFit,
+		PayloadEncodingUTF8,
+	},
+	AttackTypeFileAccess: {
+		PayloadEncodingPathTraversal,
+		PayloadEncodingUnicode,
+		PayloadEncodingHex,
+		PayloadEncodingOctal,
+		PayloadEncodingBase64,
+		PayloadEncodingBestFit,
+	},
+	AttackTypeLDAP: {
+		PayloadEncodingUnicode,
+		PayloadEncodingHex,
+		PayloadEncodingOctal,
+		PayloadEncodingBase64,
+		PayloadEncodingBestFit,
+		PayloadEncodingInterleaved,
+	},
+	AttackTypeGeneric: {
+		PayloadEncodingHTML,
+		PayloadEncodingUnicode,
+		PayloadEncodingHex,
+		PayloadEncodingOctal,
+		PayloadEncodingBase64,
+		PayloadEncodingBestFit,
+		PayloadEncodingInterleaved,
+		PayloadEncodingUnixCmd,
+		PayloadEncodingWindowsCmd,
+		PayloadEncodingPathTraversal,
+	},
+}
+
+var EvasionCategoryMap = map[PayloadEncoding]EvasionCategory{
+	PayloadEncodingHTML:          EvasionCategoryEncoder,
+	PayloadEncodingUnicode:       EvasionCategoryEncoder,
+	PayloadEncodingHex:           EvasionCategoryEncoder,
+	PayloadEncodingOctal:         EvasionCategoryEncoder,
+	PayloadEncodingBase64:        EvasionCategoryEncoder,
+	PayloadEncodingBestFit:       EvasionCategoryEncoder,
+	PayloadEncodingURL:           EvasionCategoryEncoder,
+	PayloadEncodingDoubleURL:     EvasionCategoryEncoder,
+	PayloadEncodingMixedCase:     EvasionCategoryEncoder,
+	PayloadEncodingUTF8:          EvasionCategoryEncoder,
+	PayloadEncodingUTF7:          EvasionCategoryEncoder,
+	PayloadEncodingInterleaved:   EvasionCategoryEncoder,
+	PayloadEncodingUnixCmd:       EvasionCategoryCommand,
+	PayloadEncodingWindowsCmd:    EvasionCategoryCommand,
+	PayloadEncodingPathTraversal: EvasionCategoryPath,
+}
+
+// EvasionMinimumLevels hold encodings that PayloadEvasionMap only applies
+// from a given evasion level up; an explicitly selected encoding is always
+// applied
+var EvasionMinimumLevels = map[PayloadEncoding]EvasionLevel{
+	PayloadEncodingUTF7: EvasionLevelAdvanced,
+}
+
+var evasionLevelRank = map[EvasionLevel]int{
+	EvasionLevelBasic:    0,
+	EvasionLevelMedium:   1,
+	EvasionLevelAdvanced: 2,
+}
+
+func GetEvasionsForPayload(attackType AttackType) ([]PayloadEncoding, bool) {
+	evasions, exists := PayloadEvasionMap[attackType]
+	return evasions, exists
+}
+
+// GetEvasionsForLevel is GetEvasionsForPayload without the encodings whose
+// EvasionMinimumLevels is above level
+func GetEvasionsForLevel(attackType AttackType, level EvasionLevel) ([]PayloadEncoding, bool) {
+	evasions, exists := GetEvasionsForPayload(attackType)
+	var kept []PayloadEncoding
+	for _, evasion := range evasions {
+		if EvasionAppliesAtLevel(evasion, level) {
+			kept = append(kept, evasion)
+		}
+	}
+	return kept, exists
+}
+
+// EvasionAppliesAtLevel reports whether level reaches the EvasionMinimumLevels
+// of evasion, if it has one
+func EvasionAppliesAtLevel(evasion PayloadEncoding, level EvasionLevel) bool {
+	minimum, ok := EvasionMinimumLevels[evasion]
+	return !ok || evasionLevelRank[level] >= evasionLevelRank[minimum]
+}
+
+func GetEvasionsByCategory(attackType AttackType) map[EvasionCategory][]PayloadEncoding {
+	evasions, exists := PayloadEvasionMap[attackType]
+	if !exists {
+		return nil
+	}
+	categorized := make(map[EvasionCategory][]PayloadEncoding)
+	for _, evasion := range evasions {
+		category := EvasionCategoryMap[evasion]
+		categorized[category] = append(categorized[category], evasion)
+	}
+	return categorized
+}
+
+func IsEvasionApplicable(payloadType AttackType, evasionType PayloadEncoding) bool {
+	evasions, exists := PayloadEvasionMap[payloadType]
+	if !exists {
+		return false
+	}
+
+	for _, evasion := range evasions {
+		if evasion == evasionType {
+			return true
+		}
+	}
+	return false
+}
+
+// InapplicableEvasionError reports an encoding that the attack type's
+// payloads are never generated with
+type InapplicableEvasionError struct {
+	AttackType AttackType
+	Encoding   PayloadEncoding
+	Supported  []PayloadEncoding
+}
+
+func (e *InapplicableEvasionError) Error() string {
+	return fmt.Sprintf("encoding %q is not applicable to attack type %q", e.Encoding, e.AttackType)
+}
+
+// CheckEvasionApplicable returns an *InapplicableEvasionError when
+// evasionType is not used for payloadType. Attack types without an entry in
+// PayloadEvasionMap are not checked.
+func CheckEvasionApplicable(payloadType AttackType, evasionType PayloadEncoding) error {
+	supported, exists := GetEvasionsForPayload(payloadType)
+	if !exists || IsEvasionApplicable(payloadType, evasionType) {
+		return nil
+	}
+	return &InapplicableEvasionError{
+		AttackType: payloadType,
+		Encoding:   evasionType,
+		Supported:  supported,
+	}
+}
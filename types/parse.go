@@ -0,0 +1,90 @@
+package types
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// payloadEncodingNames maps the short CLI names (-encoding) to encodings
+var payloadEncodingNames = map[string]PayloadEncoding{
+	"url":            PayloadEncodingURL,
+	"doubleurl":      PayloadEncodingDoubleURL,
+	"double-url":     PayloadEncodingDoubleURL,
+	"html":           PayloadEncodingHTML,
+	"unicode":        PayloadEncodingUnicode,
+	"base64":         PayloadEncodingBase64,
+	"b64":            PayloadEncodingBase64,
+	"hex":            PayloadEncodingHex,
+	"octal":          PayloadEncodingOctal,
+	"bestfit":        PayloadEncodingBestFit,
+	"best-fit":       PayloadEncodingBestFit,
+	"mixedcase":      PayloadEncodingMixedCase,
+	"mixed-case":     PayloadEncodingMixedCase,
+	"utf8":           PayloadEncodingUTF8,
+	"utf-8":          PayloadEncodingUTF8,
+	"utf7":           PayloadEncodingUTF7,
+	"utf-7":          PayloadEncodingUTF7,
+	"interleaved":    PayloadEncodingInterleaved,
+	"unixcmd":        PayloadEncodingUnixCmd,
+	"unix-cmd":       PayloadEncodingUnixCmd,
+	"windowscmd":     PayloadEncodingWindowsCmd,
+	"windows-cmd":    PayloadEncodingWindowsCmd,
+	"pathtraversal":  PayloadEncodingPathTraversal,
+	"path-traversal": PayloadEncodingPathTraversal,
+}
+
+// ParsePayloadEncoding resolves a short encoding name ("base64",
+// "path-traversal") or a full one ("Base64Variants"), ignoring case
+func ParsePayloadEncoding(name string) (PayloadEncoding, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if encoding, ok := payloadEncodingNames[name]; ok {
+		return encoding, true
+	}
+	for _, encoding := range payloadEncodingNames {
+		if strings.ToLower(string(encoding)) == name {
+			return encoding, true
+		}
+	}
+	return "", false
+}
+
+// ParseEvasionLevel resolves "basic", "medium" or "advanced", ignoring case
+func ParseEvasionLevel(name string) (EvasionLevel, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "basic":
+		return EvasionLevelBasic, true
+	case "medium":
+		return EvasionLevelMedium, true
+	case "advanced":
+		return EvasionLevelAdvanced, true
+	}
+	return "", false
+}
+
+// ParseThreads resolves a -threads value: a positive count, or "auto" or 0
+// for one worker per CPU. Negative and non-numeric values are rejected.
+func ParseThreads(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "auto" {
+		return runtime.NumCPU(), nil
+	}
+	threads, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid thread count %q (use a positive number or auto)", value)
+	}
+	return ResolveThreads(threads)
+}
+
+// ResolveThreads maps a thread count of 0 to runtime.NumCPU() and rejects
+// negative counts, which would leave a worker pool without workers
+func ResolveThreads(threads int) (int, error) {
+	switch {
+	case threads < 0:
+		return 0, fmt.Errorf("invalid thread count %d (must be at least 1, or 0 for auto)", threads)
+	case threads == 0:
+		return runtime.NumCPU(), nil
+	}
+	return threads, nil
+}
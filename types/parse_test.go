@@ -0,0 +1,28 @@
+package types
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestParseThreads(t *testing.T) {
+	for _, value := range []string{"0", "auto", " AUTO "} {
+		got, err := ParseThreads(value)
+		if err != nil || got != runtime.NumCPU() {
+			t.Errorf("ParseThreads(%q) = %d, %v; want %d (NumCPU)", value, got, err, runtime.NumCPU())
+		}
+	}
+
+	if got, err := ParseThreads("4"); err != nil || got != 4 {
+		t.Errorf("ParseThreads(\"4\") = %d, %v; want 4", got, err)
+	}
+
+	for _, value := range []string{"-1", "-8", "many", ""} {
+		if got, err := ParseThreads(value); err == nil {
+			t.Errorf("ParseThreads(%q) = %d, want an error", value, got)
+		}
+	}
+	if _, err := ResolveThreads(-2); err == nil {
+		t.Error("ResolveThreads(-2) succeeded, want an error")
+	}
+}
@@ -0,0 +1,19 @@
+package types
+
+// Variant is one generated payload variant with the metadata of how it was
+// made. Generators produce it and it travels unchanged through
+// model.PayloadResults and onto every request.TestResult sent with it.
+type Variant struct {
+	Value string `json:"value"`
+	// Encoding is the PayloadEncoding that generated the variant
+	Encoding string `json:"encoding,omitempty"`
+	// Technique labels the transformation within Encoding (the encoding
+	// itself when the generator does not tell its techniques apart)
+	Technique   string `json:"technique,omitempty"`
+	Explanation string `json:"explanation,omitempty"`
+	Level       string `json:"level,omitempty"`
+	// SourcePayload is the base payload the variant was generated from
+	SourcePayload string `json:"source_payload,omitempty"`
+	// ID is the stable variant ID (see model.VariantID)
+	ID string `json:"id,omitempty"`
+}
@@ -3,8 +3,7 @@ package main
 import (
 	"bytes"
 	"embed"
-	"encoding/base64"
-	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"html"
 	"io"
@@ -18,11 +17,14 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"obfuskit/internal/canon"
 )
 
 //go:embed static/*
@@ -115,7 +117,7 @@ func echoHandler(w http.ResponseWriter, r *http.Request) {
 		if step == "" {
 			continue
 		}
-		v, _ := decodeOnce(step, val)
+		v, _ := canon.DecodeOnce(step, val)
 		val = v
 	}
 	// Intentionally reflect without HTML escaping if mode=raw
@@ -139,7 +141,7 @@ func decodeHandler(w http.ResponseWriter, r *http.Request) {
 	input := getRawParam(r, "value")
 	val := input
 	for i := 0; i < repeat; i++ {
-		v, err := decodeOnce(mode, val)
+		v, err := canon.DecodeOnce(mode, val)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -206,17 +208,17 @@ func jsonHandler(w http.ResponseWriter, r *http.Request) {
 	values := map[string]any{}
 	if strings.Contains(strings.ToLower(ct), "json") {
 		// Extremely loose JSON parse: split on punctuation
-		tokens := splitLoose(string(body))
+		tokens := canon.SplitLoose(string(body))
 		for i := 0; i+1 < len(tokens); i += 2 {
 			k, v := tokens[i], tokens[i+1]
-			k = applyCase(prefer, k)
+			k = canon.ApplyCase(prefer, k)
 			values[k] = v
 		}
 	} else {
 		// Misparsed: treat body as querystring if not json
 		q, _ := url.ParseQuery(string(body))
 		for k, vs := range q {
-			k = applyCase(prefer, k)
+			k = canon.ApplyCase(prefer, k)
 			if len(vs) == 1 {
 				values[k] = vs[0]
 			} else {
@@ -226,7 +228,7 @@ func jsonHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	// HTTP parameter pollution: merge URL query (favoring last writer)
 	for k, vs := range r.URL.Query() {
-		k = applyCase(prefer, k)
+		k = canon.ApplyCase(prefer, k)
 		if len(vs) > 0 {
 			values[k] = vs[len(vs)-1]
 		}
@@ -375,6 +377,10 @@ func nullByteHandler(w http.ResponseWriter, r *http.Request) {
 		processName = parts[1]
 	}
 	log.Printf("user=%q", logName)
+	if wantsJSON(r) {
+		writeInterpretation(w, "nullbyte", logName, processName)
+		return
+	}
 	fmt.Fprintf(w, "hello %s", processName)
 }
 
@@ -385,6 +391,11 @@ func hppHandler(w http.ResponseWriter, r *http.Request) {
 	if key == "" {
 		key = "a"
 	}
+	if wantsJSON(r) {
+		// Loggers and WAFs typically read the first value, the app the last
+		writeInterpretation(w, "hpp", q.Get(key), last(q[key]))
+		return
+	}
 	fmt.Fprintf(w, "first=%q last=%q all=%q\n", q.Get(key), last(q[key]), q[key])
 }
 
@@ -442,7 +453,7 @@ func chainHandler(w http.ResponseWriter, r *http.Request) {
 		if s == "" {
 			continue
 		}
-		v, err := decodeOnce(s, value)
+		v, err := canon.DecodeOnce(s, value)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -495,13 +506,17 @@ func caseSensitivityHandler(w http.ResponseWriter, r *http.Request) {
 	upper := r.URL.Query().Get("Param")
 	lower := r.URL.Query().Get("param")
 	mixed := r.URL.Query().Get("pArAm")
-	fmt.Fprintf(w, "Param=%q param=%q pArAm=%q\n", upper, lower, mixed)
 	// But when processing, collapse to lower
 	combined := r.URL.Query()
 	collapsed := url.Values{}
 	for k, vs := range combined {
 		collapsed[strings.ToLower(k)] = vs
 	}
+	if wantsJSON(r) {
+		writeInterpretation(w, "case", combined, collapsed)
+		return
+	}
+	fmt.Fprintf(w, "Param=%q param=%q pArAm=%q\n", upper, lower, mixed)
 	fmt.Fprintf(w, "collapsed=%q\n", collapsed.Encode())
 }
 
@@ -540,7 +555,52 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "multipart/form-data required", http.StatusUnsupportedMediaType)
 }
 
+// External entity fetches are bounded so a demo cannot hang on a slow host
+// or pull a huge file into the response
+const (
+	xxeFetchTimeout  = 5 * time.Second
+	xxeMaxFetchBytes = 64 << 10
+	// xxeAllowNetworkEnv opts in to fetching http(s) SYSTEM identifiers
+	xxeAllowNetworkEnv = "XXE_DEMO_ALLOW_NETWORK"
+)
+
+var xxeClient = &http.Client{Timeout: xxeFetchTimeout}
+
+// xxeNetworkAllowed reports whether out-of-band entity fetches are enabled
+func xxeNetworkAllowed() bool {
+	allowed, _ := strconv.ParseBool(os.Getenv(xxeAllowNetworkEnv))
+	return allowed
+}
+
+// fetchEntity resolves a SYSTEM identifier, reading at most xxeMaxFetchBytes
+func fetchEntity(target string) []byte {
+	var src io.ReadCloser
+	switch {
+	case strings.HasPrefix(target, "file://"):
+		f, err := os.Open(strings.TrimPrefix(target, "file://"))
+		if err != nil {
+			return nil
+		}
+		src = f
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		if !xxeNetworkAllowed() {
+			return nil
+		}
+		resp, err := xxeClient.Get(target)
+		if err != nil {
+			return nil
+		}
+		src = resp.Body
+	default:
+		return nil
+	}
+	defer src.Close()
+	data, _ := io.ReadAll(io.LimitReader(src, xxeMaxFetchBytes))
+	return data
+}
+
 // /xml — naive entity expansion that fetches external SYSTEM identifiers
+// (file:// always, http(s) only with XXE_DEMO_ALLOW_NETWORK set)
 func xmlHandler(w http.ResponseWriter, r *http.Request) {
 	body, _ := io.ReadAll(r.Body)
 	s := string(body)
@@ -550,17 +610,7 @@ func xmlHandler(w http.ResponseWriter, r *http.Request) {
 	if len(m) == 3 {
 		name := m[1]
 		target := m[2]
-		var fetched []byte
-		if strings.HasPrefix(target, "file://") {
-			p := strings.TrimPrefix(target, "file://")
-			fetched, _ = os.ReadFile(p)
-		} else if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
-			resp, err := http.Get(target)
-			if err == nil {
-				defer resp.Body.Close()
-				fetched, _ = io.ReadAll(resp.Body)
-			}
-		}
+		fetched := fetchEntity(target)
 		s = strings.ReplaceAll(s, "&"+name+";", string(fetched))
 	}
 	// Reflect possibly dangerous processed body
@@ -570,6 +620,29 @@ func xmlHandler(w http.ResponseWriter, r *http.Request) {
 
 // Helpers
 
+// interpretation is the ?format=json view of a log-vs-process mismatch, so a
+// harness can assert the two readings differ without scraping text
+type interpretation struct {
+	Endpoint  string `json:"endpoint"`
+	Logged    any    `json:"logged"`
+	Processed any    `json:"processed"`
+	Mismatch  bool   `json:"mismatch"`
+}
+
+func wantsJSON(r *http.Request) bool {
+	return strings.EqualFold(r.URL.Query().Get("format"), "json")
+}
+
+func writeInterpretation(w http.ResponseWriter, endpoint string, logged, processed any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(interpretation{
+		Endpoint:  endpoint,
+		Logged:    logged,
+		Processed: processed,
+		Mismatch:  !reflect.DeepEqual(logged, processed),
+	})
+}
+
 func getRawParam(r *http.Request, key string) string {
 	// Ambiguity: prefer last value, not first
 	vs := r.URL.Query()[key]
@@ -586,135 +659,6 @@ func last(vs []string) string {
 	return vs[len(vs)-1]
 }
 
-func decodeOnce(mode, s string) (string, error) {
-	switch mode {
-	case "url":
-		return url.QueryUnescape(s)
-	case "b64", "base64":
-		data, err := base64.StdEncoding.DecodeString(s)
-		if err != nil {
-			// Try URL encoding variant
-			d2, err2 := base64.URLEncoding.DecodeString(s)
-			if err2 != nil {
-				return "", err
-			}
-			return string(d2), nil
-		}
-		return string(data), nil
-	case "hex":
-		data, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
-		if err != nil {
-			return "", err
-		}
-		return string(data), nil
-	case "html":
-		return html.UnescapeString(s), nil
-	case "octal":
-		return decodeOctalEscapes(s), nil
-	case "unicode":
-		return decodeUnicodeEscapes(s), nil
-	case "ws", "whitespace":
-		return removeOddWhitespace(s), nil
-	case "idna":
-		// Very naive: split host and attempt puny-like lowercasing only
-		parts := strings.Split(s, "/")
-		if len(parts) > 0 {
-			parts[0] = strings.ToLower(parts[0])
-		}
-		return strings.Join(parts, "/"), nil
-	default:
-		return s, nil
-	}
-}
-
-func removeOddWhitespace(s string) string {
-	// Remove a set of uncommon whitespace characters
-	odd := []rune{'\u00A0', '\u2007', '\u202F', '\u200B', '\u200C', '\u200D', '\u2060'}
-	for _, r := range odd {
-		s = strings.ReplaceAll(s, string(r), "")
-	}
-	return s
-}
-
-func decodeOctalEscapes(s string) string {
-	var out bytes.Buffer
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\\' && i+3 < len(s) {
-			if isOctal(s[i+1]) && isOctal(s[i+2]) && isOctal(s[i+3]) {
-				val := (int(s[i+1]-'0') << 6) | (int(s[i+2]-'0') << 3) | int(s[i+3]-'0')
-				out.WriteByte(byte(val))
-				i += 3
-				continue
-			}
-		}
-		out.WriteByte(s[i])
-	}
-	return out.String()
-}
-
-func isOctal(b byte) bool { return b >= '0' && b <= '7' }
-
-func decodeUnicodeEscapes(s string) string {
-	// Handle \uXXXX and \xHH sequences
-	var out strings.Builder
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\\' && i+1 < len(s) {
-			switch s[i+1] {
-			case 'u':
-				if i+6 <= len(s) {
-					hexDigits := s[i+2 : i+6]
-					if v, err := strconv.ParseInt(hexDigits, 16, 32); err == nil {
-						out.WriteRune(rune(v))
-						i += 5
-						continue
-					}
-				}
-			case 'x':
-				if i+4 <= len(s) {
-					hexDigits := s[i+2 : i+4]
-					if v, err := strconv.ParseInt(hexDigits, 16, 8); err == nil {
-						out.WriteByte(byte(v))
-						i += 3
-						continue
-					}
-				}
-			}
-		}
-		out.WriteByte(s[i])
-	}
-	return out.String()
-}
-
-// splitLoose splits naive JSON-like text into tokens by punctuation
-func splitLoose(s string) []string {
-	seps := func(r rune) bool {
-		switch r {
-		case '{', '}', ':', ',', '\n', '\r', '\t', ' ', '"', '\'':
-			return true
-		}
-		return false
-	}
-	fields := strings.FieldsFunc(s, seps)
-	out := make([]string, 0, len(fields))
-	for _, f := range fields {
-		if f != "" {
-			out = append(out, f)
-		}
-	}
-	return out
-}
-
-func applyCase(mode, s string) string {
-	switch strings.ToLower(mode) {
-	case "upper":
-		return strings.ToUpper(s)
-	case "lower":
-		return strings.ToLower(s)
-	default:
-		return s
-	}
-}
-
 // Utilities to create a fake multipart body in tests (not used by handlers directly)
 func buildMultipart(fieldName, filename string, data []byte) (string, *bytes.Buffer) {
 	body := &bytes.Buffer{}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -165,6 +166,19 @@ func TestNullByte_Mismatch(t *testing.T) {
 	}
 }
 
+func TestNullByte_JSONShowsMismatch(t *testing.T) {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/nullbyte?name=admin%00root&format=json", nil)
+	withLogging(nullByteHandler).ServeHTTP(rr, req)
+	var got interpretation
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rr.Body.String(), err)
+	}
+	if got.Logged != "admin" || got.Processed != "root" || !got.Mismatch {
+		t.Fatalf("expected logged=admin processed=root mismatch; got %+v", got)
+	}
+}
+
 func TestHPP(t *testing.T) {
 	rr := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/hpp?a=1&a=2&a=3", nil)
@@ -260,6 +274,47 @@ func TestXML_ExternalEntity_File(t *testing.T) {
 	}
 }
 
+func TestXML_ExternalEntity_FileIsCapped(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(p, bytes.Repeat([]byte("Z"), xxeMaxFetchBytes+1024), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	xmlBody := fmt.Sprintf("<!DOCTYPE x [<!ENTITY e SYSTEM \"file://%s\">]><x>&e;</x>", p)
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/xml", strings.NewReader(xmlBody))
+	withLogging(xmlHandler).ServeHTTP(rr, req)
+	if got := strings.Count(rr.Body.String(), "Z"); got != xxeMaxFetchBytes {
+		t.Fatalf("expanded %d bytes of the file; want cap %d", got, xxeMaxFetchBytes)
+	}
+}
+
+func TestXML_ExternalEntity_NetworkOptIn(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write(bytes.Repeat([]byte("B"), xxeMaxFetchBytes+1024))
+	}))
+	defer srv.Close()
+
+	xmlBody := fmt.Sprintf("<!DOCTYPE x [<!ENTITY e SYSTEM \"%s\">]><x>&e;</x>", srv.URL)
+	post := func() string {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/xml", strings.NewReader(xmlBody))
+		withLogging(xmlHandler).ServeHTTP(rr, req)
+		return rr.Body.String()
+	}
+
+	t.Setenv(xxeAllowNetworkEnv, "")
+	if body := post(); hits != 0 || strings.Contains(body, "B") {
+		t.Fatalf("fetched without %s set: hits=%d", xxeAllowNetworkEnv, hits)
+	}
+
+	t.Setenv(xxeAllowNetworkEnv, "1")
+	if got := strings.Count(post(), "B"); hits != 1 || got != xxeMaxFetchBytes {
+		t.Fatalf("opted in: hits=%d expanded=%d; want 1 fetch capped at %d", hits, got, xxeMaxFetchBytes)
+	}
+}
+
 func TestHeaders_Desync_NotSupportedWithRecorder(t *testing.T) {
 	rr := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/desync", nil)